@@ -0,0 +1,224 @@
+/*
+* Copyright (c) 2014,2019 Contributors to the Eclipse Foundation
+* See the NOTICE file(s) distributed with this work for additional
+* information regarding copyright ownership.
+* This program and the accompanying materials are made available under the
+* terms of the Eclipse Public License 2.0 which is available at
+* http://www.eclipse.org/legal/epl-2.0, or the Apache License, Version 2.0
+* which is available at https://www.apache.org/licenses/LICENSE-2.0.
+* SPDX-License-Identifier: EPL-2.0 OR Apache-2.0
+* Contributors: Gabriele Baldoni, ADLINK Technology Inc.
+* golang APIs
+ */
+
+package fog05sdk
+
+import "sync"
+
+// DefaultRuntimeWorkers is the default number of FDU instances whose actions a runtime plugin runs in parallel
+const DefaultRuntimeWorkers = 4
+
+// RuntimeQueueStats are the metrics of the action queues of a runtime plugin
+type RuntimeQueueStats struct {
+	// Pending is the number of actions waiting in the queues
+	Pending int
+	// MaxPending is the highest Pending reached
+	MaxPending int
+	// Running is the number of actions running
+	Running int
+	// Instances is the number of instances with actions pending or running
+	Instances int
+	// Processed is the number of actions completed
+	Processed uint64
+	// Coalesced is the number of pending actions dropped because a newer record for the same action arrived
+	Coalesced uint64
+	// Dropped is the number of pending actions dropped because the plugin was closed or the instance migrated to another node
+	Dropped uint64
+}
+
+// queuedAction is an action of an instance, the desired record for handle or a function, eg. the reconciliation of the instance
+type queuedAction struct {
+	info FDURecord
+	run  func()
+}
+
+// actionQueue runs the actions of the desired records calling handle, in order for each instance and for at most workers instances in parallel.
+// The actions for which detach returns true, eg. the migrations waiting for another node, release their worker while they run
+type actionQueue struct {
+	handle  func(FDURecord)
+	detach  func(FDURecord) bool
+	workers int
+
+	mu        sync.Mutex
+	queues    map[string][]queuedAction
+	scheduled map[string]bool
+	waiting   []string
+	running   int
+	closed    bool
+	stats     RuntimeQueueStats
+}
+
+func newActionQueue(workers int, handle func(FDURecord), detach func(FDURecord) bool) *actionQueue {
+	if workers <= 0 {
+		workers = DefaultRuntimeWorkers
+	}
+	return &actionQueue{handle: handle, detach: detach, workers: workers, queues: map[string][]queuedAction{}, scheduled: map[string]bool{}}
+}
+
+// enqueue adds the record to the queue of its instance, replacing a pending record with the same status
+func (q *actionQueue) enqueue(info FDURecord) {
+	q.push(info.UUID, queuedAction{info: info})
+}
+
+// enqueueFunc adds fn to the queue of the instance, it runs after the actions already queued and before the next ones
+func (q *actionQueue) enqueueFunc(instanceid string, fn func()) {
+	q.push(instanceid, queuedAction{run: fn})
+}
+
+func (q *actionQueue) push(id string, action queuedAction) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		q.stats.Dropped++
+		return
+	}
+	pending := q.queues[id]
+	if n := len(pending); n > 0 && action.run == nil && pending[n-1].run == nil && pending[n-1].info.Status == action.info.Status {
+		pending[n-1] = action
+		q.stats.Coalesced++
+		return
+	}
+	q.queues[id] = append(pending, action)
+	q.stats.Pending++
+	if q.stats.Pending > q.stats.MaxPending {
+		q.stats.MaxPending = q.stats.Pending
+	}
+	if q.scheduled[id] {
+		return
+	}
+	q.scheduled[id] = true
+	if q.running < q.workers {
+		q.running++
+		go q.run(id)
+		return
+	}
+	q.waiting = append(q.waiting, id)
+}
+
+// run is a worker, it runs the actions of the instance, moving to the next waiting instance after each action so that no instance starves the others
+func (q *actionQueue) run(id string) {
+	q.mu.Lock()
+	for {
+		pending := q.queues[id]
+		if len(pending) == 0 {
+			delete(q.queues, id)
+			delete(q.scheduled, id)
+			if len(q.waiting) == 0 {
+				q.running--
+				q.mu.Unlock()
+				return
+			}
+			id = q.waiting[0]
+			q.waiting = q.waiting[1:]
+			continue
+		}
+		action := pending[0]
+		q.queues[id] = pending[1:]
+		q.stats.Pending--
+		q.stats.Running++
+		detached := action.run == nil && q.detach != nil && q.detach(action.info)
+		if detached {
+			q.release()
+		}
+		q.mu.Unlock()
+
+		if action.run != nil {
+			action.run()
+		} else {
+			q.handle(action.info)
+		}
+
+		q.mu.Lock()
+		q.stats.Running--
+		q.stats.Processed++
+		if detached {
+			// the next actions of the instance need a worker again
+			if len(q.queues[id]) == 0 {
+				delete(q.queues, id)
+				delete(q.scheduled, id)
+				q.mu.Unlock()
+				return
+			}
+			if q.running >= q.workers {
+				q.waiting = append(q.waiting, id)
+				q.mu.Unlock()
+				return
+			}
+			q.running++
+			continue
+		}
+		if len(q.waiting) > 0 && len(q.queues[id]) > 0 {
+			q.waiting = append(q.waiting, id)
+			id = q.waiting[0]
+			q.waiting = q.waiting[1:]
+		}
+	}
+}
+
+// release gives the worker of the calling goroutine to the next waiting instance, the caller holds mu
+func (q *actionQueue) release() {
+	if len(q.waiting) == 0 {
+		q.running--
+		return
+	}
+	id := q.waiting[0]
+	q.waiting = q.waiting[1:]
+	go q.run(id)
+}
+
+// close drops the pending actions and the records enqueued later, the running actions complete
+func (q *actionQueue) close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.closed = true
+	for id, pending := range q.queues {
+		q.stats.Dropped += uint64(len(pending))
+		q.stats.Pending -= len(pending)
+		q.queues[id] = nil
+	}
+}
+
+// drop drops the pending actions of the instance, the running one completes
+func (q *actionQueue) drop(instanceid string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	pending := q.queues[instanceid]
+	q.stats.Dropped += uint64(len(pending))
+	q.stats.Pending -= len(pending)
+	if len(pending) > 0 {
+		q.queues[instanceid] = nil
+	}
+}
+
+// busy returns true if the instance has actions pending or running
+func (q *actionQueue) busy(instanceid string) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.scheduled[instanceid]
+}
+
+// depth returns the number of pending actions of the instance
+func (q *actionQueue) depth(instanceid string) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.queues[instanceid])
+}
+
+// statistics returns the current metrics
+func (q *actionQueue) statistics() RuntimeQueueStats {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	s := q.stats
+	s.Instances = len(q.scheduled)
+	return s
+}
@@ -0,0 +1,260 @@
+/*
+* Copyright (c) 2014,2019 Contributors to the Eclipse Foundation
+* See the NOTICE file(s) distributed with this work for additional
+* information regarding copyright ownership.
+* This program and the accompanying materials are made available under the
+* terms of the Eclipse Public License 2.0 which is available at
+* http://www.eclipse.org/legal/epl-2.0, or the Apache License, Version 2.0
+* which is available at https://www.apache.org/licenses/LICENSE-2.0.
+* SPDX-License-Identifier: EPL-2.0 OR Apache-2.0
+* Contributors: Gabriele Baldoni, ADLINK Technology Inc.
+* golang APIs
+ */
+
+package fog05sdk
+
+import (
+	"fmt"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// FOSAgentInterface is the interface to be implemented for the Agent evals in Global Actual, called by the GAD functions
+type FOSAgentInterface interface {
+
+	//OnboardFDU adds the validated FDU descriptor to the catalog and returns the stored one, called by onboard_fdu
+	OnboardFDU(FDU) (*FDU, error)
+
+	//DefineFDU defines an instance of the given FDU in the node and returns its record, called by define_fdu
+	DefineFDU(string) (*FDURecord, error)
+
+	//AddPortToNetwork connects the given connection point to the given network, called by add_port_to_network
+	AddPortToNetwork(string, string) (*ConnectionPointRecord, error)
+
+	//RemovePortFromNetwork disconnects the given connection point from its network, called by remove_port_from_network
+	RemovePortFromNetwork(string) (*ConnectionPointRecord, error)
+
+	//CreateFloatingIP creates a floating IP, called by create_floating_ip
+	CreateFloatingIP() (*FloatingIPRecord, error)
+
+	//DeleteFloatingIP deletes the given floating IP, called by delete_floating_ip
+	DeleteFloatingIP(string) (*FloatingIPRecord, error)
+
+	//AssignFloatingIP assigns the given floating IP to the given connection point, called by assign_floating_ip
+	AssignFloatingIP(string, string) (*FloatingIPRecord, error)
+
+	//RemoveFloatingIP removes the given floating IP from the given connection point, called by remove_floating_ip
+	RemoveFloatingIP(string, string) (*FloatingIPRecord, error)
+
+	//AddRouterPort adds a port of the given type to the given router, with optional network and address, called by add_router_port
+	AddRouterPort(string, string, *string, *string) (*RouterRecord, error)
+
+	//RemoveRouterPort removes the port of the given network from the given router, called by remove_router_port
+	RemoveRouterPort(string, string) (*RouterRecord, error)
+
+	//CreateNodeNetwork creates the given virtual network in the node, called by create_node_network
+	CreateNodeNetwork(VirtualNetwork) (*VirtualNetwork, error)
+
+	//RemoveNodeNetwork removes the given virtual network from the node, called by remove_node_network
+	RemoveNodeNetwork(string) (*VirtualNetwork, error)
+}
+
+// FOSAgentLocalInterface is the interface to be implemented for the Agent evals in Local Actual, called by the Agent object of the plugins
+type FOSAgentLocalInterface interface {
+
+	//GetImageInfo returns the given image, called by get_image_info
+	GetImageInfo(string) (*FDUImage, error)
+
+	//GetNodeFDUInfo returns the descriptor of the given node, FDU and instance, called by get_node_fdu_info
+	GetNodeFDUInfo(string, string, string) (*FDU, error)
+
+	//GetFDUInfo returns the descriptor of the given FDU, called by get_fdu_info
+	GetFDUInfo(string) (*FDU, error)
+
+	//GetNetworkInfo returns the given virtual network, called by get_network_info
+	GetNetworkInfo(string) (*VirtualNetwork, error)
+
+	//GetPortInfo returns the given connection point, called by get_port_info
+	GetPortInfo(string) (*ConnectionPointDescriptor, error)
+
+	//GetNodeMGMTAddress returns the management address of the given node, called by get_node_mgmt_address
+	GetNodeMGMTAddress(string) (string, error)
+}
+
+// FOSAgentAbstract serves the Agent evals of a node, the ones in Global Actual calling FOSAgentInterface and the ones in Local Actual
+// calling FOSAgentLocalInterface, either interface can be nil to not serve its evals
+type FOSAgentAbstract struct {
+	Connector *YaksConnector
+	SysID     string
+	TenantID  string
+	Node      string
+	Logger    *log.Logger
+	FOSAgentInterface
+	FOSAgentLocalInterface
+
+	mu     sync.Mutex
+	global []*Path
+	local  []*Path
+}
+
+// NewFOSAgentAbstract returns a new FOSAgentAbstract object for the given system, tenant and node
+func NewFOSAgentAbstract(con *YaksConnector, sysid string, tenantid string, nodeid string) *FOSAgentAbstract {
+	return &FOSAgentAbstract{Connector: con, SysID: sysid, TenantID: tenantid, Node: nodeid, Logger: log.New()}
+}
+
+// Start registers the evals of the interfaces that are set, on failure the evals already registered are removed
+func (ag *FOSAgentAbstract) Start() error {
+	ag.mu.Lock()
+	defer ag.mu.Unlock()
+	gad := ag.Connector.Global.Actual
+	lad := ag.Connector.Local.Actual
+
+	var err error
+	if ag.FOSAgentInterface != nil {
+		for name, handler := range ag.globalEvals() {
+			err = gad.AddAgentEval(ag.SysID, ag.TenantID, ag.Node, name, evalHandler(handler))
+			if err != nil {
+				err = &FError{"Unable to register eval " + name, err}
+				break
+			}
+			p, _ := gad.GetAgentExecPath(ag.SysID, ag.TenantID, ag.Node, name)
+			ag.global = append(ag.global, p)
+		}
+	}
+	if err == nil && ag.FOSAgentLocalInterface != nil {
+		for name, handler := range ag.localEvals() {
+			err = lad.AddAgentEval(ag.Node, name, evalHandler(handler))
+			if err != nil {
+				err = &FError{"Unable to register eval " + name, err}
+				break
+			}
+			p, _ := lad.GetAgentExecPath(ag.Node, name)
+			ag.local = append(ag.local, p)
+		}
+	}
+	if err != nil {
+		if rerr := ag.removeEvals(); rerr != nil {
+			ag.Logger.Error(fmt.Sprintf("Unable to remove the evals: %s", rerr.Error()))
+		}
+		return err
+	}
+	return nil
+}
+
+// Stop removes the evals registered by Start
+func (ag *FOSAgentAbstract) Stop() error {
+	ag.mu.Lock()
+	defer ag.mu.Unlock()
+	return ag.removeEvals()
+}
+
+func (ag *FOSAgentAbstract) removeEvals() error {
+	var errs []error
+	for _, p := range ag.global {
+		errs = appendError(errs, ag.Connector.Global.Actual.RemoveEval(p))
+	}
+	for _, p := range ag.local {
+		errs = appendError(errs, ag.Connector.Local.Actual.RemoveEval(p))
+	}
+	ag.global = nil
+	ag.local = nil
+	return multiError(errs)
+}
+
+// globalEvals returns the handlers of the Agent evals in Global Actual
+func (ag *FOSAgentAbstract) globalEvals() map[string]func(*evalArgs) func() (interface{}, error) {
+	return map[string]func(*evalArgs) func() (interface{}, error){
+		"onboard_fdu": func(a *evalArgs) func() (interface{}, error) {
+			fdu := FDU{}
+			a.json("descriptor", &fdu)
+			if a.err == nil {
+				if err := fdu.Validate(); err != nil {
+					a.err = &EvalError{Code: EvalErrorInvalidParameters, Message: err.Error()}
+				}
+			}
+			return func() (interface{}, error) { return ag.OnboardFDU(fdu) }
+		},
+		"define_fdu": func(a *evalArgs) func() (interface{}, error) {
+			fduid := a.string("fdu_id")
+			return func() (interface{}, error) { return ag.DefineFDU(fduid) }
+		},
+		"add_port_to_network": func(a *evalArgs) func() (interface{}, error) {
+			cpid, netid := a.string("cp_uuid"), a.string("network_uuid")
+			return func() (interface{}, error) { return ag.AddPortToNetwork(cpid, netid) }
+		},
+		"remove_port_from_network": func(a *evalArgs) func() (interface{}, error) {
+			cpid := a.string("cp_uuid")
+			return func() (interface{}, error) { return ag.RemovePortFromNetwork(cpid) }
+		},
+		"create_floating_ip": func(a *evalArgs) func() (interface{}, error) {
+			return func() (interface{}, error) { return ag.CreateFloatingIP() }
+		},
+		"delete_floating_ip": func(a *evalArgs) func() (interface{}, error) {
+			ipid := a.string("floating_uuid")
+			return func() (interface{}, error) { return ag.DeleteFloatingIP(ipid) }
+		},
+		"assign_floating_ip": func(a *evalArgs) func() (interface{}, error) {
+			ipid, cpid := a.string("floating_uuid"), a.string("cp_uuid")
+			return func() (interface{}, error) { return ag.AssignFloatingIP(ipid, cpid) }
+		},
+		"remove_floating_ip": func(a *evalArgs) func() (interface{}, error) {
+			ipid, cpid := a.string("floating_uuid"), a.string("cp_uuid")
+			return func() (interface{}, error) { return ag.RemoveFloatingIP(ipid, cpid) }
+		},
+		"add_router_port": func(a *evalArgs) func() (interface{}, error) {
+			routerid, porttype := a.string("router_id"), a.string("port_type")
+			var vnetid, address *string
+			if v, found := a.props["vnet_id"]; found {
+				vnetid = &v
+			}
+			if v, found := a.props["ip_address"]; found {
+				address = &v
+			}
+			return func() (interface{}, error) { return ag.AddRouterPort(routerid, porttype, vnetid, address) }
+		},
+		"remove_router_port": func(a *evalArgs) func() (interface{}, error) {
+			routerid, vnetid := a.string("router_id"), a.string("vnet_id")
+			return func() (interface{}, error) { return ag.RemoveRouterPort(routerid, vnetid) }
+		},
+		"create_node_network": func(a *evalArgs) func() (interface{}, error) {
+			net := VirtualNetwork{}
+			a.json("descriptor", &net)
+			return func() (interface{}, error) { return ag.CreateNodeNetwork(net) }
+		},
+		"remove_node_network": func(a *evalArgs) func() (interface{}, error) {
+			netid := a.string("net_id")
+			return func() (interface{}, error) { return ag.RemoveNodeNetwork(netid) }
+		},
+	}
+}
+
+// localEvals returns the handlers of the Agent evals in Local Actual
+func (ag *FOSAgentAbstract) localEvals() map[string]func(*evalArgs) func() (interface{}, error) {
+	return map[string]func(*evalArgs) func() (interface{}, error){
+		"get_image_info": func(a *evalArgs) func() (interface{}, error) {
+			imgid := a.string("image_uuid")
+			return func() (interface{}, error) { return ag.GetImageInfo(imgid) }
+		},
+		"get_node_fdu_info": func(a *evalArgs) func() (interface{}, error) {
+			nodeid, fduid, instanceid := a.string("node_uuid"), a.string("fdu_uuid"), a.string("instance_uuid")
+			return func() (interface{}, error) { return ag.GetNodeFDUInfo(nodeid, fduid, instanceid) }
+		},
+		"get_fdu_info": func(a *evalArgs) func() (interface{}, error) {
+			fduid := a.string("fdu_uuid")
+			return func() (interface{}, error) { return ag.GetFDUInfo(fduid) }
+		},
+		"get_network_info": func(a *evalArgs) func() (interface{}, error) {
+			netid := a.string("uuid")
+			return func() (interface{}, error) { return ag.GetNetworkInfo(netid) }
+		},
+		"get_port_info": func(a *evalArgs) func() (interface{}, error) {
+			cpid := a.string("cp_uuid")
+			return func() (interface{}, error) { return ag.GetPortInfo(cpid) }
+		},
+		"get_node_mgmt_address": func(a *evalArgs) func() (interface{}, error) {
+			nodeid := a.string("node_uuid")
+			return func() (interface{}, error) { return ag.GetNodeMGMTAddress(nodeid) }
+		},
+	}
+}
@@ -0,0 +1,167 @@
+/*
+* Copyright (c) 2014,2019 Contributors to the Eclipse Foundation
+* See the NOTICE file(s) distributed with this work for additional
+* information regarding copyright ownership.
+* This program and the accompanying materials are made available under the
+* terms of the Eclipse Public License 2.0 which is available at
+* http://www.eclipse.org/legal/epl-2.0, or the Apache License, Version 2.0
+* which is available at https://www.apache.org/licenses/LICENSE-2.0.
+* SPDX-License-Identifier: EPL-2.0 OR Apache-2.0
+* Contributors: Gabriele Baldoni, ADLINK Technology Inc.
+* golang APIs
+ */
+
+package fog05sdk_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	fog05sdk "github.com/eclipse-fog05/sdk-go/fog05sdk"
+	"github.com/eclipse-fog05/sdk-go/fog05sdk/fostest"
+	"github.com/sirupsen/logrus/hooks/test"
+)
+
+// fakeAgent implements the Agent functions used by the tests, the others panic
+type fakeAgent struct {
+	fog05sdk.FOSAgentInterface
+	fog05sdk.FOSAgentLocalInterface
+}
+
+func (f *fakeAgent) OnboardFDU(fdu fog05sdk.FDU) (*fog05sdk.FDU, error) {
+	fdu.UUID = &fdu.ID
+	return &fdu, nil
+}
+
+func (f *fakeAgent) DefineFDU(fduid string) (*fog05sdk.FDURecord, error) {
+	return &fog05sdk.FDURecord{UUID: "i-" + fduid, FDUID: fduid, Status: fog05sdk.DEFINE}, nil
+}
+
+func (f *fakeAgent) AddRouterPort(routerid string, porttype string, vnetid *string, address *string) (*fog05sdk.RouterRecord, error) {
+	r := fog05sdk.RouterRecord{UUID: routerid, State: porttype}
+	if vnetid != nil {
+		r.RouterNS = *vnetid
+	}
+	return &r, nil
+}
+
+func (f *fakeAgent) RemoveNodeNetwork(netid string) (*fog05sdk.VirtualNetwork, error) {
+	return nil, &fog05sdk.EvalError{Code: 2, Message: "no network " + netid}
+}
+
+func (f *fakeAgent) GetFDUInfo(fduid string) (*fog05sdk.FDU, error) {
+	return &fog05sdk.FDU{ID: fduid}, nil
+}
+
+func (f *fakeAgent) GetNodeMGMTAddress(nodeid string) (string, error) {
+	return "10.0.0.1", nil
+}
+
+// startAgent starts the fake Agent of node n1 in system s and tenant t
+func startAgent(t *testing.T, yc *fog05sdk.YaksConnector) *fog05sdk.FOSAgentAbstract {
+	t.Helper()
+	ag := fog05sdk.NewFOSAgentAbstract(yc, "s", "t", "n1")
+	ag.Logger, _ = test.NewNullLogger()
+	f := &fakeAgent{}
+	ag.FOSAgentInterface = f
+	ag.FOSAgentLocalInterface = f
+	if err := ag.Start(); err != nil {
+		t.Fatal(err)
+	}
+	return ag
+}
+
+func TestAgentEvals(t *testing.T) {
+	yc, st := fostest.NewConnector()
+	ag := startAgent(t, yc)
+	if len(st.EvalPaths()) != 18 {
+		t.Errorf("Agent registered %d evals, want 18", len(st.EvalPaths()))
+	}
+	ga := yc.Global.Actual
+
+	fdu := fog05sdk.FDU{ID: "f1", Name: "f1", Hypervisor: "BARE", MigrationKind: "COLD"}
+	fdu.ComputationRequirements.CPUMinCount = 1
+	r, err := ga.OnboardFDUFromNode("s", "t", "n1", fdu)
+	if err != nil || !strings.Contains(*r.Result, `"uuid":"f1"`) {
+		t.Errorf("OnboardFDUFromNode() = %+v, %v", r, err)
+	}
+	bad := fdu
+	bad.Hypervisor = "x"
+	var eerr *fog05sdk.EvalError
+	d, _ := json.Marshal(bad)
+	// the client validates the descriptor too, the eval is called directly
+	_, err = ga.EvalAgent("s", "t", "n1", "onboard_fdu", map[string]interface{}{"descriptor": string(d)}, nil)
+	if !errors.As(err, &eerr) || eerr.Code != fog05sdk.EvalErrorInvalidParameters {
+		t.Errorf("onboard_fdu of an invalid descriptor = %v, want an invalid parameters error", err)
+	}
+
+	record := fog05sdk.FDURecord{}
+	if _, err := ga.EvalAgent("s", "t", "n1", "define_fdu", map[string]interface{}{"fdu_id": "f1"}, &record); err != nil || record.UUID != "i-f1" {
+		t.Errorf("define_fdu = %+v, %v", record, err)
+	}
+	vnet := "net1"
+	routers := []struct {
+		name  string
+		vnet  *string
+		state string
+		ns    string
+	}{
+		{"external", nil, "EXTERNAL", ""},
+		{"internal", &vnet, "INTERNAL", "net1"},
+	}
+	for _, tt := range routers {
+		props := map[string]interface{}{"router_id": "r1", "port_type": tt.state}
+		if tt.vnet != nil {
+			props["vnet_id"] = *tt.vnet
+		}
+		router := fog05sdk.RouterRecord{}
+		if _, err := ga.EvalAgent("s", "t", "n1", "add_router_port", props, &router); err != nil || router.State != tt.state || router.RouterNS != tt.ns {
+			t.Errorf("add_router_port %s = %+v, %v", tt.name, router, err)
+		}
+	}
+	if _, err := ga.RemoveNetworkFromNode("s", "t", "n1", "net1"); !errors.As(err, &eerr) || eerr.Code != 2 {
+		t.Errorf("RemoveNetworkFromNode() failing in the Agent = %v, want its EvalError", err)
+	}
+	if _, err := ga.EvalAgent("s", "t", "n1", "define_fdu", nil, nil); !errors.As(err, &eerr) || eerr.Code != fog05sdk.EvalErrorInvalidParameters {
+		t.Errorf("define_fdu without fdu_id = %v, want an invalid parameters error", err)
+	}
+
+	// the Agent is discovered through its evals
+	pl := fog05sdk.NewPluginWithConnector(1, "p1", yc, "n1")
+	if ok, err := pl.GetAgent(); !ok || err != nil {
+		t.Fatalf("GetAgent() = %v, %v", ok, err)
+	}
+	if d, err := pl.Agent.GetFDUDescriptor("f1"); err != nil || d.ID != "f1" {
+		t.Errorf("GetFDUDescriptor() = %+v, %v", d, err)
+	}
+	if a, err := pl.Agent.GetNodeMGMTAddress("n1"); err != nil || a != "10.0.0.1" {
+		t.Errorf("GetNodeMGMTAddress() = %q, %v", a, err)
+	}
+
+	if err := ag.Stop(); err != nil || len(st.EvalPaths()) != 0 {
+		t.Errorf("Stop() = %v, left evals %v", err, st.EvalPaths())
+	}
+	if ok, err := fog05sdk.NewPluginWithConnector(1, "p2", yc, "n1").GetAgent(); ok || err != nil {
+		t.Errorf("GetAgent() after Stop = %v, %v", ok, err)
+	}
+}
+
+func TestAgentLocalInterfaceOnly(t *testing.T) {
+	yc, st := fostest.NewConnector()
+	ag := fog05sdk.NewFOSAgentAbstract(yc, "s", "t", "n1")
+	ag.FOSAgentLocalInterface = &fakeAgent{}
+	if err := ag.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer ag.Stop()
+	for _, p := range st.EvalPaths() {
+		if !strings.HasPrefix(p, fog05sdk.LocalActualPrefix+"/") {
+			t.Errorf("Agent without FOSAgentInterface registered %s", p)
+		}
+	}
+	if len(st.EvalPaths()) != 6 {
+		t.Errorf("Agent registered %d local evals, want 6", len(st.EvalPaths()))
+	}
+}
@@ -0,0 +1,302 @@
+/*
+* Copyright (c) 2014,2019 Contributors to the Eclipse Foundation
+* See the NOTICE file(s) distributed with this work for additional
+* information regarding copyright ownership.
+* This program and the accompanying materials are made available under the
+* terms of the Eclipse Public License 2.0 which is available at
+* http://www.eclipse.org/legal/epl-2.0, or the Apache License, Version 2.0
+* which is available at https://www.apache.org/licenses/LICENSE-2.0.
+* SPDX-License-Identifier: EPL-2.0 OR Apache-2.0
+* Contributors: Gabriele Baldoni, ADLINK Technology Inc.
+* golang APIs
+ */
+
+package fog05sdk
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// CycleError is returned when the DependsOn of a set of FDUs contain a cycle, Cycle lists the FDUs in the cycle, the first one repeated at the end
+type CycleError struct {
+	Cycle []string
+}
+
+func (e *CycleError) Error() string {
+	return "Dependency cycle: " + strings.Join(e.Cycle, " -> ")
+}
+
+// DependencyGraph is the DAG of a set of FDUs, built from their DependsOn
+type DependencyGraph struct {
+	fdus       map[string]*FDU
+	deps       map[string][]string
+	dependents map[string][]string
+	order      []string
+}
+
+// NewDependencyGraph builds the graph of the FDUs, it returns a ValidationError if an FDU id is duplicated or a DependsOn references an FDU
+// not in the set, and a CycleError if the dependencies contain a cycle
+func NewDependencyGraph(fdus []FDU) (*DependencyGraph, error) {
+	g := DependencyGraph{fdus: map[string]*FDU{}, deps: map[string][]string{}, dependents: map[string][]string{}}
+	v := validator{}
+	for i := range fdus {
+		field := fmt.Sprintf("fdus[%d].id", i)
+		v.required(field, fdus[i].ID)
+		if _, found := g.fdus[fdus[i].ID]; found {
+			v.add(field, "%q is duplicated", fdus[i].ID)
+		}
+		g.fdus[fdus[i].ID] = &fdus[i]
+	}
+	for i, f := range fdus {
+		seen := map[string]bool{}
+		for j, d := range f.DependsOn {
+			if _, found := g.fdus[d]; !found {
+				v.add(fmt.Sprintf("fdus[%d].depends_on[%d]", i, j), "unknown FDU %q", d)
+				continue
+			}
+			if seen[d] {
+				continue
+			}
+			seen[d] = true
+			g.deps[f.ID] = append(g.deps[f.ID], d)
+			g.dependents[d] = append(g.dependents[d], f.ID)
+		}
+	}
+	err := v.err()
+	if err != nil {
+		return nil, err
+	}
+	for _, ids := range g.dependents {
+		sort.Strings(ids)
+	}
+
+	g.order, err = g.sort()
+	if err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
+
+// sort returns the FDUs in topological order, ties are sorted by id, or a CycleError
+func (g *DependencyGraph) sort() ([]string, error) {
+	pending := map[string]int{}
+	ready := []string{}
+	for id := range g.fdus {
+		pending[id] = len(g.deps[id])
+		if pending[id] == 0 {
+			ready = append(ready, id)
+		}
+	}
+	order := []string{}
+	for len(ready) > 0 {
+		sort.Strings(ready)
+		id := ready[0]
+		ready = ready[1:]
+		order = append(order, id)
+		for _, d := range g.dependents[id] {
+			pending[d]--
+			if pending[d] == 0 {
+				ready = append(ready, d)
+			}
+		}
+	}
+	if len(order) < len(g.fdus) {
+		return nil, &CycleError{g.findCycle(pending)}
+	}
+	return order, nil
+}
+
+// findCycle follows the unresolved dependencies from the first unresolved FDU until an FDU is visited twice
+func (g *DependencyGraph) findCycle(pending map[string]int) []string {
+	ids := []string{}
+	for id, n := range pending {
+		if n > 0 {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	path := []string{}
+	visited := map[string]int{}
+	id := ids[0]
+	for {
+		if i, found := visited[id]; found {
+			return append(path[i:], id)
+		}
+		visited[id] = len(path)
+		path = append(path, id)
+		for _, d := range g.deps[id] {
+			if pending[d] > 0 {
+				id = d
+				break
+			}
+		}
+	}
+}
+
+// Order returns the FDU ids in topological order, each FDU after its dependencies
+func (g *DependencyGraph) Order() []string {
+	return append([]string{}, g.order...)
+}
+
+// Levels returns the FDU ids grouped in levels, the FDUs of a level depend only on the ones in the previous levels and can be deployed in parallel
+func (g *DependencyGraph) Levels() [][]string {
+	level := map[string]int{}
+	levels := [][]string{}
+	for _, id := range g.order {
+		l := 0
+		for _, d := range g.deps[id] {
+			if level[d]+1 > l {
+				l = level[d] + 1
+			}
+		}
+		level[id] = l
+		if l == len(levels) {
+			levels = append(levels, []string{})
+		}
+		levels[l] = append(levels[l], id)
+	}
+	return levels
+}
+
+// FDU returns the FDU with the given id, nil if it is not in the graph
+func (g *DependencyGraph) FDU(id string) *FDU {
+	return g.fdus[id]
+}
+
+// Dependencies returns the FDUs the given FDU depends on
+func (g *DependencyGraph) Dependencies(id string) []string {
+	return append([]string{}, g.deps[id]...)
+}
+
+// Dependents returns the FDUs that depend on the given FDU
+func (g *DependencyGraph) Dependents(id string) []string {
+	return append([]string{}, g.dependents[id]...)
+}
+
+// Deployment is the result of Deployer.Deploy, Instances has the record of the running instance of each deployed FDU
+// and FDUs the catalog id assigned at onboard to each descriptor id
+type Deployment struct {
+	Instances map[string]*FDURecord
+	FDUs      map[string]string
+}
+
+// Deployer deploys and terminates a set of FDUs in dependency order through a FIMClient, independent FDUs are deployed in parallel
+type Deployer struct {
+	fim   *FIMClient
+	place func(ctx context.Context, fdu *FDU) (string, error)
+}
+
+// NewDeployer returns a Deployer using place to choose the node of each FDU instance, eg. the Schedule method of a Scheduler
+func NewDeployer(fim *FIMClient, place func(ctx context.Context, fdu *FDU) (string, error)) *Deployer {
+	return &Deployer{fim: fim, place: place}
+}
+
+// Deploy onboards the FDUs in topological order, then instantiates each FDU once all its dependencies are running.
+// When an FDU fails its dependents are not deployed, the others are, the returned Deployment has the instances deployed also on failure,
+// so that they can be terminated
+func (d *Deployer) Deploy(ctx context.Context, g *DependencyGraph) (*Deployment, error) {
+	dep := Deployment{Instances: map[string]*FDURecord{}, FDUs: map[string]string{}}
+	for _, id := range g.order {
+		onboarded, err := d.fim.Onboard(ctx, *g.fdus[id])
+		if err != nil {
+			return &dep, &FError{"Unable to onboard FDU " + id, err}
+		}
+		if onboarded.UUID == nil {
+			return &dep, &FError{"Catalog returned no UUID for FDU " + id, nil}
+		}
+		dep.FDUs[id] = *onboarded.UUID
+	}
+
+	var mu sync.Mutex
+	err := runOrdered(ctx, g.order, g.deps, func(ctx context.Context, id string) error {
+		nodeid, err := d.place(ctx, g.fdus[id])
+		if err != nil {
+			return err
+		}
+		record, err := d.fim.Instantiate(ctx, dep.FDUs[id], nodeid)
+		if record != nil {
+			mu.Lock()
+			dep.Instances[id] = record
+			mu.Unlock()
+		}
+		return err
+	})
+	return &dep, err
+}
+
+// Terminate terminates the instances of the Deployment in reverse topological order, each FDU after all its dependents.
+// Terminated instances are removed from the Deployment
+func (d *Deployer) Terminate(ctx context.Context, g *DependencyGraph, dep *Deployment) error {
+	order := make([]string, len(g.order))
+	for i, id := range g.order {
+		order[len(order)-1-i] = id
+	}
+	var mu sync.Mutex
+	return runOrdered(ctx, order, g.dependents, func(ctx context.Context, id string) error {
+		mu.Lock()
+		record, found := dep.Instances[id]
+		mu.Unlock()
+		if !found {
+			return nil
+		}
+		err := d.fim.Terminate(ctx, record.UUID)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		delete(dep.Instances, id)
+		mu.Unlock()
+		return nil
+	})
+}
+
+// runOrdered runs fn for each id in its own goroutine, after fn returned successfully for all the ids in after[id].
+// When fn fails for an id, it is not run for the ids waiting for it, all the errors are returned in a MultiError
+func runOrdered(ctx context.Context, ids []string, after map[string][]string, fn func(context.Context, string) error) error {
+	done := map[string]chan struct{}{}
+	errs := map[string]error{}
+	for _, id := range ids {
+		done[id] = make(chan struct{})
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, id := range ids {
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			defer close(done[id])
+			var err error
+			for _, a := range after[id] {
+				<-done[a]
+				mu.Lock()
+				failed := errs[a] != nil
+				mu.Unlock()
+				if failed {
+					err = &FError{"Skipped " + id + ", " + a + " failed", nil}
+					break
+				}
+			}
+			if err == nil {
+				err = fn(ctx, id)
+				if err != nil {
+					err = &FError{"FDU " + id + " failed", err}
+				}
+			}
+			mu.Lock()
+			errs[id] = err
+			mu.Unlock()
+		}(id)
+	}
+	wg.Wait()
+
+	var all []error
+	for _, id := range ids {
+		all = appendError(all, errs[id])
+	}
+	return multiError(all)
+}
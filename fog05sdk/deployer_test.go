@@ -0,0 +1,72 @@
+/*
+* Copyright (c) 2014,2019 Contributors to the Eclipse Foundation
+* See the NOTICE file(s) distributed with this work for additional
+* information regarding copyright ownership.
+* This program and the accompanying materials are made available under the
+* terms of the Eclipse Public License 2.0 which is available at
+* http://www.eclipse.org/legal/epl-2.0, or the Apache License, Version 2.0
+* which is available at https://www.apache.org/licenses/LICENSE-2.0.
+* SPDX-License-Identifier: EPL-2.0 OR Apache-2.0
+* Contributors: Gabriele Baldoni, ADLINK Technology Inc.
+* golang APIs
+ */
+
+package fog05sdk
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestDependencyGraph(t *testing.T) {
+	fdu := func(id string, deps ...string) FDU {
+		return FDU{ID: id, DependsOn: deps}
+	}
+	tests := []struct {
+		name    string
+		fdus    []FDU
+		order   []string
+		levels  [][]string
+		cycle   []string
+		invalid []string
+	}{
+		{"empty", nil, []string{}, [][]string{}, nil, nil},
+		{"independent", []FDU{fdu("b"), fdu("a")}, []string{"a", "b"}, [][]string{{"a", "b"}}, nil, nil},
+		{"chain", []FDU{fdu("c", "b"), fdu("b", "a"), fdu("a")}, []string{"a", "b", "c"}, [][]string{{"a"}, {"b"}, {"c"}}, nil, nil},
+		{"diamond", []FDU{fdu("d", "b", "c"), fdu("c", "a"), fdu("b", "a"), fdu("a")},
+			[]string{"a", "b", "c", "d"}, [][]string{{"a"}, {"b", "c"}, {"d"}}, nil, nil},
+		{"duplicated dependency", []FDU{fdu("b", "a", "a"), fdu("a")}, []string{"a", "b"}, [][]string{{"a"}, {"b"}}, nil, nil},
+		{"self", []FDU{fdu("a", "a")}, nil, nil, []string{"a", "a"}, nil},
+		{"two", []FDU{fdu("a", "b"), fdu("b", "a")}, nil, nil, []string{"a", "b", "a"}, nil},
+		{"behind a dependency", []FDU{fdu("a", "b"), fdu("b", "c"), fdu("c", "b"), fdu("d")}, nil, nil, []string{"b", "c", "b"}, nil},
+		{"unknown dependency", []FDU{fdu("a", "x")}, nil, nil, nil, []string{"fdus[0].depends_on[0]"}},
+		{"duplicated id", []FDU{fdu("a"), fdu("a")}, nil, nil, nil, []string{"fdus[1].id"}},
+		{"missing id", []FDU{fdu("")}, nil, nil, nil, []string{"fdus[0].id"}},
+	}
+	for _, tt := range tests {
+		g, err := NewDependencyGraph(tt.fdus)
+		switch {
+		case tt.cycle != nil:
+			var cerr *CycleError
+			if !errors.As(err, &cerr) {
+				t.Errorf("%s: NewDependencyGraph() = %v, want CycleError", tt.name, err)
+			} else if !reflect.DeepEqual(cerr.Cycle, tt.cycle) {
+				t.Errorf("%s: cycle = %v, want %v", tt.name, cerr.Cycle, tt.cycle)
+			}
+		case tt.invalid != nil:
+			if got := fields(t, err); !reflect.DeepEqual(got, tt.invalid) {
+				t.Errorf("%s: NewDependencyGraph() fields = %v, want %v", tt.name, got, tt.invalid)
+			}
+		case err != nil:
+			t.Errorf("%s: NewDependencyGraph() = %v", tt.name, err)
+		default:
+			if got := g.Order(); !reflect.DeepEqual(got, tt.order) {
+				t.Errorf("%s: Order() = %v, want %v", tt.name, got, tt.order)
+			}
+			if got := g.Levels(); !reflect.DeepEqual(got, tt.levels) {
+				t.Errorf("%s: Levels() = %v, want %v", tt.name, got, tt.levels)
+			}
+		}
+	}
+}
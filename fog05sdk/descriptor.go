@@ -0,0 +1,419 @@
+/*
+* Copyright (c) 2014,2019 Contributors to the Eclipse Foundation
+* See the NOTICE file(s) distributed with this work for additional
+* information regarding copyright ownership.
+* This program and the accompanying materials are made available under the
+* terms of the Eclipse Public License 2.0 which is available at
+* http://www.eclipse.org/legal/epl-2.0, or the Apache License, Version 2.0
+* which is available at https://www.apache.org/licenses/LICENSE-2.0.
+* SPDX-License-Identifier: EPL-2.0 OR Apache-2.0
+* Contributors: Gabriele Baldoni, ADLINK Technology Inc.
+* golang APIs
+ */
+
+package fog05sdk
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// DescriptorKind is the kind of a descriptor in a descriptor file
+type DescriptorKind string
+
+const (
+	// FDUKind is the kind of FDU descriptors
+	FDUKind DescriptorKind = "fdu"
+
+	// NetworkKind is the kind of VirtualNetwork descriptors
+	NetworkKind DescriptorKind = "network"
+
+	// RouterKind is the kind of RouterDescriptor descriptors
+	RouterKind DescriptorKind = "router"
+
+	// FlavorKind is the kind of FDUComputationalRequirements descriptors
+	FlavorKind DescriptorKind = "flavor"
+)
+
+// DescriptorFormat is the format of a descriptor file
+type DescriptorFormat int
+
+const (
+	// JSONFormat descriptors are JSON values, one after the other or in a JSON array
+	JSONFormat DescriptorFormat = iota
+
+	// YAMLFormat descriptors are YAML documents separated by ---
+	YAMLFormat
+)
+
+// descriptorKindKey is the key of the optional kind field of the documents in a descriptor file
+const descriptorKindKey = "kind"
+
+// Descriptor is a descriptor read from a descriptor file, only the field of its Kind is set
+type Descriptor struct {
+	Kind    DescriptorKind
+	FDU     *FDU
+	Network *VirtualNetwork
+	Router  *RouterDescriptor
+	Flavor  *FDUComputationalRequirements
+}
+
+// value returns the descriptor of the Descriptor Kind
+func (d *Descriptor) value() (interface{}, error) {
+	var v interface{}
+	switch d.Kind {
+	case FDUKind:
+		if d.FDU != nil {
+			v = d.FDU
+		}
+	case NetworkKind:
+		if d.Network != nil {
+			v = d.Network
+		}
+	case RouterKind:
+		if d.Router != nil {
+			v = d.Router
+		}
+	case FlavorKind:
+		if d.Flavor != nil {
+			v = d.Flavor
+		}
+	default:
+		return nil, &FError{"Unknown descriptor kind " + string(d.Kind), nil}
+	}
+	if v == nil {
+		return nil, &FError{"Missing " + string(d.Kind) + " descriptor", nil}
+	}
+	return v, nil
+}
+
+// JSON returns the descriptor encoded as it is stored in YAKS
+func (d *Descriptor) JSON() ([]byte, error) {
+	v, err := d.value()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+// LoadDescriptors reads all the descriptors in r, that can be JSON or YAML, the format is detected from the first character.
+// Each document can have a kind field set to fdu, network, router or flavor, when missing the kind is detected from the document fields.
+// When strict is true documents with unknown fields, or YAML documents with duplicated keys, are rejected
+func LoadDescriptors(r io.Reader, strict bool) ([]Descriptor, error) {
+	docs, err := readDocuments(r, strict)
+	if err != nil {
+		return nil, err
+	}
+	ds := []Descriptor{}
+	for i, doc := range docs {
+		d, err := decodeDescriptor(doc, strict)
+		if err != nil {
+			return nil, &FError{"Invalid descriptor in document " + strconv.Itoa(i), err}
+		}
+		ds = append(ds, d)
+	}
+	return ds, nil
+}
+
+// LoadDescriptorFile reads all the descriptors in the file, as LoadDescriptors
+func LoadDescriptorFile(path string, strict bool) ([]Descriptor, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return LoadDescriptors(f, strict)
+}
+
+// DecodeDescriptor decodes a single JSON or YAML descriptor in v, that is a pointer to the descriptor type, eg. *FDU.
+// When strict is true documents with unknown fields, or YAML documents with duplicated keys, are rejected
+func DecodeDescriptor(data []byte, v interface{}, strict bool) error {
+	docs, err := readDocuments(bytes.NewReader(data), strict)
+	if err != nil {
+		return err
+	}
+	if len(docs) != 1 {
+		return &FError{"Expected one descriptor, found " + strconv.Itoa(len(docs)), nil}
+	}
+	fields := map[string]json.RawMessage{}
+	err = decode(string(docs[0]), &fields)
+	if err != nil {
+		return err
+	}
+	delete(fields, descriptorKindKey)
+	return decodeFields(fields, v, strict)
+}
+
+// WriteDescriptors writes the descriptors in w in the given format, each with its kind field, so that they can be read back by LoadDescriptors
+func WriteDescriptors(w io.Writer, ds []Descriptor, format DescriptorFormat) error {
+	for i, d := range ds {
+		js, err := d.JSON()
+		if err != nil {
+			return err
+		}
+		var out []byte
+		switch format {
+		case JSONFormat:
+			var b bytes.Buffer
+			b.WriteString(fmt.Sprintf("{\"%s\":\"%s\"", descriptorKindKey, d.Kind))
+			if len(js) > 2 {
+				b.WriteString(",")
+			}
+			b.Write(js[1:])
+			var ib bytes.Buffer
+			err = json.Indent(&ib, b.Bytes(), "", "  ")
+			if err != nil {
+				return err
+			}
+			ib.WriteString("\n")
+			out = ib.Bytes()
+		case YAMLFormat:
+			// JSON is valid YAML, reading it as MapSlice keeps the fields order
+			ms := yaml.MapSlice{}
+			err = yaml.Unmarshal(js, &ms)
+			if err != nil {
+				return err
+			}
+			ms = append(yaml.MapSlice{{Key: descriptorKindKey, Value: string(d.Kind)}}, ms...)
+			out, err = yaml.Marshal(ms)
+			if err != nil {
+				return err
+			}
+			if i > 0 {
+				out = append([]byte("---\n"), out...)
+			}
+		default:
+			return &FError{"Unknown descriptor format " + strconv.Itoa(int(format)), nil}
+		}
+		_, err = w.Write(out)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SaveDescriptorFile writes the descriptors in the file, in YAML if its extension is .yaml or .yml, in JSON otherwise
+func SaveDescriptorFile(path string, ds []Descriptor) error {
+	format := JSONFormat
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		format = YAMLFormat
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	err = WriteDescriptors(f, ds, format)
+	cerr := f.Close()
+	if err != nil {
+		return err
+	}
+	return cerr
+}
+
+// readDocuments splits r in documents, converting YAML documents to JSON
+func readDocuments(r io.Reader, strict bool) ([]json.RawMessage, error) {
+	br := bufio.NewReader(r)
+	first, err := firstRune(br)
+	if err == io.EOF {
+		return []json.RawMessage{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if first == '{' || first == '[' {
+		return readJSONDocuments(br)
+	}
+	return readYAMLDocuments(br, strict)
+}
+
+// firstRune returns the first non space rune in br, without consuming it
+func firstRune(br *bufio.Reader) (rune, error) {
+	for {
+		c, _, err := br.ReadRune()
+		if err != nil {
+			return 0, err
+		}
+		if !strings.ContainsRune(" \t\r\n\ufeff", c) {
+			return c, br.UnreadRune()
+		}
+	}
+}
+
+func readJSONDocuments(r io.Reader) ([]json.RawMessage, error) {
+	docs := []json.RawMessage{}
+	dec := json.NewDecoder(r)
+	for {
+		var doc json.RawMessage
+		err := dec.Decode(&doc)
+		if err == io.EOF {
+			return docs, nil
+		}
+		if err != nil {
+			return nil, &DecodeError{err}
+		}
+		if bytes.HasPrefix(doc, []byte("[")) {
+			var array []json.RawMessage
+			err = decode(string(doc), &array)
+			if err != nil {
+				return nil, err
+			}
+			docs = append(docs, array...)
+			continue
+		}
+		docs = append(docs, doc)
+	}
+}
+
+func readYAMLDocuments(r io.Reader, strict bool) ([]json.RawMessage, error) {
+	docs := []json.RawMessage{}
+	dec := yaml.NewDecoder(r)
+	dec.SetStrict(strict)
+	for {
+		var doc interface{}
+		err := dec.Decode(&doc)
+		if err == io.EOF {
+			return docs, nil
+		}
+		if err != nil {
+			return nil, &DecodeError{err}
+		}
+		if doc == nil {
+			continue
+		}
+		v, err := yamlToJSON(doc)
+		if err != nil {
+			return nil, &DecodeError{err}
+		}
+		js, err := json.Marshal(v)
+		if err != nil {
+			return nil, &DecodeError{err}
+		}
+		docs = append(docs, js)
+	}
+}
+
+// yamlToJSON converts the maps decoded by yaml, that have interface{} keys, to maps with string keys
+func yamlToJSON(v interface{}) (interface{}, error) {
+	switch x := v.(type) {
+	case map[interface{}]interface{}:
+		m := map[string]interface{}{}
+		for k, e := range x {
+			ks, ok := k.(string)
+			if !ok {
+				ks = fmt.Sprint(k)
+			}
+			c, err := yamlToJSON(e)
+			if err != nil {
+				return nil, err
+			}
+			m[ks] = c
+		}
+		return m, nil
+	case []interface{}:
+		a := make([]interface{}, len(x))
+		for i, e := range x {
+			c, err := yamlToJSON(e)
+			if err != nil {
+				return nil, err
+			}
+			a[i] = c
+		}
+		return a, nil
+	default:
+		return v, nil
+	}
+}
+
+// decodeDescriptor decodes a JSON document, using its kind field or detecting its kind from its fields
+func decodeDescriptor(doc json.RawMessage, strict bool) (Descriptor, error) {
+	fields := map[string]json.RawMessage{}
+	err := decode(string(doc), &fields)
+	if err != nil {
+		return Descriptor{}, err
+	}
+
+	d := Descriptor{}
+	if k, found := fields[descriptorKindKey]; found {
+		var kind string
+		err = decode(string(k), &kind)
+		if err != nil {
+			return Descriptor{}, err
+		}
+		d.Kind = DescriptorKind(kind)
+		delete(fields, descriptorKindKey)
+	} else {
+		d.Kind, err = detectKind(fields)
+		if err != nil {
+			return Descriptor{}, err
+		}
+	}
+
+	switch d.Kind {
+	case FDUKind:
+		d.FDU = &FDU{}
+		err = decodeFields(fields, d.FDU, strict)
+	case NetworkKind:
+		d.Network = &VirtualNetwork{}
+		err = decodeFields(fields, d.Network, strict)
+	case RouterKind:
+		d.Router = &RouterDescriptor{}
+		err = decodeFields(fields, d.Router, strict)
+	case FlavorKind:
+		d.Flavor = &FDUComputationalRequirements{}
+		err = decodeFields(fields, d.Flavor, strict)
+	default:
+		err = &FError{"Unknown descriptor kind " + string(d.Kind), nil}
+	}
+	if err != nil {
+		return Descriptor{}, err
+	}
+	return d, nil
+}
+
+// detectKind detects the kind of a document without kind field from the fields that are only in one descriptor type
+func detectKind(fields map[string]json.RawMessage) (DescriptorKind, error) {
+	kinds := []DescriptorKind{}
+	if _, found := fields["hypervisor"]; found {
+		kinds = append(kinds, FDUKind)
+	}
+	if _, found := fields["net_type"]; found {
+		kinds = append(kinds, NetworkKind)
+	}
+	if _, found := fields["ports"]; found {
+		kinds = append(kinds, RouterKind)
+	}
+	if _, found := fields["cpu_arch"]; found {
+		kinds = append(kinds, FlavorKind)
+	}
+	if len(kinds) != 1 {
+		return "", &FError{"Unable to detect the descriptor kind, set the kind field", nil}
+	}
+	return kinds[0], nil
+}
+
+func decodeFields(fields map[string]json.RawMessage, v interface{}, strict bool) error {
+	js, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(js))
+	if strict {
+		dec.DisallowUnknownFields()
+	}
+	err = dec.Decode(v)
+	if err != nil {
+		return &DecodeError{err}
+	}
+	return nil
+}
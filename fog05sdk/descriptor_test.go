@@ -0,0 +1,130 @@
+/*
+* Copyright (c) 2014,2019 Contributors to the Eclipse Foundation
+* See the NOTICE file(s) distributed with this work for additional
+* information regarding copyright ownership.
+* This program and the accompanying materials are made available under the
+* terms of the Eclipse Public License 2.0 which is available at
+* http://www.eclipse.org/legal/epl-2.0, or the Apache License, Version 2.0
+* which is available at https://www.apache.org/licenses/LICENSE-2.0.
+* SPDX-License-Identifier: EPL-2.0 OR Apache-2.0
+* Contributors: Gabriele Baldoni, ADLINK Technology Inc.
+* golang APIs
+ */
+
+package fog05sdk
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func testDescriptors() []Descriptor {
+	fdu := validFDU()
+	fdu.DependsOn = []string{"fdu0"}
+	duty := 0.5
+	fdu.ComputationRequirements.DutyCycle = &duty
+	fdu.GeographicalRequirements = &FDUGeographicalRequirements{Position: &FDUPosition{Latitude: "45.07", Longitude: "7.68", Radius: 10}}
+	vlan := 100
+	arch := "x86_64"
+	vnet := "net1"
+	return []Descriptor{
+		{Kind: FDUKind, FDU: &fdu},
+		{Kind: NetworkKind, Network: &VirtualNetwork{UUID: "net1", Name: "net1", NetworkType: "ELAN", VLANID: &vlan}},
+		{Kind: RouterKind, Router: &RouterDescriptor{Ports: []RouterPort{{PortType: "EXTERNAL"}, {PortType: "INTERNAL", VirtualNetID: &vnet}}}},
+		{Kind: FlavorKind, Flavor: &FDUComputationalRequirements{Name: &arch, CPUArch: arch, CPUMinCount: 2, RAMSizeMB: 512.5}},
+	}
+}
+
+func TestDescriptorRoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		format DescriptorFormat
+	}{
+		{"json", JSONFormat},
+		{"yaml", YAMLFormat},
+	}
+	for _, tt := range tests {
+		ds := testDescriptors()
+		var b bytes.Buffer
+		if err := WriteDescriptors(&b, ds, tt.format); err != nil {
+			t.Fatalf("%s: WriteDescriptors() = %v", tt.name, err)
+		}
+		got, err := LoadDescriptors(&b, true)
+		if err != nil {
+			t.Fatalf("%s: LoadDescriptors() = %v", tt.name, err)
+		}
+		if !reflect.DeepEqual(got, ds) {
+			t.Errorf("%s: round trip = %+v, want %+v", tt.name, got, ds)
+		}
+	}
+}
+
+func TestDescriptorFileRoundTrip(t *testing.T) {
+	dir, err := ioutil.TempDir("", "descriptors")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	for _, name := range []string{"fdus.json", "fdus.yaml", "fdus.yml"} {
+		ds := testDescriptors()
+		path := filepath.Join(dir, name)
+		if err := SaveDescriptorFile(path, ds); err != nil {
+			t.Fatalf("SaveDescriptorFile(%s) = %v", name, err)
+		}
+		got, err := LoadDescriptorFile(path, true)
+		if err != nil {
+			t.Fatalf("LoadDescriptorFile(%s) = %v", name, err)
+		}
+		if !reflect.DeepEqual(got, ds) {
+			t.Errorf("%s: round trip = %+v, want %+v", name, got, ds)
+		}
+	}
+}
+
+func TestLoadDescriptors(t *testing.T) {
+	tests := []struct {
+		name   string
+		in     string
+		strict bool
+		kinds  []DescriptorKind
+		fail   bool
+	}{
+		{"empty", "  \n", true, []DescriptorKind{}, false},
+		{"json values", `{"id":"a","hypervisor":"LXD"} {"net_type":"ELAN"}`, true, []DescriptorKind{FDUKind, NetworkKind}, false},
+		{"json array", `[{"ports":[]},{"cpu_arch":"x86_64"}]`, true, []DescriptorKind{RouterKind, FlavorKind}, false},
+		{"yaml documents", "id: a\nhypervisor: LXD\n---\n---\nkind: flavor\ncpu_min_count: 1\n", true, []DescriptorKind{FDUKind, FlavorKind}, false},
+		{"kind overrides detection", `{"kind":"network","name":"n","net_type":"ELAN","hypervisor":"LXD"}`, false, []DescriptorKind{NetworkKind}, false},
+		{"ambiguous kind", `{"hypervisor":"LXD","net_type":"ELAN"}`, false, nil, true},
+		{"undetectable kind", `{"name":"x"}`, false, nil, true},
+		{"unknown kind", `{"kind":"volume"}`, false, nil, true},
+		{"unknown field", `{"kind":"fdu","id":"a","extra":1}`, false, []DescriptorKind{FDUKind}, false},
+		{"unknown field strict", `{"kind":"fdu","id":"a","extra":1}`, true, nil, true},
+		{"duplicated key strict", "kind: fdu\nid: a\nid: b\n", true, nil, true},
+		{"invalid json", `{"kind":"fdu",`, false, nil, true},
+	}
+	for _, tt := range tests {
+		ds, err := LoadDescriptors(strings.NewReader(tt.in), tt.strict)
+		if tt.fail {
+			if err == nil {
+				t.Errorf("%s: LoadDescriptors() = %+v, want an error", tt.name, ds)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: LoadDescriptors() = %v", tt.name, err)
+			continue
+		}
+		kinds := []DescriptorKind{}
+		for _, d := range ds {
+			kinds = append(kinds, d.Kind)
+		}
+		if !reflect.DeepEqual(kinds, tt.kinds) {
+			t.Errorf("%s: kinds = %v, want %v", tt.name, kinds, tt.kinds)
+		}
+	}
+}
@@ -0,0 +1,237 @@
+/*
+* Copyright (c) 2014,2019 Contributors to the Eclipse Foundation
+* See the NOTICE file(s) distributed with this work for additional
+* information regarding copyright ownership.
+* This program and the accompanying materials are made available under the
+* terms of the Eclipse Public License 2.0 which is available at
+* http://www.eclipse.org/legal/epl-2.0, or the Apache License, Version 2.0
+* which is available at https://www.apache.org/licenses/LICENSE-2.0.
+* SPDX-License-Identifier: EPL-2.0 OR Apache-2.0
+* Contributors: Gabriele Baldoni, ADLINK Technology Inc.
+* golang APIs
+ */
+
+package fog05sdk
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// requirementsPollInterval is how often WaitRequirements looks for the required plugins
+const requirementsPollInterval = 1 * time.Second
+
+// PluginFilter selects the plugins of a node, the empty fields match any plugin
+type PluginFilter struct {
+	Type string
+	Name string
+
+	// MinVersion and MaxVersion are the bounds of the version, included, 0 for no bound
+	MinVersion int
+	MaxVersion int
+}
+
+// Match returns true if the plugin matches the filter
+func (f PluginFilter) Match(p Plugin) bool {
+	return (f.Type == "" || p.Type == f.Type) &&
+		(f.Name == "" || p.Name == f.Name) &&
+		(f.MinVersion == 0 || p.Version >= f.MinVersion) &&
+		(f.MaxVersion == 0 || p.Version <= f.MaxVersion)
+}
+
+// FindPlugins returns the plugins of the node matching the filter, sorted by UUID, skipping the ones whose heartbeat expired
+func (pl *FOSPlugin) FindPlugins(filter PluginFilter) ([]Plugin, error) {
+	pids, err := pl.connector.Local.Actual.GetAllPlugins(pl.node)
+	if err != nil {
+		return nil, err
+	}
+	plugins := []Plugin{}
+	for _, pid := range pids {
+		p, err := pl.connector.Local.Actual.GetNodePlugin(pl.node, pid)
+		if errors.Is(err, ErrNotFound) {
+			// removed meanwhile
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !filter.Match(*p) {
+			continue
+		}
+		alive, err := pl.isAlive(pid)
+		if err != nil {
+			return nil, err
+		}
+		if alive {
+			plugins = append(plugins, *p)
+		}
+	}
+	sort.Slice(plugins, func(i, j int) bool { return plugins[i].UUID < plugins[j].UUID })
+	return plugins, nil
+}
+
+// findPlugin returns the first plugin of the given type, nil if there is none
+func (pl *FOSPlugin) findPlugin(plugintype string) (*Plugin, error) {
+	plugins, err := pl.FindPlugins(PluginFilter{Type: plugintype})
+	if err != nil || len(plugins) == 0 {
+		return nil, err
+	}
+	return &plugins[0], nil
+}
+
+// WaitRequirements waits that each requirement, eg. the Requirements of the plugin manifest, is satisfied by another plugin of the node
+// with that type or name, then loads the Agent, OS and NM plugins that are running. It fails when the context is done
+// or when looking up the Agent, OS and NM plugins fails
+func (pl *FOSPlugin) WaitRequirements(ctx context.Context, requirements []string) error {
+	for {
+		missing, err := pl.missingRequirements(requirements)
+		if err == nil && len(missing) == 0 {
+			var errs []error
+			for _, get := range []func() (bool, error){pl.GetAgent, pl.GetOSPlugin, pl.GetNMPlugin} {
+				_, err := get()
+				errs = appendError(errs, err)
+			}
+			return multiError(errs)
+		}
+		select {
+		case <-ctx.Done():
+			if err == nil {
+				err = ctx.Err()
+			}
+			return &FError{"Missing requirements " + strings.Join(missing, ", "), err}
+		case <-time.After(requirementsPollInterval):
+		}
+	}
+}
+
+// missingRequirements returns the requirements not satisfied by the plugins of the node
+func (pl *FOSPlugin) missingRequirements(requirements []string) ([]string, error) {
+	plugins, err := pl.FindPlugins(PluginFilter{})
+	if err != nil {
+		return requirements, err
+	}
+	missing := []string{}
+	for _, r := range requirements {
+		found := false
+		for _, p := range plugins {
+			if p.UUID != pl.UUID && (p.Type == r || p.Name == r) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, r)
+		}
+	}
+	return missing, nil
+}
+
+// PluginEvent is a plugin registered, updated, removed or whose heartbeat expired in a node, for a removed plugin Plugin is its last record
+type PluginEvent struct {
+	Plugin  Plugin
+	Removed bool
+
+	// Stale is true when the heartbeat of the plugin expired, eg. its process died without removing its record
+	Stale bool
+}
+
+// WatchPlugins calls listener for the plugins matching the filter that are registered, updated or removed in the node,
+// and once for each plugin whose heartbeat expired, checking the heartbeats every HeartbeatInterval. It stops when the context is done
+func (pl *FOSPlugin) WatchPlugins(ctx context.Context, filter PluginFilter, listener func(PluginEvent)) error {
+	var mu sync.Mutex
+	known := map[string]Plugin{}
+	// the plugins notified before the snapshot below is taken are not overwritten by it
+	notified := map[string]bool{}
+	// deliver serializes the calls to listener from the store and from the heartbeat checks
+	var deliver sync.Mutex
+	lad := pl.connector.Local.Actual.WithContext(ctx)
+	_, err := lad.ObserveNodePluginsChanges(pl.node, func(pluginid string, p *Plugin) {
+		mu.Lock()
+		last, found := known[pluginid]
+		if p != nil {
+			known[pluginid] = *p
+		} else {
+			delete(known, pluginid)
+		}
+		notified[pluginid] = true
+		mu.Unlock()
+
+		deliver.Lock()
+		defer deliver.Unlock()
+		switch {
+		case p != nil && filter.Match(*p):
+			listener(PluginEvent{Plugin: *p})
+		case p == nil && found && filter.Match(last):
+			listener(PluginEvent{Plugin: last, Removed: true})
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	plugins, err := pl.FindPlugins(PluginFilter{})
+	if err != nil {
+		return err
+	}
+	mu.Lock()
+	for _, p := range plugins {
+		if !notified[p.UUID] {
+			known[p.UUID] = p
+		}
+	}
+	mu.Unlock()
+
+	interval := pl.HeartbeatInterval
+	if interval <= 0 {
+		interval = DefaultHeartbeatInterval
+	}
+	w := NewPluginWatcher(pl.connector, pl.node)
+	if pl.HeartbeatTimeout > 0 {
+		w.Timeout = pl.HeartbeatTimeout
+	}
+	go watchStale(ctx, w, interval, func(sp StalePlugin) {
+		if filter.Match(sp.Plugin) {
+			deliver.Lock()
+			listener(PluginEvent{Plugin: sp.Plugin, Stale: true})
+			deliver.Unlock()
+		}
+	})
+	return nil
+}
+
+// watchStale runs the Check of the watcher every interval until the context is done, calling listener once for each plugin
+// whose heartbeat expired, again only after its heartbeat is published
+func watchStale(ctx context.Context, w *PluginWatcher, interval time.Duration, listener func(StalePlugin)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	reported := map[string]bool{}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		stale, err := w.Check()
+		if err != nil {
+			// a failed check is retried at the next interval
+			continue
+		}
+		expired := map[string]bool{}
+		for _, sp := range stale {
+			expired[sp.Plugin.UUID] = true
+			if !reported[sp.Plugin.UUID] {
+				reported[sp.Plugin.UUID] = true
+				listener(sp)
+			}
+		}
+		for pid := range reported {
+			if !expired[pid] {
+				delete(reported, pid)
+			}
+		}
+	}
+}
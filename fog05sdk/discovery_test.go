@@ -0,0 +1,192 @@
+/*
+* Copyright (c) 2014,2019 Contributors to the Eclipse Foundation
+* See the NOTICE file(s) distributed with this work for additional
+* information regarding copyright ownership.
+* This program and the accompanying materials are made available under the
+* terms of the Eclipse Public License 2.0 which is available at
+* http://www.eclipse.org/legal/epl-2.0, or the Apache License, Version 2.0
+* which is available at https://www.apache.org/licenses/LICENSE-2.0.
+* SPDX-License-Identifier: EPL-2.0 OR Apache-2.0
+* Contributors: Gabriele Baldoni, ADLINK Technology Inc.
+* golang APIs
+ */
+
+package fog05sdk_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	fog05sdk "github.com/eclipse-fog05/sdk-go/fog05sdk"
+	"github.com/eclipse-fog05/sdk-go/fog05sdk/fostest"
+)
+
+// addPlugins adds the records of the plugins to node n1
+func addPlugins(t *testing.T, yc *fog05sdk.YaksConnector, plugins ...fog05sdk.Plugin) {
+	t.Helper()
+	for _, p := range plugins {
+		if err := yc.Local.Actual.AddNodePlugin("n1", p.UUID, p); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestFindPlugins(t *testing.T) {
+	yc, _ := fostest.NewConnector()
+	addPlugins(t, yc,
+		fog05sdk.Plugin{UUID: "nm1", Name: "linuxbridge", Type: fog05sdk.NetworkManagerPluginType, Version: 1},
+		fog05sdk.Plugin{UUID: "rt1", Name: "native", Type: fog05sdk.RuntimePluginType, Version: 1},
+		fog05sdk.Plugin{UUID: "rt2", Name: "kvm", Type: fog05sdk.RuntimePluginType, Version: 3})
+	pl := fog05sdk.NewPluginWithConnector(1, "p1", yc, "n1")
+
+	tests := []struct {
+		filter fog05sdk.PluginFilter
+		want   string
+	}{
+		{fog05sdk.PluginFilter{}, "nm1,rt1,rt2"},
+		{fog05sdk.PluginFilter{Type: fog05sdk.RuntimePluginType}, "rt1,rt2"},
+		{fog05sdk.PluginFilter{Type: fog05sdk.RuntimePluginType, MinVersion: 2}, "rt2"},
+		{fog05sdk.PluginFilter{MaxVersion: 2}, "nm1,rt1"},
+		{fog05sdk.PluginFilter{Name: "kvm", MaxVersion: 2}, ""},
+	}
+	for _, tt := range tests {
+		plugins, err := pl.FindPlugins(tt.filter)
+		ids := []string{}
+		for _, p := range plugins {
+			ids = append(ids, p.UUID)
+		}
+		if err != nil || strings.Join(ids, ",") != tt.want {
+			t.Errorf("FindPlugins(%+v) = %v, %v, want %s", tt.filter, ids, err, tt.want)
+		}
+	}
+
+	if ok, err := pl.GetNMPlugin(); !ok || err != nil || pl.NM == nil {
+		t.Errorf("GetNMPlugin() = %v, %v", ok, err)
+	}
+	if ok, err := pl.GetOSPlugin(); ok || err != nil || pl.OS != nil {
+		t.Errorf("GetOSPlugin() without OS plugin = %v, %v", ok, err)
+	}
+	// the Agent is not bound to the network manager
+	if ok, err := pl.GetAgent(); ok || err != nil || pl.Agent != nil {
+		t.Errorf("GetAgent() without Agent = %v, %v", ok, err)
+	}
+}
+
+func TestPluginsNotLoaded(t *testing.T) {
+	yc, _ := fostest.NewConnector()
+	pl := fog05sdk.NewPluginWithConnector(1, "p1", yc, "n1")
+	if _, err := pl.GetLocalMGMTAddress(); !errors.Is(err, fog05sdk.ErrNotFound) {
+		t.Errorf("GetLocalMGMTAddress() without OS plugin = %v", err)
+	}
+	if _, err := pl.OS.CallOSPluginFunction("dir_exists", nil); !errors.Is(err, fog05sdk.ErrNotFound) {
+		t.Errorf("CallOSPluginFunction() without OS plugin = %v", err)
+	}
+	if _, err := pl.NM.GetAllNodePorts(); !errors.Is(err, fog05sdk.ErrNotFound) {
+		t.Errorf("GetAllNodePorts() without NM plugin = %v", err)
+	}
+	if _, err := pl.Agent.GetNodeMGMTAddress("n1"); !errors.Is(err, fog05sdk.ErrNotFound) {
+		t.Errorf("GetNodeMGMTAddress() without Agent = %v", err)
+	}
+	rt, _ := newRuntime(t, yc, "n1", "rt1")
+	rt.FOSPlugin.Agent = nil
+	if _, err := rt.GetFDUDescriptor("f1", "i1"); !errors.Is(err, fog05sdk.ErrNotFound) {
+		t.Errorf("GetFDUDescriptor() without Agent = %v", err)
+	}
+}
+
+// agentFailingStore fails the evals of the Agent from the fostest Store it wraps
+type agentFailingStore struct {
+	*fostest.Store
+}
+
+func (as *agentFailingStore) Get(ctx context.Context, s *fog05sdk.Selector) ([]fog05sdk.Entry, error) {
+	if strings.Contains(s.ToString(), "/agent/exec/") {
+		return nil, &fog05sdk.FError{Msg: "Get failed", Cause: fog05sdk.ErrNotConnected}
+	}
+	return as.Store.Get(ctx, s)
+}
+
+func TestWaitRequirements(t *testing.T) {
+	st := fostest.NewStore()
+	yc := fog05sdk.NewConnector(st)
+	addPlugins(t, yc, fog05sdk.Plugin{UUID: "nm1", Name: "linuxbridge", Type: fog05sdk.NetworkManagerPluginType})
+	pl := fog05sdk.NewPluginWithConnector(1, "p1", yc, "n1")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	err := pl.WaitRequirements(ctx, []string{"linuxbridge", fog05sdk.OSPluginType})
+	cancel()
+	if !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "Missing requirements os") {
+		t.Errorf("WaitRequirements() without OS plugin = %v", err)
+	}
+
+	startAgent(t, yc)
+	addPlugins(t, yc, fog05sdk.Plugin{UUID: "os1", Name: "linux", Type: fog05sdk.OSPluginType})
+	if err := pl.WaitRequirements(context.Background(), []string{"linuxbridge", fog05sdk.OSPluginType}); err != nil {
+		t.Fatalf("WaitRequirements() = %v", err)
+	}
+	if pl.Agent == nil || pl.OS == nil || pl.NM == nil {
+		t.Errorf("WaitRequirements() loaded Agent %v, OS %v, NM %v", pl.Agent, pl.OS, pl.NM)
+	}
+
+	// the failures looking up the Agent, OS and NM plugins are returned
+	failing := fog05sdk.NewPluginWithConnector(1, "p2", fog05sdk.NewConnector(&agentFailingStore{st}), "n1")
+	if err := failing.WaitRequirements(context.Background(), []string{fog05sdk.OSPluginType}); !errors.Is(err, fog05sdk.ErrNotConnected) {
+		t.Errorf("WaitRequirements() with the Agent unreachable = %v", err)
+	}
+}
+
+func TestWatchPlugins(t *testing.T) {
+	yc, _ := fostest.NewConnector()
+	addPlugins(t, yc, fog05sdk.Plugin{UUID: "rt1", Name: "native", Type: fog05sdk.RuntimePluginType})
+	pl := fog05sdk.NewPluginWithConnector(1, "p1", yc, "n1")
+	pl.HeartbeatInterval = 10 * time.Millisecond
+	pl.HeartbeatTimeout = 50 * time.Millisecond
+
+	var mu sync.Mutex
+	events := []string{}
+	history := func() string {
+		mu.Lock()
+		defer mu.Unlock()
+		return strings.Join(events, ",")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	err := pl.WatchPlugins(ctx, fog05sdk.PluginFilter{Type: fog05sdk.RuntimePluginType}, func(e fog05sdk.PluginEvent) {
+		event := "+" + e.Plugin.Name
+		switch {
+		case e.Removed:
+			event = "-" + e.Plugin.Name
+		case e.Stale:
+			event = "stale " + e.Plugin.Name
+		}
+		mu.Lock()
+		events = append(events, event)
+		mu.Unlock()
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	addPlugins(t, yc,
+		fog05sdk.Plugin{UUID: "os1", Name: "linux", Type: fog05sdk.OSPluginType},
+		fog05sdk.Plugin{UUID: "rt2", Name: "kvm", Type: fog05sdk.RuntimePluginType})
+	if err := yc.Local.Actual.RemoveNodePlugin("n1", "rt1"); err != nil {
+		t.Fatal(err)
+	}
+	eventually(t, "the plugin events", func() bool { return history() == "+kvm,-native" })
+
+	// the process of kvm dies without removing its record, it is reported once
+	old := time.Now().Add(-time.Minute).UnixNano() / int64(time.Millisecond)
+	if err := yc.Local.Actual.AddNodePluginHeartbeat("n1", "rt2", fog05sdk.PluginHeartbeat{Timestamp: old, Status: fog05sdk.HeartbeatRunning}); err != nil {
+		t.Fatal(err)
+	}
+	eventually(t, "the stale plugin", func() bool { return history() == "+kvm,-native,stale kvm" })
+	time.Sleep(5 * pl.HeartbeatInterval)
+	if h := history(); h != "+kvm,-native,stale kvm" {
+		t.Errorf("events = %s, want the stale plugin once", h)
+	}
+}
@@ -0,0 +1,132 @@
+/*
+* Copyright (c) 2014,2019 Contributors to the Eclipse Foundation
+* See the NOTICE file(s) distributed with this work for additional
+* information regarding copyright ownership.
+* This program and the accompanying materials are made available under the
+* terms of the Eclipse Public License 2.0 which is available at
+* http://www.eclipse.org/legal/epl-2.0, or the Apache License, Version 2.0
+* which is available at https://www.apache.org/licenses/LICENSE-2.0.
+* SPDX-License-Identifier: EPL-2.0 OR Apache-2.0
+* Contributors: Gabriele Baldoni, ADLINK Technology Inc.
+* golang APIs
+ */
+
+package fog05sdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// ErrNotFound is the cause of the errors returned when the requested information is not in the store
+var ErrNotFound = errors.New("not found")
+
+// ErrNotConnected is the cause of the errors returned when the store is not connected
+var ErrNotConnected = errors.New("not connected")
+
+// ErrTimeout is the cause of the errors returned when an operation did not complete before the context deadline, it is context.DeadlineExceeded
+var ErrTimeout = context.DeadlineExceeded
+
+// EvalError is returned when an eval replied with an error
+type EvalError struct {
+	Code    int
+	Message string
+}
+
+func (e *EvalError) Error() string {
+	return e.Message + " ErrNo: " + strconv.Itoa(e.Code)
+}
+
+// DecodeError is returned when a value read from the store or returned by an eval cannot be decoded
+type DecodeError struct {
+	Cause error
+}
+
+func (e *DecodeError) Error() string {
+	return "Error on conversion: " + e.Cause.Error()
+}
+
+// Unwrap returns the cause of the DecodeError
+func (e *DecodeError) Unwrap() error {
+	return e.Cause
+}
+
+// MultiError is returned by the operations that can fail on several independent steps, like Close, it collects all the errors
+type MultiError struct {
+	Errors []error
+}
+
+func (e *MultiError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+	return strconv.Itoa(len(e.Errors)) + " errors occurred: " + strings.Join(msgs, "; ")
+}
+
+// Is returns true if one of the errors is target, so that errors.Is looks into all the collected errors
+func (e *MultiError) Is(target error) bool {
+	for _, err := range e.Errors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first error that matches target, as errors.As does, so that errors.As looks into all the collected errors
+func (e *MultiError) As(target interface{}) bool {
+	for _, err := range e.Errors {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// appendError adds err to errs, flattening MultiError and skipping nil
+func appendError(errs []error, err error) []error {
+	if err == nil {
+		return errs
+	}
+	if me, ok := err.(*MultiError); ok {
+		return append(errs, me.Errors...)
+	}
+	return append(errs, err)
+}
+
+// multiError returns nil if errs is empty, the error if there is only one, a MultiError otherwise
+func multiError(errs []error) error {
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return errs[0]
+	default:
+		return &MultiError{errs}
+	}
+}
+
+// decode unmarshals the JSON value in v, returning a DecodeError on failure
+func decode(value string, v interface{}) error {
+	err := json.Unmarshal([]byte(value), v)
+	if err != nil {
+		return &DecodeError{err}
+	}
+	return nil
+}
+
+// newEvalError returns an EvalError from the error and message of an eval result, nil if there is no error
+func newEvalError(code *int, msg *string) error {
+	if code == nil {
+		return nil
+	}
+	e := EvalError{Code: *code}
+	if msg != nil {
+		e.Message = *msg
+	}
+	return &e
+}
@@ -0,0 +1,111 @@
+/*
+* Copyright (c) 2014,2019 Contributors to the Eclipse Foundation
+* See the NOTICE file(s) distributed with this work for additional
+* information regarding copyright ownership.
+* This program and the accompanying materials are made available under the
+* terms of the Eclipse Public License 2.0 which is available at
+* http://www.eclipse.org/legal/epl-2.0, or the Apache License, Version 2.0
+* which is available at https://www.apache.org/licenses/LICENSE-2.0.
+* SPDX-License-Identifier: EPL-2.0 OR Apache-2.0
+* Contributors: Gabriele Baldoni, ADLINK Technology Inc.
+* golang APIs
+ */
+
+package fog05sdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestMultiError(t *testing.T) {
+	eerr := &EvalError{Code: EvalErrorInvalidParameters, Message: "bad"}
+	derr := &DecodeError{errors.New("bad json")}
+	tests := []struct {
+		name     string
+		errs     []error
+		nil      bool
+		notFound bool
+		eval     bool
+		decode   bool
+	}{
+		{"none", nil, true, false, false, false},
+		{"only nil", []error{nil, nil}, true, false, false, false},
+		{"one", []error{&FError{"missing", ErrNotFound}}, false, true, false, false},
+		{"many", []error{nil, &FError{"missing", ErrNotFound}, eerr}, false, true, true, false},
+		{"wrapped", []error{&FError{"failed", eerr}, derr}, false, false, true, true},
+		{"nested", []error{&MultiError{[]error{derr, &FError{"missing", ErrNotFound}}}, context.DeadlineExceeded}, false, true, false, true},
+	}
+	for _, tt := range tests {
+		var errs []error
+		for _, err := range tt.errs {
+			errs = appendError(errs, err)
+		}
+		err := multiError(errs)
+		if (err == nil) != tt.nil {
+			t.Errorf("%s: multiError() = %v", tt.name, err)
+			continue
+		}
+		if got := errors.Is(err, ErrNotFound); got != tt.notFound {
+			t.Errorf("%s: errors.Is(ErrNotFound) = %v", tt.name, got)
+		}
+		var e *EvalError
+		if got := errors.As(err, &e); got != tt.eval || (got && e != eerr) {
+			t.Errorf("%s: errors.As(EvalError) = %v, %v", tt.name, got, e)
+		}
+		var d *DecodeError
+		if got := errors.As(err, &d); got != tt.decode {
+			t.Errorf("%s: errors.As(DecodeError) = %v", tt.name, got)
+		}
+	}
+}
+
+func TestEvalResults(t *testing.T) {
+	tests := []struct {
+		name   string
+		reply  RawEvalResult
+		result string
+		code   int
+	}{
+		{"string", NewEvalResult("hello"), `"hello"`, 0},
+		{"object", NewEvalResult(map[string]int{"a": 1}), `{"a":1}`, 0},
+		{"not encodable", NewEvalResult(func() {}), "", EvalErrorFailed},
+		{"eval error", NewEvalErrorResult(&EvalError{Code: EvalErrorInvalidParameters, Message: "bad"}), "", EvalErrorInvalidParameters},
+		{"wrapped eval error", NewEvalErrorResult(&FError{"failed", &EvalError{Code: 2, Message: "missing"}}), "", 2},
+		{"other error", NewEvalErrorResult(errors.New("boom")), "", EvalErrorFailed},
+	}
+	for _, tt := range tests {
+		var r RawEvalResult
+		if err := json.Unmarshal([]byte(evalReply(tt.reply)), &r); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		err := r.Err()
+		if tt.code == 0 {
+			if err != nil || string(r.Result) != tt.result {
+				t.Errorf("%s: reply %s, %v, want %s", tt.name, r.Result, err, tt.result)
+			}
+			continue
+		}
+		var eerr *EvalError
+		if !errors.As(err, &eerr) || eerr.Code != tt.code {
+			t.Errorf("%s: reply error %v, want code %d", tt.name, err, tt.code)
+		}
+		var derr *DecodeError
+		if errors.As(err, &derr) {
+			t.Errorf("%s: reply error %v is a DecodeError", tt.name, err)
+		}
+	}
+
+	if reply := evalReply(func() {}); !json.Valid([]byte(reply)) {
+		t.Errorf("evalReply() of a value that cannot be encoded = %s", reply)
+	} else {
+		var r RawEvalResult
+		json.Unmarshal([]byte(reply), &r)
+		var eerr *EvalError
+		if !errors.As(r.Err(), &eerr) || eerr.Code != EvalErrorFailed {
+			t.Errorf("evalReply() of a value that cannot be encoded = %s, want an EvalErrorFailed reply", reply)
+		}
+	}
+}
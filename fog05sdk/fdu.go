@@ -13,6 +13,11 @@
 
 package fog05sdk
 
+import (
+	"fmt"
+	"strconv"
+)
+
 const (
 	// LIVE is Live Migration kind
 	LIVE string = "LIVE"
@@ -142,14 +147,14 @@ type FDUGeographicalRequirements struct {
 	Proximity *FDUProximity `json:"proximity,omitempty"`
 }
 
-// FDUPosition represents the FDU Position
+// FDUPosition represents the FDU Position, Radius is in km, 0 for no bound
 type FDUPosition struct {
 	Latitude  string  `json:"lat"`
 	Longitude string  `json:"lon"`
 	Radius    float64 `json:"radius"`
 }
 
-// FDUProximity represents the FDU Proximity
+// FDUProximity represents the FDU Proximity, Radius is in km, 0 for no bound
 type FDUProximity struct {
 	Neighbor string  `json:"neighbour"`
 	Radius   float64 `json:"radius"`
@@ -291,3 +296,136 @@ type FDURecord struct {
 	MigrationProperties      *FDUMigrationProperties      `json:"migration_properties,omitempty"`
 	HypervisorInfo           *jsont                       `json:"hypervisor_info,omitempty"`
 }
+
+// Validate checks the FDU descriptor, it returns a ValidationError listing all the invalid fields, or nil if the descriptor is valid.
+// DependsOn is checked only for self and duplicated references, the referenced FDUs are checked by ValidateDependencies
+func (fdu *FDU) Validate() error {
+	v := validator{}
+	fdu.validate(&v)
+	return v.err()
+}
+
+// ValidateDependencies checks the FDU descriptor as Validate, and also that all the FDUs in DependsOn are in catalog,
+// the descriptor ids of the FDUs in the catalog
+func (fdu *FDU) ValidateDependencies(catalog []string) error {
+	v := validator{}
+	fdu.validate(&v)
+	known := map[string]bool{}
+	for _, id := range catalog {
+		known[id] = true
+	}
+	for i, d := range fdu.DependsOn {
+		if d != "" && d != fdu.ID && !known[d] {
+			v.add(fmt.Sprintf("depends_on[%d]", i), "unknown FDU %q", d)
+		}
+	}
+	return v.err()
+}
+
+func (fdu *FDU) validate(v *validator) {
+	v.required("id", fdu.ID)
+	v.required("name", fdu.Name)
+	v.oneOf("hypervisor", fdu.Hypervisor, BARE, KVM, KVMUK, XEN, XENUK, LXD, DOCKER, MCU)
+	v.oneOf("migration_kind", fdu.MigrationKind, LIVE, COLD)
+
+	fdu.ComputationRequirements.validate(v, "computation_requirements")
+
+	if fdu.Image != nil {
+		v.required("image.uri", fdu.Image.URI)
+	}
+	if fdu.Command != nil {
+		v.required("command.binary", fdu.Command.Binary)
+	}
+	if fdu.GeographicalRequirements != nil {
+		fdu.GeographicalRequirements.validate(v, "geographical_requirements")
+	}
+	if fdu.Configuration != nil {
+		v.oneOf("configuration.conf_type", fdu.Configuration.ConfType, SCRIPT, CLOUDINIT)
+	}
+
+	cps := map[string]bool{}
+	for i, cp := range fdu.ConnectionPoints {
+		field := fmt.Sprintf("connection_points[%d].id", i)
+		v.required(field, cp.ID)
+		v.unique(field, cp.ID, cps)
+	}
+
+	names := map[string]bool{}
+	for i, intf := range fdu.Interfaces {
+		field := fmt.Sprintf("interfaces[%d]", i)
+		v.required(field+".name", intf.Name)
+		v.unique(field+".name", intf.Name, names)
+		v.oneOf(field+".if_type", intf.InterfaceType, INTERNAL, EXTERNAL, WLAN, BLUETOOTH)
+		v.oneOf(field+".virtual_interface.intf_type", intf.VirtualInterface.InterfaceType, PARAVIRT, FOSMGMT, PCIPASSTHROUGH, SRIOV, E1000, RTL8139, PHYSICAL, BRIDGED)
+		v.atLeast(field+".virtual_interface.bandwidth", float64(intf.VirtualInterface.Bandwidth), 0)
+		if intf.CPID != nil && !cps[*intf.CPID] {
+			v.add(field+".cp_id", "unknown connection point %q", *intf.CPID)
+		}
+	}
+
+	ids := map[string]bool{}
+	for i, st := range fdu.Storage {
+		field := fmt.Sprintf("storage[%d]", i)
+		v.required(field+".id", st.ID)
+		v.unique(field+".id", st.ID, ids)
+		v.oneOf(field+".storage_type", st.StorageType, BLOCK, FILE, OBJECT)
+		v.atLeast(field+".size", float64(st.Size), 0)
+		if st.CPID != nil && !cps[*st.CPID] {
+			v.add(field+".cp_id", "unknown connection point %q", *st.CPID)
+		}
+	}
+
+	for i, port := range fdu.IOPorts {
+		field := fmt.Sprintf("io_ports[%d]", i)
+		v.oneOf(field+".io_kind", port.IOKind, GPIO, I2C, BUS, COM, CAN)
+		v.atLeast(field+".min_io_ports", float64(port.MinIOPorts), 0)
+	}
+
+	deps := map[string]bool{}
+	for i, d := range fdu.DependsOn {
+		field := fmt.Sprintf("depends_on[%d]", i)
+		v.required(field, d)
+		v.unique(field, d, deps)
+		if d != "" && d == fdu.ID {
+			v.add(field, "the FDU depends on itself")
+		}
+	}
+}
+
+func (cr *FDUComputationalRequirements) validate(v *validator, field string) {
+	v.atLeast(field+".cpu_min_freq", float64(cr.CPUMinFrequency), 0)
+	v.atLeast(field+".cpu_min_count", float64(cr.CPUMinCount), 1)
+	if cr.GPUMinCount != nil {
+		v.atLeast(field+".gpu_min_count", float64(*cr.GPUMinCount), 0)
+	}
+	if cr.FPGAMinCount != nil {
+		v.atLeast(field+".fpga_min_count", float64(*cr.FPGAMinCount), 0)
+	}
+	v.atLeast(field+".ram_size_mb", cr.RAMSizeMB, 0)
+	v.atLeast(field+".storage_size_gb", cr.StorageSizeGB, 0)
+	if cr.DutyCycle != nil {
+		v.between(field+".duty_cycle", *cr.DutyCycle, 0, 1)
+	}
+}
+
+func (gr *FDUGeographicalRequirements) validate(v *validator, field string) {
+	if gr.Position != nil {
+		lat, err := strconv.ParseFloat(gr.Position.Latitude, 64)
+		if err != nil {
+			v.add(field+".position.lat", "%q is not a number", gr.Position.Latitude)
+		} else {
+			v.between(field+".position.lat", lat, -90, 90)
+		}
+		lon, err := strconv.ParseFloat(gr.Position.Longitude, 64)
+		if err != nil {
+			v.add(field+".position.lon", "%q is not a number", gr.Position.Longitude)
+		} else {
+			v.between(field+".position.lon", lon, -180, 180)
+		}
+		v.atLeast(field+".position.radius", gr.Position.Radius, 0)
+	}
+	if gr.Proximity != nil {
+		v.required(field+".proximity.neighbour", gr.Proximity.Neighbor)
+		v.atLeast(field+".proximity.radius", gr.Proximity.Radius, 0)
+	}
+}
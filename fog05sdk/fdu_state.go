@@ -0,0 +1,116 @@
+/*
+* Copyright (c) 2014,2019 Contributors to the Eclipse Foundation
+* See the NOTICE file(s) distributed with this work for additional
+* information regarding copyright ownership.
+* This program and the accompanying materials are made available under the
+* terms of the Eclipse Public License 2.0 which is available at
+* http://www.eclipse.org/legal/epl-2.0, or the Apache License, Version 2.0
+* which is available at https://www.apache.org/licenses/LICENSE-2.0.
+* SPDX-License-Identifier: EPL-2.0 OR Apache-2.0
+* Contributors: Gabriele Baldoni, ADLINK Technology Inc.
+* golang APIs
+ */
+
+package fog05sdk
+
+import "strings"
+
+// FDUState is the state of an FDU instance, as stored in FDURecord.Status, the empty state is the one of an instance without record
+type FDUState string
+
+// fduTransitions are the legal transitions between FDU instance states, ERROR can be reached from any state but UNDEFINE
+var fduTransitions = map[string][]string{
+	"":        {DEFINE, LAND},
+	DEFINE:    {CONFIGURE, UNDEFINE},
+	CONFIGURE: {CLEAN, DEFINE, STARTING, RUN},
+	CLEAN:     {DEFINE, UNDEFINE},
+	STARTING:  {RUN, STOP},
+	RUN:       {STOP, CONFIGURE, PAUSE, SCALE, MIGRATE, TAKEOFF},
+	STOP:      {CONFIGURE, CLEAN},
+	PAUSE:     {RESUME, RUN, STOP},
+	RESUME:    {RUN},
+	SCALE:     {RUN},
+	MIGRATE:   {TAKEOFF, LAND, RUN},
+	TAKEOFF:   {STOP, CLEAN, UNDEFINE, RUN},
+	LAND:      {RUN, CONFIGURE},
+	ERROR:     {DEFINE, CLEAN, UNDEFINE},
+	UNDEFINE:  {},
+}
+
+// IsValid returns true if the state is a known FDU instance state
+func (s FDUState) IsValid() bool {
+	_, found := fduTransitions[string(s)]
+	return found
+}
+
+// Transitions returns the states that can be reached from the state
+func (s FDUState) Transitions() []FDUState {
+	next := []FDUState{}
+	for _, n := range fduTransitions[string(s)] {
+		next = append(next, FDUState(n))
+	}
+	if s.IsValid() && s != FDUState(UNDEFINE) && s != FDUState(ERROR) {
+		next = append(next, FDUState(ERROR))
+	}
+	return next
+}
+
+// CanTransition returns true if the state can move to the given state, staying in the same state is always allowed
+func (s FDUState) CanTransition(to FDUState) bool {
+	if !s.IsValid() || !to.IsValid() {
+		return false
+	}
+	if s == to {
+		return true
+	}
+	for _, n := range s.Transitions() {
+		if n == to {
+			return true
+		}
+	}
+	return false
+}
+
+// FDUTransitionError is returned when an FDU instance is moved to a state that cannot be reached from its current one
+type FDUTransitionError struct {
+	InstanceID string
+	From       FDUState
+	To         FDUState
+}
+
+func (e *FDUTransitionError) Error() string {
+	from := string(e.From)
+	if from == "" {
+		from = "no state"
+	}
+	if !e.To.IsValid() {
+		return "Unknown state " + string(e.To) + " for FDU instance " + e.InstanceID
+	}
+	allowed := []string{}
+	for _, n := range e.From.Transitions() {
+		allowed = append(allowed, string(n))
+	}
+	msg := "Illegal transition of FDU instance " + e.InstanceID + " from " + from + " to " + string(e.To)
+	if len(allowed) == 0 {
+		return msg + ", no transition allowed"
+	}
+	return msg + ", allowed: " + strings.Join(allowed, ", ")
+}
+
+// CheckTransition returns an FDUTransitionError if the instance cannot move from its current status to the given one
+func (r *FDURecord) CheckTransition(status string) error {
+	if !FDUState(r.Status).CanTransition(FDUState(status)) {
+		return &FDUTransitionError{InstanceID: r.UUID, From: FDUState(r.Status), To: FDUState(status)}
+	}
+	return nil
+}
+
+// SetStatus moves the instance to the given status, returning an FDUTransitionError if the transition is illegal
+func (r *FDURecord) SetStatus(status string) error {
+	err := r.CheckTransition(status)
+	if err != nil {
+		return err
+	}
+	r.Status = status
+	return nil
+}
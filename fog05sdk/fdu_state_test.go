@@ -0,0 +1,91 @@
+/*
+* Copyright (c) 2014,2019 Contributors to the Eclipse Foundation
+* See the NOTICE file(s) distributed with this work for additional
+* information regarding copyright ownership.
+* This program and the accompanying materials are made available under the
+* terms of the Eclipse Public License 2.0 which is available at
+* http://www.eclipse.org/legal/epl-2.0, or the Apache License, Version 2.0
+* which is available at https://www.apache.org/licenses/LICENSE-2.0.
+* SPDX-License-Identifier: EPL-2.0 OR Apache-2.0
+* Contributors: Gabriele Baldoni, ADLINK Technology Inc.
+* golang APIs
+ */
+
+package fog05sdk
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFDUTransitions(t *testing.T) {
+	for from, tos := range fduTransitions {
+		for _, to := range tos {
+			if !FDUState(to).IsValid() {
+				t.Errorf("transition %q -> %q leads to an unknown state", from, to)
+			}
+		}
+	}
+
+	tests := []struct {
+		from string
+		to   string
+		ok   bool
+	}{
+		{"", DEFINE, true},
+		{"", LAND, true},
+		{"", RUN, false},
+		{"", ERROR, true},
+		{DEFINE, CONFIGURE, true},
+		{DEFINE, RUN, false},
+		{DEFINE, DEFINE, true},
+		{DEFINE, ERROR, true},
+		{CONFIGURE, RUN, true},
+		{CONFIGURE, STARTING, true},
+		{STARTING, RUN, true},
+		{RUN, STOP, true},
+		{RUN, MIGRATE, true},
+		{RUN, UNDEFINE, false},
+		{RUN, ERROR, true},
+		{STOP, CLEAN, true},
+		{STOP, RUN, false},
+		{CLEAN, UNDEFINE, true},
+		{PAUSE, RESUME, true},
+		{RESUME, RUN, true},
+		{MIGRATE, TAKEOFF, true},
+		{MIGRATE, LAND, true},
+		{TAKEOFF, UNDEFINE, true},
+		{LAND, RUN, true},
+		{LAND, STOP, false},
+		{ERROR, UNDEFINE, true},
+		{ERROR, RUN, false},
+		{ERROR, ERROR, true},
+		{UNDEFINE, DEFINE, false},
+		{UNDEFINE, ERROR, false},
+		{UNDEFINE, UNDEFINE, true},
+		{"UNKNOWN", RUN, false},
+		{RUN, "UNKNOWN", false},
+	}
+	for _, tt := range tests {
+		if got := FDUState(tt.from).CanTransition(FDUState(tt.to)); got != tt.ok {
+			t.Errorf("CanTransition(%q -> %q) = %v, want %v", tt.from, tt.to, got, tt.ok)
+		}
+		r := FDURecord{UUID: "i1", Status: tt.from}
+		err := r.SetStatus(tt.to)
+		if tt.ok {
+			if err != nil || r.Status != tt.to {
+				t.Errorf("SetStatus(%q -> %q) = %v, status %q", tt.from, tt.to, err, r.Status)
+			}
+			continue
+		}
+		var terr *FDUTransitionError
+		if !errors.As(err, &terr) {
+			t.Errorf("SetStatus(%q -> %q) = %v, want FDUTransitionError", tt.from, tt.to, err)
+		} else if terr.From != FDUState(tt.from) || terr.To != FDUState(tt.to) || terr.InstanceID != "i1" {
+			t.Errorf("SetStatus(%q -> %q) error = %+v", tt.from, tt.to, terr)
+		}
+		if r.Status != tt.from {
+			t.Errorf("SetStatus(%q -> %q) changed the status to %q", tt.from, tt.to, r.Status)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+/*
+* Copyright (c) 2014,2019 Contributors to the Eclipse Foundation
+* See the NOTICE file(s) distributed with this work for additional
+* information regarding copyright ownership.
+* This program and the accompanying materials are made available under the
+* terms of the Eclipse Public License 2.0 which is available at
+* http://www.eclipse.org/legal/epl-2.0, or the Apache License, Version 2.0
+* which is available at https://www.apache.org/licenses/LICENSE-2.0.
+* SPDX-License-Identifier: EPL-2.0 OR Apache-2.0
+* Contributors: Gabriele Baldoni, ADLINK Technology Inc.
+* golang APIs
+ */
+
+package fog05sdk
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func validFDU() FDU {
+	cp := "cp1"
+	return FDU{
+		ID:                      "fdu1",
+		Name:                    "fdu1",
+		ComputationRequirements: FDUComputationalRequirements{CPUMinCount: 1, RAMSizeMB: 128},
+		Hypervisor:              LXD,
+		MigrationKind:           COLD,
+		Image:                   &FDUImage{URI: "lxd://alpine"},
+		ConnectionPoints:        []ConnectionPointDescriptor{{ID: cp, Name: cp}},
+		Interfaces: []FDUInterfaceDescriptor{{
+			Name:             "eth0",
+			InterfaceType:    INTERNAL,
+			VirtualInterface: FDUVirtualInterface{InterfaceType: PARAVIRT},
+			CPID:             &cp,
+		}},
+		Storage: []FDUStorageDescriptor{{ID: "st1", StorageType: BLOCK, Size: 10}},
+	}
+}
+
+// fields returns the fields of the FieldErrors in err, or nil if err is nil
+func fields(t *testing.T, err error) []string {
+	t.Helper()
+	if err == nil {
+		return nil
+	}
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("error %v is not a ValidationError", err)
+	}
+	res := []string{}
+	for _, fe := range verr.Errors {
+		res = append(res, fe.Field)
+	}
+	return res
+}
+
+func TestFDUValidate(t *testing.T) {
+	duty := 1.5
+	unknown := "cp2"
+	tests := []struct {
+		name   string
+		modify func(*FDU)
+		want   []string
+	}{
+		{"valid", func(*FDU) {}, nil},
+		{"missing id and name", func(f *FDU) { f.ID = ""; f.Name = "" }, []string{"id", "name"}},
+		{"unknown hypervisor", func(f *FDU) { f.Hypervisor = "vmware" }, []string{"hypervisor"}},
+		{"unknown migration kind", func(f *FDU) { f.MigrationKind = "WARM" }, []string{"migration_kind"}},
+		{"no cpu", func(f *FDU) { f.ComputationRequirements.CPUMinCount = 0 }, []string{"computation_requirements.cpu_min_count"}},
+		{"negative ram", func(f *FDU) { f.ComputationRequirements.RAMSizeMB = -1 }, []string{"computation_requirements.ram_size_mb"}},
+		{"duty cycle", func(f *FDU) { f.ComputationRequirements.DutyCycle = &duty }, []string{"computation_requirements.duty_cycle"}},
+		{"image without uri", func(f *FDU) { f.Image.URI = "" }, []string{"image.uri"}},
+		{"command without binary", func(f *FDU) { f.Command = &FDUCommand{} }, []string{"command.binary"}},
+		{"configuration type", func(f *FDU) { f.Configuration = &FDUConfiguration{ConfType: "ansible"} }, []string{"configuration.conf_type"}},
+		{"duplicated connection point", func(f *FDU) {
+			f.ConnectionPoints = append(f.ConnectionPoints, ConnectionPointDescriptor{ID: "cp1"})
+		}, []string{"connection_points[1].id"}},
+		{"interface on unknown connection point", func(f *FDU) { f.Interfaces[0].CPID = &unknown }, []string{"interfaces[0].cp_id"}},
+		{"duplicated interface", func(f *FDU) {
+			f.Interfaces = append(f.Interfaces, FDUInterfaceDescriptor{Name: "eth0", InterfaceType: EXTERNAL, VirtualInterface: FDUVirtualInterface{InterfaceType: BRIDGED}})
+		}, []string{"interfaces[1].name"}},
+		{"interface types", func(f *FDU) {
+			f.Interfaces[0].InterfaceType = "SERIAL"
+			f.Interfaces[0].VirtualInterface.InterfaceType = "VIRTIO"
+		}, []string{"interfaces[0].if_type", "interfaces[0].virtual_interface.intf_type"}},
+		{"storage", func(f *FDU) { f.Storage[0].StorageType = "TAPE"; f.Storage[0].Size = -1 }, []string{"storage[0].storage_type", "storage[0].size"}},
+		{"io port", func(f *FDU) { f.IOPorts = []FDUIOPort{{IOKind: "USB", MinIOPorts: -1}} }, []string{"io_ports[0].io_kind", "io_ports[0].min_io_ports"}},
+		{"position", func(f *FDU) {
+			f.GeographicalRequirements = &FDUGeographicalRequirements{Position: &FDUPosition{Latitude: "91", Longitude: "east", Radius: -1}}
+		}, []string{"geographical_requirements.position.lat", "geographical_requirements.position.lon", "geographical_requirements.position.radius"}},
+		{"position without radius", func(f *FDU) {
+			f.GeographicalRequirements = &FDUGeographicalRequirements{Position: &FDUPosition{Latitude: "45.07", Longitude: "7.68"}}
+		}, nil},
+		{"proximity", func(f *FDU) {
+			f.GeographicalRequirements = &FDUGeographicalRequirements{Proximity: &FDUProximity{Radius: -1}}
+		}, []string{"geographical_requirements.proximity.neighbour", "geographical_requirements.proximity.radius"}},
+		{"dependencies", func(f *FDU) { f.DependsOn = []string{"fdu2", "fdu2", "", "fdu1"} }, []string{"depends_on[1]", "depends_on[2]", "depends_on[3]"}},
+	}
+	for _, tt := range tests {
+		fdu := validFDU()
+		tt.modify(&fdu)
+		if got := fields(t, fdu.Validate()); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: Validate() fields = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFDUValidateDependencies(t *testing.T) {
+	tests := []struct {
+		name      string
+		dependsOn []string
+		catalog   []string
+		want      []string
+	}{
+		{"no dependencies", nil, nil, nil},
+		{"known", []string{"fdu2", "fdu3"}, []string{"fdu2", "fdu3"}, nil},
+		{"unknown", []string{"fdu2", "fdu3"}, []string{"fdu2"}, []string{"depends_on[1]"}},
+		{"self", []string{"fdu1"}, []string{"fdu1"}, []string{"depends_on[0]"}},
+	}
+	for _, tt := range tests {
+		fdu := validFDU()
+		fdu.DependsOn = tt.dependsOn
+		if got := fields(t, fdu.ValidateDependencies(tt.catalog)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: ValidateDependencies() fields = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,326 @@
+/*
+* Copyright (c) 2014,2019 Contributors to the Eclipse Foundation
+* See the NOTICE file(s) distributed with this work for additional
+* information regarding copyright ownership.
+* This program and the accompanying materials are made available under the
+* terms of the Eclipse Public License 2.0 which is available at
+* http://www.eclipse.org/legal/epl-2.0, or the Apache License, Version 2.0
+* which is available at https://www.apache.org/licenses/LICENSE-2.0.
+* SPDX-License-Identifier: EPL-2.0 OR Apache-2.0
+* Contributors: Gabriele Baldoni, ADLINK Technology Inc.
+* golang APIs
+ */
+
+package fog05sdk
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// DefaultFIMTimeout is the default time the FIMClient waits for an FDU instance to reach the requested state
+const DefaultFIMTimeout = 5 * time.Minute
+
+// FIMClient is a client of a fog05 system, like the FIMAPI of the Python SDK.
+// It onboards FDUs in the catalog and manages their instances writing in Global Desired, then waits the instances to reach
+// the requested state in Global Actual
+type FIMClient struct {
+	connector *YaksConnector
+	sysid     string
+	tenantid  string
+
+	// Timeout is the time the operations wait for the instance state when the context has no deadline, 0 to wait forever
+	Timeout time.Duration
+}
+
+// NewFIMClient returns a FIMClient for the given system and tenant
+func NewFIMClient(connector *YaksConnector, sysid string, tenantid string) *FIMClient {
+	return &FIMClient{connector: connector, sysid: sysid, tenantid: tenantid, Timeout: DefaultFIMTimeout}
+}
+
+// Onboard adds the FDU descriptor to the catalog through the Agent of one of the nodes, returns the descriptor stored in the catalog
+func (c *FIMClient) Onboard(ctx context.Context, fdu FDU) (*FDU, error) {
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+	gad := c.connector.Global.Actual.WithContext(ctx)
+
+	nodes, err := gad.GetAllNodes(c.sysid, c.tenantid)
+	if err != nil {
+		return nil, err
+	}
+	s, err := gad.onboardFDUSelector(c.sysid, c.tenantid, nodes[0], fdu)
+	if err != nil {
+		return nil, err
+	}
+	sv := FDU{}
+	res, err := gad.Eval(s, &sv)
+	if err != nil {
+		return nil, err
+	}
+	if !res.hasResult() {
+		return &fdu, nil
+	}
+	return &sv, nil
+}
+
+// Offboard removes the FDU descriptor from the catalog
+func (c *FIMClient) Offboard(ctx context.Context, fduid string) error {
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+	return c.connector.Global.Desired.WithContext(ctx).RemoveCatalogFDUInfo(c.sysid, c.tenantid, fduid)
+}
+
+// Define defines an instance of the FDU in the node, and waits it to be in DEFINE state
+func (c *FIMClient) Define(ctx context.Context, fduid string, nodeid string) (*FDURecord, error) {
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+	gad := c.connector.Global.Actual.WithContext(ctx)
+
+	s, err := gad.defineFDUSelector(c.sysid, c.tenantid, nodeid, fduid)
+	if err != nil {
+		return nil, err
+	}
+	record := FDURecord{}
+	res, err := gad.Eval(s, &record)
+	if err != nil {
+		return nil, err
+	}
+	if !res.hasResult() {
+		return nil, &FError{"DefineFDUInNode replied without result", nil}
+	}
+	return c.waitState(ctx, nodeid, record.UUID, "", DEFINE, func() error { return nil })
+}
+
+// Configure configures the instance, and waits it to be in CONFIGURE state
+func (c *FIMClient) Configure(ctx context.Context, instanceid string) (*FDURecord, error) {
+	return c.changeState(ctx, instanceid, CONFIGURE, CONFIGURE)
+}
+
+// Clean cleans the configuration of the instance, and waits it to be in DEFINE state
+func (c *FIMClient) Clean(ctx context.Context, instanceid string) (*FDURecord, error) {
+	return c.changeState(ctx, instanceid, CLEAN, DEFINE)
+}
+
+// Start starts the instance with the given environment, and waits it to be in RUN state
+func (c *FIMClient) Start(ctx context.Context, instanceid string, env string) (*FDURecord, error) {
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+	gad := c.connector.Global.Actual.WithContext(ctx)
+
+	nodeid, err := gad.GetFDUInstanceNode(c.sysid, c.tenantid, instanceid)
+	if err != nil {
+		return nil, err
+	}
+	return c.waitState(ctx, nodeid, instanceid, "", RUN, func() error {
+		_, err := gad.StartFDUInNode(c.sysid, c.tenantid, instanceid, env)
+		return err
+	})
+}
+
+// Stop stops the instance, and waits it to be in CONFIGURE state
+func (c *FIMClient) Stop(ctx context.Context, instanceid string) (*FDURecord, error) {
+	return c.changeState(ctx, instanceid, STOP, CONFIGURE)
+}
+
+// Pause pauses the instance, and waits it to be in PAUSE state
+func (c *FIMClient) Pause(ctx context.Context, instanceid string) (*FDURecord, error) {
+	return c.changeState(ctx, instanceid, PAUSE, PAUSE)
+}
+
+// Resume resumes the paused instance, and waits it to be in RUN state
+func (c *FIMClient) Resume(ctx context.Context, instanceid string) (*FDURecord, error) {
+	return c.changeState(ctx, instanceid, RESUME, RUN)
+}
+
+// Undefine undefines the instance, and waits its record to be removed
+func (c *FIMClient) Undefine(ctx context.Context, instanceid string) error {
+	_, err := c.changeState(ctx, instanceid, UNDEFINE, "")
+	return err
+}
+
+// Migrate migrates the instance to the destination node, and waits it to be in RUN state in the destination node.
+// The returned record is the one of the destination node
+func (c *FIMClient) Migrate(ctx context.Context, instanceid string, destinationid string) (*FDURecord, error) {
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+	actual := c.connector.Global.Actual.WithContext(ctx)
+	desired := c.connector.Global.Desired.WithContext(ctx)
+
+	nodeid, err := actual.GetFDUInstanceNode(c.sysid, c.tenantid, instanceid)
+	if err != nil {
+		return nil, err
+	}
+	src, err := actual.GetNodeFDUInstance(c.sysid, c.tenantid, nodeid, instanceid)
+	if err != nil {
+		return nil, err
+	}
+	from := src.Status
+	err = src.SetStatus(TAKEOFF)
+	if err != nil {
+		return nil, err
+	}
+	dst := *src
+	dst.Status = LAND
+	props := FDUMigrationProperties{Source: nodeid, Destination: destinationid}
+	src.MigrationProperties = &props
+	dst.MigrationProperties = &props
+
+	return c.waitState(ctx, destinationid, instanceid, from, RUN, func() error {
+		err := desired.AddNodeFDU(c.sysid, c.tenantid, destinationid, dst.FDUID, instanceid, dst)
+		if err != nil {
+			return err
+		}
+		return desired.AddNodeFDU(c.sysid, c.tenantid, nodeid, src.FDUID, instanceid, *src)
+	})
+}
+
+// Instantiate defines, configures and starts an instance of the FDU in the node, returns the record of the running instance
+func (c *FIMClient) Instantiate(ctx context.Context, fduid string, nodeid string) (*FDURecord, error) {
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+
+	record, err := c.Define(ctx, fduid, nodeid)
+	if err != nil {
+		return nil, err
+	}
+	_, err = c.Configure(ctx, record.UUID)
+	if err != nil {
+		return nil, err
+	}
+	return c.Start(ctx, record.UUID, "")
+}
+
+// Terminate stops, cleans and undefines the instance, starting from its current state
+func (c *FIMClient) Terminate(ctx context.Context, instanceid string) error {
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+	gad := c.connector.Global.Actual.WithContext(ctx)
+
+	nodeid, err := gad.GetFDUInstanceNode(c.sysid, c.tenantid, instanceid)
+	if err != nil {
+		return err
+	}
+	record, err := gad.GetNodeFDUInstance(c.sysid, c.tenantid, nodeid, instanceid)
+	if err != nil {
+		return err
+	}
+	switch record.Status {
+	case RUN, PAUSE, STARTING:
+		record, err = c.Stop(ctx, instanceid)
+		if err != nil {
+			return err
+		}
+	}
+	if record.Status == CONFIGURE {
+		_, err = c.Clean(ctx, instanceid)
+		if err != nil {
+			return err
+		}
+	}
+	return c.Undefine(ctx, instanceid)
+}
+
+// context applies the default timeout to ctx if it has no deadline
+func (c *FIMClient) context(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok || c.Timeout == 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, c.Timeout)
+}
+
+// changeState writes the status in the Global Desired record of the instance and waits the instance to reach the state in Global Actual
+func (c *FIMClient) changeState(ctx context.Context, instanceid string, status string, state string) (*FDURecord, error) {
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+	actual := c.connector.Global.Actual.WithContext(ctx)
+	desired := c.connector.Global.Desired.WithContext(ctx)
+
+	nodeid, err := actual.GetFDUInstanceNode(c.sysid, c.tenantid, instanceid)
+	if err != nil {
+		return nil, err
+	}
+	record, err := actual.GetNodeFDUInstance(c.sysid, c.tenantid, nodeid, instanceid)
+	if err != nil {
+		return nil, err
+	}
+	from := record.Status
+	err = record.SetStatus(status)
+	if err != nil {
+		return nil, err
+	}
+	return c.waitState(ctx, nodeid, instanceid, from, state, func() error {
+		return desired.AddNodeFDU(c.sysid, c.tenantid, nodeid, record.FDUID, instanceid, *record)
+	})
+}
+
+// waitState calls action and waits the instance to reach the state in Global Actual, the empty state waits the record to be removed.
+// It fails if the context is done or if the instance goes in ERROR state, unless from, the status before action, is ERROR too
+func (c *FIMClient) waitState(ctx context.Context, nodeid string, instanceid string, from string, state string, action func() error) (*FDURecord, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	// cancelling the context removes the subscription
+	defer cancel()
+	gad := c.connector.Global.Actual.WithContext(ctx)
+
+	var mu sync.Mutex
+	done := make(chan struct{})
+	finished := false
+	var result *FDURecord
+	var rerr error
+	finish := func(record *FDURecord, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if finished {
+			return
+		}
+		finished = true
+		result = record
+		rerr = err
+		close(done)
+	}
+	check := func(record *FDURecord, removed bool) {
+		switch {
+		case removed && state == "":
+			finish(nil, nil)
+		case removed:
+			finish(nil, &FError{"FDU instance " + instanceid + " was removed", ErrNotFound})
+		case record.Status == state:
+			finish(record, nil)
+		case record.Status == ERROR && from != ERROR:
+			finish(record, &FError{"FDU instance " + instanceid + " is in ERROR state", newEvalError(record.ErrorCode, record.ErrorMsg)})
+		}
+	}
+
+	_, err := gad.ObserveNodeFDUInstance(c.sysid, c.tenantid, nodeid, instanceid, check)
+	if err != nil {
+		return nil, err
+	}
+	err = action()
+	if err != nil {
+		return nil, err
+	}
+	// the instance may have reached the state before the subscription
+	record, err := gad.GetNodeFDUInstance(c.sysid, c.tenantid, nodeid, instanceid)
+	switch {
+	case err == nil:
+		check(record, false)
+	case errors.Is(err, ErrNotFound):
+		if state == "" {
+			check(nil, true)
+		}
+	default:
+		return nil, err
+	}
+
+	select {
+	case <-done:
+		return result, rerr
+	case <-ctx.Done():
+		target := state
+		if target == "" {
+			target = "removed"
+		}
+		return nil, &FError{"FDU instance " + instanceid + " is not " + target, ctx.Err()}
+	}
+}
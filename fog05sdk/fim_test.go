@@ -0,0 +1,180 @@
+/*
+* Copyright (c) 2014,2019 Contributors to the Eclipse Foundation
+* See the NOTICE file(s) distributed with this work for additional
+* information regarding copyright ownership.
+* This program and the accompanying materials are made available under the
+* terms of the Eclipse Public License 2.0 which is available at
+* http://www.eclipse.org/legal/epl-2.0, or the Apache License, Version 2.0
+* which is available at https://www.apache.org/licenses/LICENSE-2.0.
+* SPDX-License-Identifier: EPL-2.0 OR Apache-2.0
+* Contributors: Gabriele Baldoni, ADLINK Technology Inc.
+* golang APIs
+ */
+
+package fog05sdk_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	fog05sdk "github.com/eclipse-fog05/sdk-go/fog05sdk"
+	"github.com/eclipse-fog05/sdk-go/fog05sdk/fostest"
+)
+
+// fakeNodes plays the Agents of the given nodes of system s and tenant t: they define instances of any FDU with UUID i1,
+// start them and apply the desired states to Global Actual after a short delay, as the plugins would
+func fakeNodes(t *testing.T, yc *fog05sdk.YaksConnector, st *fostest.Store, nodes ...string) {
+	ga := yc.Global.Actual
+	later := func(f func() error) {
+		go func() {
+			time.Sleep(5 * time.Millisecond)
+			if err := f(); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	put := func(node string, r fog05sdk.FDURecord) {
+		later(func() error { return ga.AddNodeFDU("s", "t", node, r.FDUID, r.UUID, r) })
+	}
+	encode := func(r fog05sdk.RawEvalResult) string {
+		v, _ := json.Marshal(r)
+		return string(v)
+	}
+	register := func(p *fog05sdk.Path, eval fog05sdk.EvalHandler) {
+		if err := st.RegisterEval(context.Background(), p, eval); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, n := range nodes {
+		node := n
+		if err := ga.AddNodeInfo("s", "t", node, fog05sdk.NodeInfo{UUID: node}); err != nil {
+			t.Fatal(err)
+		}
+		p, _ := ga.GetAgentExecPath("s", "t", node, "define_fdu")
+		register(p, func(path *fog05sdk.Path, props fog05sdk.Properties) string {
+			r := fog05sdk.FDURecord{UUID: "i1", FDUID: props["fdu_id"], Status: fog05sdk.DEFINE}
+			put(node, r)
+			return encode(fog05sdk.NewEvalResult(r))
+		})
+		p, _ = ga.GetFDUStartEvalPath("s", "t", node, "f1", "i1")
+		register(p, func(path *fog05sdk.Path, props fog05sdk.Properties) string {
+			r, err := ga.GetNodeFDUInstance("s", "t", node, "i1")
+			if err != nil {
+				return encode(fog05sdk.NewEvalErrorResult(err))
+			}
+			r.Status = fog05sdk.RUN
+			put(node, *r)
+			return encode(fog05sdk.NewEvalResult(nil))
+		})
+		_, err := yc.Global.Desired.ObserveNodeFDU("s", "t", node, func(r *fog05sdk.FDURecord, removed bool) {
+			if removed {
+				return
+			}
+			switch r.Status {
+			case fog05sdk.CLEAN:
+				r.Status = fog05sdk.DEFINE
+			case fog05sdk.STOP:
+				r.Status = fog05sdk.CONFIGURE
+			case fog05sdk.RESUME, fog05sdk.LAND:
+				r.Status = fog05sdk.RUN
+			case fog05sdk.TAKEOFF:
+				if err := ga.RemoveNodeFDU("s", "t", node, r.FDUID, r.UUID); err != nil {
+					t.Error(err)
+				}
+				return
+			case fog05sdk.UNDEFINE:
+				later(func() error { return ga.RemoveNodeFDU("s", "t", node, r.FDUID, r.UUID) })
+				return
+			}
+			put(node, *r)
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestFIMClientLifecycle(t *testing.T) {
+	yc, st := fostest.NewConnector()
+	fakeNodes(t, yc, st, "n1", "n2")
+	subs := st.Subscriptions()
+	c := fog05sdk.NewFIMClient(yc, "s", "t")
+	c.Timeout = 2 * time.Second
+	ctx := context.Background()
+
+	r, err := c.Instantiate(ctx, "f1", "n1")
+	if err != nil || r.UUID != "i1" || r.Status != fog05sdk.RUN {
+		t.Fatalf("Instantiate = %+v, %v, want i1 running", r, err)
+	}
+	steps := []struct {
+		name   string
+		step   func() (*fog05sdk.FDURecord, error)
+		status string
+	}{
+		{"Pause", func() (*fog05sdk.FDURecord, error) { return c.Pause(ctx, "i1") }, fog05sdk.PAUSE},
+		{"Resume", func() (*fog05sdk.FDURecord, error) { return c.Resume(ctx, "i1") }, fog05sdk.RUN},
+		{"Stop", func() (*fog05sdk.FDURecord, error) { return c.Stop(ctx, "i1") }, fog05sdk.CONFIGURE},
+		{"Start", func() (*fog05sdk.FDURecord, error) { return c.Start(ctx, "i1", "") }, fog05sdk.RUN},
+		{"Migrate", func() (*fog05sdk.FDURecord, error) { return c.Migrate(ctx, "i1", "n2") }, fog05sdk.RUN},
+	}
+	for _, s := range steps {
+		r, err := s.step()
+		if err != nil || r.Status != s.status {
+			t.Fatalf("%s = %+v, %v, want status %s", s.name, r, err, s.status)
+		}
+	}
+	if n, err := yc.Global.Actual.GetFDUInstanceNode("s", "t", "i1"); err != nil || n != "n2" {
+		t.Errorf("node of the migrated instance = %s, %v, want n2", n, err)
+	}
+
+	if err := c.Terminate(ctx, "i1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := yc.Global.Actual.GetFDUInstanceNode("s", "t", "i1"); !errors.Is(err, fog05sdk.ErrNotFound) {
+		t.Errorf("instance after Terminate: %v, want ErrNotFound", err)
+	}
+	// the subscriptions are removed after the FIMClient calls return, when their context is cancelled
+	eventually(t, "the removal of the FIMClient subscriptions", func() bool { return st.Subscriptions() == subs })
+}
+
+func TestFIMClientFailures(t *testing.T) {
+	yc, st := fostest.NewConnector()
+	ga := yc.Global.Actual
+	c := fog05sdk.NewFIMClient(yc, "s", "t")
+	c.Timeout = 50 * time.Millisecond
+	ctx := context.Background()
+	// no plugin applies the desired states of n3
+	if err := ga.AddNodeFDU("s", "t", "n3", "f1", "i3", fog05sdk.FDURecord{UUID: "i3", FDUID: "f1", Status: fog05sdk.RUN}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Pause(ctx, "i3"); !errors.Is(err, fog05sdk.ErrTimeout) {
+		t.Errorf("Pause never applied = %v, want ErrTimeout", err)
+	}
+
+	_, err := yc.Global.Desired.ObserveNodeFDU("s", "t", "n3", func(r *fog05sdk.FDURecord, removed bool) {
+		if removed || r.Status != fog05sdk.STOP {
+			return
+		}
+		r.Status = fog05sdk.ERROR
+		code, msg := 11, "cannot stop"
+		r.ErrorCode, r.ErrorMsg = &code, &msg
+		go ga.AddNodeFDU("s", "t", "n3", r.FDUID, r.UUID, *r)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var eerr *fog05sdk.EvalError
+	if _, err := c.Stop(ctx, "i3"); !errors.As(err, &eerr) || eerr.Code != 11 {
+		t.Errorf("Stop failing in the node = %v, want the EvalError of the record", err)
+	}
+	if _, err := c.Define(ctx, "f1", "n4"); !errors.Is(err, fog05sdk.ErrNotFound) {
+		t.Errorf("Define in a node without Agent = %v, want ErrNotFound", err)
+	}
+	if _, err := c.Configure(ctx, "i4"); !errors.Is(err, fog05sdk.ErrNotFound) {
+		t.Errorf("Configure of a missing instance = %v, want ErrNotFound", err)
+	}
+	eventually(t, "the removal of the FIMClient subscriptions", func() bool { return st.Subscriptions() == 1 })
+}
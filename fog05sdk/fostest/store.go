@@ -0,0 +1,322 @@
+/*
+* Copyright (c) 2014,2019 Contributors to the Eclipse Foundation
+* See the NOTICE file(s) distributed with this work for additional
+* information regarding copyright ownership.
+* This program and the accompanying materials are made available under the
+* terms of the Eclipse Public License 2.0 which is available at
+* http://www.eclipse.org/legal/epl-2.0, or the Apache License, Version 2.0
+* which is available at https://www.apache.org/licenses/LICENSE-2.0.
+* SPDX-License-Identifier: EPL-2.0 OR Apache-2.0
+* Contributors: Gabriele Baldoni, ADLINK Technology Inc.
+* golang APIs
+ */
+
+// Package fostest provides an in-process stand-in for YAKS, to run the SDK in tests without a router
+package fostest
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	fog05sdk "github.com/eclipse-fog05/sdk-go/fog05sdk"
+)
+
+type subscription struct {
+	selector *regexp.Regexp
+	listener func([]fog05sdk.Change)
+}
+
+type eval struct {
+	path    *fog05sdk.Path
+	handler fog05sdk.EvalHandler
+}
+
+// Store is an in-memory fog05sdk.Store, subscriptions are notified synchronously by the goroutine making the change
+type Store struct {
+	mu            sync.Mutex
+	values        map[string]string
+	evals         map[string]eval
+	subscriptions map[int]subscription
+	nextID        int
+	closed        bool
+}
+
+// NewStore returns a new empty Store
+func NewStore() *Store {
+	return &Store{values: map[string]string{}, evals: map[string]eval{}, subscriptions: map[int]subscription{}}
+}
+
+// NewConnector returns a fog05sdk.YaksConnector built on a new empty Store, together with the Store
+func NewConnector() (*fog05sdk.YaksConnector, *Store) {
+	st := NewStore()
+	return fog05sdk.NewConnector(st), st
+}
+
+// Get returns the values stored at the paths matching the selector, and the results of the evals whose path matches the selector.
+// Evals receive the properties of the selector, in the (k1=v1;k2=v2) form generated by fog05sdk.Dict2Args
+func (st *Store) Get(ctx context.Context, selector *fog05sdk.Selector) ([]fog05sdk.Entry, error) {
+	re, err := compileSelector(selector.Path())
+	if err != nil {
+		return nil, err
+	}
+	if ctx.Err() != nil {
+		return nil, contextError(ctx)
+	}
+
+	st.mu.Lock()
+	if st.closed {
+		st.mu.Unlock()
+		return nil, &fog05sdk.FError{Msg: "Store is closed", Cause: fog05sdk.ErrNotConnected}
+	}
+	entries := []fog05sdk.Entry{}
+	for k, v := range st.values {
+		if re.MatchString(k) {
+			p, _ := fog05sdk.NewPath(k)
+			entries = append(entries, fog05sdk.Entry{Path: p, Value: v})
+		}
+	}
+	evals := []eval{}
+	for k, e := range st.evals {
+		if re.MatchString(k) {
+			evals = append(evals, e)
+		}
+	}
+	st.mu.Unlock()
+
+	props := ParseProperties(selector.Properties())
+	for _, e := range evals {
+		v, err := evaluate(ctx, e, props)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, fog05sdk.Entry{Path: e.path, Value: v})
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Path.ToString() < entries[j].Path.ToString() })
+	return entries, nil
+}
+
+// Put stores the value and notifies the matching subscriptions with a PUT change
+func (st *Store) Put(ctx context.Context, path *fog05sdk.Path, value string) error {
+	if ctx.Err() != nil {
+		return contextError(ctx)
+	}
+	st.mu.Lock()
+	if st.closed {
+		st.mu.Unlock()
+		return &fog05sdk.FError{Msg: "Store is closed", Cause: fog05sdk.ErrNotConnected}
+	}
+	st.values[path.ToString()] = value
+	listeners := st.matchingListeners(path.ToString())
+	st.mu.Unlock()
+
+	notify(listeners, fog05sdk.Change{Path: path, Kind: fog05sdk.PUT, Time: uint64(time.Now().UnixNano()), Value: value})
+	return nil
+}
+
+// Remove removes the value and notifies the matching subscriptions with a REMOVE change, removing a missing path is not an error
+func (st *Store) Remove(ctx context.Context, path *fog05sdk.Path) error {
+	if ctx.Err() != nil {
+		return contextError(ctx)
+	}
+	st.mu.Lock()
+	if st.closed {
+		st.mu.Unlock()
+		return &fog05sdk.FError{Msg: "Store is closed", Cause: fog05sdk.ErrNotConnected}
+	}
+	_, found := st.values[path.ToString()]
+	delete(st.values, path.ToString())
+	var listeners []func([]fog05sdk.Change)
+	if found {
+		listeners = st.matchingListeners(path.ToString())
+	}
+	st.mu.Unlock()
+
+	notify(listeners, fog05sdk.Change{Path: path, Kind: fog05sdk.REMOVE, Time: uint64(time.Now().UnixNano())})
+	return nil
+}
+
+// Subscribe registers the listener for the changes on the paths matching the selector
+func (st *Store) Subscribe(ctx context.Context, selector *fog05sdk.Selector, listener func([]fog05sdk.Change)) (*fog05sdk.SubscriptionID, error) {
+	re, err := compileSelector(selector.Path())
+	if err != nil {
+		return nil, err
+	}
+	if ctx.Err() != nil {
+		return nil, contextError(ctx)
+	}
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if st.closed {
+		return nil, &fog05sdk.FError{Msg: "Store is closed", Cause: fog05sdk.ErrNotConnected}
+	}
+	st.nextID++
+	st.subscriptions[st.nextID] = subscription{selector: re, listener: listener}
+	return &fog05sdk.SubscriptionID{Handle: st.nextID}, nil
+}
+
+// Unsubscribe removes the subscription
+func (st *Store) Unsubscribe(ctx context.Context, sid *fog05sdk.SubscriptionID) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	id, ok := sid.Handle.(int)
+	if !ok {
+		return &fog05sdk.FError{Msg: "Subscription not found", Cause: fog05sdk.ErrNotFound}
+	}
+	if _, found := st.subscriptions[id]; !found {
+		return &fog05sdk.FError{Msg: "Subscription not found", Cause: fog05sdk.ErrNotFound}
+	}
+	delete(st.subscriptions, id)
+	return nil
+}
+
+// RegisterEval registers the eval at the given path, replacing any eval already registered there
+func (st *Store) RegisterEval(ctx context.Context, path *fog05sdk.Path, handler fog05sdk.EvalHandler) error {
+	if ctx.Err() != nil {
+		return contextError(ctx)
+	}
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if st.closed {
+		return &fog05sdk.FError{Msg: "Store is closed", Cause: fog05sdk.ErrNotConnected}
+	}
+	st.evals[path.ToString()] = eval{path: path, handler: handler}
+	return nil
+}
+
+// UnregisterEval removes the eval registered at the given path
+func (st *Store) UnregisterEval(ctx context.Context, path *fog05sdk.Path) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	delete(st.evals, path.ToString())
+	return nil
+}
+
+// Close closes the Store, removing all the subscriptions and evals, any further operation fails
+func (st *Store) Close() error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.closed = true
+	st.subscriptions = map[int]subscription{}
+	st.evals = map[string]eval{}
+	return nil
+}
+
+// Paths returns the stored paths, sorted
+func (st *Store) Paths() []string {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	paths := []string{}
+	for k := range st.values {
+		paths = append(paths, k)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// EvalPaths returns the paths of the registered evals, sorted
+func (st *Store) EvalPaths() []string {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	paths := []string{}
+	for k := range st.evals {
+		paths = append(paths, k)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// Subscriptions returns the number of active subscriptions
+func (st *Store) Subscriptions() int {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	return len(st.subscriptions)
+}
+
+func (st *Store) matchingListeners(path string) []func([]fog05sdk.Change) {
+	ids := []int{}
+	for id, s := range st.subscriptions {
+		if s.selector.MatchString(path) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+	listeners := []func([]fog05sdk.Change){}
+	for _, id := range ids {
+		listeners = append(listeners, st.subscriptions[id].listener)
+	}
+	return listeners
+}
+
+// evaluate calls the eval handler, returning early if the context is done before the handler returns
+func evaluate(ctx context.Context, e eval, props fog05sdk.Properties) (string, error) {
+	if ctx.Done() == nil {
+		return e.handler(e.path, props), nil
+	}
+	res := make(chan string, 1)
+	go func() {
+		res <- e.handler(e.path, props)
+	}()
+	select {
+	case v := <-res:
+		return v, nil
+	case <-ctx.Done():
+		return "", contextError(ctx)
+	}
+}
+
+func contextError(ctx context.Context) error {
+	return &fog05sdk.FError{Msg: "Context done", Cause: ctx.Err()}
+}
+
+func notify(listeners []func([]fog05sdk.Change), change fog05sdk.Change) {
+	for _, l := range listeners {
+		l([]fog05sdk.Change{change})
+	}
+}
+
+// ParseProperties parses eval properties in the k1=v1;k2=v2 form, the enclosing parentheses are optional
+func ParseProperties(s string) fog05sdk.Properties {
+	props := fog05sdk.Properties{}
+	s = strings.TrimSuffix(strings.TrimPrefix(s, "("), ")")
+	for _, kv := range strings.Split(s, ";") {
+		i := strings.Index(kv, "=")
+		if i > 0 {
+			props[kv[:i]] = kv[i+1:]
+		}
+	}
+	return props
+}
+
+// Match returns true if the path matches the selector path, where * matches any character but / and ** matches any character
+func Match(selector string, path string) bool {
+	re, err := compileSelector(selector)
+	if err != nil {
+		return false
+	}
+	return re.MatchString(path)
+}
+
+func compileSelector(selector string) (*regexp.Regexp, error) {
+	selector = strings.TrimSuffix(selector, "/")
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(selector); i++ {
+		if selector[i] == '*' {
+			if i+1 < len(selector) && selector[i+1] == '*' {
+				b.WriteString(".*")
+				i++
+			} else {
+				b.WriteString("[^/]*")
+			}
+			continue
+		}
+		b.WriteString(regexp.QuoteMeta(string(selector[i])))
+	}
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
@@ -0,0 +1,203 @@
+/*
+* Copyright (c) 2014,2019 Contributors to the Eclipse Foundation
+* See the NOTICE file(s) distributed with this work for additional
+* information regarding copyright ownership.
+* This program and the accompanying materials are made available under the
+* terms of the Eclipse Public License 2.0 which is available at
+* http://www.eclipse.org/legal/epl-2.0, or the Apache License, Version 2.0
+* which is available at https://www.apache.org/licenses/LICENSE-2.0.
+* SPDX-License-Identifier: EPL-2.0 OR Apache-2.0
+* Contributors: Gabriele Baldoni, ADLINK Technology Inc.
+* golang APIs
+ */
+
+package fostest
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	fog05sdk "github.com/eclipse-fog05/sdk-go/fog05sdk"
+)
+
+func path(t *testing.T, p string) *fog05sdk.Path {
+	t.Helper()
+	res, err := fog05sdk.NewPath(p)
+	if err != nil {
+		t.Fatalf("NewPath(%q): %v", p, err)
+	}
+	return res
+}
+
+func selector(t *testing.T, s string) *fog05sdk.Selector {
+	t.Helper()
+	res, err := fog05sdk.NewSelector(s)
+	if err != nil {
+		t.Fatalf("NewSelector(%q): %v", s, err)
+	}
+	return res
+}
+
+func TestCompileSelector(t *testing.T) {
+	tests := []struct {
+		selector string
+		path     string
+		match    bool
+	}{
+		{"/agfos/0/tenants/0", "/agfos/0/tenants/0", true},
+		{"/agfos/0/tenants/0", "/agfos/0/tenants/01", false},
+		{"/agfos/0/nodes/*/info", "/agfos/0/nodes/n1/info", true},
+		{"/agfos/0/nodes/*/info", "/agfos/0/nodes/n1/x/info", false},
+		{"/agfos/0/nodes/*", "/agfos/0/nodes/n1/info", false},
+		{"/agfos/0/nodes/**", "/agfos/0/nodes/n1/info", true},
+		{"/agfos/0/nodes/**/info", "/agfos/0/nodes/n1/fdu/f1/info", true},
+		{"/agfos/0/nodes/**/info", "/agfos/0/nodes/n1/fdu/f1/status", false},
+		{"/agfos/0/nodes/n*", "/agfos/0/nodes/n1", true},
+		{"/agfos/0/nodes/n*", "/agfos/0/nodes/m1", false},
+		{"/agfos/0/nodes/n1/", "/agfos/0/nodes/n1", true},
+		{"/agfos/0/nodes/n.1", "/agfos/0/nodes/nx1", false},
+	}
+	for _, tt := range tests {
+		re, err := compileSelector(tt.selector)
+		if err != nil {
+			t.Fatalf("compileSelector(%q): %v", tt.selector, err)
+		}
+		if got := re.MatchString(tt.path); got != tt.match {
+			t.Errorf("compileSelector(%q) match %q = %v, want %v", tt.selector, tt.path, got, tt.match)
+		}
+		if got := Match(tt.selector, tt.path); got != tt.match {
+			t.Errorf("Match(%q, %q) = %v, want %v", tt.selector, tt.path, got, tt.match)
+		}
+	}
+}
+
+func TestParseProperties(t *testing.T) {
+	tests := []struct {
+		in   string
+		want fog05sdk.Properties
+	}{
+		{"", fog05sdk.Properties{}},
+		{"()", fog05sdk.Properties{}},
+		{"k1=v1", fog05sdk.Properties{"k1": "v1"}},
+		{"(k1=v1;k2=v2)", fog05sdk.Properties{"k1": "v1", "k2": "v2"}},
+		{"k1=v1;k2=v2", fog05sdk.Properties{"k1": "v1", "k2": "v2"}},
+		{"k1={\"a\":1};k2=", fog05sdk.Properties{"k1": "{\"a\":1}", "k2": ""}},
+		{"k1=a=b", fog05sdk.Properties{"k1": "a=b"}},
+		{"noval;=v;k=v", fog05sdk.Properties{"k": "v"}},
+	}
+	for _, tt := range tests {
+		if got := ParseProperties(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseProperties(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestGetEvals(t *testing.T) {
+	st := NewStore()
+	ctx := context.Background()
+	handler := func(name string) fog05sdk.EvalHandler {
+		return func(p *fog05sdk.Path, props fog05sdk.Properties) string {
+			return name + ":" + props["k"]
+		}
+	}
+	if err := st.RegisterEval(ctx, path(t, "/alfos/n1/plugins/p1/exec/a"), handler("a")); err != nil {
+		t.Fatal(err)
+	}
+	if err := st.RegisterEval(ctx, path(t, "/alfos/n1/plugins/p1/exec/b"), handler("b")); err != nil {
+		t.Fatal(err)
+	}
+	if err := st.Put(ctx, path(t, "/alfos/n1/plugins/p1/info"), "info"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		selector string
+		want     []string
+	}{
+		{"/alfos/n1/plugins/p1/exec/a?(k=1)", []string{"a:1"}},
+		{"/alfos/n1/plugins/p1/exec/b?(k=2)", []string{"b:2"}},
+		{"/alfos/n1/plugins/p1/exec/*?(k=3)", []string{"a:3", "b:3"}},
+		{"/alfos/n1/plugins/p1/exec/c?(k=4)", []string{}},
+		{"/alfos/n1/plugins/p1/**", []string{"a:", "b:", "info"}},
+	}
+	for _, tt := range tests {
+		entries, err := st.Get(ctx, selector(t, tt.selector))
+		if err != nil {
+			t.Fatalf("Get(%q): %v", tt.selector, err)
+		}
+		got := []string{}
+		for _, e := range entries {
+			got = append(got, e.Value)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Get(%q) = %v, want %v", tt.selector, got, tt.want)
+		}
+	}
+}
+
+func TestRemoveNotifications(t *testing.T) {
+	tests := []struct {
+		name   string
+		put    bool
+		remove string
+		want   []fog05sdk.ChangeKind
+	}{
+		{"existing", true, "/a/b/c", []fog05sdk.ChangeKind{fog05sdk.PUT, fog05sdk.REMOVE}},
+		{"missing", false, "/a/b/c", []fog05sdk.ChangeKind{}},
+		{"other", true, "/a/b/d", []fog05sdk.ChangeKind{fog05sdk.PUT}},
+	}
+	for _, tt := range tests {
+		st := NewStore()
+		ctx := context.Background()
+		got := []fog05sdk.ChangeKind{}
+		_, err := st.Subscribe(ctx, selector(t, "/a/**"), func(cs []fog05sdk.Change) {
+			for _, c := range cs {
+				got = append(got, c.Kind)
+			}
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tt.put {
+			if err := st.Put(ctx, path(t, "/a/b/c"), "v"); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := st.Remove(ctx, path(t, tt.remove)); err != nil {
+			t.Fatalf("%s: Remove: %v", tt.name, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: changes = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestClosedStore(t *testing.T) {
+	st := NewStore()
+	ctx := context.Background()
+	if err := st.Close(); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		op   func() error
+	}{
+		{"Get", func() error { _, err := st.Get(ctx, selector(t, "/a/**")); return err }},
+		{"Put", func() error { return st.Put(ctx, path(t, "/a/b"), "v") }},
+		{"Remove", func() error { return st.Remove(ctx, path(t, "/a/b")) }},
+		{"Subscribe", func() error {
+			_, err := st.Subscribe(ctx, selector(t, "/a/**"), func([]fog05sdk.Change) {})
+			return err
+		}},
+		{"RegisterEval", func() error {
+			return st.RegisterEval(ctx, path(t, "/a/b"), func(*fog05sdk.Path, fog05sdk.Properties) string { return "" })
+		}},
+	}
+	for _, tt := range tests {
+		if err := tt.op(); !errors.Is(err, fog05sdk.ErrNotConnected) {
+			t.Errorf("%s on a closed store = %v, want ErrNotConnected", tt.name, err)
+		}
+	}
+}
@@ -0,0 +1,215 @@
+/*
+* Copyright (c) 2014,2019 Contributors to the Eclipse Foundation
+* See the NOTICE file(s) distributed with this work for additional
+* information regarding copyright ownership.
+* This program and the accompanying materials are made available under the
+* terms of the Eclipse Public License 2.0 which is available at
+* http://www.eclipse.org/legal/epl-2.0, or the Apache License, Version 2.0
+* which is available at https://www.apache.org/licenses/LICENSE-2.0.
+* SPDX-License-Identifier: EPL-2.0 OR Apache-2.0
+* Contributors: Gabriele Baldoni, ADLINK Technology Inc.
+* golang APIs
+ */
+
+package fog05sdk
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// EarthRadius is the mean radius of the Earth in km, used to compute great-circle distances
+const EarthRadius float64 = 6371.0
+
+// Distance returns the great-circle distance in km between two positions, computed with the haversine formula
+func Distance(from PositionSpec, to PositionSpec) float64 {
+	lat1 := from.Latitude * math.Pi / 180
+	lat2 := to.Latitude * math.Pi / 180
+	dlat := lat2 - lat1
+	dlon := (to.Longitude - from.Longitude) * math.Pi / 180
+	a := math.Sin(dlat/2)*math.Sin(dlat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dlon/2)*math.Sin(dlon/2)
+	return 2 * EarthRadius * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// Validate returns an error if the latitude is not in [-90, 90] or the longitude is not in [-180, 180]
+func (p PositionSpec) Validate() error {
+	if math.IsNaN(p.Latitude) || p.Latitude < -90 || p.Latitude > 90 {
+		return &FError{fmt.Sprintf("Latitude %v is not between -90 and 90", p.Latitude), nil}
+	}
+	if math.IsNaN(p.Longitude) || p.Longitude < -180 || p.Longitude > 180 {
+		return &FError{fmt.Sprintf("Longitude %v is not between -180 and 180", p.Longitude), nil}
+	}
+	return nil
+}
+
+// Coordinates parses the latitude and longitude of the FDU position, that are strings, and validates them
+func (p *FDUPosition) Coordinates() (PositionSpec, error) {
+	lat, err := strconv.ParseFloat(p.Latitude, 64)
+	if err != nil {
+		return PositionSpec{}, &FError{"Latitude " + strconv.Quote(p.Latitude) + " is not a number", nil}
+	}
+	lon, err := strconv.ParseFloat(p.Longitude, 64)
+	if err != nil {
+		return PositionSpec{}, &FError{"Longitude " + strconv.Quote(p.Longitude) + " is not a number", nil}
+	}
+	pos := PositionSpec{Latitude: lat, Longitude: lon}
+	return pos, pos.Validate()
+}
+
+// GeoDistance is a node that satisfies the geographical requirements of an FDU, with its distances in km
+type GeoDistance struct {
+	NodeID string
+
+	// Distance is the distance from the required position, nil if the FDU has no position requirement
+	Distance *float64
+
+	// NeighbourDistance is the distance from the neighbour, nil if the FDU has no proximity requirement
+	NeighbourDistance *float64
+}
+
+// GeographicalFilter evaluates the geographical requirements of an FDU against the node positions.
+// A requirement with radius 0 is unbounded, it excludes only the nodes without position and gives the distances for scoring
+type GeographicalFilter struct {
+	position        *PositionSpec
+	radius          float64
+	neighbour       *PositionSpec
+	neighbourRadius float64
+}
+
+// NewGeographicalFilter returns the filter for the geographical requirements of the FDU.
+// The proximity neighbour is either one of the nodes or an FDU instance, locate returns the node of an FDU instance and can be nil
+// if the neighbour is always a node
+func NewGeographicalFilter(fdu *FDU, nodes []NodeResources, locate func(instanceid string) (string, error)) (*GeographicalFilter, error) {
+	g := GeographicalFilter{}
+	gr := fdu.GeographicalRequirements
+	if gr == nil {
+		return &g, nil
+	}
+	if gr.Position != nil {
+		pos, err := gr.Position.Coordinates()
+		if err != nil {
+			return nil, err
+		}
+		g.position = &pos
+		g.radius = gr.Position.Radius
+	}
+	if gr.Proximity != nil {
+		pos, err := neighbourPosition(gr.Proximity.Neighbor, nodes, locate)
+		if err != nil {
+			return nil, err
+		}
+		g.neighbour = pos
+		g.neighbourRadius = gr.Proximity.Radius
+	}
+	return &g, nil
+}
+
+// neighbourPosition returns the position of the neighbour node, or of the node of the neighbour FDU instance
+func neighbourPosition(neighbour string, nodes []NodeResources, locate func(string) (string, error)) (*PositionSpec, error) {
+	nodeid := neighbour
+	if findNode(nodes, neighbour) == nil {
+		if locate == nil {
+			return nil, &FError{"Neighbour " + neighbour + " not found", ErrNotFound}
+		}
+		id, err := locate(neighbour)
+		if err != nil {
+			return nil, &FError{"Neighbour " + neighbour + " not found", err}
+		}
+		nodeid = id
+	}
+	n := findNode(nodes, nodeid)
+	if n == nil {
+		return nil, &FError{"Node " + nodeid + " of neighbour " + neighbour + " not found", ErrNotFound}
+	}
+	if n.Info.Position == nil {
+		return nil, &FError{"Node " + nodeid + " of neighbour " + neighbour + " has no position", nil}
+	}
+	return n.Info.Position, n.Info.Position.Validate()
+}
+
+func findNode(nodes []NodeResources, nodeid string) *NodeResources {
+	for i := range nodes {
+		if nodes[i].NodeID == nodeid {
+			return &nodes[i]
+		}
+	}
+	return nil
+}
+
+// Distances returns the distances of the node from the required position and the neighbour, or an error if the node does not satisfy the requirements
+func (g *GeographicalFilter) Distances(node *NodeResources) (GeoDistance, error) {
+	d := GeoDistance{NodeID: node.NodeID}
+	if g.position == nil && g.neighbour == nil {
+		return d, nil
+	}
+	if node.Info.Position == nil {
+		return d, &FError{"Node has no position", nil}
+	}
+	pos := *node.Info.Position
+	err := pos.Validate()
+	if err != nil {
+		return d, err
+	}
+	if g.position != nil {
+		dist := Distance(*g.position, pos)
+		if g.radius > 0 && dist > g.radius {
+			return d, &FError{fmt.Sprintf("Node is %.3f km from the required position, radius is %v km", dist, g.radius), nil}
+		}
+		d.Distance = &dist
+	}
+	if g.neighbour != nil {
+		dist := Distance(*g.neighbour, pos)
+		if g.neighbourRadius > 0 && dist > g.neighbourRadius {
+			return d, &FError{fmt.Sprintf("Node is %.3f km from the neighbour, radius is %v km", dist, g.neighbourRadius), nil}
+		}
+		d.NeighbourDistance = &dist
+	}
+	return d, nil
+}
+
+// Filter is a NodeFilter excluding the nodes that do not satisfy the geographical requirements
+func (g *GeographicalFilter) Filter(fdu *FDU, node *NodeResources) error {
+	_, err := g.Distances(node)
+	return err
+}
+
+// Eligible returns the nodes that satisfy the geographical requirements with their distances, the PlacementError lists why the other nodes were excluded
+func (g *GeographicalFilter) Eligible(fduid string, nodes []NodeResources) ([]GeoDistance, *PlacementError) {
+	perr := &PlacementError{FDUID: fduid, Reasons: map[string]error{}}
+	eligible := []GeoDistance{}
+	for i := range nodes {
+		d, err := g.Distances(&nodes[i])
+		if err != nil {
+			perr.Reasons[nodes[i].NodeID] = err
+			continue
+		}
+		eligible = append(eligible, d)
+	}
+	return eligible, perr
+}
+
+// GeographicalFilter returns the filter for the geographical requirements of the FDU, the proximity neighbour can be a node or an FDU instance
+func (s *Scheduler) GeographicalFilter(ctx context.Context, fdu *FDU, nodes []NodeResources) (*GeographicalFilter, error) {
+	gad := s.connector.Global.Actual.WithContext(ctx)
+	locate := func(instanceid string) (string, error) {
+		return gad.GetFDUInstanceNode(s.sysid, s.tenantid, instanceid)
+	}
+	return NewGeographicalFilter(fdu, nodes, locate)
+}
+
+// EligibleNodes returns the nodes that satisfy the geographical requirements of the FDU with their distances,
+// the PlacementError lists why the other nodes were excluded
+func (s *Scheduler) EligibleNodes(ctx context.Context, fdu *FDU) ([]GeoDistance, *PlacementError, error) {
+	nodes, err := s.Nodes(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	g, err := s.GeographicalFilter(ctx, fdu, nodes)
+	if err != nil {
+		return nil, nil, err
+	}
+	eligible, perr := g.Eligible(fdu.ID, nodes)
+	return eligible, perr, nil
+}
@@ -0,0 +1,104 @@
+/*
+* Copyright (c) 2014,2019 Contributors to the Eclipse Foundation
+* See the NOTICE file(s) distributed with this work for additional
+* information regarding copyright ownership.
+* This program and the accompanying materials are made available under the
+* terms of the Eclipse Public License 2.0 which is available at
+* http://www.eclipse.org/legal/epl-2.0, or the Apache License, Version 2.0
+* which is available at https://www.apache.org/licenses/LICENSE-2.0.
+* SPDX-License-Identifier: EPL-2.0 OR Apache-2.0
+* Contributors: Gabriele Baldoni, ADLINK Technology Inc.
+* golang APIs
+ */
+
+package fog05sdk
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestDistance(t *testing.T) {
+	quarter := math.Pi * EarthRadius / 2
+	tests := []struct {
+		name string
+		from PositionSpec
+		to   PositionSpec
+		want float64
+		tol  float64
+	}{
+		{"same point", PositionSpec{Latitude: 45.07, Longitude: 7.68}, PositionSpec{Latitude: 45.07, Longitude: 7.68}, 0, 1e-9},
+		{"equator quarter", PositionSpec{}, PositionSpec{Longitude: 90}, quarter, 1e-6},
+		{"pole", PositionSpec{}, PositionSpec{Latitude: 90}, quarter, 1e-6},
+		{"antipodes", PositionSpec{Latitude: 10, Longitude: 20}, PositionSpec{Latitude: -10, Longitude: -160}, 2 * quarter, 1e-6},
+		{"across the antimeridian", PositionSpec{Longitude: 179}, PositionSpec{Longitude: -179}, 2 * quarter / 90, 1e-6},
+		{"paris london", PositionSpec{Latitude: 48.8566, Longitude: 2.3522}, PositionSpec{Latitude: 51.5074, Longitude: -0.1278}, 343.5, 0.5},
+	}
+	for _, tt := range tests {
+		if got := Distance(tt.from, tt.to); math.Abs(got-tt.want) > tt.tol {
+			t.Errorf("%s: Distance() = %v, want %v", tt.name, got, tt.want)
+		}
+		if got, back := Distance(tt.from, tt.to), Distance(tt.to, tt.from); math.Abs(got-back) > 1e-9 {
+			t.Errorf("%s: Distance() is not symmetric, %v and %v", tt.name, got, back)
+		}
+	}
+}
+
+func TestGeographicalFilter(t *testing.T) {
+	node := func(id string, pos *PositionSpec) NodeResources {
+		return NodeResources{NodeID: id, Info: NodeInfo{UUID: id, Position: pos}}
+	}
+	nodes := []NodeResources{
+		node("paris", &PositionSpec{Latitude: 48.8566, Longitude: 2.3522}),
+		node("london", &PositionSpec{Latitude: 51.5074, Longitude: -0.1278}),
+		node("turin", &PositionSpec{Latitude: 45.0703, Longitude: 7.6869}),
+		node("nowhere", nil),
+	}
+	locate := func(instanceid string) (string, error) {
+		if instanceid == "i1" {
+			return "london", nil
+		}
+		return "", &FError{"Instance not found", ErrNotFound}
+	}
+	tests := []struct {
+		name string
+		req  *FDUGeographicalRequirements
+		want []string
+	}{
+		{"no requirements", nil, []string{"paris", "london", "turin", "nowhere"}},
+		{"position", &FDUGeographicalRequirements{Position: &FDUPosition{Latitude: "48.85", Longitude: "2.35", Radius: 400}}, []string{"paris", "london"}},
+		{"position without radius", &FDUGeographicalRequirements{Position: &FDUPosition{Latitude: "48.85", Longitude: "2.35"}}, []string{"paris", "london", "turin"}},
+		{"neighbour node", &FDUGeographicalRequirements{Proximity: &FDUProximity{Neighbor: "turin", Radius: 100}}, []string{"turin"}},
+		{"neighbour instance", &FDUGeographicalRequirements{Proximity: &FDUProximity{Neighbor: "i1", Radius: 400}}, []string{"paris", "london"}},
+		{"both", &FDUGeographicalRequirements{
+			Position:  &FDUPosition{Latitude: "45", Longitude: "7.7", Radius: 800},
+			Proximity: &FDUProximity{Neighbor: "i1", Radius: 400},
+		}, []string{"paris"}},
+	}
+	for _, tt := range tests {
+		fdu := FDU{ID: "fdu1", GeographicalRequirements: tt.req}
+		g, err := NewGeographicalFilter(&fdu, nodes, locate)
+		if err != nil {
+			t.Fatalf("%s: NewGeographicalFilter() = %v", tt.name, err)
+		}
+		eligible, _ := g.Eligible(fdu.ID, nodes)
+		got := []string{}
+		for _, d := range eligible {
+			got = append(got, d.NodeID)
+			if tt.req != nil && (tt.req.Position != nil) != (d.Distance != nil) {
+				t.Errorf("%s: node %s distance %v", tt.name, d.NodeID, d.Distance)
+			}
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: Eligible() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	for _, neighbour := range []string{"i2", "nowhere"} {
+		fdu := FDU{ID: "fdu1", GeographicalRequirements: &FDUGeographicalRequirements{Proximity: &FDUProximity{Neighbor: neighbour}}}
+		if _, err := NewGeographicalFilter(&fdu, nodes, locate); err == nil {
+			t.Errorf("NewGeographicalFilter() with neighbour %s = nil, want an error", neighbour)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+/*
+* Copyright (c) 2014,2019 Contributors to the Eclipse Foundation
+* See the NOTICE file(s) distributed with this work for additional
+* information regarding copyright ownership.
+* This program and the accompanying materials are made available under the
+* terms of the Eclipse Public License 2.0 which is available at
+* http://www.eclipse.org/legal/epl-2.0, or the Apache License, Version 2.0
+* which is available at https://www.apache.org/licenses/LICENSE-2.0.
+* SPDX-License-Identifier: EPL-2.0 OR Apache-2.0
+* Contributors: Gabriele Baldoni, ADLINK Technology Inc.
+* golang APIs
+ */
+
+package fog05sdk
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultHeartbeatInterval is the default interval of the heartbeats of a plugin
+const DefaultHeartbeatInterval = 5 * time.Second
+
+// DefaultHeartbeatTimeout is the default age after which the heartbeat of a plugin is expired
+const DefaultHeartbeatTimeout = 3 * DefaultHeartbeatInterval
+
+// HeartbeatRunning is the heartbeat status of a running plugin
+const HeartbeatRunning = "running"
+
+// heartbeat is the state of the heartbeats published by a plugin, done is closed when the publisher goroutine returns
+type heartbeat struct {
+	mu     sync.Mutex
+	status string
+	stop   chan struct{}
+	done   chan struct{}
+}
+
+// StartHeartbeat publishes the heartbeat of the plugin, with status HeartbeatRunning, then every HeartbeatInterval until StopHeartbeat
+func (pl *FOSPlugin) StartHeartbeat() error {
+	pl.heartbeatMu.Lock()
+	defer pl.heartbeatMu.Unlock()
+	if pl.heartbeat != nil {
+		return pl.setHeartbeatStatus(HeartbeatRunning)
+	}
+	err := pl.publishHeartbeat(HeartbeatRunning)
+	if err != nil {
+		return err
+	}
+	interval := pl.HeartbeatInterval
+	if interval <= 0 {
+		interval = DefaultHeartbeatInterval
+	}
+	hb := &heartbeat{status: HeartbeatRunning, stop: make(chan struct{}), done: make(chan struct{})}
+	pl.heartbeat = hb
+	go func() {
+		defer close(hb.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-hb.stop:
+				return
+			case <-ticker.C:
+				hb.mu.Lock()
+				status := hb.status
+				hb.mu.Unlock()
+				// a failed heartbeat is published again at the next tick
+				pl.publishHeartbeat(status)
+			}
+		}
+	}()
+	return nil
+}
+
+// SetHeartbeatStatus publishes a heartbeat with the given status, the next heartbeats have the same status
+func (pl *FOSPlugin) SetHeartbeatStatus(status string) error {
+	pl.heartbeatMu.Lock()
+	defer pl.heartbeatMu.Unlock()
+	return pl.setHeartbeatStatus(status)
+}
+
+// setHeartbeatStatus is SetHeartbeatStatus, the caller holds heartbeatMu
+func (pl *FOSPlugin) setHeartbeatStatus(status string) error {
+	if pl.heartbeat != nil {
+		pl.heartbeat.mu.Lock()
+		pl.heartbeat.status = status
+		pl.heartbeat.mu.Unlock()
+	}
+	return pl.publishHeartbeat(status)
+}
+
+// StopHeartbeat stops the heartbeats and removes the heartbeat of the plugin, after the heartbeat being published, if any
+func (pl *FOSPlugin) StopHeartbeat() error {
+	pl.heartbeatMu.Lock()
+	defer pl.heartbeatMu.Unlock()
+	if pl.heartbeat != nil {
+		close(pl.heartbeat.stop)
+		<-pl.heartbeat.done
+		pl.heartbeat = nil
+	}
+	return pl.connector.Local.Actual.RemoveNodePluginHeartbeat(pl.node, pl.UUID)
+}
+
+func (pl *FOSPlugin) publishHeartbeat(status string) error {
+	hb := PluginHeartbeat{Timestamp: time.Now().UnixNano() / int64(time.Millisecond), Status: status}
+	return pl.connector.Local.Actual.AddNodePluginHeartbeat(pl.node, pl.UUID, hb)
+}
+
+// isAlive returns false if the heartbeat of the plugin expired, the plugins that never published a heartbeat are alive.
+// It returns false with the error when the heartbeat cannot be read
+func (pl *FOSPlugin) isAlive(pluginid string) (bool, error) {
+	hb, err := pl.connector.Local.Actual.GetNodePluginHeartbeat(pl.node, pluginid)
+	if errors.Is(err, ErrNotFound) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	timeout := pl.HeartbeatTimeout
+	if timeout <= 0 {
+		timeout = DefaultHeartbeatTimeout
+	}
+	return time.Since(hb.Time()) <= timeout, nil
+}
+
+// StalePlugin is a plugin whose heartbeat expired, Plugin has only the UUID if the plugin record was already removed
+type StalePlugin struct {
+	Plugin    Plugin
+	Heartbeat PluginHeartbeat
+}
+
+// PluginWatcher detects the plugins of a node whose heartbeat expired, eg. because their process died without removing their record.
+// The plugins that never published a heartbeat are not considered
+type PluginWatcher struct {
+	connector *YaksConnector
+	node      string
+
+	// Timeout is the age after which a heartbeat is expired
+	Timeout time.Duration
+
+	// RemoveStale makes Check remove the record and the heartbeat of the stale plugins
+	RemoveStale bool
+}
+
+// NewPluginWatcher returns a PluginWatcher for the plugins of the given node
+func NewPluginWatcher(connector *YaksConnector, nodeid string) *PluginWatcher {
+	return &PluginWatcher{connector: connector, node: nodeid, Timeout: DefaultHeartbeatTimeout}
+}
+
+// Check returns the plugins of the node whose heartbeat expired, sorted by id, removing them if RemoveStale is set.
+// The stale plugins found are returned also with an error
+func (w *PluginWatcher) Check() ([]StalePlugin, error) {
+	lad := w.connector.Local.Actual
+	heartbeats, err := lad.GetNodePluginHeartbeats(w.node)
+	if err != nil {
+		return nil, err
+	}
+	ids := []string{}
+	for pid, hb := range heartbeats {
+		if time.Since(hb.Time()) > w.Timeout {
+			ids = append(ids, pid)
+		}
+	}
+	sort.Strings(ids)
+
+	stale := []StalePlugin{}
+	var errs []error
+	for _, pid := range ids {
+		pl, err := lad.GetNodePlugin(w.node, pid)
+		if errors.Is(err, ErrNotFound) {
+			pl = &Plugin{UUID: pid}
+		} else if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		stale = append(stale, StalePlugin{Plugin: *pl, Heartbeat: heartbeats[pid]})
+		if w.RemoveStale {
+			errs = appendError(errs, lad.RemoveNodePlugin(w.node, pid))
+			errs = appendError(errs, lad.RemoveNodePluginHeartbeat(w.node, pid))
+		}
+	}
+	return stale, multiError(errs)
+}
+
+// Watch runs Check every interval until the context is done, calling listener when stale plugins are found or Check fails
+func (w *PluginWatcher) Watch(ctx context.Context, interval time.Duration, listener func([]StalePlugin, error)) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			stale, err := w.Check()
+			if len(stale) > 0 || err != nil {
+				listener(stale, err)
+			}
+		}
+	}
+}
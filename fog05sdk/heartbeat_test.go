@@ -0,0 +1,127 @@
+/*
+* Copyright (c) 2014,2019 Contributors to the Eclipse Foundation
+* See the NOTICE file(s) distributed with this work for additional
+* information regarding copyright ownership.
+* This program and the accompanying materials are made available under the
+* terms of the Eclipse Public License 2.0 which is available at
+* http://www.eclipse.org/legal/epl-2.0, or the Apache License, Version 2.0
+* which is available at https://www.apache.org/licenses/LICENSE-2.0.
+* SPDX-License-Identifier: EPL-2.0 OR Apache-2.0
+* Contributors: Gabriele Baldoni, ADLINK Technology Inc.
+* golang APIs
+ */
+
+package fog05sdk_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	fog05sdk "github.com/eclipse-fog05/sdk-go/fog05sdk"
+	"github.com/eclipse-fog05/sdk-go/fog05sdk/fostest"
+)
+
+func TestHeartbeat(t *testing.T) {
+	yc, _ := fostest.NewConnector()
+	pl := fog05sdk.NewPluginWithConnector(1, "os1", yc, "n1")
+	pl.HeartbeatInterval = 10 * time.Millisecond
+	if err := pl.StartHeartbeat(); err != nil {
+		t.Fatal(err)
+	}
+	first, err := yc.Local.Actual.GetNodePluginHeartbeat("n1", "os1")
+	if err != nil || first.Status != fog05sdk.HeartbeatRunning {
+		t.Fatalf("GetNodePluginHeartbeat() = %v, %v", first, err)
+	}
+	if err := pl.SetHeartbeatStatus("busy"); err != nil {
+		t.Fatal(err)
+	}
+	eventually(t, "a newer heartbeat", func() bool {
+		hb, err := yc.Local.Actual.GetNodePluginHeartbeat("n1", "os1")
+		return err == nil && hb.Status == "busy" && hb.Timestamp > first.Timestamp
+	})
+
+	// the heartbeats published concurrently do not outlive StopHeartbeat
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			pl.SetHeartbeatStatus("busy")
+		}()
+	}
+	wg.Wait()
+	if err := pl.StopHeartbeat(); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(5 * pl.HeartbeatInterval)
+	if hb, err := yc.Local.Actual.GetNodePluginHeartbeat("n1", "os1"); !errors.Is(err, fog05sdk.ErrNotFound) {
+		t.Errorf("heartbeat after StopHeartbeat = %v, %v", hb, err)
+	}
+}
+
+// heartbeatFailingStore fails the reads of the heartbeats from the fostest Store it wraps
+type heartbeatFailingStore struct {
+	*fostest.Store
+}
+
+func (hs *heartbeatFailingStore) Get(ctx context.Context, s *fog05sdk.Selector) ([]fog05sdk.Entry, error) {
+	if strings.HasSuffix(s.ToString(), "/heartbeat") {
+		return nil, &fog05sdk.FError{Msg: "Get failed", Cause: fog05sdk.ErrNotConnected}
+	}
+	return hs.Store.Get(ctx, s)
+}
+
+func TestFindPluginsHeartbeats(t *testing.T) {
+	st := fostest.NewStore()
+	yc := fog05sdk.NewConnector(st)
+	for _, p := range []fog05sdk.Plugin{
+		{UUID: "os1", Name: "linux", Type: fog05sdk.OSPluginType},
+		{UUID: "rt1", Name: "native", Type: "runtime"},
+		{UUID: "rt2", Name: "kvm", Type: "runtime"},
+	} {
+		if err := yc.Local.Actual.AddNodePlugin("n1", p.UUID, p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// os1 never published a heartbeat, the one of rt2 expired
+	now := time.Now().UnixNano() / int64(time.Millisecond)
+	if err := yc.Local.Actual.AddNodePluginHeartbeat("n1", "rt1", fog05sdk.PluginHeartbeat{Timestamp: now, Status: fog05sdk.HeartbeatRunning}); err != nil {
+		t.Fatal(err)
+	}
+	if err := yc.Local.Actual.AddNodePluginHeartbeat("n1", "rt2", fog05sdk.PluginHeartbeat{Timestamp: now - 60000, Status: fog05sdk.HeartbeatRunning}); err != nil {
+		t.Fatal(err)
+	}
+
+	pl := fog05sdk.NewPluginWithConnector(1, "p1", yc, "n1")
+	plugins, err := pl.FindPlugins(fog05sdk.PluginFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := []string{}
+	for _, p := range plugins {
+		ids = append(ids, p.UUID)
+	}
+	if strings.Join(ids, ",") != "os1,rt1" {
+		t.Errorf("FindPlugins() = %v, want os1,rt1", ids)
+	}
+
+	// a heartbeat that cannot be read does not make the plugin alive
+	failing := fog05sdk.NewPluginWithConnector(1, "p1", fog05sdk.NewConnector(&heartbeatFailingStore{st}), "n1")
+	if plugins, err := failing.FindPlugins(fog05sdk.PluginFilter{Type: "runtime"}); !errors.Is(err, fog05sdk.ErrNotConnected) {
+		t.Errorf("FindPlugins() with unreadable heartbeats = %v, %v", plugins, err)
+	}
+
+	w := fog05sdk.NewPluginWatcher(yc, "n1")
+	w.RemoveStale = true
+	stale, err := w.Check()
+	if err != nil || len(stale) != 1 || stale[0].Plugin.Name != "kvm" {
+		t.Fatalf("Check() = %v, %v", stale, err)
+	}
+	if _, err := yc.Local.Actual.GetNodePlugin("n1", "rt2"); !errors.Is(err, fog05sdk.ErrNotFound) {
+		t.Errorf("stale plugin not removed: %v", err)
+	}
+}
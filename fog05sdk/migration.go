@@ -0,0 +1,237 @@
+/*
+* Copyright (c) 2014,2019 Contributors to the Eclipse Foundation
+* See the NOTICE file(s) distributed with this work for additional
+* information regarding copyright ownership.
+* This program and the accompanying materials are made available under the
+* terms of the Eclipse Public License 2.0 which is available at
+* http://www.eclipse.org/legal/epl-2.0, or the Apache License, Version 2.0
+* which is available at https://www.apache.org/licenses/LICENSE-2.0.
+* SPDX-License-Identifier: EPL-2.0 OR Apache-2.0
+* Contributors: Gabriele Baldoni, ADLINK Technology Inc.
+* golang APIs
+ */
+
+package fog05sdk
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DefaultMigrationTimeout is the default time each side of a migration waits for the other one
+const DefaultMigrationTimeout = 5 * time.Minute
+
+// migrationPollInterval is how often a side of a migration reads the record of the other one
+const migrationPollInterval = 500 * time.Millisecond
+
+// FOSRuntimeMigrationInterface is implemented by the runtime plugins that take part in the migration protocol of FOSRuntimePluginAbstract,
+// for the plugins not implementing it MIGRATE, TAKEOFF and LAND call MigrateFDU.
+//
+// The destination node calls LandFDU with the record of the source node, then writes the instance in LAND state to signal that it is ready.
+// The source node, in MIGRATE state, waits the destination to be ready, calls TakeOffFDU and moves to TAKEOFF.
+// The destination then starts a COLD instance, a LIVE one is already running, and moves to RUN, after that the source undefines the instance.
+// When a step fails both sides call AbortMigrationFDU, the source goes back to RUN and the destination to ERROR.
+// The other actions of the instance wait for the migration to complete
+type FOSRuntimeMigrationInterface interface {
+
+	//LandFDU prepares the node to receive the instance described by the record of the source node, without changing its status
+	LandFDU(FDURecord) error
+
+	//TakeOffFDU sends the instance to the destination node, that is ready to receive it, without changing its status.
+	//A LIVE instance keeps running until it runs in the destination, a COLD one has to be stopped
+	TakeOffFDU(FDURecord) error
+
+	//AbortMigrationFDU rolls back what LandFDU or TakeOffFDU did, the source node has to leave the instance running
+	AbortMigrationFDU(FDURecord) error
+}
+
+// migrate runs the side of the migration of the node, described by the MigrationProperties of the record.
+// It runs in the action queue of the instance, the actions queued meanwhile run after it, and are dropped
+// when the instance left the source node
+func (rt *FOSRuntimePluginAbstract) migrate(m FOSRuntimeMigrationInterface, info FDURecord) {
+	props := info.MigrationProperties
+	var err error
+	switch rt.Node {
+	case props.Destination:
+		err = rt.land(m, info)
+	case props.Source:
+		err = rt.takeOff(m, info)
+		if err == nil && rt.actions != nil {
+			rt.actions.drop(info.UUID)
+		}
+	default:
+		err = &FError{"Node is neither the source nor the destination of the migration", nil}
+	}
+	if err != nil {
+		rt.Logger.Error(fmt.Sprintf("Migration of %s from %s to %s failed: %s", info.UUID, props.Source, props.Destination, err.Error()))
+	}
+}
+
+// land is the destination side of the migration, on failure the instance is in ERROR state
+func (rt *FOSRuntimePluginAbstract) land(m FOSRuntimeMigrationInterface, info FDURecord) error {
+	props := *info.MigrationProperties
+	if cur, err := rt.GetFDURecord(info.UUID); err == nil {
+		// an instance already in the node can land only from MIGRATE
+		if err = cur.CheckTransition(LAND); err != nil {
+			return err
+		}
+	}
+	record := info
+	if src, err := rt.Connector.Local.Actual.GetNodeFDU(props.Source, "*", info.FDUID, info.UUID); err == nil {
+		record = *src
+	}
+	record.Status = LAND
+	record.MigrationProperties = &props
+	record.ErrorCode = nil
+	record.ErrorMsg = nil
+
+	err := m.LandFDU(record)
+	if err == nil {
+		// the LAND record tells the source that the node is ready
+		err = rt.AddFDURecord(record.UUID, &record)
+	}
+	if err == nil {
+		err = rt.AddFDUEvals(record.FDUID, record.UUID)
+	}
+	if err == nil {
+		err = rt.waitInstance(props.Source, record.FDUID, record.UUID, tookOff())
+	}
+	if err == nil && record.MigrationKind != LIVE {
+		err = rt.UpdateFDUStatus(record.FDUID, record.UUID, CONFIGURE)
+		if err == nil {
+			res := rt.StartFDU(record.UUID, nil)
+			err = res.Err()
+		}
+	}
+	if err == nil {
+		err = rt.UpdateFDUStatus(record.FDUID, record.UUID, RUN)
+	}
+	if err != nil {
+		rt.abortMigration(m, record)
+		if rerr := rt.RemoveFDUEvals(record.FDUID, record.UUID); rerr != nil {
+			rt.Logger.Error(fmt.Sprintf("Unable to remove the evals of %s: %s", record.UUID, rerr.Error()))
+		}
+		rt.writeError(record, err)
+		return err
+	}
+	return nil
+}
+
+// takeOff is the source side of the migration, on failure the instance goes back to RUN state
+func (rt *FOSRuntimePluginAbstract) takeOff(m FOSRuntimeMigrationInterface, info FDURecord) error {
+	props := *info.MigrationProperties
+	record, err := rt.GetFDURecord(info.UUID)
+	if err != nil {
+		return err
+	}
+	record.MigrationProperties = &props
+	err = rt.UpdateFDUStatus(record.FDUID, record.UUID, MIGRATE)
+	if err != nil {
+		return err
+	}
+	record.Status = MIGRATE
+
+	if !rt.WaitDestinationReady(record.FDUID, record.UUID, props.Destination) {
+		err = &FError{"Destination " + props.Destination + " not ready", nil}
+	}
+	if err == nil {
+		err = m.TakeOffFDU(*record)
+	}
+	if err == nil {
+		err = rt.UpdateFDUStatus(record.FDUID, record.UUID, TAKEOFF)
+	}
+	if err == nil {
+		err = rt.waitInstance(props.Destination, record.FDUID, record.UUID, landed())
+	}
+	if err != nil {
+		rt.abortMigration(m, *record)
+		if uerr := rt.UpdateFDUStatus(record.FDUID, record.UUID, RUN); uerr != nil {
+			rt.Logger.Error(fmt.Sprintf("Unable to restore %s: %s", record.UUID, uerr.Error()))
+		}
+		return err
+	}
+
+	// the instance runs in the destination, the source one is removed
+	if rerr := rt.RemoveFDUEvals(record.FDUID, record.UUID); rerr != nil {
+		rt.Logger.Error(fmt.Sprintf("Unable to remove the evals of %s: %s", record.UUID, rerr.Error()))
+	}
+	err = rt.UndefineFDU(record.UUID)
+	if err != nil {
+		return err
+	}
+	err = rt.Connector.Local.Actual.RemoveNodeFDU(rt.Node, rt.FOSPlugin.UUID, record.FDUID, record.UUID)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	return err
+}
+
+// abortMigration calls AbortMigrationFDU, logging its failure
+func (rt *FOSRuntimePluginAbstract) abortMigration(m FOSRuntimeMigrationInterface, record FDURecord) {
+	if err := m.AbortMigrationFDU(record); err != nil {
+		rt.Logger.Error(fmt.Sprintf("Unable to abort the migration of %s: %s", record.UUID, err.Error()))
+	}
+}
+
+// tookOff checks the source record for the destination, the source fails when it goes in ERROR or back to RUN after MIGRATE
+func tookOff() func(*FDURecord) (bool, error) {
+	migrating := false
+	return func(r *FDURecord) (bool, error) {
+		if r == nil {
+			return false, &FError{"Instance not found in the source node", ErrNotFound}
+		}
+		switch r.Status {
+		case TAKEOFF:
+			return true, nil
+		case MIGRATE:
+			migrating = true
+		case ERROR:
+			return false, &FError{"Instance failed in the source node", nil}
+		case RUN:
+			if migrating {
+				return false, &FError{"Migration aborted by the source node", nil}
+			}
+		}
+		return false, nil
+	}
+}
+
+// landed checks the destination record for the source, the destination fails when it goes in ERROR or removes the instance
+func landed() func(*FDURecord) (bool, error) {
+	return func(r *FDURecord) (bool, error) {
+		if r == nil {
+			return false, &FError{"Instance not found in the destination node", ErrNotFound}
+		}
+		switch r.Status {
+		case RUN:
+			return true, nil
+		case ERROR:
+			return false, &FError{"Instance failed in the destination node", nil}
+		}
+		return false, nil
+	}
+}
+
+// waitInstance reads the record of the instance in the node until check returns true or an error, or MigrationTimeout expires.
+// check gets nil if the node has no record of the instance
+func (rt *FOSRuntimePluginAbstract) waitInstance(nodeid string, fduid string, instanceid string, check func(*FDURecord) (bool, error)) error {
+	var deadline time.Time
+	if rt.MigrationTimeout > 0 {
+		deadline = time.Now().Add(rt.MigrationTimeout)
+	}
+	for {
+		record, err := rt.Connector.Local.Actual.GetNodeFDU(nodeid, "*", fduid, instanceid)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return err
+		}
+		done, err := check(record)
+		if err != nil || done {
+			return err
+		}
+		if !deadline.IsZero() && time.Now().After(deadline) {
+			return &FError{"Timeout waiting " + instanceid + " in node " + nodeid, ErrTimeout}
+		}
+		time.Sleep(migrationPollInterval)
+	}
+}
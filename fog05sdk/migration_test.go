@@ -0,0 +1,194 @@
+/*
+* Copyright (c) 2014,2019 Contributors to the Eclipse Foundation
+* See the NOTICE file(s) distributed with this work for additional
+* information regarding copyright ownership.
+* This program and the accompanying materials are made available under the
+* terms of the Eclipse Public License 2.0 which is available at
+* http://www.eclipse.org/legal/epl-2.0, or the Apache License, Version 2.0
+* which is available at https://www.apache.org/licenses/LICENSE-2.0.
+* SPDX-License-Identifier: EPL-2.0 OR Apache-2.0
+* Contributors: Gabriele Baldoni, ADLINK Technology Inc.
+* golang APIs
+ */
+
+package fog05sdk_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	fog05sdk "github.com/eclipse-fog05/sdk-go/fog05sdk"
+	"github.com/eclipse-fog05/sdk-go/fog05sdk/fostest"
+)
+
+// migratingRuntime is a fakeRuntime taking part in the migration protocol
+type migratingRuntime struct {
+	*fakeRuntime
+	failLand bool
+}
+
+func (m *migratingRuntime) LandFDU(r fog05sdk.FDURecord) error {
+	m.called("land " + r.MigrationKind)
+	if m.failLand {
+		return errors.New("no room")
+	}
+	m.mu.Lock()
+	m.fdus[r.UUID] = r
+	m.mu.Unlock()
+	return nil
+}
+
+func (m *migratingRuntime) TakeOffFDU(r fog05sdk.FDURecord) error {
+	m.called("takeoff " + r.Status)
+	return nil
+}
+
+func (m *migratingRuntime) AbortMigrationFDU(r fog05sdk.FDURecord) error {
+	m.called("abort")
+	return nil
+}
+
+func TestRuntimeMigration(t *testing.T) {
+	tests := []struct {
+		name     string
+		kind     string
+		failLand bool
+		noDest   bool
+		src      string
+		srcCalls string
+		dst      string
+		dstCalls string
+	}{
+		{"live", fog05sdk.LIVE, false, false, "", "takeoff MIGRATE,undefine i1", fog05sdk.RUN, "land LIVE"},
+		{"cold", fog05sdk.COLD, false, false, "", "takeoff MIGRATE,undefine i1", fog05sdk.RUN, "land COLD,start i1"},
+		{"land failure", fog05sdk.COLD, true, false, fog05sdk.RUN, "abort", fog05sdk.ERROR, "land COLD,abort"},
+		{"no destination", fog05sdk.LIVE, false, true, fog05sdk.RUN, "abort", "", ""},
+	}
+	for _, tt := range tests {
+		st := fostest.NewStore()
+		yc := fog05sdk.NewConnector(st)
+		status := func(nodeid string) string {
+			r, err := yc.Local.Actual.GetNodeFDU(nodeid, "*", "f1", "i1")
+			if err != nil {
+				return ""
+			}
+			return r.Status
+		}
+
+		srt, sf := newRuntime(t, fog05sdk.NewConnector(st), "n1", "rt1")
+		srt.MigrationTimeout = 5 * time.Second
+		if tt.noDest {
+			srt.MigrationTimeout = 500 * time.Millisecond
+		}
+		src := &migratingRuntime{fakeRuntime: sf}
+		srt.FOSRuntimePluginInterface = src
+		record := fog05sdk.FDURecord{UUID: "i1", FDUID: "f1", Status: fog05sdk.RUN, MigrationKind: tt.kind}
+		sf.fdus["i1"] = record
+		if err := srt.AddFDURecord("i1", &record); err != nil {
+			t.Fatal(err)
+		}
+		if err := srt.AddFDUEvals("f1", "i1"); err != nil {
+			t.Fatal(err)
+		}
+		srt.Start()
+		dst := &migratingRuntime{failLand: tt.failLand}
+		if !tt.noDest {
+			drt, df := newRuntime(t, fog05sdk.NewConnector(st), "n2", "rt2")
+			drt.MigrationTimeout = 5 * time.Second
+			dst.fakeRuntime = df
+			drt.FOSRuntimePluginInterface = dst
+			drt.Start()
+		}
+
+		// the desired records written by FIMClient.Migrate
+		props := fog05sdk.FDUMigrationProperties{Source: "n1", Destination: "n2"}
+		land, takeoff := record, record
+		land.Status, land.MigrationProperties = fog05sdk.LAND, &props
+		takeoff.Status, takeoff.MigrationProperties = fog05sdk.TAKEOFF, &props
+		if err := yc.Local.Desired.AddNodeFDU("n2", "rt2", "f1", "i1", land); err != nil {
+			t.Fatal(err)
+		}
+		if err := yc.Local.Desired.AddNodeFDU("n1", "rt1", "f1", "i1", takeoff); err != nil {
+			t.Fatal(err)
+		}
+
+		within(t, 5*time.Second, tt.name+" migration", func() bool {
+			return src.history() == tt.srcCalls && (tt.noDest || dst.history() == tt.dstCalls)
+		})
+		if s := status("n1"); s != tt.src {
+			t.Errorf("%s: source status = %q, want %q", tt.name, s, tt.src)
+		}
+		if s := status("n2"); s != tt.dst {
+			t.Errorf("%s: destination status = %q, want %q", tt.name, s, tt.dst)
+		}
+		evals := 0
+		if tt.src == fog05sdk.RUN {
+			evals += 5
+		}
+		if tt.dst == fog05sdk.RUN {
+			evals += 5
+		}
+		if len(st.EvalPaths()) != evals {
+			t.Errorf("%s: evals after the migration = %v, want %d", tt.name, st.EvalPaths(), evals)
+		}
+		st.Close()
+	}
+}
+
+func TestRuntimeCrossMigration(t *testing.T) {
+	st := fostest.NewStore()
+	defer st.Close()
+	yc := fog05sdk.NewConnector(st)
+
+	// each node has one worker and migrates its instance to the other one
+	nodes := []struct{ node, plugin, instance, peer, peerPlugin string }{
+		{"n1", "rt1", "i1", "n2", "rt2"},
+		{"n2", "rt2", "i2", "n1", "rt1"},
+	}
+	fakes := []*migratingRuntime{}
+	for _, n := range nodes {
+		rt, f := newRuntime(t, fog05sdk.NewConnector(st), n.node, n.plugin)
+		rt.Workers = 1
+		rt.MigrationTimeout = 3 * time.Second
+		m := &migratingRuntime{fakeRuntime: f}
+		rt.FOSRuntimePluginInterface = m
+		record := fog05sdk.FDURecord{UUID: n.instance, FDUID: "f1", Status: fog05sdk.RUN, MigrationKind: fog05sdk.LIVE}
+		f.fdus[n.instance] = record
+		if err := rt.AddFDURecord(n.instance, &record); err != nil {
+			t.Fatal(err)
+		}
+		rt.Start()
+		fakes = append(fakes, m)
+	}
+
+	// the sources take their worker first, the landings queue behind them
+	for _, n := range nodes {
+		record := fog05sdk.FDURecord{UUID: n.instance, FDUID: "f1", Status: fog05sdk.TAKEOFF, MigrationKind: fog05sdk.LIVE,
+			MigrationProperties: &fog05sdk.FDUMigrationProperties{Source: n.node, Destination: n.peer}}
+		if err := yc.Local.Desired.AddNodeFDU(n.node, n.plugin, "f1", n.instance, record); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, n := range nodes {
+		record := fog05sdk.FDURecord{UUID: n.instance, FDUID: "f1", Status: fog05sdk.LAND, MigrationKind: fog05sdk.LIVE,
+			MigrationProperties: &fog05sdk.FDUMigrationProperties{Source: n.node, Destination: n.peer}}
+		if err := yc.Local.Desired.AddNodeFDU(n.peer, n.peerPlugin, "f1", n.instance, record); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	within(t, 5*time.Second, "the cross migrations", func() bool {
+		return strings.Contains(fakes[0].history(), "undefine i1") && strings.Contains(fakes[1].history(), "undefine i2")
+	})
+	for i, n := range nodes {
+		if h := fakes[i].history(); strings.Contains(h, "abort") {
+			t.Errorf("%s aborted a migration: %s", n.node, h)
+		}
+		r, err := yc.Local.Actual.GetNodeFDU(n.peer, "*", "f1", n.instance)
+		if err != nil || r.Status != fog05sdk.RUN {
+			t.Errorf("%s in %s = %v, %v, want RUN", n.instance, n.peer, r, err)
+		}
+	}
+}
@@ -0,0 +1,601 @@
+/*
+* Copyright (c) 2014,2019 Contributors to the Eclipse Foundation
+* See the NOTICE file(s) distributed with this work for additional
+* information regarding copyright ownership.
+* This program and the accompanying materials are made available under the
+* terms of the Eclipse Public License 2.0 which is available at
+* http://www.eclipse.org/legal/epl-2.0, or the Apache License, Version 2.0
+* which is available at https://www.apache.org/licenses/LICENSE-2.0.
+* SPDX-License-Identifier: EPL-2.0 OR Apache-2.0
+* Contributors: Gabriele Baldoni, ADLINK Technology Inc.
+* golang APIs
+ */
+
+package fog05sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+// NetworkManagerPluginType is the type of the Network Manager plugins in their manifest
+const NetworkManagerPluginType = "network"
+
+// FOSNetworkManagerPluginInterface is the interface to be implemented for a Network Manager Plugin, each function but StartNM, StopNM
+// and the virtual network ones is called by the eval with the same name used by the NM object
+type FOSNetworkManagerPluginInterface interface {
+
+	//StartNM starts the plugin
+	StartNM() error
+
+	//StopNM stops the plugin
+	StopNM() error
+
+	//CreateVirtualNetwork creates the virtual network requested in Local Desired
+	CreateVirtualNetwork(VirtualNetwork) error
+
+	//DeleteVirtualNetwork deletes the given virtual network
+	DeleteVirtualNetwork(string) error
+
+	//CreateVirtualInterface creates the given virtual interface, called by create_virtual_interface
+	CreateVirtualInterface(string, FDUInterfaceRecord) (map[string]interface{}, error)
+
+	//DeleteVirtualInterface deletes the given virtual interface, called by delete_virtual_interface
+	DeleteVirtualInterface(string) (string, error)
+
+	//CreateVirtualBridge creates a bridge with the given name and uuid, called by create_virtual_bridge
+	CreateVirtualBridge(string, string) (map[string]interface{}, error)
+
+	//DeleteVirtualBridge deletes the given bridge, called by delete_virtual_bridge
+	DeleteVirtualBridge(string) (string, error)
+
+	//CreateBridgesIfNotExist creates the given bridges if they do not exist, called by create_bridges_if_not_exist
+	CreateBridgesIfNotExist([]string) ([]map[string]interface{}, error)
+
+	//ConnectInterfaceToConnectionPoint connects the given interface to the given connection point, called by connect_interface_to_connection_point
+	ConnectInterfaceToConnectionPoint(string, string) (map[string]interface{}, error)
+
+	//DisconnectInterface disconnects the given interface, called by disconnect_interface
+	DisconnectInterface(string) (map[string]interface{}, error)
+
+	//ConnectCPToVNetwork connects the given connection point to the given virtual network, called by connect_cp_to_vnetwork
+	ConnectCPToVNetwork(string, string) (*ConnectionPointRecord, error)
+
+	//DisconnectCP disconnects the given connection point, called by disconnect_cp
+	DisconnectCP(string) (*ConnectionPointRecord, error)
+
+	//DeletePort deletes the given connection point, called by delete_port
+	DeletePort(string) (bool, error)
+
+	//GetAddress returns the IP address of the given connection point, called by get_address
+	GetAddress(string) (string, error)
+
+	//AddRouterPort adds a port of the given type, network and address to the given router, called by add_router_port
+	AddRouterPort(string, string, string, string) (*RouterRecord, error)
+
+	//RemovePortFromRouter removes the port of the given network from the given router, called by remove_port_from_router
+	RemovePortFromRouter(string, string) (*RouterRecord, error)
+
+	//CreateFloatingIP creates a floating IP, called by create_floating_ip
+	CreateFloatingIP() (*FloatingIPRecord, error)
+
+	//DeleteFloatingIP deletes the given floating IP, called by delete_floating_ip
+	DeleteFloatingIP(string) (*FloatingIPRecord, error)
+
+	//AssignFloatingIP assigns the given floating IP to the given connection point, called by assign_floating_ip
+	AssignFloatingIP(string, string) (*FloatingIPRecord, error)
+
+	//RemoveFloatingIP removes the given floating IP from the given connection point, called by remove_floating_ip
+	RemoveFloatingIP(string, string) (*FloatingIPRecord, error)
+
+	//GetOverlayFace returns the interface used for overlay networks, called by get_overlay_face
+	GetOverlayFace() (string, error)
+
+	//GetVLANFace returns the interface used for VLAN networks, called by get_vlan_face
+	GetVLANFace() (string, error)
+
+	//CreatePortAgent creates the given connection point, called by create_port_agent
+	CreatePortAgent(ConnectionPointDescriptor) (*ConnectionPointRecord, error)
+
+	//DestroyPortAgent destroys the given connection point, called by destroy_port_agent
+	DestroyPortAgent(string) (*ConnectionPointRecord, error)
+
+	//CreateMACVLANInterface creates a MACVLAN interface over the given interface, called by create_macvlan_interface
+	CreateMACVLANInterface(string) (string, error)
+
+	//DeleteMACVLANInterface deletes the given MACVLAN interface from the given namespace, called by delete_macvlan_interface
+	DeleteMACVLANInterface(string, string) (string, error)
+
+	//CreateNetworkNamespace creates a network namespace and returns its name, called by create_network_namespace
+	CreateNetworkNamespace() (string, error)
+
+	//DeleteNetworkNamespace deletes the given network namespace, called by delete_network_namespace
+	DeleteNetworkNamespace(string) (string, error)
+
+	//MoveInterfaceInNamespace moves the given interface in the given namespace, called by move_interface_in_namespace
+	MoveInterfaceInNamespace(string, string) (*InterfaceInfo, error)
+
+	//RenameVirtualInterfaceInNamespace renames the given interface in the given namespace, empty for the default one,
+	//called by rename_virtual_interface_in_namespace
+	RenameVirtualInterfaceInNamespace(string, string, string) (string, error)
+
+	//AttachInterfaceToBridge attaches the given interface to the given bridge, called by attach_interface_to_bridge
+	AttachInterfaceToBridge(string, string) (*InterfaceInfo, error)
+
+	//DetachInterfaceFromBridge detaches the given interface from its bridge, called by detach_interface_from_bridge
+	DetachInterfaceFromBridge(string) (*InterfaceInfo, error)
+
+	//CreateVirtualInterfaceInNamespace creates a veth pair with the given internal interface in the given namespace,
+	//called by create_virtual_interface_in_namespace
+	CreateVirtualInterfaceInNamespace(string, string) (*NamespaceInfo, error)
+
+	//DeleteVirtualInterfaceFromNamespace deletes the given interface from the given namespace, called by delete_virtual_interface_from_namespace
+	DeleteVirtualInterfaceFromNamespace(string, string) (*NamespaceInfo, error)
+
+	//AssignAddressToInterfaceInNamespace assigns the given address, that can be empty, to the given interface in the given namespace,
+	//called by assign_address_to_interface_in_namespace
+	AssignAddressToInterfaceInNamespace(string, string, string) (*NamespaceInfo, error)
+
+	//AssignMACAddressToInterfaceInNamespace assigns the given MAC address to the given interface in the given namespace,
+	//called by assign_mac_address_to_interface_in_namespace
+	AssignMACAddressToInterfaceInNamespace(string, string, string) (*NamespaceInfo, error)
+
+	//GetAddressOfInterfaceInNamespace returns the addresses of the given interface in the given namespace, called by get_address_of_interface_in_namespace
+	GetAddressOfInterfaceInNamespace(string, string) (*InterfaceInfo, error)
+
+	//RemoveAddressFromInterfaceInNamespace removes the address from the given interface in the given namespace,
+	//called by remove_address_from_interface_in_namespace
+	RemoveAddressFromInterfaceInNamespace(string, string) (*NamespaceInfo, error)
+}
+
+// FOSNetworkManagerPluginAbstract represents a Network Manager Plugin for Eclipse fog05, it registers the NM evals and keeps
+// the networks, ports, routers and floating IPs they create in Local Actual
+type FOSNetworkManagerPluginAbstract struct {
+	Pid           int
+	Name          string
+	Connector     *YaksConnector
+	Node          string
+	Configuration map[string]interface{}
+	Requirements  []string
+	Logger        *log.Logger
+	FOSNetworkManagerPluginInterface
+	FOSPlugin
+}
+
+// NewFOSNetworkManagerPluginAbstract returns a new FOSNetworkManagerPluginAbstract object
+func NewFOSNetworkManagerPluginAbstract(name string, version int, pluginid string, manifest Plugin) (*FOSNetworkManagerPluginAbstract, error) {
+	con, logger, err := connectorFromManifest(manifest)
+	if err != nil {
+		return nil, err
+	}
+	nm, err := NewFOSNetworkManagerPluginAbstractWithConnector(name, version, pluginid, manifest, con)
+	if err != nil {
+		con.Close()
+		return nil, err
+	}
+	nm.Logger = logger
+	return nm, nil
+}
+
+// NewFOSNetworkManagerPluginAbstractWithConnector returns a new FOSNetworkManagerPluginAbstract object using the given connector instead of connecting to YAKS
+func NewFOSNetworkManagerPluginAbstractWithConnector(name string, version int, pluginid string, manifest Plugin, con *YaksConnector) (*FOSNetworkManagerPluginAbstract, error) {
+	if pluginid == "" {
+		pluginid = uuid.UUID.String(uuid.New())
+	}
+	if manifest.Configuration == nil {
+		return nil, &FError{"Missing configuration in plugin manifest", nil}
+	}
+	conf := *manifest.Configuration
+	node, ok := conf["nodeid"].(string)
+	if !ok {
+		return nil, &FError{"Missing nodeid in plugin configuration", nil}
+	}
+	pl := NewPluginWithConnector(version, pluginid, con, node)
+
+	return &FOSNetworkManagerPluginAbstract{Pid: os.Getpid(), Name: name, Connector: con, Node: node, FOSPlugin: *pl, Logger: log.New(), Configuration: conf, Requirements: manifest.Requirements}, nil
+}
+
+// Start starts the Plugin, registers the NM evals, observes the desired networks and ports and calls StartNM of FOSNetworkManagerPluginInterface
+func (nm *FOSNetworkManagerPluginAbstract) Start() {
+	nm.WaitDependencies()
+	for name, handler := range nm.evals() {
+		err := nm.Connector.Local.Actual.AddNMEval(nm.Node, nm.FOSPlugin.UUID, name, evalHandler(handler))
+		if err != nil {
+			nm.Logger.Error(fmt.Sprintf("Unable to register eval %s: %s", name, err.Error()))
+			nm.Close()
+			return
+		}
+	}
+	_, err := nm.Connector.Local.Desired.ObserveNodeNetworks(nm.Node, nm.FOSPlugin.UUID, nm.reactNetwork)
+	if err == nil {
+		_, err = nm.Connector.Local.Desired.ObserveNodePorts(nm.Node, nm.FOSPlugin.UUID, nm.reactPort)
+	}
+	if err != nil {
+		nm.Logger.Error(fmt.Sprintf("Unable to observe the desired networks and ports: %s", err.Error()))
+		nm.Close()
+		return
+	}
+	err = nm.FOSNetworkManagerPluginInterface.StartNM()
+	if err != nil {
+		nm.Logger.Error(fmt.Sprintf("Plugin StartNM returned error %s", err.Error()))
+		nm.Close()
+	}
+}
+
+// Close closes the Plugin, called by FOSNetworkManagerPluginInterface.StopNM()
+func (nm *FOSNetworkManagerPluginAbstract) Close() {
+	nm.RemovePlugin()
+	if err := nm.Connector.Close(); err != nil {
+		nm.Logger.Error(fmt.Sprintf("Unable to close the connector: %s", err.Error()))
+	}
+	nm.Logger.Info("Plugin closed")
+}
+
+// WaitDependencies waits the plugins in Requirements, or if there are none that the Agent and OS Plugin are up, and gets those from YAKS
+func (nm *FOSNetworkManagerPluginAbstract) WaitDependencies() {
+	if len(nm.Requirements) > 0 {
+		if err := nm.FOSPlugin.WaitRequirements(context.Background(), nm.Requirements); err != nil {
+			nm.Logger.Error(fmt.Sprintf("Unable to get the requirements: %s", err.Error()))
+		}
+		return
+	}
+	for nm.FOSPlugin.Agent == nil {
+		if _, err := nm.FOSPlugin.GetAgent(); err != nil {
+			nm.Logger.Warn(fmt.Sprintf("Unable to get the Agent: %s", err.Error()))
+		}
+		time.Sleep(1 * time.Second)
+	}
+	for nm.FOSPlugin.OS == nil {
+		if _, err := nm.FOSPlugin.GetOSPlugin(); err != nil {
+			nm.Logger.Warn(fmt.Sprintf("Unable to get the OS Plugin: %s", err.Error()))
+		}
+		time.Sleep(1 * time.Second)
+	}
+}
+
+// RegisterPlugin registers the plugin in the node and starts its heartbeats
+func (nm *FOSNetworkManagerPluginAbstract) RegisterPlugin(manifest *Plugin) {
+	nm.Connector.Local.Actual.AddNodePlugin(nm.Node, nm.FOSPlugin.UUID, *manifest)
+	if err := nm.FOSPlugin.StartHeartbeat(); err != nil {
+		nm.Logger.Error(fmt.Sprintf("Unable to start the heartbeat: %s", err.Error()))
+	}
+}
+
+// RemovePlugin stops the heartbeats and removes the plugin in the node
+func (nm *FOSNetworkManagerPluginAbstract) RemovePlugin() {
+	if err := nm.FOSPlugin.StopHeartbeat(); err != nil {
+		nm.Logger.Error(fmt.Sprintf("Unable to remove the heartbeat: %s", err.Error()))
+	}
+	nm.Connector.Local.Actual.RemoveNodePlugin(nm.Node, nm.FOSPlugin.UUID)
+}
+
+// AddNetworkRecord stores the virtual network in Local Actual
+func (nm *FOSNetworkManagerPluginAbstract) AddNetworkRecord(info VirtualNetwork) error {
+	return nm.Connector.Local.Actual.AddNodeNetwork(nm.Node, nm.FOSPlugin.UUID, info.UUID, info)
+}
+
+// GetNetworkRecord retrieves the given virtual network from Local Actual
+func (nm *FOSNetworkManagerPluginAbstract) GetNetworkRecord(netid string) (*VirtualNetwork, error) {
+	return nm.Connector.Local.Actual.GetNodeNetwork(nm.Node, nm.FOSPlugin.UUID, netid)
+}
+
+// GetAllNetworkRecords retrieves all the virtual networks of the plugin from Local Actual
+func (nm *FOSNetworkManagerPluginAbstract) GetAllNetworkRecords() ([]VirtualNetwork, error) {
+	return nm.Connector.Local.Actual.GetAllNodeNetworks(nm.Node, nm.FOSPlugin.UUID)
+}
+
+// RemoveNetworkRecord removes the given virtual network from Local Actual
+func (nm *FOSNetworkManagerPluginAbstract) RemoveNetworkRecord(netid string) error {
+	return nm.Connector.Local.Actual.RemoveNodeNetwork(nm.Node, nm.FOSPlugin.UUID, netid)
+}
+
+// AddPortRecord stores the connection point in Local Actual
+func (nm *FOSNetworkManagerPluginAbstract) AddPortRecord(info ConnectionPointRecord) error {
+	return nm.Connector.Local.Actual.AddNodePort(nm.Node, nm.FOSPlugin.UUID, info.UUID, info)
+}
+
+// GetPortRecord retrieves the given connection point from Local Actual
+func (nm *FOSNetworkManagerPluginAbstract) GetPortRecord(cpid string) (*ConnectionPointRecord, error) {
+	return nm.Connector.Local.Actual.GetNodePort(nm.Node, nm.FOSPlugin.UUID, cpid)
+}
+
+// GetAllPortRecords retrieves all the connection points of the plugin from Local Actual
+func (nm *FOSNetworkManagerPluginAbstract) GetAllPortRecords() ([]ConnectionPointRecord, error) {
+	return nm.Connector.Local.Actual.GetAllNodePorts(nm.Node, nm.FOSPlugin.UUID)
+}
+
+// RemovePortRecord removes the given connection point from Local Actual
+func (nm *FOSNetworkManagerPluginAbstract) RemovePortRecord(cpid string) error {
+	return nm.Connector.Local.Actual.RemoveNodePort(nm.Node, nm.FOSPlugin.UUID, cpid)
+}
+
+// AddRouterRecord stores the router in Local Actual
+func (nm *FOSNetworkManagerPluginAbstract) AddRouterRecord(info RouterRecord) error {
+	return nm.Connector.Local.Actual.AddNodeRouter(nm.Node, nm.FOSPlugin.UUID, info.UUID, info)
+}
+
+// GetRouterRecord retrieves the given router from Local Actual
+func (nm *FOSNetworkManagerPluginAbstract) GetRouterRecord(routerid string) (*RouterRecord, error) {
+	return nm.Connector.Local.Actual.GetNodeRouter(nm.Node, nm.FOSPlugin.UUID, routerid)
+}
+
+// GetAllRouterRecords retrieves all the routers of the plugin from Local Actual
+func (nm *FOSNetworkManagerPluginAbstract) GetAllRouterRecords() ([]RouterRecord, error) {
+	return nm.Connector.Local.Actual.GetAllNodeRouters(nm.Node, nm.FOSPlugin.UUID)
+}
+
+// RemoveRouterRecord removes the given router from Local Actual
+func (nm *FOSNetworkManagerPluginAbstract) RemoveRouterRecord(routerid string) error {
+	return nm.Connector.Local.Actual.RemoveNodeRouter(nm.Node, nm.FOSPlugin.UUID, routerid)
+}
+
+// AddFloatingIPRecord stores the floating IP in Local Actual
+func (nm *FOSNetworkManagerPluginAbstract) AddFloatingIPRecord(info FloatingIPRecord) error {
+	return nm.Connector.Local.Actual.AddNodeFloatingIP(nm.Node, nm.FOSPlugin.UUID, info.UUID, info)
+}
+
+// GetFloatingIPRecord retrieves the given floating IP from Local Actual
+func (nm *FOSNetworkManagerPluginAbstract) GetFloatingIPRecord(ipid string) (*FloatingIPRecord, error) {
+	return nm.Connector.Local.Actual.GetNodeFloatingIP(nm.Node, nm.FOSPlugin.UUID, ipid)
+}
+
+// GetAllFloatingIPRecords retrieves all the floating IPs of the plugin from Local Actual
+func (nm *FOSNetworkManagerPluginAbstract) GetAllFloatingIPRecords() ([]FloatingIPRecord, error) {
+	return nm.Connector.Local.Actual.GetAllNodeFloatingIPs(nm.Node, nm.FOSPlugin.UUID)
+}
+
+// RemoveFloatingIPRecord removes the given floating IP from Local Actual
+func (nm *FOSNetworkManagerPluginAbstract) RemoveFloatingIPRecord(ipid string) error {
+	return nm.Connector.Local.Actual.RemoveNodeFloatingIP(nm.Node, nm.FOSPlugin.UUID, ipid)
+}
+
+// evals returns the handlers of the NM evals, each decodes the parameters and returns the call to the plugin
+func (nm *FOSNetworkManagerPluginAbstract) evals() map[string]func(*evalArgs) func() (interface{}, error) {
+	return map[string]func(*evalArgs) func() (interface{}, error){
+		"create_virtual_interface": func(a *evalArgs) func() (interface{}, error) {
+			intfid := a.string("intf_id")
+			descriptor := FDUInterfaceRecord{}
+			a.json("descriptor", &descriptor)
+			return func() (interface{}, error) { return nm.CreateVirtualInterface(intfid, descriptor) }
+		},
+		"delete_virtual_interface": func(a *evalArgs) func() (interface{}, error) {
+			intfid := a.string("intf_id")
+			return func() (interface{}, error) { return nm.DeleteVirtualInterface(intfid) }
+		},
+		"create_virtual_bridge": func(a *evalArgs) func() (interface{}, error) {
+			name, id := a.string("name"), a.string("uuid")
+			return func() (interface{}, error) { return nm.CreateVirtualBridge(name, id) }
+		},
+		"delete_virtual_bridge": func(a *evalArgs) func() (interface{}, error) {
+			id := a.string("br_uuid")
+			return func() (interface{}, error) { return nm.DeleteVirtualBridge(id) }
+		},
+		"create_bridges_if_not_exist": func(a *evalArgs) func() (interface{}, error) {
+			expected := a.strings("expected_bridges")
+			return func() (interface{}, error) { return nm.CreateBridgesIfNotExist(expected) }
+		},
+		"connect_interface_to_connection_point": func(a *evalArgs) func() (interface{}, error) {
+			intfid, cpid := a.string("intf_id"), a.string("cp_id")
+			return func() (interface{}, error) { return nm.ConnectInterfaceToConnectionPoint(intfid, cpid) }
+		},
+		"disconnect_interface": func(a *evalArgs) func() (interface{}, error) {
+			intfid := a.string("intf_id")
+			return func() (interface{}, error) { return nm.DisconnectInterface(intfid) }
+		},
+		"connect_cp_to_vnetwork": func(a *evalArgs) func() (interface{}, error) {
+			cpid, vnetid := a.string("cp_id"), a.string("vnet_id")
+			return func() (interface{}, error) { return nm.storePort(nm.ConnectCPToVNetwork(cpid, vnetid)) }
+		},
+		"disconnect_cp": func(a *evalArgs) func() (interface{}, error) {
+			cpid := a.string("cp_id")
+			return func() (interface{}, error) { return nm.storePort(nm.DisconnectCP(cpid)) }
+		},
+		"delete_port": func(a *evalArgs) func() (interface{}, error) {
+			cpid := a.string("cp_id")
+			return func() (interface{}, error) {
+				deleted, err := nm.DeletePort(cpid)
+				if err != nil || !deleted {
+					return deleted, err
+				}
+				return deleted, nm.removeRecord(nm.RemovePortRecord(cpid))
+			}
+		},
+		"get_address": func(a *evalArgs) func() (interface{}, error) {
+			cpid := a.string("cp_id")
+			return func() (interface{}, error) { return nm.GetAddress(cpid) }
+		},
+		"add_router_port": func(a *evalArgs) func() (interface{}, error) {
+			routerid, porttype, vnetid, address := a.string("router_id"), a.string("port_type"), a.optional("vnet_id", ""), a.optional("ip_address", "")
+			return func() (interface{}, error) {
+				return nm.storeRouter(nm.AddRouterPort(routerid, porttype, vnetid, address))
+			}
+		},
+		"remove_port_from_router": func(a *evalArgs) func() (interface{}, error) {
+			routerid, vnetid := a.string("router_id"), a.string("vnet_id")
+			return func() (interface{}, error) { return nm.storeRouter(nm.RemovePortFromRouter(routerid, vnetid)) }
+		},
+		"create_floating_ip": func(a *evalArgs) func() (interface{}, error) {
+			return func() (interface{}, error) { return nm.storeFloatingIP(nm.CreateFloatingIP()) }
+		},
+		"delete_floating_ip": func(a *evalArgs) func() (interface{}, error) {
+			ipid := a.string("ip_id")
+			return func() (interface{}, error) {
+				ip, err := nm.DeleteFloatingIP(ipid)
+				if err != nil {
+					return nil, err
+				}
+				return ip, nm.removeRecord(nm.RemoveFloatingIPRecord(ipid))
+			}
+		},
+		"assign_floating_ip": func(a *evalArgs) func() (interface{}, error) {
+			ipid, cpid := a.string("ip_id"), a.string("cp_id")
+			return func() (interface{}, error) { return nm.storeFloatingIP(nm.AssignFloatingIP(ipid, cpid)) }
+		},
+		"remove_floating_ip": func(a *evalArgs) func() (interface{}, error) {
+			ipid, cpid := a.string("ip_id"), a.string("cp_id")
+			return func() (interface{}, error) { return nm.storeFloatingIP(nm.RemoveFloatingIP(ipid, cpid)) }
+		},
+		"get_overlay_face": func(a *evalArgs) func() (interface{}, error) {
+			return func() (interface{}, error) { return nm.GetOverlayFace() }
+		},
+		"get_vlan_face": func(a *evalArgs) func() (interface{}, error) {
+			return func() (interface{}, error) { return nm.GetVLANFace() }
+		},
+		"create_port_agent": func(a *evalArgs) func() (interface{}, error) {
+			descriptor := ConnectionPointDescriptor{}
+			a.json("descriptor", &descriptor)
+			return func() (interface{}, error) { return nm.storePort(nm.CreatePortAgent(descriptor)) }
+		},
+		"destroy_port_agent": func(a *evalArgs) func() (interface{}, error) {
+			cpid := a.string("cp_id")
+			return func() (interface{}, error) {
+				cp, err := nm.DestroyPortAgent(cpid)
+				if err != nil {
+					return nil, err
+				}
+				return cp, nm.removeRecord(nm.RemovePortRecord(cpid))
+			}
+		},
+		"create_macvlan_interface": func(a *evalArgs) func() (interface{}, error) {
+			master := a.string("master_intf")
+			return func() (interface{}, error) { return nm.CreateMACVLANInterface(master) }
+		},
+		"delete_macvlan_interface": func(a *evalArgs) func() (interface{}, error) {
+			name, netns := a.string("intfName"), a.optional("netns", "1")
+			return func() (interface{}, error) { return nm.DeleteMACVLANInterface(name, netns) }
+		},
+		"create_network_namespace": func(a *evalArgs) func() (interface{}, error) {
+			return func() (interface{}, error) { return nm.CreateNetworkNamespace() }
+		},
+		"delete_network_namespace": func(a *evalArgs) func() (interface{}, error) {
+			netns := a.string("nsname")
+			return func() (interface{}, error) { return nm.DeleteNetworkNamespace(netns) }
+		},
+		"move_interface_in_namespace": func(a *evalArgs) func() (interface{}, error) {
+			name, netns := a.string("intf_name"), a.optional("nsname", "1")
+			return func() (interface{}, error) { return nm.MoveInterfaceInNamespace(name, netns) }
+		},
+		"rename_virtual_interface_in_namespace": func(a *evalArgs) func() (interface{}, error) {
+			name, newname, netns := a.string("name"), a.string("newname"), a.optional("nsname", "")
+			return func() (interface{}, error) { return nm.RenameVirtualInterfaceInNamespace(name, newname, netns) }
+		},
+		"attach_interface_to_bridge": func(a *evalArgs) func() (interface{}, error) {
+			name, bridge := a.string("intf_name"), a.string("br_name")
+			return func() (interface{}, error) { return nm.AttachInterfaceToBridge(name, bridge) }
+		},
+		"detach_interface_from_bridge": func(a *evalArgs) func() (interface{}, error) {
+			name := a.string("intf_name")
+			return func() (interface{}, error) { return nm.DetachInterfaceFromBridge(name) }
+		},
+		"create_virtual_interface_in_namespace": func(a *evalArgs) func() (interface{}, error) {
+			name, netns := a.string("internal_name"), a.string("nsname")
+			return func() (interface{}, error) { return nm.CreateVirtualInterfaceInNamespace(name, netns) }
+		},
+		"delete_virtual_interface_from_namespace": func(a *evalArgs) func() (interface{}, error) {
+			name, netns := a.string("internal_name"), a.string("nsname")
+			return func() (interface{}, error) { return nm.DeleteVirtualInterfaceFromNamespace(name, netns) }
+		},
+		"assign_address_to_interface_in_namespace": func(a *evalArgs) func() (interface{}, error) {
+			name, netns, address := a.string("intf_name"), a.string("nsname"), a.optional("address", "")
+			return func() (interface{}, error) { return nm.AssignAddressToInterfaceInNamespace(name, netns, address) }
+		},
+		"assign_mac_address_to_interface_in_namespace": func(a *evalArgs) func() (interface{}, error) {
+			name, netns, address := a.string("intf_name"), a.string("nsname"), a.string("address")
+			return func() (interface{}, error) { return nm.AssignMACAddressToInterfaceInNamespace(name, netns, address) }
+		},
+		"get_address_of_interface_in_namespace": func(a *evalArgs) func() (interface{}, error) {
+			name, netns := a.string("intf_name"), a.string("nsname")
+			return func() (interface{}, error) { return nm.GetAddressOfInterfaceInNamespace(name, netns) }
+		},
+		"remove_address_from_interface_in_namespace": func(a *evalArgs) func() (interface{}, error) {
+			name, netns := a.string("intf_name"), a.string("nsname")
+			return func() (interface{}, error) { return nm.RemoveAddressFromInterfaceInNamespace(name, netns) }
+		},
+	}
+}
+
+// storePort stores the connection point returned by the plugin in Local Actual
+func (nm *FOSNetworkManagerPluginAbstract) storePort(cp *ConnectionPointRecord, err error) (*ConnectionPointRecord, error) {
+	if err != nil || cp == nil {
+		return cp, err
+	}
+	return cp, nm.AddPortRecord(*cp)
+}
+
+// storeRouter stores the router returned by the plugin in Local Actual
+func (nm *FOSNetworkManagerPluginAbstract) storeRouter(router *RouterRecord, err error) (*RouterRecord, error) {
+	if err != nil || router == nil {
+		return router, err
+	}
+	return router, nm.AddRouterRecord(*router)
+}
+
+// storeFloatingIP stores the floating IP returned by the plugin in Local Actual
+func (nm *FOSNetworkManagerPluginAbstract) storeFloatingIP(ip *FloatingIPRecord, err error) (*FloatingIPRecord, error) {
+	if err != nil || ip == nil {
+		return ip, err
+	}
+	return ip, nm.AddFloatingIPRecord(*ip)
+}
+
+// removeRecord ignores the error of removing a record that is not in Local Actual
+func (nm *FOSNetworkManagerPluginAbstract) removeRecord(err error) error {
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	return err
+}
+
+func (nm *FOSNetworkManagerPluginAbstract) reactNetwork(info VirtualNetwork) {
+	if info.Status != nil && *info.Status == DESTROY {
+		err := nm.DeleteVirtualNetwork(info.UUID)
+		if err == nil {
+			err = nm.removeRecord(nm.RemoveNetworkRecord(info.UUID))
+		}
+		if err != nil {
+			nm.Logger.Error(fmt.Sprintf("Unable to delete network %s: %s", info.UUID, err.Error()))
+		}
+		return
+	}
+	err := nm.CreateVirtualNetwork(info)
+	if err != nil {
+		nm.Logger.Error(fmt.Sprintf("Unable to create network %s: %s", info.UUID, err.Error()))
+		return
+	}
+	status := CREATE
+	info.Status = &status
+	err = nm.AddNetworkRecord(info)
+	if err != nil {
+		// a network without record would never be deleted, it is removed so that the next desired record creates it again
+		nm.Logger.Error(fmt.Sprintf("Unable to store network %s, deleting it: %s", info.UUID, err.Error()))
+		if derr := nm.DeleteVirtualNetwork(info.UUID); derr != nil {
+			nm.Logger.Error(fmt.Sprintf("Unable to delete network %s: %s", info.UUID, derr.Error()))
+		}
+	}
+}
+
+func (nm *FOSNetworkManagerPluginAbstract) reactPort(info ConnectionPointRecord) {
+	switch info.Status {
+	case DESTROY:
+		_, err := nm.DestroyPortAgent(info.UUID)
+		if err == nil {
+			err = nm.removeRecord(nm.RemovePortRecord(info.UUID))
+		}
+		if err != nil {
+			nm.Logger.Error(fmt.Sprintf("Unable to destroy port %s: %s", info.UUID, err.Error()))
+		}
+	case CREATE, "":
+		id := info.UUID
+		descriptor := ConnectionPointDescriptor{UUID: &id, ID: info.CPID, Name: info.CPID, CPType: info.CPType, VLDRef: info.VLDRef, PortSecurityEnabled: info.PortSecurityEnabled}
+		_, err := nm.storePort(nm.CreatePortAgent(descriptor))
+		if err != nil {
+			nm.Logger.Error(fmt.Sprintf("Unable to create port %s: %s", info.UUID, err.Error()))
+		}
+	}
+}
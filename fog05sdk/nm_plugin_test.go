@@ -0,0 +1,172 @@
+/*
+* Copyright (c) 2014,2019 Contributors to the Eclipse Foundation
+* See the NOTICE file(s) distributed with this work for additional
+* information regarding copyright ownership.
+* This program and the accompanying materials are made available under the
+* terms of the Eclipse Public License 2.0 which is available at
+* http://www.eclipse.org/legal/epl-2.0, or the Apache License, Version 2.0
+* which is available at https://www.apache.org/licenses/LICENSE-2.0.
+* SPDX-License-Identifier: EPL-2.0 OR Apache-2.0
+* Contributors: Gabriele Baldoni, ADLINK Technology Inc.
+* golang APIs
+ */
+
+package fog05sdk_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	fog05sdk "github.com/eclipse-fog05/sdk-go/fog05sdk"
+	"github.com/eclipse-fog05/sdk-go/fog05sdk/fostest"
+	"github.com/sirupsen/logrus/hooks/test"
+)
+
+// fakeNM implements the network manager functions used by the tests, the others panic
+type fakeNM struct {
+	fog05sdk.FOSNetworkManagerPluginInterface
+	mu       sync.Mutex
+	networks map[string]bool
+}
+
+func (f *fakeNM) StartNM() error { return nil }
+
+func (f *fakeNM) CreateFloatingIP() (*fog05sdk.FloatingIPRecord, error) {
+	return &fog05sdk.FloatingIPRecord{UUID: "ip1", Address: "1.2.3.4"}, nil
+}
+
+func (f *fakeNM) MoveInterfaceInNamespace(name string, netns string) (*fog05sdk.InterfaceInfo, error) {
+	return &fog05sdk.InterfaceInfo{Name: name, Namespace: netns}, nil
+}
+
+func (f *fakeNM) CreateBridgesIfNotExist(expected []string) ([]map[string]interface{}, error) {
+	bridges := []map[string]interface{}{}
+	for _, b := range expected {
+		bridges = append(bridges, map[string]interface{}{"name": b})
+	}
+	return bridges, nil
+}
+
+func (f *fakeNM) CreatePortAgent(d fog05sdk.ConnectionPointDescriptor) (*fog05sdk.ConnectionPointRecord, error) {
+	return &fog05sdk.ConnectionPointRecord{UUID: "cp-" + d.ID, CPID: d.ID, Status: fog05sdk.CREATE}, nil
+}
+
+func (f *fakeNM) DeletePort(cpid string) (bool, error) { return true, nil }
+
+func (f *fakeNM) GetAddress(cpid string) (string, error) {
+	return "", &fog05sdk.EvalError{Code: 2, Message: "no address for " + cpid}
+}
+
+func (f *fakeNM) CreateVirtualNetwork(n fog05sdk.VirtualNetwork) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.networks[n.UUID] = true
+	return nil
+}
+
+func (f *fakeNM) DeleteVirtualNetwork(netid string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.networks, netid)
+	return nil
+}
+
+func (f *fakeNM) hasNetwork(netid string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.networks[netid]
+}
+
+// startNM starts the fake network manager nm1 in node n1, requiring only the os plugin whose record is added
+func startNM(t *testing.T, yc *fog05sdk.YaksConnector) (*fog05sdk.FOSNetworkManagerPluginAbstract, *fakeNM) {
+	t.Helper()
+	if err := yc.Local.Actual.AddNodePlugin("n1", "os1", fog05sdk.Plugin{UUID: "os1", Name: "linux", Type: fog05sdk.OSPluginType}); err != nil {
+		t.Fatal(err)
+	}
+	conf := map[string]interface{}{"nodeid": "n1"}
+	man := fog05sdk.Plugin{UUID: "nm1", Name: "linuxbridge", Type: fog05sdk.NetworkManagerPluginType, Configuration: &conf, Requirements: []string{fog05sdk.OSPluginType}}
+	nm, err := fog05sdk.NewFOSNetworkManagerPluginAbstractWithConnector("linuxbridge", 1, "nm1", man, yc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nm.Logger, _ = test.NewNullLogger()
+	fake := &fakeNM{networks: map[string]bool{}}
+	nm.FOSNetworkManagerPluginInterface = fake
+	nm.RegisterPlugin(&man)
+	nm.Start()
+	return nm, fake
+}
+
+func TestNMPluginEvals(t *testing.T) {
+	yc, _ := fostest.NewConnector()
+	nm, _ := startNM(t, yc)
+	defer nm.Close()
+	pl := fog05sdk.NewPluginWithConnector(1, "p1", yc, "n1")
+	if ok, err := pl.GetNMPlugin(); !ok || err != nil {
+		t.Fatalf("GetNMPlugin() = %v, %v", ok, err)
+	}
+	c := pl.NM
+
+	ip, err := c.CreateFloatingIP()
+	if err != nil || (*ip)["address"] != "1.2.3.4" {
+		t.Errorf("CreateFloatingIP() = %v, %v", ip, err)
+	}
+	if ips, err := nm.GetAllFloatingIPRecords(); err != nil || len(ips) != 1 || ips[0].UUID != "ip1" {
+		t.Errorf("floating IP records = %v, %v, want the created one", ips, err)
+	}
+	if i, err := c.MoveInterfaceInNamespace("eth0", "ns1"); err != nil || i.Name != "eth0" || i.Namespace != "ns1" {
+		t.Errorf("MoveInterfaceInNamespace() = %+v, %v", i, err)
+	}
+	if b, err := c.CreateBridgesIfNotExists([]string{"br0", "br1"}); err != nil || len(*b) != 2 || (*b)[1]["name"] != "br1" {
+		t.Errorf("CreateBridgesIfNotExists() = %v, %v", b, err)
+	}
+
+	cp, err := c.CreateConnectionPoint(fog05sdk.ConnectionPointDescriptor{ID: "c1", Name: "c1"})
+	if err != nil || cp.UUID != "cp-c1" || cp.Status != fog05sdk.CREATE {
+		t.Fatalf("CreateConnectionPoint() = %+v, %v", cp, err)
+	}
+	if ports, err := nm.GetAllPortRecords(); err != nil || len(ports) != 1 {
+		t.Errorf("port records = %v, %v, want the created one", ports, err)
+	}
+	if ok, err := c.DeletePort("cp-c1"); !ok || err != nil {
+		t.Errorf("DeletePort() = %v, %v", ok, err)
+	}
+	if ports, err := nm.GetAllPortRecords(); err != nil || len(ports) != 0 {
+		t.Errorf("port records after DeletePort = %v, %v, want none", ports, err)
+	}
+
+	var eerr *fog05sdk.EvalError
+	if _, err := c.GetAddress("c9"); !errors.As(err, &eerr) || eerr.Code != 2 {
+		t.Errorf("GetAddress() failing in the plugin = %v, want its EvalError", err)
+	}
+	res, err := yc.Local.Actual.ExecNMEval("n1", "nm1", "attach_interface_to_bridge", map[string]interface{}{"br_name": "br0"})
+	if !errors.As(err, &eerr) || eerr.Code != fog05sdk.EvalErrorInvalidParameters || res.Err() == nil {
+		t.Errorf("attach_interface_to_bridge without intf_name = %v, want a missing parameter error", err)
+	}
+}
+
+func TestNMPluginNetworks(t *testing.T) {
+	yc, _ := fostest.NewConnector()
+	nm, fake := startNM(t, yc)
+	defer nm.Close()
+	desired := yc.Local.Desired
+
+	if err := desired.AddNodeNetwork("n1", "nm1", "net1", fog05sdk.VirtualNetwork{UUID: "net1", Name: "net1"}); err != nil {
+		t.Fatal(err)
+	}
+	eventually(t, "the network creation", func() bool { return fake.hasNetwork("net1") })
+	n, err := nm.GetNetworkRecord("net1")
+	if err != nil || n.Status == nil || *n.Status != fog05sdk.CREATE {
+		t.Errorf("network record = %+v, %v, want net1 created", n, err)
+	}
+
+	destroy := fog05sdk.DESTROY
+	if err := desired.AddNodeNetwork("n1", "nm1", "net1", fog05sdk.VirtualNetwork{UUID: "net1", Name: "net1", Status: &destroy}); err != nil {
+		t.Fatal(err)
+	}
+	eventually(t, "the network deletion", func() bool { return !fake.hasNetwork("net1") })
+	if _, err := nm.GetNetworkRecord("net1"); !errors.Is(err, fog05sdk.ErrNotFound) {
+		t.Errorf("network record after the deletion: %v, want ErrNotFound", err)
+	}
+}
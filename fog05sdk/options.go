@@ -0,0 +1,99 @@
+/*
+* Copyright (c) 2014,2019 Contributors to the Eclipse Foundation
+* See the NOTICE file(s) distributed with this work for additional
+* information regarding copyright ownership.
+* This program and the accompanying materials are made available under the
+* terms of the Eclipse Public License 2.0 which is available at
+* http://www.eclipse.org/legal/epl-2.0, or the Apache License, Version 2.0
+* which is available at https://www.apache.org/licenses/LICENSE-2.0.
+* SPDX-License-Identifier: EPL-2.0 OR Apache-2.0
+* Contributors: Gabriele Baldoni, ADLINK Technology Inc.
+* golang APIs
+ */
+
+package fog05sdk
+
+import (
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// ConnectorOptions are the options of a YaksConnector, set through ConnectorOption
+type ConnectorOptions struct {
+	// User is the user used to login in YAKS, empty for no credentials
+	User string
+
+	// Password is the password used to login in YAKS
+	Password string
+
+	// WorkspaceRoot is the path under which all the connector paths are stored
+	WorkspaceRoot string
+
+	// Timeout is the default timeout of the operations without a context deadline, 0 for no timeout
+	Timeout time.Duration
+
+	// Logger is the logger used by the connector
+	Logger log.FieldLogger
+
+	// Executor when true listeners and evals are executed by their own goroutine, otherwise by the I/O goroutine
+	Executor bool
+
+	// ReconnectPolicy is the policy used to reconnect to YAKS
+	ReconnectPolicy ReconnectPolicy
+}
+
+// ConnectorOption sets an option of a YaksConnector
+type ConnectorOption func(*ConnectorOptions)
+
+// NewConnectorOptions returns the default options modified by the given ConnectorOption
+func NewConnectorOptions(opts ...ConnectorOption) ConnectorOptions {
+	o := ConnectorOptions{WorkspaceRoot: "/", Logger: logger, Executor: true, ReconnectPolicy: DefaultReconnectPolicy}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithCredentials sets the user and password used to login in YAKS
+func WithCredentials(user string, password string) ConnectorOption {
+	return func(o *ConnectorOptions) {
+		o.User = user
+		o.Password = password
+	}
+}
+
+// WithWorkspaceRoot sets the path under which all the connector paths are stored, by default /
+func WithWorkspaceRoot(root string) ConnectorOption {
+	return func(o *ConnectorOptions) {
+		o.WorkspaceRoot = root
+	}
+}
+
+// WithTimeout sets the default timeout of the operations made without a context deadline
+func WithTimeout(timeout time.Duration) ConnectorOption {
+	return func(o *ConnectorOptions) {
+		o.Timeout = timeout
+	}
+}
+
+// WithLogger sets the logger used by the connector
+func WithLogger(l log.FieldLogger) ConnectorOption {
+	return func(o *ConnectorOptions) {
+		o.Logger = l
+	}
+}
+
+// WithExecutor sets if listeners and evals are executed by their own goroutine, the default, or by the I/O goroutine
+func WithExecutor(executor bool) ConnectorOption {
+	return func(o *ConnectorOptions) {
+		o.Executor = executor
+	}
+}
+
+// WithReconnectPolicy sets the policy used to reconnect to YAKS
+func WithReconnectPolicy(policy ReconnectPolicy) ConnectorOption {
+	return func(o *ConnectorOptions) {
+		o.ReconnectPolicy = policy
+	}
+}
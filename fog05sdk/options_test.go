@@ -0,0 +1,73 @@
+/*
+* Copyright (c) 2014,2019 Contributors to the Eclipse Foundation
+* See the NOTICE file(s) distributed with this work for additional
+* information regarding copyright ownership.
+* This program and the accompanying materials are made available under the
+* terms of the Eclipse Public License 2.0 which is available at
+* http://www.eclipse.org/legal/epl-2.0, or the Apache License, Version 2.0
+* which is available at https://www.apache.org/licenses/LICENSE-2.0.
+* SPDX-License-Identifier: EPL-2.0 OR Apache-2.0
+* Contributors: Gabriele Baldoni, ADLINK Technology Inc.
+* golang APIs
+ */
+
+package fog05sdk_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	fog05sdk "github.com/eclipse-fog05/sdk-go/fog05sdk"
+	"github.com/eclipse-fog05/sdk-go/fog05sdk/fostest"
+	"github.com/sirupsen/logrus/hooks/test"
+)
+
+func TestConnectorOptions(t *testing.T) {
+	l, _ := test.NewNullLogger()
+	policy := fog05sdk.ReconnectPolicy{InitialDelay: time.Second}
+	tests := []struct {
+		name   string
+		opts   []fog05sdk.ConnectorOption
+		change func(*fog05sdk.ConnectorOptions)
+	}{
+		{"defaults", nil, func(*fog05sdk.ConnectorOptions) {}},
+		{"credentials", []fog05sdk.ConnectorOption{fog05sdk.WithCredentials("u", "p")}, func(o *fog05sdk.ConnectorOptions) { o.User, o.Password = "u", "p" }},
+		{"workspace root", []fog05sdk.ConnectorOption{fog05sdk.WithWorkspaceRoot("/fos")}, func(o *fog05sdk.ConnectorOptions) { o.WorkspaceRoot = "/fos" }},
+		{"timeout", []fog05sdk.ConnectorOption{fog05sdk.WithTimeout(time.Second)}, func(o *fog05sdk.ConnectorOptions) { o.Timeout = time.Second }},
+		{"logger", []fog05sdk.ConnectorOption{fog05sdk.WithLogger(l)}, func(o *fog05sdk.ConnectorOptions) { o.Logger = l }},
+		{"executor", []fog05sdk.ConnectorOption{fog05sdk.WithExecutor(false)}, func(o *fog05sdk.ConnectorOptions) { o.Executor = false }},
+		{"reconnect policy", []fog05sdk.ConnectorOption{fog05sdk.WithReconnectPolicy(policy)}, func(o *fog05sdk.ConnectorOptions) { o.ReconnectPolicy = policy }},
+		{"last wins", []fog05sdk.ConnectorOption{fog05sdk.WithTimeout(time.Second), fog05sdk.WithTimeout(time.Minute)}, func(o *fog05sdk.ConnectorOptions) { o.Timeout = time.Minute }},
+	}
+	defaults := fog05sdk.NewConnectorOptions()
+	if defaults.WorkspaceRoot != "/" || !defaults.Executor || defaults.Timeout != 0 || defaults.User != "" || defaults.Logger == nil ||
+		defaults.ReconnectPolicy != fog05sdk.DefaultReconnectPolicy {
+		t.Fatalf("default options = %+v", defaults)
+	}
+	for _, tt := range tests {
+		want := defaults
+		tt.change(&want)
+		if got := fog05sdk.NewConnectorOptions(tt.opts...); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: options = %+v, want %+v", tt.name, got, want)
+		}
+	}
+}
+
+func TestConnectorLogger(t *testing.T) {
+	l, hook := test.NewNullLogger()
+	st := fostest.NewStore()
+	yc := fog05sdk.NewConnector(st, fog05sdk.WithLogger(l))
+	if _, err := yc.Local.Actual.ObserveNodeStatus("n1", func(fog05sdk.NodeStatus) {}); err != nil {
+		t.Fatal(err)
+	}
+	p, _ := yc.Local.Actual.GetNodeStatusPath("n1")
+	if err := st.Put(context.Background(), p, "not json"); err != nil {
+		t.Fatal(err)
+	}
+	e := hook.LastEntry()
+	if e == nil || e.Message != "Unable to decode change" {
+		t.Errorf("connector logged %+v, want the decoding error on the given logger", e)
+	}
+}
@@ -0,0 +1,319 @@
+//go:build linux
+// +build linux
+
+/*
+* Copyright (c) 2014,2019 Contributors to the Eclipse Foundation
+* See the NOTICE file(s) distributed with this work for additional
+* information regarding copyright ownership.
+* This program and the accompanying materials are made available under the
+* terms of the Eclipse Public License 2.0 which is available at
+* http://www.eclipse.org/legal/epl-2.0, or the Apache License, Version 2.0
+* which is available at https://www.apache.org/licenses/LICENSE-2.0.
+* SPDX-License-Identifier: EPL-2.0 OR Apache-2.0
+* Contributors: Gabriele Baldoni, ADLINK Technology Inc.
+* golang APIs
+ */
+
+package fog05sdk
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// DefaultDownloadTimeout is the time limit of the downloads of the HTTP client of NewLinuxOS
+const DefaultDownloadTimeout = 10 * time.Minute
+
+// sysClassNet is where Linux exposes the network interfaces
+const sysClassNet = "/sys/class/net"
+
+// LinuxOS is an implementation of FOSOSPluginInterface for Linux using the Go standard library
+type LinuxOS struct {
+	// MgmtInterface is the interface whose address is the management one, if empty the first IPv4 address of an up interface is used
+	MgmtInterface string
+
+	// Client is the HTTP client used to download files, its Timeout limits the time of a download
+	Client *http.Client
+
+	mu          sync.Mutex
+	unavailable map[string]bool
+}
+
+// NewLinuxOS returns a LinuxOS using the given management interface, eg. the mgmt_interface of the Agent configuration
+func NewLinuxOS(mgmtInterface string) *LinuxOS {
+	return &LinuxOS{MgmtInterface: mgmtInterface, Client: &http.Client{Timeout: DefaultDownloadTimeout}, unavailable: map[string]bool{}}
+}
+
+// StartOS starts the plugin, there is nothing to start
+func (l *LinuxOS) StartOS() error {
+	return nil
+}
+
+// StopOS stops the plugin, there is nothing to stop
+func (l *LinuxOS) StopOS() error {
+	return nil
+}
+
+// DirExists checks if the given directory exists
+func (l *LinuxOS) DirExists(dirpath string) (bool, error) {
+	info, err := os.Stat(dirpath)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return info.IsDir(), nil
+}
+
+// CreateDir creates the given directory together with its parents
+func (l *LinuxOS) CreateDir(dirpath string) (bool, error) {
+	err := os.MkdirAll(dirpath, 0755)
+	return err == nil, err
+}
+
+// RemoveDir removes the given directory and its content
+func (l *LinuxOS) RemoveDir(dirpath string) (bool, error) {
+	err := os.RemoveAll(dirpath)
+	return err == nil, err
+}
+
+// DownloadFile downloads the given URL in the given file, the file is removed if the download fails
+func (l *LinuxOS) DownloadFile(url string, filepath string) (bool, error) {
+	resp, err := l.Client.Get(url)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return false, &FError{"Unable to download " + url + ": " + resp.Status, nil}
+	}
+	f, err := os.Create(filepath)
+	if err != nil {
+		return false, err
+	}
+	_, err = io.Copy(f, resp.Body)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(filepath)
+		return false, err
+	}
+	return true, nil
+}
+
+// ExecuteCommand executes the given command, in sh if external. When blocking it waits the command and returns its output,
+// otherwise it returns the PID of the started command
+func (l *LinuxOS) ExecuteCommand(command string, blocking bool, external bool) (string, error) {
+	var cmd *exec.Cmd
+	if external {
+		cmd = exec.Command("sh", "-c", command)
+	} else {
+		args := strings.Fields(command)
+		if len(args) == 0 {
+			return "", &FError{"Empty command", nil}
+		}
+		cmd = exec.Command(args[0], args[1:]...)
+	}
+	if blocking {
+		out, err := cmd.CombinedOutput()
+		return strings.TrimSpace(string(out)), err
+	}
+	err := cmd.Start()
+	if err != nil {
+		return "", err
+	}
+	// reaps the command when it ends
+	go cmd.Wait()
+	return strconv.Itoa(cmd.Process.Pid), nil
+}
+
+// CreateFile creates the given empty file, an existing file is truncated
+func (l *LinuxOS) CreateFile(filepath string) (bool, error) {
+	f, err := os.Create(filepath)
+	if err != nil {
+		return false, err
+	}
+	err = f.Close()
+	return err == nil, err
+}
+
+// RemoveFile removes the given file
+func (l *LinuxOS) RemoveFile(filepath string) (bool, error) {
+	err := os.Remove(filepath)
+	return err == nil, err
+}
+
+// StoreFile stores the content in the file with the given directory and name, creating the directory if needed
+func (l *LinuxOS) StoreFile(content string, dirpath string, filename string) (bool, error) {
+	err := os.MkdirAll(dirpath, 0755)
+	if err != nil {
+		return false, err
+	}
+	err = ioutil.WriteFile(filepath.Join(dirpath, filename), []byte(content), 0644)
+	return err == nil, err
+}
+
+// ReadFile reads the given file, through sudo if root is requested
+func (l *LinuxOS) ReadFile(filepath string, root bool) (string, error) {
+	if root {
+		out, err := exec.Command("sudo", "cat", filepath).Output()
+		return string(out), err
+	}
+	b, err := ioutil.ReadFile(filepath)
+	return string(b), err
+}
+
+// FileExists checks if the given file exists and is not a directory
+func (l *LinuxOS) FileExists(filepath string) (bool, error) {
+	info, err := os.Stat(filepath)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return !info.IsDir(), nil
+}
+
+// SendSigInt sends the INT signal to the given PID
+func (l *LinuxOS) SendSigInt(pid int) (bool, error) {
+	err := syscall.Kill(pid, syscall.SIGINT)
+	return err == nil, err
+}
+
+// SendSigKill sends the KILL signal to the given PID
+func (l *LinuxOS) SendSigKill(pid int) (bool, error) {
+	err := syscall.Kill(pid, syscall.SIGKILL)
+	return err == nil, err
+}
+
+// CheckIfPIDExists checks if the given PID is running, sending it the signal 0
+func (l *LinuxOS) CheckIfPIDExists(pid int) (bool, error) {
+	err := syscall.Kill(pid, 0)
+	switch err {
+	case nil, syscall.EPERM:
+		return true, nil
+	case syscall.ESRCH:
+		return false, nil
+	}
+	return false, err
+}
+
+// GetInterfaceType returns the type of the given network interface, one of loopback, wireless, bridge, virtual and ethernet
+func (l *LinuxOS) GetInterfaceType(facename string) (string, error) {
+	dir := filepath.Join(sysClassNet, facename)
+	if _, err := os.Stat(dir); err != nil {
+		return "", &FError{"Interface " + facename + " not found", ErrNotFound}
+	}
+	t, _ := ioutil.ReadFile(filepath.Join(dir, "type"))
+	// 772 is ARPHRD_LOOPBACK
+	if strings.TrimSpace(string(t)) == "772" {
+		return "loopback", nil
+	}
+	if _, err := os.Stat(filepath.Join(dir, "wireless")); err == nil {
+		return "wireless", nil
+	}
+	if _, err := os.Stat(filepath.Join(dir, "bridge")); err == nil {
+		return "bridge", nil
+	}
+	if link, err := os.Readlink(dir); err == nil && strings.Contains(link, "/virtual/") {
+		return "virtual", nil
+	}
+	return "ethernet", nil
+}
+
+// SetInterfaceUnaviable marks the given network interface as unavailable, eg. because it is assigned to an FDU
+func (l *LinuxOS) SetInterfaceUnaviable(facename string) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.unavailable == nil {
+		l.unavailable = map[string]bool{}
+	}
+	l.unavailable[facename] = true
+	return true, nil
+}
+
+// SetInterfaceAvailable marks the given network interface as available
+func (l *LinuxOS) SetInterfaceAvailable(facename string) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.unavailable, facename)
+	return true, nil
+}
+
+// IsInterfaceAvailable returns false if the given network interface was set as unavailable
+func (l *LinuxOS) IsInterfaceAvailable(facename string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return !l.unavailable[facename]
+}
+
+// Checksum returns the hex encoded SHA256 checksum of the given file
+func (l *LinuxOS) Checksum(filepath string) (string, error) {
+	f, err := os.Open(filepath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	_, err = io.Copy(h, f)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// LocalMgmtAddress returns the IPv4 address of the management interface, or the first IPv4 address of an up interface that is not a loopback
+func (l *LinuxOS) LocalMgmtAddress() (string, error) {
+	if l.MgmtInterface != "" {
+		intf, err := net.InterfaceByName(l.MgmtInterface)
+		if err != nil {
+			return "", &FError{"Management interface " + l.MgmtInterface + " not found", err}
+		}
+		if ip := interfaceIPv4(intf); ip != "" {
+			return ip, nil
+		}
+		return "", &FError{"Management interface " + l.MgmtInterface + " has no IPv4 address", ErrNotFound}
+	}
+	intfs, err := net.Interfaces()
+	if err != nil {
+		return "", err
+	}
+	for i := range intfs {
+		if intfs[i].Flags&net.FlagUp == 0 || intfs[i].Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		if ip := interfaceIPv4(&intfs[i]); ip != "" {
+			return ip, nil
+		}
+	}
+	return "", &FError{"No interface with an IPv4 address", ErrNotFound}
+}
+
+// interfaceIPv4 returns the first IPv4 address of the interface, empty if it has none
+func interfaceIPv4(intf *net.Interface) string {
+	addrs, err := intf.Addrs()
+	if err != nil {
+		return ""
+	}
+	for _, a := range addrs {
+		if ipnet, ok := a.(*net.IPNet); ok && ipnet.IP.To4() != nil {
+			return ipnet.IP.String()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,274 @@
+/*
+* Copyright (c) 2014,2019 Contributors to the Eclipse Foundation
+* See the NOTICE file(s) distributed with this work for additional
+* information regarding copyright ownership.
+* This program and the accompanying materials are made available under the
+* terms of the Eclipse Public License 2.0 which is available at
+* http://www.eclipse.org/legal/epl-2.0, or the Apache License, Version 2.0
+* which is available at https://www.apache.org/licenses/LICENSE-2.0.
+* SPDX-License-Identifier: EPL-2.0 OR Apache-2.0
+* Contributors: Gabriele Baldoni, ADLINK Technology Inc.
+* golang APIs
+ */
+
+package fog05sdk
+
+import (
+	b64 "encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"os"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+// OSPluginType is the type of the OS plugins in their manifest
+const OSPluginType = "os"
+
+// FOSOSPluginInterface is the interface to be implemented for an OS Plugin, each function but StartOS and StopOS
+// is called by the eval with the same name used by the OS object
+type FOSOSPluginInterface interface {
+
+	//StartOS starts the plugin
+	StartOS() error
+
+	//StopOS stops the plugin
+	StopOS() error
+
+	//DirExists checks if the given directory exists, called by dir_exists
+	DirExists(string) (bool, error)
+
+	//CreateDir creates the given directory, called by create_dir
+	CreateDir(string) (bool, error)
+
+	//RemoveDir removes the given directory, called by remove_dir
+	RemoveDir(string) (bool, error)
+
+	//DownloadFile downloads the given URL in the given file, called by download_file
+	DownloadFile(string, string) (bool, error)
+
+	//ExecuteCommand executes the given command, blocking until it ends if requested, or in a shell if external, called by execute_command
+	ExecuteCommand(string, bool, bool) (string, error)
+
+	//CreateFile creates the given empty file, called by create_file
+	CreateFile(string) (bool, error)
+
+	//RemoveFile removes the given file, called by remove_file
+	RemoveFile(string) (bool, error)
+
+	//StoreFile stores the given content in the file with the given directory and name, called by store_file
+	StoreFile(string, string, string) (bool, error)
+
+	//ReadFile reads the given file, as root if requested, called by read_file
+	ReadFile(string, bool) (string, error)
+
+	//FileExists checks if the given file exists, called by file_exists
+	FileExists(string) (bool, error)
+
+	//SendSigInt sends the INT signal to the given PID, called by send_sig_int
+	SendSigInt(int) (bool, error)
+
+	//SendSigKill sends the KILL signal to the given PID, called by send_sig_kill
+	SendSigKill(int) (bool, error)
+
+	//CheckIfPIDExists checks if the given PID is running, called by check_if_pid_exists
+	CheckIfPIDExists(int) (bool, error)
+
+	//GetInterfaceType returns the type of the given network interface, called by get_intf_type
+	GetInterfaceType(string) (string, error)
+
+	//SetInterfaceUnaviable sets the given network interface as unavailable, called by set_interface_unaviable
+	SetInterfaceUnaviable(string) (bool, error)
+
+	//SetInterfaceAvailable sets the given network interface as available, called by set_interface_available
+	SetInterfaceAvailable(string) (bool, error)
+
+	//Checksum returns the SHA256 checksum of the given file, called by checksum
+	Checksum(string) (string, error)
+
+	//LocalMgmtAddress returns the management IP address of the node, called by local_mgmt_address
+	LocalMgmtAddress() (string, error)
+}
+
+// FOSOSPluginAbstract represents an OS Plugin for Eclipse fog05, it registers the OS evals calling FOSOSPluginInterface
+type FOSOSPluginAbstract struct {
+	Pid           int
+	Name          string
+	Connector     *YaksConnector
+	Node          string
+	Configuration map[string]interface{}
+	Logger        *log.Logger
+	FOSOSPluginInterface
+	FOSPlugin
+}
+
+// NewFOSOSPluginAbstract returns a new FOSOSPluginAbstract object
+func NewFOSOSPluginAbstract(name string, version int, pluginid string, manifest Plugin) (*FOSOSPluginAbstract, error) {
+	con, logger, err := connectorFromManifest(manifest)
+	if err != nil {
+		return nil, err
+	}
+	osp, err := NewFOSOSPluginAbstractWithConnector(name, version, pluginid, manifest, con)
+	if err != nil {
+		con.Close()
+		return nil, err
+	}
+	osp.Logger = logger
+	return osp, nil
+}
+
+// NewFOSOSPluginAbstractWithConnector returns a new FOSOSPluginAbstract object using the given connector instead of connecting to YAKS
+func NewFOSOSPluginAbstractWithConnector(name string, version int, pluginid string, manifest Plugin, con *YaksConnector) (*FOSOSPluginAbstract, error) {
+	if pluginid == "" {
+		pluginid = uuid.UUID.String(uuid.New())
+	}
+	if manifest.Configuration == nil {
+		return nil, &FError{"Missing configuration in plugin manifest", nil}
+	}
+	conf := *manifest.Configuration
+	node, ok := conf["nodeid"].(string)
+	if !ok {
+		return nil, &FError{"Missing nodeid in plugin configuration", nil}
+	}
+	pl := NewPluginWithConnector(version, pluginid, con, node)
+
+	return &FOSOSPluginAbstract{Pid: os.Getpid(), Name: name, Connector: con, Node: node, FOSPlugin: *pl, Logger: log.New(), Configuration: conf}, nil
+}
+
+// Start starts the Plugin, registers the OS evals and calls StartOS of FOSOSPluginInterface
+func (osp *FOSOSPluginAbstract) Start() {
+	for name, handler := range osp.evals() {
+		err := osp.Connector.Local.Actual.AddOSEval(osp.Node, name, evalHandler(handler))
+		if err != nil {
+			osp.Logger.Error(fmt.Sprintf("Unable to register eval %s: %s", name, err.Error()))
+			osp.Close()
+			return
+		}
+	}
+	err := osp.FOSOSPluginInterface.StartOS()
+	if err != nil {
+		osp.Logger.Error(fmt.Sprintf("Plugin StartOS returned error %s", err.Error()))
+		osp.Close()
+	}
+}
+
+// Close closes the Plugin, called by FOSOSPluginInterface.StopOS()
+func (osp *FOSOSPluginAbstract) Close() {
+	osp.RemovePlugin()
+	if err := osp.Connector.Close(); err != nil {
+		osp.Logger.Error(fmt.Sprintf("Unable to close the connector: %s", err.Error()))
+	}
+	osp.Logger.Info("Plugin closed")
+}
+
+// RegisterPlugin registers the plugin in the node and starts its heartbeats
+func (osp *FOSOSPluginAbstract) RegisterPlugin(manifest *Plugin) {
+	osp.Connector.Local.Actual.AddNodePlugin(osp.Node, osp.FOSPlugin.UUID, *manifest)
+	if err := osp.FOSPlugin.StartHeartbeat(); err != nil {
+		osp.Logger.Error(fmt.Sprintf("Unable to start the heartbeat: %s", err.Error()))
+	}
+}
+
+// RemovePlugin stops the heartbeats and removes the plugin in the node
+func (osp *FOSOSPluginAbstract) RemovePlugin() {
+	if err := osp.FOSPlugin.StopHeartbeat(); err != nil {
+		osp.Logger.Error(fmt.Sprintf("Unable to remove the heartbeat: %s", err.Error()))
+	}
+	osp.Connector.Local.Actual.RemoveNodePlugin(osp.Node, osp.FOSPlugin.UUID)
+}
+
+// evals returns the handlers of the OS evals, each decodes the parameters and returns the call to the plugin
+func (osp *FOSOSPluginAbstract) evals() map[string]func(*evalArgs) func() (interface{}, error) {
+	return map[string]func(*evalArgs) func() (interface{}, error){
+		"dir_exists": func(a *evalArgs) func() (interface{}, error) {
+			path := a.string("dir_path")
+			return func() (interface{}, error) { return osp.DirExists(path) }
+		},
+		"create_dir": func(a *evalArgs) func() (interface{}, error) {
+			path := a.string("dir_path")
+			return func() (interface{}, error) { return osp.CreateDir(path) }
+		},
+		"remove_dir": func(a *evalArgs) func() (interface{}, error) {
+			path := a.string("dir_path")
+			return func() (interface{}, error) { return osp.RemoveDir(path) }
+		},
+		"download_file": func(a *evalArgs) func() (interface{}, error) {
+			url, path := a.string("url"), a.string("file_path")
+			return func() (interface{}, error) { return osp.DownloadFile(url, path) }
+		},
+		"execute_command": func(a *evalArgs) func() (interface{}, error) {
+			command, blocking, external := a.string("command"), a.bool("blocking"), a.bool("external")
+			return func() (interface{}, error) { return osp.ExecuteCommand(command, blocking, external) }
+		},
+		"create_file": func(a *evalArgs) func() (interface{}, error) {
+			path := a.string("file_path")
+			return func() (interface{}, error) { return osp.CreateFile(path) }
+		},
+		"remove_file": func(a *evalArgs) func() (interface{}, error) {
+			path := a.string("file_path")
+			return func() (interface{}, error) { return osp.RemoveFile(path) }
+		},
+		"store_file": func(a *evalArgs) func() (interface{}, error) {
+			path, name := a.string("file_path"), a.string("filename")
+			content := decodeFileContent(a, "content")
+			return func() (interface{}, error) { return osp.StoreFile(content, path, name) }
+		},
+		"read_file": func(a *evalArgs) func() (interface{}, error) {
+			path, root := a.string("file_path"), a.bool("root")
+			return func() (interface{}, error) { return osp.ReadFile(path, root) }
+		},
+		"file_exists": func(a *evalArgs) func() (interface{}, error) {
+			path := a.string("file_path")
+			return func() (interface{}, error) { return osp.FileExists(path) }
+		},
+		"send_sig_int": func(a *evalArgs) func() (interface{}, error) {
+			pid := a.int("pid")
+			return func() (interface{}, error) { return osp.SendSigInt(pid) }
+		},
+		"send_sig_kill": func(a *evalArgs) func() (interface{}, error) {
+			pid := a.int("pid")
+			return func() (interface{}, error) { return osp.SendSigKill(pid) }
+		},
+		"check_if_pid_exists": func(a *evalArgs) func() (interface{}, error) {
+			pid := a.int("pid")
+			return func() (interface{}, error) { return osp.CheckIfPIDExists(pid) }
+		},
+		"get_intf_type": func(a *evalArgs) func() (interface{}, error) {
+			name := a.string("name")
+			return func() (interface{}, error) { return osp.GetInterfaceType(name) }
+		},
+		"set_interface_unaviable": func(a *evalArgs) func() (interface{}, error) {
+			name := a.string("intf_name")
+			return func() (interface{}, error) { return osp.SetInterfaceUnaviable(name) }
+		},
+		"set_interface_available": func(a *evalArgs) func() (interface{}, error) {
+			name := a.string("intf_name")
+			return func() (interface{}, error) { return osp.SetInterfaceAvailable(name) }
+		},
+		"checksum": func(a *evalArgs) func() (interface{}, error) {
+			path := a.string("file_path")
+			return func() (interface{}, error) { return osp.Checksum(path) }
+		},
+		"local_mgmt_address": func(a *evalArgs) func() (interface{}, error) {
+			return func() (interface{}, error) { return osp.LocalMgmtAddress() }
+		},
+	}
+}
+
+// decodeFileContent decodes the content of store_file, that OS.StoreFile sends as the hex encoding of the base64 encoding
+func decodeFileContent(a *evalArgs, name string) string {
+	s := a.string(name)
+	if a.err != nil {
+		return ""
+	}
+	b, err := hex.DecodeString(s)
+	if err == nil {
+		b, err = b64.StdEncoding.DecodeString(string(b))
+	}
+	if err != nil {
+		a.err = &EvalError{Code: EvalErrorInvalidParameters, Message: "Invalid parameter " + name + ": " + err.Error()}
+		return ""
+	}
+	return string(b)
+}
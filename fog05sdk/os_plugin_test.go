@@ -0,0 +1,175 @@
+/*
+* Copyright (c) 2014,2019 Contributors to the Eclipse Foundation
+* See the NOTICE file(s) distributed with this work for additional
+* information regarding copyright ownership.
+* This program and the accompanying materials are made available under the
+* terms of the Eclipse Public License 2.0 which is available at
+* http://www.eclipse.org/legal/epl-2.0, or the Apache License, Version 2.0
+* which is available at https://www.apache.org/licenses/LICENSE-2.0.
+* SPDX-License-Identifier: EPL-2.0 OR Apache-2.0
+* Contributors: Gabriele Baldoni, ADLINK Technology Inc.
+* golang APIs
+ */
+
+package fog05sdk_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	fog05sdk "github.com/eclipse-fog05/sdk-go/fog05sdk"
+	"github.com/eclipse-fog05/sdk-go/fog05sdk/fostest"
+	"github.com/sirupsen/logrus/hooks/test"
+)
+
+// startLinuxOS starts the OS plugin os1 of node n1 with the LinuxOS implementation, and returns the OS client of another plugin
+func startLinuxOS(t *testing.T, yc *fog05sdk.YaksConnector) (*fog05sdk.FOSOSPluginAbstract, *fog05sdk.OS) {
+	t.Helper()
+	conf := map[string]interface{}{"nodeid": "n1"}
+	man := fog05sdk.Plugin{UUID: "os1", Name: "linux", Type: fog05sdk.OSPluginType, Configuration: &conf}
+	osp, err := fog05sdk.NewFOSOSPluginAbstractWithConnector("linux", 1, "os1", man, yc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	osp.Logger, _ = test.NewNullLogger()
+	osp.FOSOSPluginInterface = fog05sdk.NewLinuxOS("")
+	osp.RegisterPlugin(&man)
+	osp.Start()
+	pl := fog05sdk.NewPluginWithConnector(1, "p1", yc, "n1")
+	if ok, err := pl.GetOSPlugin(); !ok || err != nil {
+		t.Fatalf("GetOSPlugin() = %v, %v", ok, err)
+	}
+	return osp, pl.OS
+}
+
+func tempDir(t *testing.T) (string, func()) {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "fog05sdk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir, func() { os.RemoveAll(dir) }
+}
+
+func TestOSPluginEvals(t *testing.T) {
+	yc, _ := fostest.NewConnector()
+	osp, c := startLinuxOS(t, yc)
+	defer osp.Close()
+	dir, clean := tempDir(t)
+	defer clean()
+	sub := filepath.Join(dir, "a", "b")
+	file := filepath.Join(dir, "a", "f.txt")
+
+	bools := []struct {
+		name string
+		call func() (bool, error)
+		want bool
+	}{
+		{"DirExists", func() (bool, error) { return c.DirExists(sub) }, false},
+		{"CreateDir", func() (bool, error) { return c.CreateDir(sub) }, true},
+		{"DirExists created", func() (bool, error) { return c.DirExists(sub) }, true},
+		{"StoreFile", func() (bool, error) { return c.StoreFile("hello world", filepath.Dir(file), "f.txt") }, true},
+		{"FileExists", func() (bool, error) { return c.FileExists(file) }, true},
+		{"CheckIfPIDExists", func() (bool, error) { return c.CheckIfPIDExists(os.Getpid()) }, true},
+		{"RemoveFile", func() (bool, error) { return c.RemoveFile(file) }, true},
+		{"FileExists removed", func() (bool, error) { return c.FileExists(file) }, false},
+		{"RemoveDir", func() (bool, error) { return c.RemoveDir(sub) }, true},
+		{"DirExists removed", func() (bool, error) { return c.DirExists(sub) }, false},
+	}
+	for _, tt := range bools {
+		if got, err := tt.call(); err != nil || got != tt.want {
+			t.Errorf("%s() = %v, %v, want %v", tt.name, got, err, tt.want)
+		}
+	}
+
+	if _, err := c.StoreFile("hello world", filepath.Dir(file), "f.txt"); err != nil {
+		t.Fatal(err)
+	}
+	strs := []struct {
+		name string
+		call func() (string, error)
+		want string
+	}{
+		{"ReadFile", func() (string, error) { return c.ReadFile(file, false) }, "hello world"},
+		{"Checksum", func() (string, error) { return c.Checksum(file) }, "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9"},
+		{"ExecuteCommand", func() (string, error) { return c.ExecuteCommand("echo hi there", true, false) }, "hi there"},
+		{"ExecuteCommand external", func() (string, error) { return c.ExecuteCommand("echo a | tr a b", true, true) }, "b"},
+		{"GetInterfaceType", func() (string, error) { return c.GetInterfaceType("lo") }, "loopback"},
+	}
+	for _, tt := range strs {
+		if got, err := tt.call(); err != nil || got != tt.want {
+			t.Errorf("%s() = %q, %v, want %q", tt.name, got, err, tt.want)
+		}
+	}
+
+	var eerr *fog05sdk.EvalError
+	if _, err := c.GetInterfaceType("nope0"); !errors.As(err, &eerr) {
+		t.Errorf("GetInterfaceType() of a missing interface = %v, want an EvalError", err)
+	}
+	_, err := yc.Local.Actual.ExecOSEval("n1", "send_sig_int", map[string]interface{}{"pid": "abc"})
+	if !errors.As(err, &eerr) || eerr.Code != fog05sdk.EvalErrorInvalidParameters {
+		t.Errorf("send_sig_int with an invalid pid = %v, want an invalid parameters error", err)
+	}
+}
+
+func TestLinuxOSDownloadFile(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ok", func(w http.ResponseWriter, r *http.Request) { w.Write([]byte("content")) })
+	mux.HandleFunc("/truncated", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "100")
+		w.Write([]byte("partial"))
+	})
+	mux.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("partial"))
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	dir, clean := tempDir(t)
+	defer clean()
+
+	l := fog05sdk.NewLinuxOS("")
+	if l.Client.Timeout != fog05sdk.DefaultDownloadTimeout {
+		t.Errorf("default download timeout = %v, want %v", l.Client.Timeout, fog05sdk.DefaultDownloadTimeout)
+	}
+	l.Client.Timeout = 100 * time.Millisecond
+	tests := []struct {
+		name string
+		path string
+		want string
+	}{
+		{"ok", "/ok", "content"},
+		{"not found", "/missing", ""},
+		{"truncated", "/truncated", ""},
+		{"timeout", "/slow", ""},
+	}
+	for _, tt := range tests {
+		file := filepath.Join(dir, tt.name)
+		ok, err := l.DownloadFile(srv.URL+tt.path, file)
+		content, rerr := ioutil.ReadFile(file)
+		if tt.want == "" {
+			if ok || err == nil || !os.IsNotExist(rerr) {
+				t.Errorf("%s: DownloadFile() = %v, %v, file read %q, %v, want an error and no file", tt.name, ok, err, content, rerr)
+			}
+			continue
+		}
+		if !ok || err != nil || string(content) != tt.want {
+			t.Errorf("%s: DownloadFile() = %v, %v, file %q, %v, want %q", tt.name, ok, err, content, rerr, tt.want)
+		}
+	}
+
+	yc, _ := fostest.NewConnector()
+	osp, c := startLinuxOS(t, yc)
+	defer osp.Close()
+	file := filepath.Join(dir, "eval")
+	if ok, err := c.DownloadFile(srv.URL+"/ok", file); !ok || err != nil {
+		t.Errorf("DownloadFile() through the OS plugin = %v, %v", ok, err)
+	}
+}
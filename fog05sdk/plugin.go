@@ -1,279 +1,259 @@
 package fog05sdk
 
 import (
+	"context"
 	b64 "encoding/base64"
 	"encoding/hex"
 	"encoding/json"
-	"strconv"
+	"errors"
+	"sync"
+	"time"
 
 	"github.com/google/uuid"
 )
 
+// notLoaded is the error of the calls to an Agent, OS or NM plugin not loaded by the plugin
+func notLoaded(what string) error {
+	return &FError{what + " not loaded", ErrNotFound}
+}
+
 // OS is the object to interact with OS Plugin
 type OS struct {
 	uuid      string
 	connector *YaksConnector
 	node      string
+	ctx       context.Context
+}
+
+// WithContext returns a copy of the OS whose calls use the given context
+func (os *OS) WithContext(ctx context.Context) *OS {
+	c := *os
+	c.ctx = ctx
+	return &c
+}
+
+func (os *OS) context() context.Context {
+	if os.ctx == nil {
+		return context.Background()
+	}
+	return os.ctx
+}
+
+// call calls the Eval registered within the OS Plugin decoding its result in result
+func (os *OS) call(fname string, fparameters map[string]interface{}, result interface{}) error {
+	if os == nil {
+		return notLoaded("OS Plugin")
+	}
+	r, err := os.connector.Local.Actual.WithContext(os.context()).EvalOS(os.node, fname, fparameters, result)
+	if err != nil {
+		return err
+	}
+	if !r.hasResult() {
+		return &FError{"Function " + fname + " replied without result", nil}
+	}
+	return nil
 }
 
 // CallOSPluginFunction calls an Eval registered within the OS Plugin, returns a pointer to a genering interface{}
 func (os *OS) CallOSPluginFunction(fname string, fparameters map[string]interface{}) (*string, error) {
-	res, err := os.connector.Local.Actual.ExecOSEval(os.node, fname, fparameters)
+	if os == nil {
+		return nil, notLoaded("OS Plugin")
+	}
+	res, err := os.connector.Local.Actual.WithContext(os.context()).ExecOSEval(os.node, fname, fparameters)
 	if err != nil {
 		return nil, err
 	}
-	if res.Error != nil {
-		er := FError{*res.ErrorMessage + " ErrNo: " + string(*res.Error), nil}
-		return nil, &er
+	if res.Result == nil {
+		return nil, &FError{"Function " + fname + " replied without result", nil}
 	}
 	return res.Result, nil
 }
 
 // DirExists check if the given directory exists
 func (os *OS) DirExists(dirpath string) (bool, error) {
-	r, err := os.CallOSPluginFunction("dir_exists", map[string]interface{}{"dir_path": dirpath})
+	var b evalBool
+	err := os.call("dir_exists", map[string]interface{}{"dir_path": dirpath}, &b)
 	if err != nil {
 		return false, err
 	}
-
-	b, err := strconv.ParseBool(*r)
-	if err != nil {
-		er := FError{"Error on conversion: " + err.Error(), nil}
-		return false, &er
-	}
-	return b, nil
+	return bool(b), nil
 }
 
 // CreateDir creates the given directory
 func (os *OS) CreateDir(dirpath string) (bool, error) {
-	r, err := os.CallOSPluginFunction("create_dir", map[string]interface{}{"dir_path": dirpath})
+	var b evalBool
+	err := os.call("create_dir", map[string]interface{}{"dir_path": dirpath}, &b)
 	if err != nil {
 		return false, err
 	}
-
-	b, err := strconv.ParseBool(*r)
-	if err != nil {
-		er := FError{"Error on conversion: " + err.Error(), nil}
-		return false, &er
-	}
-	return b, nil
+	return bool(b), nil
 }
 
 // RemoveDir removes the given directory
 func (os *OS) RemoveDir(dirpath string) (bool, error) {
-	r, err := os.CallOSPluginFunction("remove_dir", map[string]interface{}{"dir_path": dirpath})
+	var b evalBool
+	err := os.call("remove_dir", map[string]interface{}{"dir_path": dirpath}, &b)
 	if err != nil {
 		return false, err
 	}
-
-	b, err := strconv.ParseBool(*r)
-	if err != nil {
-		er := FError{"Error on conversion: " + err.Error(), nil}
-		return false, &er
-	}
-	return b, nil
+	return bool(b), nil
 }
 
 // DownloadFile downloads the given file into the given path
 func (os *OS) DownloadFile(url string, filepath string) (bool, error) {
-	r, err := os.CallOSPluginFunction("download_file", map[string]interface{}{"url": url, "file_path": filepath})
+	var b evalBool
+	err := os.call("download_file", map[string]interface{}{"url": url, "file_path": filepath}, &b)
 	if err != nil {
 		return false, err
 	}
-
-	b, err := strconv.ParseBool(*r)
-	if err != nil {
-		er := FError{"Error on conversion: " + err.Error(), nil}
-		return false, &er
-	}
-	return b, nil
+	return bool(b), nil
 }
 
 // ExecuteCommand executes the given command, with given flags
 func (os *OS) ExecuteCommand(command string, blocking bool, external bool) (string, error) {
-	r, err := os.CallOSPluginFunction("execute_command", map[string]interface{}{"command": command, "blocking": blocking, "external": external})
+	var r string
+	err := os.call("execute_command", map[string]interface{}{"command": command, "blocking": blocking, "external": external}, &r)
 	if err != nil {
 		return "", err
 	}
 
-	return *r, nil
+	return r, nil
 }
 
 // CreateFile creates the empty given file
 func (os *OS) CreateFile(filepath string) (bool, error) {
-	r, err := os.CallOSPluginFunction("create_file", map[string]interface{}{"file_path": filepath})
+	var b evalBool
+	err := os.call("create_file", map[string]interface{}{"file_path": filepath}, &b)
 	if err != nil {
 		return false, err
 	}
-
-	b, err := strconv.ParseBool(*r)
-	if err != nil {
-		er := FError{"Error on conversion: " + err.Error(), nil}
-		return false, &er
-	}
-	return b, nil
+	return bool(b), nil
 }
 
 // RemoveFile removes the given file
 func (os *OS) RemoveFile(filepath string) (bool, error) {
-	r, err := os.CallOSPluginFunction("remove_file", map[string]interface{}{"file_path": filepath})
+	var b evalBool
+	err := os.call("remove_file", map[string]interface{}{"file_path": filepath}, &b)
 	if err != nil {
 		return false, err
 	}
-
-	b, err := strconv.ParseBool(*r)
-	if err != nil {
-		er := FError{"Error on conversion: " + err.Error(), nil}
-		return false, &er
-	}
-	return b, nil
+	return bool(b), nil
 }
 
 // StoreFile creates and stores the given content into the given file
 func (os *OS) StoreFile(content string, filepath string, filename string) (bool, error) {
 
 	c := hex.EncodeToString([]byte(b64.StdEncoding.EncodeToString([]byte(content))))
-	r, err := os.CallOSPluginFunction("store_file", map[string]interface{}{"file_path": filepath, "filename": filename, "content": c})
+	var b evalBool
+	err := os.call("store_file", map[string]interface{}{"file_path": filepath, "filename": filename, "content": c}, &b)
 	if err != nil {
 		return false, err
 	}
-
-	b, err := strconv.ParseBool(*r)
-	if err != nil {
-		er := FError{"Error on conversion: " + err.Error(), nil}
-		return false, &er
-	}
-	return b, nil
+	return bool(b), nil
 }
 
 // ReadFile reads the given file
 func (os *OS) ReadFile(filepath string, root bool) (string, error) {
-	r, err := os.CallOSPluginFunction("read_file", map[string]interface{}{"file_path": filepath, "root": root})
+	var r string
+	err := os.call("read_file", map[string]interface{}{"file_path": filepath, "root": root}, &r)
 	if err != nil {
 		return "", err
 	}
 
-	return *r, nil
+	return r, nil
 }
 
 // FileExists checks if the given file exists
 func (os *OS) FileExists(filepath string) (bool, error) {
-	r, err := os.CallOSPluginFunction("file_exists", map[string]interface{}{"file_path": filepath})
+	var b evalBool
+	err := os.call("file_exists", map[string]interface{}{"file_path": filepath}, &b)
 	if err != nil {
 		return false, err
 	}
-
-	b, err := strconv.ParseBool(*r)
-	if err != nil {
-		er := FError{"Error on conversion: " + err.Error(), nil}
-		return false, &er
-	}
-	return b, nil
+	return bool(b), nil
 }
 
 // SendSigInt sends INT signal to the given PID
 func (os *OS) SendSigInt(pid int) (bool, error) {
-	r, err := os.CallOSPluginFunction("send_sig_int", map[string]interface{}{"pid": pid})
+	var b evalBool
+	err := os.call("send_sig_int", map[string]interface{}{"pid": pid}, &b)
 	if err != nil {
 		return false, err
 	}
-
-	b, err := strconv.ParseBool(*r)
-	if err != nil {
-		er := FError{"Error on conversion: " + err.Error(), nil}
-		return false, &er
-	}
-	return b, nil
+	return bool(b), nil
 }
 
 // SendSigKill sends the KILL signal to the given PID
 func (os *OS) SendSigKill(pid int) (bool, error) {
-	r, err := os.CallOSPluginFunction("send_sig_kill", map[string]interface{}{"pid": pid})
+	var b evalBool
+	err := os.call("send_sig_kill", map[string]interface{}{"pid": pid}, &b)
 	if err != nil {
 		return false, err
 	}
-
-	b, err := strconv.ParseBool(*r)
-	if err != nil {
-		er := FError{"Error on conversion: " + err.Error(), nil}
-		return false, &er
-	}
-	return b, nil
+	return bool(b), nil
 }
 
 // CheckIfPIDExists check if the PID is still running
 func (os *OS) CheckIfPIDExists(pid int) (bool, error) {
-	r, err := os.CallOSPluginFunction("check_if_pid_exists", map[string]interface{}{"pid": pid})
+	var b evalBool
+	err := os.call("check_if_pid_exists", map[string]interface{}{"pid": pid}, &b)
 	if err != nil {
 		return false, err
 	}
-
-	b, err := strconv.ParseBool(*r)
-	if err != nil {
-		er := FError{"Error on conversion: " + err.Error(), nil}
-		return false, &er
-	}
-	return b, nil
+	return bool(b), nil
 }
 
 // GetInterfaceType get the interface type for the given network interface
 func (os *OS) GetInterfaceType(facename string) (string, error) {
-	r, err := os.CallOSPluginFunction("get_intf_type", map[string]interface{}{"name": facename})
+	var r string
+	err := os.call("get_intf_type", map[string]interface{}{"name": facename}, &r)
 	if err != nil {
 		return "", err
 	}
 
-	return *r, nil
+	return r, nil
 }
 
 // SetInterfaceUnaviable sets the given network interface as unaviable
 func (os *OS) SetInterfaceUnaviable(facename string) (bool, error) {
-	r, err := os.CallOSPluginFunction("set_interface_unaviable", map[string]interface{}{"intf_name": facename})
+	var b evalBool
+	err := os.call("set_interface_unaviable", map[string]interface{}{"intf_name": facename}, &b)
 	if err != nil {
 		return false, err
 	}
-
-	b, err := strconv.ParseBool(*r)
-	if err != nil {
-		er := FError{"Error on conversion: " + err.Error(), nil}
-		return false, &er
-	}
-	return b, nil
+	return bool(b), nil
 }
 
 // SetInterfaceAvailable sets the given network interface as available
 func (os *OS) SetInterfaceAvailable(facename string) (bool, error) {
-	r, err := os.CallOSPluginFunction("set_interface_available", map[string]interface{}{"intf_name": facename})
+	var b evalBool
+	err := os.call("set_interface_available", map[string]interface{}{"intf_name": facename}, &b)
 	if err != nil {
 		return false, err
 	}
-
-	b, err := strconv.ParseBool(*r)
-	if err != nil {
-		er := FError{"Error on conversion: " + err.Error(), nil}
-		return false, &er
-	}
-	return b, nil
+	return bool(b), nil
 }
 
 // Checksum computes the checksum (SHA256) for the given file
 func (os *OS) Checksum(filepath string) (string, error) {
-	r, err := os.CallOSPluginFunction("checksum", map[string]interface{}{"file_path": filepath})
+	var r string
+	err := os.call("checksum", map[string]interface{}{"file_path": filepath}, &r)
 	if err != nil {
 		return "", err
 	}
 
-	return *r, nil
+	return r, nil
 }
 
 // LocalMgmtAddress gets the local management ip address
 func (os *OS) LocalMgmtAddress() (string, error) {
-	r, err := os.CallOSPluginFunction("local_mgmt_address", map[string]interface{}{})
+	var r string
+	err := os.call("local_mgmt_address", map[string]interface{}{}, &r)
 	if err != nil {
 		return "", err
 	}
 
-	return *r, nil
+	return r, nil
 }
 
 // NM is the object to interact with the network manager plugin
@@ -281,17 +261,49 @@ type NM struct {
 	uuid      string
 	connector *YaksConnector
 	node      string
+	ctx       context.Context
+}
+
+// WithContext returns a copy of the NM whose calls use the given context
+func (nm *NM) WithContext(ctx context.Context) *NM {
+	c := *nm
+	c.ctx = ctx
+	return &c
+}
+
+func (nm *NM) context() context.Context {
+	if nm.ctx == nil {
+		return context.Background()
+	}
+	return nm.ctx
+}
+
+// call calls the Eval registered within the network manager decoding its result in result
+func (nm *NM) call(fname string, fparameters map[string]interface{}, result interface{}) error {
+	if nm == nil {
+		return notLoaded("NM Plugin")
+	}
+	r, err := nm.connector.Local.Actual.WithContext(nm.context()).EvalNM(nm.node, nm.uuid, fname, fparameters, result)
+	if err != nil {
+		return err
+	}
+	if !r.hasResult() {
+		return &FError{"Function " + fname + " replied without result", nil}
+	}
+	return nil
 }
 
 // CallNMPluginFunction calls an Eval register within the network manager, returns a genering pointer to interface{}
 func (nm *NM) CallNMPluginFunction(fname string, fparameters map[string]interface{}) (*string, error) {
-	res, err := nm.connector.Local.Actual.ExecNMEval(nm.node, nm.uuid, fname, fparameters)
+	if nm == nil {
+		return nil, notLoaded("NM Plugin")
+	}
+	res, err := nm.connector.Local.Actual.WithContext(nm.context()).ExecNMEval(nm.node, nm.uuid, fname, fparameters)
 	if err != nil {
 		return nil, err
 	}
-	if res.Error != nil {
-		er := FError{*res.ErrorMessage + " ErrNo: " + string(*res.Error), nil}
-		return nil, &er
+	if res.Result == nil {
+		return nil, &FError{"Function " + fname + " replied without result", nil}
 	}
 	return res.Result, nil
 }
@@ -300,20 +312,17 @@ func (nm *NM) CallNMPluginFunction(fname string, fparameters map[string]interfac
 func (nm *NM) CreateVirtualInterface(intfid string, descriptor FDUInterfaceRecord) (*map[string]interface{}, error) {
 
 	jd, err := json.Marshal(descriptor)
-	var md map[string]interface{}
-
-	json.Unmarshal(jd, &md)
-
-	r, err := nm.CallNMPluginFunction("create_virtual_interface", map[string]interface{}{"intf_id": intfid, "descriptor": md})
 	if err != nil {
 		return nil, err
 	}
+	var md map[string]interface{}
+
+	json.Unmarshal(jd, &md)
 
 	myVar := make(map[string]interface{})
-	err = json.Unmarshal([]byte(*r), &myVar)
+	err = nm.call("create_virtual_interface", map[string]interface{}{"intf_id": intfid, "descriptor": md}, &myVar)
 	if err != nil {
-		er := FError{"Error on conversion: " + err.Error(), nil}
-		return nil, &er
+		return nil, err
 	}
 
 	return &myVar, nil
@@ -321,26 +330,21 @@ func (nm *NM) CreateVirtualInterface(intfid string, descriptor FDUInterfaceRecor
 
 // DeleteVirtualInterface deletes the given network interface and returns its information
 func (nm *NM) DeleteVirtualInterface(intfid string) (*string, error) {
-	r, err := nm.CallNMPluginFunction("delete_virtual_interface", map[string]interface{}{"intf_id": intfid})
+	var r string
+	err := nm.call("delete_virtual_interface", map[string]interface{}{"intf_id": intfid}, &r)
 	if err != nil {
 		return nil, err
 	}
 
-	return r, nil
+	return &r, nil
 }
 
 // CreateVirtualBridge creates the given virtual bridge and returns its information
 func (nm *NM) CreateVirtualBridge(name string, uuid string) (*map[string]interface{}, error) {
-	r, err := nm.CallNMPluginFunction("create_virtual_bridge", map[string]interface{}{"name": name, "uuid": uuid})
-	if err != nil {
-		return nil, err
-	}
-
 	myVar := make(map[string]interface{})
-	err = json.Unmarshal([]byte(*r), &myVar)
+	err := nm.call("create_virtual_bridge", map[string]interface{}{"name": name, "uuid": uuid}, &myVar)
 	if err != nil {
-		er := FError{"Error on conversion: " + err.Error(), nil}
-		return nil, &er
+		return nil, err
 	}
 
 	return &myVar, nil
@@ -348,26 +352,21 @@ func (nm *NM) CreateVirtualBridge(name string, uuid string) (*map[string]interfa
 
 // DeleteVirtualBridge removes the given virtual bridge and returns its information
 func (nm *NM) DeleteVirtualBridge(uuid string) (string, error) {
-	r, err := nm.CallNMPluginFunction("delete_virtual_bridge", map[string]interface{}{"br_uuid": uuid})
+	var r string
+	err := nm.call("delete_virtual_bridge", map[string]interface{}{"br_uuid": uuid}, &r)
 	if err != nil {
 		return "", err
 	}
 
-	return *r, nil
+	return r, nil
 }
 
 // CreateBridgesIfNotExists create the given bridges if they are not existing and returns a slice with the bridges informations
 func (nm *NM) CreateBridgesIfNotExists(expected []string) (*[]map[string]interface{}, error) {
-	r, err := nm.CallNMPluginFunction("create_bridges_if_not_exist", map[string]interface{}{"expected_bridges": expected})
-	if err != nil {
-		return nil, err
-	}
-
 	myVar := [](map[string]interface{}){}
-	err = json.Unmarshal([]byte(*r), &myVar)
+	err := nm.call("create_bridges_if_not_exist", map[string]interface{}{"expected_bridges": expected}, &myVar)
 	if err != nil {
-		er := FError{"Error on conversion: " + err.Error(), nil}
-		return nil, &er
+		return nil, err
 	}
 
 	return &myVar, nil
@@ -375,16 +374,10 @@ func (nm *NM) CreateBridgesIfNotExists(expected []string) (*[]map[string]interfa
 
 // ConnectInterfaceToConnectionPoint connects the given interface to the given connection point and returns interface information
 func (nm *NM) ConnectInterfaceToConnectionPoint(intfid string, cpid string) (*map[string]interface{}, error) {
-	r, err := nm.CallNMPluginFunction("connect_interface_to_connection_point", map[string]interface{}{"intf_id": intfid, "cp_id": cpid})
-	if err != nil {
-		return nil, err
-	}
-
 	myVar := make(map[string]interface{})
-	err = json.Unmarshal([]byte(*r), &myVar)
+	err := nm.call("connect_interface_to_connection_point", map[string]interface{}{"intf_id": intfid, "cp_id": cpid}, &myVar)
 	if err != nil {
-		er := FError{"Error on conversion: " + err.Error(), nil}
-		return nil, &er
+		return nil, err
 	}
 
 	return &myVar, nil
@@ -392,16 +385,10 @@ func (nm *NM) ConnectInterfaceToConnectionPoint(intfid string, cpid string) (*ma
 
 // DisconnectInterface disconnects the given interface and returns its information
 func (nm *NM) DisconnectInterface(intfid string) (*map[string]interface{}, error) {
-	r, err := nm.CallNMPluginFunction("disconnect_interface", map[string]interface{}{"intf_id": intfid})
-	if err != nil {
-		return nil, err
-	}
-
 	myVar := make(map[string]interface{})
-	err = json.Unmarshal([]byte(*r), &myVar)
+	err := nm.call("disconnect_interface", map[string]interface{}{"intf_id": intfid}, &myVar)
 	if err != nil {
-		er := FError{"Error on conversion: " + err.Error(), nil}
-		return nil, &er
+		return nil, err
 	}
 
 	return &myVar, nil
@@ -409,16 +396,10 @@ func (nm *NM) DisconnectInterface(intfid string) (*map[string]interface{}, error
 
 // ConnectCPToVNetwork connect the given connection point to the given network and returns connection point information
 func (nm *NM) ConnectCPToVNetwork(cpid string, vnetid string) (*map[string]interface{}, error) {
-	r, err := nm.CallNMPluginFunction("connect_cp_to_vnetwork", map[string]interface{}{"cp_id": cpid, "vnet_id": vnetid})
-	if err != nil {
-		return nil, err
-	}
-
 	myVar := make(map[string]interface{})
-	err = json.Unmarshal([]byte(*r), &myVar)
+	err := nm.call("connect_cp_to_vnetwork", map[string]interface{}{"cp_id": cpid, "vnet_id": vnetid}, &myVar)
 	if err != nil {
-		er := FError{"Error on conversion: " + err.Error(), nil}
-		return nil, &er
+		return nil, err
 	}
 
 	return &myVar, nil
@@ -426,16 +407,10 @@ func (nm *NM) ConnectCPToVNetwork(cpid string, vnetid string) (*map[string]inter
 
 // DisconnectCP disconnect the given connection point and returns its information
 func (nm *NM) DisconnectCP(cpid string) (*map[string]interface{}, error) {
-	r, err := nm.CallNMPluginFunction("disconnect_cp", map[string]interface{}{"cp_id": cpid})
-	if err != nil {
-		return nil, err
-	}
-
 	myVar := make(map[string]interface{})
-	err = json.Unmarshal([]byte(*r), &myVar)
+	err := nm.call("disconnect_cp", map[string]interface{}{"cp_id": cpid}, &myVar)
 	if err != nil {
-		er := FError{"Error on conversion: " + err.Error(), nil}
-		return nil, &er
+		return nil, err
 	}
 
 	return &myVar, nil
@@ -443,41 +418,31 @@ func (nm *NM) DisconnectCP(cpid string) (*map[string]interface{}, error) {
 
 // DeletePort deletes the given connection point
 func (nm *NM) DeletePort(cpid string) (bool, error) {
-	r, err := nm.CallNMPluginFunction("delete_port", map[string]interface{}{"cp_id": cpid})
+	var b evalBool
+	err := nm.call("delete_port", map[string]interface{}{"cp_id": cpid}, &b)
 	if err != nil {
 		return false, err
 	}
-
-	b, err := strconv.ParseBool(*r)
-	if err != nil {
-		er := FError{"Error on conversion: " + err.Error(), nil}
-		return false, &er
-	}
-	return b, nil
+	return bool(b), nil
 }
 
 // GetAddress gets the IP address of the specified connection point
 func (nm *NM) GetAddress(cpid string) (string, error) {
-	r, err := nm.CallNMPluginFunction("delete_port", map[string]interface{}{"cp_id": cpid})
+	var r string
+	err := nm.call("get_address", map[string]interface{}{"cp_id": cpid}, &r)
 	if err != nil {
 		return "", err
 	}
 
-	return *r, nil
+	return r, nil
 }
 
 // AddPortToRouter adds the given port to the given router and returns router information
 func (nm *NM) AddPortToRouter(routerid string, porttype string, vnetid string, ipaddress string) (*map[string]interface{}, error) {
-	r, err := nm.CallNMPluginFunction("add_router_port", map[string]interface{}{"router_id": routerid, "port_type": porttype, "vnet_id": vnetid, "ip_address": ipaddress})
-	if err != nil {
-		return nil, err
-	}
-
 	myVar := make(map[string]interface{})
-	err = json.Unmarshal([]byte(*r), &myVar)
+	err := nm.call("add_router_port", map[string]interface{}{"router_id": routerid, "port_type": porttype, "vnet_id": vnetid, "ip_address": ipaddress}, &myVar)
 	if err != nil {
-		er := FError{"Error on conversion: " + err.Error(), nil}
-		return nil, &er
+		return nil, err
 	}
 
 	return &myVar, nil
@@ -485,16 +450,10 @@ func (nm *NM) AddPortToRouter(routerid string, porttype string, vnetid string, i
 
 // RemovePortFromRouter remove the given port from the given router and returns router information
 func (nm *NM) RemovePortFromRouter(routerid string, vnetid string) (*map[string]interface{}, error) {
-	r, err := nm.CallNMPluginFunction("remove_port_from_router", map[string]interface{}{"router_id": routerid, "vnet_id": vnetid})
-	if err != nil {
-		return nil, err
-	}
-
 	myVar := make(map[string]interface{})
-	err = json.Unmarshal([]byte(*r), &myVar)
+	err := nm.call("remove_port_from_router", map[string]interface{}{"router_id": routerid, "vnet_id": vnetid}, &myVar)
 	if err != nil {
-		er := FError{"Error on conversion: " + err.Error(), nil}
-		return nil, &er
+		return nil, err
 	}
 
 	return &myVar, nil
@@ -502,16 +461,10 @@ func (nm *NM) RemovePortFromRouter(routerid string, vnetid string) (*map[string]
 
 // CreateFloatingIP creates a floating IP and returns its information
 func (nm *NM) CreateFloatingIP() (*map[string]interface{}, error) {
-	r, err := nm.CallNMPluginFunction("create_floating_ip", map[string]interface{}{})
-	if err != nil {
-		return nil, err
-	}
-
 	myVar := make(map[string]interface{})
-	err = json.Unmarshal([]byte(*r), &myVar)
+	err := nm.call("create_floating_ip", map[string]interface{}{}, &myVar)
 	if err != nil {
-		er := FError{"Error on conversion: " + err.Error(), nil}
-		return nil, &er
+		return nil, err
 	}
 
 	return &myVar, nil
@@ -519,16 +472,10 @@ func (nm *NM) CreateFloatingIP() (*map[string]interface{}, error) {
 
 // DeleteFloatingIP deletes the given floaing IP and returns its information
 func (nm *NM) DeleteFloatingIP(ipid string) (*map[string]interface{}, error) {
-	r, err := nm.CallNMPluginFunction("delete_floating_ip", map[string]interface{}{"ip_id": ipid})
-	if err != nil {
-		return nil, err
-	}
-
 	myVar := make(map[string]interface{})
-	err = json.Unmarshal([]byte(*r), &myVar)
+	err := nm.call("delete_floating_ip", map[string]interface{}{"ip_id": ipid}, &myVar)
 	if err != nil {
-		er := FError{"Error on conversion: " + err.Error(), nil}
-		return nil, &er
+		return nil, err
 	}
 
 	return &myVar, nil
@@ -536,16 +483,10 @@ func (nm *NM) DeleteFloatingIP(ipid string) (*map[string]interface{}, error) {
 
 // AssignFloatingIP assign the given floating IP to the given connection point and returns floating IP information
 func (nm *NM) AssignFloatingIP(ipid string, cpid string) (*map[string]interface{}, error) {
-	r, err := nm.CallNMPluginFunction("assign_floating_ip", map[string]interface{}{"ip_id": ipid, "cp_id": cpid})
-	if err != nil {
-		return nil, err
-	}
-
 	myVar := make(map[string]interface{})
-	err = json.Unmarshal([]byte(*r), &myVar)
+	err := nm.call("assign_floating_ip", map[string]interface{}{"ip_id": ipid, "cp_id": cpid}, &myVar)
 	if err != nil {
-		er := FError{"Error on conversion: " + err.Error(), nil}
-		return nil, &er
+		return nil, err
 	}
 
 	return &myVar, nil
@@ -553,16 +494,10 @@ func (nm *NM) AssignFloatingIP(ipid string, cpid string) (*map[string]interface{
 
 // RemoveFloatingIP retain the given floating ip from the given connection point and returns floating IP information
 func (nm *NM) RemoveFloatingIP(ipid string, cpid string) (*map[string]interface{}, error) {
-	r, err := nm.CallNMPluginFunction("remove_floating_ip", map[string]interface{}{"ip_id": ipid, "cp_id": cpid})
-	if err != nil {
-		return nil, err
-	}
-
 	myVar := make(map[string]interface{})
-	err = json.Unmarshal([]byte(*r), &myVar)
+	err := nm.call("remove_floating_ip", map[string]interface{}{"ip_id": ipid, "cp_id": cpid}, &myVar)
 	if err != nil {
-		er := FError{"Error on conversion: " + err.Error(), nil}
-		return nil, &er
+		return nil, err
 	}
 
 	return &myVar, nil
@@ -570,46 +505,60 @@ func (nm *NM) RemoveFloatingIP(ipid string, cpid string) (*map[string]interface{
 
 // GetOverlayFace gets the configured network interface for overlay networks
 func (nm *NM) GetOverlayFace() (string, error) {
-	r, err := nm.CallNMPluginFunction("get_overlay_face", map[string]interface{}{})
+	var r string
+	err := nm.call("get_overlay_face", map[string]interface{}{}, &r)
 	if err != nil {
 		return "", err
 	}
 
-	return *r, nil
+	return r, nil
 }
 
 // GetVLANFace gets the configured network interfaces for VLAN networks
 func (nm *NM) GetVLANFace() (string, error) {
-	r, err := nm.CallNMPluginFunction("get_vlan_face", map[string]interface{}{})
+	var r string
+	err := nm.call("get_vlan_face", map[string]interface{}{}, &r)
 	if err != nil {
 		return "", err
 	}
 
-	return *r, nil
+	return r, nil
 }
 
 // AddNodePort creates a new network port in the node
 func (nm *NM) AddNodePort(cp ConnectionPointRecord) error {
-	return nm.connector.Local.Desired.AddNodePort(nm.node, nm.uuid, cp.UUID, cp)
+	if nm == nil {
+		return notLoaded("NM Plugin")
+	}
+	return nm.connector.Local.Desired.WithContext(nm.context()).AddNodePort(nm.node, nm.uuid, cp.UUID, cp)
 }
 
 // GetNodePort gets the given port information
 func (nm *NM) GetNodePort(cpid string) (*ConnectionPointRecord, error) {
-	return nm.connector.Local.Desired.GetNodePort(nm.node, nm.uuid, cpid)
+	if nm == nil {
+		return nil, notLoaded("NM Plugin")
+	}
+	return nm.connector.Local.Desired.WithContext(nm.context()).GetNodePort(nm.node, nm.uuid, cpid)
 }
 
 // GetAllNodePorts gets information about all the port in the node
 func (nm *NM) GetAllNodePorts() ([]ConnectionPointRecord, error) {
-	return nm.connector.Local.Desired.GetAllNodePorts(nm.node, nm.uuid)
+	if nm == nil {
+		return nil, notLoaded("NM Plugin")
+	}
+	return nm.connector.Local.Desired.WithContext(nm.context()).GetAllNodePorts(nm.node, nm.uuid)
 }
 
 // RemoveNodePort removes the given port
 func (nm *NM) RemoveNodePort(cpid string) error {
 
-	cpd, _ := nm.GetNodePort(cpid)
-	(*cpd).Status = DESTROY
+	cpd, err := nm.GetNodePort(cpid)
+	if err != nil {
+		return err
+	}
+	cpd.Status = DESTROY
 
-	return nm.connector.Local.Desired.AddNodePort(nm.node, nm.uuid, cpid, *cpd)
+	return nm.connector.Local.Desired.WithContext(nm.context()).AddNodePort(nm.node, nm.uuid, cpid, *cpd)
 }
 
 // CreateConnectionPoint creates the given connection point
@@ -625,16 +574,10 @@ func (nm *NM) CreateConnectionPoint(descriptor ConnectionPointDescriptor) (*Conn
 		return nil, err
 	}
 
-	r, err := nm.CallNMPluginFunction("create_port_agent", map[string]interface{}{"descriptor": md})
-	if err != nil {
-		return nil, err
-	}
-
 	myVar := ConnectionPointRecord{}
-	err = json.Unmarshal([]byte(*r), &myVar)
+	err = nm.call("create_port_agent", map[string]interface{}{"descriptor": md}, &myVar)
 	if err != nil {
-		er := FError{"Error on conversion: " + err.Error(), nil}
-		return nil, &er
+		return nil, err
 	}
 
 	return &myVar, nil
@@ -643,16 +586,10 @@ func (nm *NM) CreateConnectionPoint(descriptor ConnectionPointDescriptor) (*Conn
 
 // RemoveConnectionPoint removes the given connection point
 func (nm *NM) RemoveConnectionPoint(cpid string) (*ConnectionPointRecord, error) {
-	r, err := nm.CallNMPluginFunction("destroy_port_agent", map[string]interface{}{"cp_id": cpid})
-	if err != nil {
-		return nil, err
-	}
-
 	myVar := ConnectionPointRecord{}
-	err = json.Unmarshal([]byte(*r), &myVar)
+	err := nm.call("destroy_port_agent", map[string]interface{}{"cp_id": cpid}, &myVar)
 	if err != nil {
-		er := FError{"Error on conversion: " + err.Error(), nil}
-		return nil, &er
+		return nil, err
 	}
 
 	return &myVar, nil
@@ -660,12 +597,13 @@ func (nm *NM) RemoveConnectionPoint(cpid string) (*ConnectionPointRecord, error)
 
 // CreateMACVLANInterface creates a MACVLAN interface over the given interface
 func (nm *NM) CreateMACVLANInterface(masterIntf string) (string, error) {
-	r, err := nm.CallNMPluginFunction("create_macvlan_interface", map[string]interface{}{"master_intf": masterIntf})
+	var r string
+	err := nm.call("create_macvlan_interface", map[string]interface{}{"master_intf": masterIntf}, &r)
 	if err != nil {
 		return "", err
 	}
 
-	return *r, nil
+	return r, nil
 }
 
 // DeleteMACVLANInterface deletes the given MACVLAN interface
@@ -673,32 +611,35 @@ func (nm *NM) DeleteMACVLANInterface(intfName string, netns string) (string, err
 	if netns == "" {
 		netns = "1"
 	}
-	r, err := nm.CallNMPluginFunction("delete_macvlan_interface", map[string]interface{}{"intfName": intfName, "netns": netns})
+	var r string
+	err := nm.call("delete_macvlan_interface", map[string]interface{}{"intfName": intfName, "netns": netns}, &r)
 	if err != nil {
 		return "", err
 	}
 
-	return *r, nil
+	return r, nil
 }
 
 // CreateNetworkNamespace creates a new network namespace, and returns its name
 func (nm *NM) CreateNetworkNamespace() (string, error) {
-	r, err := nm.CallNMPluginFunction("create_network_namespace", map[string]interface{}{})
+	var r string
+	err := nm.call("create_network_namespace", map[string]interface{}{}, &r)
 	if err != nil {
 		return "", err
 	}
 
-	return *r, nil
+	return r, nil
 }
 
 // DeleteNetworkNamespace deletes the given network namespace, and returns its name
 func (nm *NM) DeleteNetworkNamespace(netns string) (string, error) {
-	r, err := nm.CallNMPluginFunction("delete_network_namespace", map[string]interface{}{"nsname": netns})
+	var r string
+	err := nm.call("delete_network_namespace", map[string]interface{}{"nsname": netns}, &r)
 	if err != nil {
 		return "", err
 	}
 
-	return *r, nil
+	return r, nil
 }
 
 // MoveInterfaceInNamespace moves the given interface to the given namespace, is netns is empty will move to the default namespace
@@ -706,16 +647,10 @@ func (nm *NM) MoveInterfaceInNamespace(intfName string, netns string) (*Interfac
 	if netns == "" {
 		netns = "1"
 	}
-	r, err := nm.CallNMPluginFunction("move_interface_in_namespace", map[string]interface{}{"intf_name": intfName, "nsname": netns})
-	if err != nil {
-		return nil, err
-	}
-
 	myVar := InterfaceInfo{}
-	err = json.Unmarshal([]byte(*r), &myVar)
+	err := nm.call("move_interface_in_namespace", map[string]interface{}{"intf_name": intfName, "nsname": netns}, &myVar)
 	if err != nil {
-		er := FError{"Error on conversion: " + err.Error(), nil}
-		return nil, &er
+		return nil, err
 	}
 
 	return &myVar, nil
@@ -723,32 +658,26 @@ func (nm *NM) MoveInterfaceInNamespace(intfName string, netns string) (*Interfac
 
 // RenameVirtualInterfaceInNamespace renames the given interface
 func (nm *NM) RenameVirtualInterfaceInNamespace(name string, newname string, nsname string) (string, error) {
-	var r *string
+	var r string
 	var err error
 	if nsname == "" {
-		r, err = nm.CallNMPluginFunction("rename_virtual_interface_in_namespace", map[string]interface{}{"name": name, "newname": newname})
+		err = nm.call("rename_virtual_interface_in_namespace", map[string]interface{}{"name": name, "newname": newname}, &r)
 	} else {
-		r, err = nm.CallNMPluginFunction("rename_virtual_interface_in_namespace", map[string]interface{}{"name": name, "newname": newname, "nsname": nsname})
+		err = nm.call("rename_virtual_interface_in_namespace", map[string]interface{}{"name": name, "newname": newname, "nsname": nsname}, &r)
 	}
 	if err != nil {
 		return "", err
 	}
 
-	return *r, nil
+	return r, nil
 }
 
 // AttachInterfaceToBridge attaches the given interface to the given bridge
 func (nm *NM) AttachInterfaceToBridge(intfName string, brName string) (*InterfaceInfo, error) {
-	r, err := nm.CallNMPluginFunction("attach_interface_to_bridge", map[string]interface{}{"intf_name": intfName, "br_name": brName})
-	if err != nil {
-		return nil, err
-	}
-
 	myVar := InterfaceInfo{}
-	err = json.Unmarshal([]byte(*r), &myVar)
+	err := nm.call("attach_interface_to_bridge", map[string]interface{}{"intf_name": intfName, "br_name": brName}, &myVar)
 	if err != nil {
-		er := FError{"Error on conversion: " + err.Error(), nil}
-		return nil, &er
+		return nil, err
 	}
 
 	return &myVar, nil
@@ -756,16 +685,10 @@ func (nm *NM) AttachInterfaceToBridge(intfName string, brName string) (*Interfac
 
 // DetachInterfaceFromBridge detaches the interface from the current connected bridge
 func (nm *NM) DetachInterfaceFromBridge(intfName string) (*InterfaceInfo, error) {
-	r, err := nm.CallNMPluginFunction("detach_interface_from_bridge", map[string]interface{}{"intf_name": intfName})
-	if err != nil {
-		return nil, err
-	}
-
 	myVar := InterfaceInfo{}
-	err = json.Unmarshal([]byte(*r), &myVar)
+	err := nm.call("detach_interface_from_bridge", map[string]interface{}{"intf_name": intfName}, &myVar)
 	if err != nil {
-		er := FError{"Error on conversion: " + err.Error(), nil}
-		return nil, &er
+		return nil, err
 	}
 
 	return &myVar, nil
@@ -773,16 +696,10 @@ func (nm *NM) DetachInterfaceFromBridge(intfName string) (*InterfaceInfo, error)
 
 // CreateVirtualInterfaceInNamespace creates a veth pair in the given network namespace, with the given name for the internal interface
 func (nm *NM) CreateVirtualInterfaceInNamespace(intfName string, netns string) (*NamespaceInfo, error) {
-	r, err := nm.CallNMPluginFunction("create_virtual_interface_in_namespace", map[string]interface{}{"internal_name": intfName, "nsname": netns})
-	if err != nil {
-		return nil, err
-	}
-
 	myVar := NamespaceInfo{}
-	err = json.Unmarshal([]byte(*r), &myVar)
+	err := nm.call("create_virtual_interface_in_namespace", map[string]interface{}{"internal_name": intfName, "nsname": netns}, &myVar)
 	if err != nil {
-		er := FError{"Error on conversion: " + err.Error(), nil}
-		return nil, &er
+		return nil, err
 	}
 
 	return &myVar, nil
@@ -790,16 +707,10 @@ func (nm *NM) CreateVirtualInterfaceInNamespace(intfName string, netns string) (
 
 // DeleteVirtualInterfaceFromNamespace deletes the given interface from the the given network namespace
 func (nm *NM) DeleteVirtualInterfaceFromNamespace(intfName string, netns string) (*NamespaceInfo, error) {
-	r, err := nm.CallNMPluginFunction("delete_virtual_interface_from_namespace", map[string]interface{}{"internal_name": intfName, "nsname": netns})
-	if err != nil {
-		return nil, err
-	}
-
 	myVar := NamespaceInfo{}
-	err = json.Unmarshal([]byte(*r), &myVar)
+	err := nm.call("delete_virtual_interface_from_namespace", map[string]interface{}{"internal_name": intfName, "nsname": netns}, &myVar)
 	if err != nil {
-		er := FError{"Error on conversion: " + err.Error(), nil}
-		return nil, &er
+		return nil, err
 	}
 
 	return &myVar, nil
@@ -807,39 +718,26 @@ func (nm *NM) DeleteVirtualInterfaceFromNamespace(intfName string, netns string)
 
 // AssignAddressToInterfaceInNamespace assigns the given address to the given interface in the the given network namespace, address are in the form AAA.AAA.AAA.AAA/NM
 func (nm *NM) AssignAddressToInterfaceInNamespace(intfName string, netns string, address string) (*NamespaceInfo, error) {
-	var r *string
+	myVar := NamespaceInfo{}
 	var err error
 	if address == "" {
-		r, err = nm.CallNMPluginFunction("assign_address_to_interface_in_namespace", map[string]interface{}{"intf_name": intfName, "nsname": netns})
+		err = nm.call("assign_address_to_interface_in_namespace", map[string]interface{}{"intf_name": intfName, "nsname": netns}, &myVar)
 	} else {
-		r, err = nm.CallNMPluginFunction("assign_address_to_interface_in_namespace", map[string]interface{}{"intf_name": intfName, "nsname": netns, "address": address})
+		err = nm.call("assign_address_to_interface_in_namespace", map[string]interface{}{"intf_name": intfName, "nsname": netns, "address": address}, &myVar)
 	}
 	if err != nil {
 		return nil, err
 	}
 
-	myVar := NamespaceInfo{}
-	err = json.Unmarshal([]byte(*r), &myVar)
-	if err != nil {
-		er := FError{"Error on conversion: " + err.Error(), nil}
-		return nil, &er
-	}
-
 	return &myVar, nil
 }
 
 // AssignMACAddressToInterfaceInNamespace assigns the given address to the given interface in the the given network namespace, address are in the form AA:BB:CC:DD:EE:FF
 func (nm *NM) AssignMACAddressToInterfaceInNamespace(intfName string, netns string, address string) (*NamespaceInfo, error) {
-	r, err := nm.CallNMPluginFunction("assign_mac_address_to_interface_in_namespace", map[string]interface{}{"intf_name": intfName, "nsname": netns, "address": address})
-	if err != nil {
-		return nil, err
-	}
-
 	myVar := NamespaceInfo{}
-	err = json.Unmarshal([]byte(*r), &myVar)
+	err := nm.call("assign_mac_address_to_interface_in_namespace", map[string]interface{}{"intf_name": intfName, "nsname": netns, "address": address}, &myVar)
 	if err != nil {
-		er := FError{"Error on conversion: " + err.Error(), nil}
-		return nil, &er
+		return nil, err
 	}
 
 	return &myVar, nil
@@ -847,16 +745,10 @@ func (nm *NM) AssignMACAddressToInterfaceInNamespace(intfName string, netns stri
 
 // GetAddressOfInterfaceInNamespace retrieves the address to the given interface in the the given network namespace
 func (nm *NM) GetAddressOfInterfaceInNamespace(intfName string, netns string) (*InterfaceInfo, error) {
-	r, err := nm.CallNMPluginFunction("get_address_of_interface_in_namespace", map[string]interface{}{"intf_name": intfName, "nsname": netns})
-	if err != nil {
-		return nil, err
-	}
-
 	myVar := InterfaceInfo{}
-	err = json.Unmarshal([]byte(*r), &myVar)
+	err := nm.call("get_address_of_interface_in_namespace", map[string]interface{}{"intf_name": intfName, "nsname": netns}, &myVar)
 	if err != nil {
-		er := FError{"Error on conversion: " + err.Error(), nil}
-		return nil, &er
+		return nil, err
 	}
 
 	return &myVar, nil
@@ -864,16 +756,10 @@ func (nm *NM) GetAddressOfInterfaceInNamespace(intfName string, netns string) (*
 
 // RemoveAddressFromInterfaceInNamespace removes the address from the given interface in the the given network namespace
 func (nm *NM) RemoveAddressFromInterfaceInNamespace(intfName string, netns string) (*NamespaceInfo, error) {
-	r, err := nm.CallNMPluginFunction("remove_address_from_interface_in_namespace", map[string]interface{}{"intf_name": intfName, "nsname": netns})
-	if err != nil {
-		return nil, err
-	}
-
 	myVar := NamespaceInfo{}
-	err = json.Unmarshal([]byte(*r), &myVar)
+	err := nm.call("remove_address_from_interface_in_namespace", map[string]interface{}{"intf_name": intfName, "nsname": netns}, &myVar)
 	if err != nil {
-		er := FError{"Error on conversion: " + err.Error(), nil}
-		return nil, &er
+		return nil, err
 	}
 
 	return &myVar, nil
@@ -883,33 +769,59 @@ func (nm *NM) RemoveAddressFromInterfaceInNamespace(intfName string, netns strin
 type Agent struct {
 	connector *YaksConnector
 	node      string
+	ctx       context.Context
+}
+
+// WithContext returns a copy of the Agent whose calls use the given context
+func (ag *Agent) WithContext(ctx context.Context) *Agent {
+	c := *ag
+	c.ctx = ctx
+	return &c
+}
+
+func (ag *Agent) context() context.Context {
+	if ag.ctx == nil {
+		return context.Background()
+	}
+	return ag.ctx
+}
+
+// call calls the Eval registered within the Agent decoding its result in result
+func (ag *Agent) call(fname string, fparameters map[string]interface{}, result interface{}) error {
+	if ag == nil {
+		return notLoaded("Agent")
+	}
+	r, err := ag.connector.Local.Actual.WithContext(ag.context()).EvalAgent(ag.node, fname, fparameters, result)
+	if err != nil {
+		return err
+	}
+	if !r.hasResult() {
+		return &FError{"Function " + fname + " replied without result", nil}
+	}
+	return nil
 }
 
 // CallAgentFunction calls an Eval registered within the Agent and returns a generic pointer to interface
 func (ag *Agent) CallAgentFunction(fname string, fparameters map[string]interface{}) (*string, error) {
-	res, err := ag.connector.Local.Actual.ExecAgentEval(ag.node, fname, fparameters)
+	if ag == nil {
+		return nil, notLoaded("Agent")
+	}
+	res, err := ag.connector.Local.Actual.WithContext(ag.context()).ExecAgentEval(ag.node, fname, fparameters)
 	if err != nil {
 		return nil, err
 	}
-	if res.Error != nil {
-		er := FError{*res.ErrorMessage + " ErrNo: " + string(*res.Error), nil}
-		return nil, &er
+	if res.Result == nil {
+		return nil, &FError{"Function " + fname + " replied without result", nil}
 	}
 	return res.Result, nil
 }
 
 // GetImageInfo given an image UUID retruns the FDUImage object associated
 func (ag *Agent) GetImageInfo(imgid string) (*FDUImage, error) {
-	r, err := ag.CallAgentFunction("get_image_info", map[string]interface{}{"image_uuid": imgid})
-	if err != nil {
-		return nil, err
-	}
-
 	myVar := FDUImage{}
-	err = json.Unmarshal([]byte(*r), &myVar)
+	err := ag.call("get_image_info", map[string]interface{}{"image_uuid": imgid}, &myVar)
 	if err != nil {
-		er := FError{"Error on conversion: " + err.Error(), nil}
-		return nil, &er
+		return nil, err
 	}
 
 	return &myVar, nil
@@ -917,16 +829,10 @@ func (ag *Agent) GetImageInfo(imgid string) (*FDUImage, error) {
 
 // GetFDUInfo given a node id, fdu id and instance id returns the FDU object associated
 func (ag *Agent) GetFDUInfo(nodeid string, fduid string, instanceid string) (*FDU, error) {
-	r, err := ag.CallAgentFunction("get_node_fdu_info", map[string]interface{}{"fdu_uuid": fduid, "instance_uuid": instanceid, "node_uuid": nodeid})
-	if err != nil {
-		return nil, err
-	}
-
 	myVar := FDU{}
-	err = json.Unmarshal([]byte(*r), &myVar)
+	err := ag.call("get_node_fdu_info", map[string]interface{}{"fdu_uuid": fduid, "instance_uuid": instanceid, "node_uuid": nodeid}, &myVar)
 	if err != nil {
-		er := FError{"Error on conversion: " + err.Error(), nil}
-		return nil, &er
+		return nil, err
 	}
 
 	return &myVar, nil
@@ -934,16 +840,10 @@ func (ag *Agent) GetFDUInfo(nodeid string, fduid string, instanceid string) (*FD
 
 // GetFDUDescriptor returns the descriptor for the given FDU ID
 func (ag *Agent) GetFDUDescriptor(fduid string) (*FDU, error) {
-	r, err := ag.CallAgentFunction("get_fdu_info", map[string]interface{}{"fdu_uuid": fduid})
-	if err != nil {
-		return nil, err
-	}
-
 	myVar := FDU{}
-	err = json.Unmarshal([]byte(*r), &myVar)
+	err := ag.call("get_fdu_info", map[string]interface{}{"fdu_uuid": fduid}, &myVar)
 	if err != nil {
-		er := FError{"Error on conversion: " + err.Error(), nil}
-		return nil, &er
+		return nil, err
 	}
 
 	return &myVar, nil
@@ -951,16 +851,10 @@ func (ag *Agent) GetFDUDescriptor(fduid string) (*FDU, error) {
 
 // GetNetworkInfo given a network id returns the VirtualNetwork object associated
 func (ag *Agent) GetNetworkInfo(netid string) (*VirtualNetwork, error) {
-	r, err := ag.CallAgentFunction("get_network_info", map[string]interface{}{"uuid": netid})
-	if err != nil {
-		return nil, err
-	}
-
 	myVar := VirtualNetwork{}
-	err = json.Unmarshal([]byte(*r), &myVar)
+	err := ag.call("get_network_info", map[string]interface{}{"uuid": netid}, &myVar)
 	if err != nil {
-		er := FError{"Error on conversion: " + err.Error(), nil}
-		return nil, &er
+		return nil, err
 	}
 
 	return &myVar, nil
@@ -968,16 +862,10 @@ func (ag *Agent) GetNetworkInfo(netid string) (*VirtualNetwork, error) {
 
 // GetPortInfo given a connection point id returns the ConnectionPointDescriptor associated
 func (ag *Agent) GetPortInfo(cpid string) (*ConnectionPointDescriptor, error) {
-	r, err := ag.CallAgentFunction("get_port_info", map[string]interface{}{"cp_uuid": cpid})
-	if err != nil {
-		return nil, err
-	}
-
 	myVar := ConnectionPointDescriptor{}
-	err = json.Unmarshal([]byte(*r), &myVar)
+	err := ag.call("get_port_info", map[string]interface{}{"cp_uuid": cpid}, &myVar)
 	if err != nil {
-		er := FError{"Error on conversion: " + err.Error(), nil}
-		return nil, &er
+		return nil, err
 	}
 
 	return &myVar, nil
@@ -985,12 +873,13 @@ func (ag *Agent) GetPortInfo(cpid string) (*ConnectionPointDescriptor, error) {
 
 // GetNodeMGMTAddress given a node id return the node management IP address
 func (ag *Agent) GetNodeMGMTAddress(nodeid string) (string, error) {
-	r, err := ag.CallAgentFunction("get_node_mgmt_address", map[string]interface{}{"node_uuid": nodeid})
+	var r string
+	err := ag.call("get_node_mgmt_address", map[string]interface{}{"node_uuid": nodeid}, &r)
 	if err != nil {
 		return "", err
 	}
 
-	return *r, nil
+	return r, nil
 }
 
 // FOSPlugin rapresents an Eclipse fog05 Plugin
@@ -1002,6 +891,16 @@ type FOSPlugin struct {
 	OS        *OS
 	Agent     *Agent
 	UUID      string
+
+	// HeartbeatInterval is the interval of the heartbeats published after StartHeartbeat
+	HeartbeatInterval time.Duration
+
+	// HeartbeatTimeout is the age after which the heartbeat of a plugin is expired, the discovery skips the plugins with an expired heartbeat
+	HeartbeatTimeout time.Duration
+
+	// heartbeatMu guards heartbeat, it is shared by the copies of the plugin
+	heartbeatMu *sync.Mutex
+	heartbeat   *heartbeat
 }
 
 // NewPlugin returns a new FOSPlugin object
@@ -1009,73 +908,58 @@ func NewPlugin(version int, pluginuuid string) *FOSPlugin {
 	if pluginuuid == "" {
 		pluginuuid = uuid.UUID.String(uuid.New())
 	}
-	return &FOSPlugin{version: version, UUID: pluginuuid, node: "", NM: nil, OS: nil, connector: nil, Agent: nil}
+	return &FOSPlugin{version: version, UUID: pluginuuid, node: "", NM: nil, OS: nil, connector: nil, Agent: nil,
+		HeartbeatInterval: DefaultHeartbeatInterval, HeartbeatTimeout: DefaultHeartbeatTimeout, heartbeatMu: &sync.Mutex{}}
 }
 
-// GetOSPlugin loads the OS plugin discovering it from YAKS
-func (pl *FOSPlugin) GetOSPlugin() bool {
-	pls, err := pl.connector.Local.Actual.GetAllPlugins(pl.node)
-	if err != nil {
-		panic(err.Error())
-	}
-	for _, pid := range pls {
-		pld, err := pl.connector.Local.Actual.GetNodePlugin(pl.node, pid)
-		if err != nil {
-			panic(err.Error())
-		}
-		if pld.Type == "os" {
-			pl.OS = &OS{uuid: pld.UUID, connector: pl.connector, node: pl.node}
-			return true
-		}
-	}
-	return false
+// NewPluginWithConnector returns a new FOSPlugin object for the given node, using the given connector
+func NewPluginWithConnector(version int, pluginuuid string, connector *YaksConnector, nodeid string) *FOSPlugin {
+	pl := NewPlugin(version, pluginuuid)
+	pl.connector = connector
+	pl.node = nodeid
+	return pl
 }
 
-// GetNMPlugin loads the Network Manager plugin discovering it from YAKS
-func (pl *FOSPlugin) GetNMPlugin() bool {
-	pls, err := pl.connector.Local.Actual.GetAllPlugins(pl.node)
-	if err != nil {
-		panic(err.Error())
-	}
-	for _, pid := range pls {
-		pld, err := pl.connector.Local.Actual.GetNodePlugin(pl.node, pid)
-		if err != nil {
-			panic(err.Error())
-		}
-		if pld.Type == "network" {
-			pl.NM = &NM{uuid: pld.UUID, connector: pl.connector, node: pl.node}
-			return true
-		}
+// GetOSPlugin loads the OS plugin discovering it from YAKS, returns false if it is not running or its heartbeat expired
+func (pl *FOSPlugin) GetOSPlugin() (bool, error) {
+	p, err := pl.findPlugin(OSPluginType)
+	if err != nil || p == nil {
+		return false, err
 	}
-	return false
+	pl.OS = &OS{uuid: p.UUID, connector: pl.connector, node: pl.node}
+	return true, nil
 }
 
-// GetAgent loads the Agent discovering it from YAKS
-func (pl *FOSPlugin) GetAgent() bool {
-	pls, err := pl.connector.Local.Actual.GetAllPlugins(pl.node)
-	if err != nil {
-		panic(err.Error())
+// GetNMPlugin loads the Network Manager plugin discovering it from YAKS, returns false if it is not running or its heartbeat expired
+func (pl *FOSPlugin) GetNMPlugin() (bool, error) {
+	p, err := pl.findPlugin(NetworkManagerPluginType)
+	if err != nil || p == nil {
+		return false, err
 	}
-	for _, pid := range pls {
-		pld, err := pl.connector.Local.Actual.GetNodePlugin(pl.node, pid)
-		if err != nil {
-			panic(err.Error())
-		}
-		if pld.Type == "network" {
-			pl.Agent = &Agent{connector: pl.connector, node: pl.node}
-			return true
+	pl.NM = &NM{uuid: p.UUID, connector: pl.connector, node: pl.node}
+	return true, nil
+}
+
+// GetAgent loads the Agent probing its get_node_mgmt_address eval, returns false if it is not running
+func (pl *FOSPlugin) GetAgent() (bool, error) {
+	// the Agent has no plugin record nor heartbeat, it is up once it replies to its evals, even with an error
+	res, err := pl.connector.Local.Actual.ExecAgentEval(pl.node, "get_node_mgmt_address", map[string]interface{}{"node_uuid": pl.node})
+	if res == nil {
+		if errors.Is(err, ErrNotFound) {
+			return false, nil
 		}
+		return false, err
 	}
-	return false
+	pl.Agent = &Agent{connector: pl.connector, node: pl.node}
+	return true, nil
 }
 
 // GetLocalMGMTAddress returns the local management IP address
-func (pl *FOSPlugin) GetLocalMGMTAddress() string {
-	ip, err := pl.OS.LocalMgmtAddress()
-	if err != nil {
-		panic(err.Error())
+func (pl *FOSPlugin) GetLocalMGMTAddress() (string, error) {
+	if pl.OS == nil {
+		return "", notLoaded("OS Plugin")
 	}
-	return ip
+	return pl.OS.LocalMgmtAddress()
 }
 
 // GetNodeConfiguration returns the node configuration
@@ -1088,12 +972,12 @@ func (pl *FOSPlugin) GetNodeConfiguration() (*NodeConfiguration, error) {
 }
 
 // GetPluginState returns the plugin state, retrives it from YAKS, as a map[string]interface, each implementation of the plugin can have his own state representation
-func (pl *FOSPlugin) GetPluginState() map[string]interface{} {
+func (pl *FOSPlugin) GetPluginState() (map[string]interface{}, error) {
 	s, err := pl.connector.Local.Actual.GetNodePluginState(pl.node, pl.UUID)
 	if err != nil {
-		panic(err.Error())
+		return nil, err
 	}
-	return *s
+	return *s, nil
 }
 
 // SavePluginState stores the plugin state into YAKS
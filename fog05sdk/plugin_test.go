@@ -0,0 +1,123 @@
+/*
+* Copyright (c) 2014,2019 Contributors to the Eclipse Foundation
+* See the NOTICE file(s) distributed with this work for additional
+* information regarding copyright ownership.
+* This program and the accompanying materials are made available under the
+* terms of the Eclipse Public License 2.0 which is available at
+* http://www.eclipse.org/legal/epl-2.0, or the Apache License, Version 2.0
+* which is available at https://www.apache.org/licenses/LICENSE-2.0.
+* SPDX-License-Identifier: EPL-2.0 OR Apache-2.0
+* Contributors: Gabriele Baldoni, ADLINK Technology Inc.
+* golang APIs
+ */
+
+package fog05sdk_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	fog05sdk "github.com/eclipse-fog05/sdk-go/fog05sdk"
+	"github.com/eclipse-fog05/sdk-go/fog05sdk/fostest"
+)
+
+// reply registers at the given eval path of the node a handler replying the given JSON
+func reply(t *testing.T, yc *fog05sdk.YaksConnector, add func(string, func(fog05sdk.Properties) interface{}) error, fname string, js string) {
+	t.Helper()
+	err := add(fname, func(fog05sdk.Properties) interface{} {
+		return fog05sdk.RawEvalResult{Result: json.RawMessage(js)}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// clientPlugin returns a FOSPlugin of node n1 with the OS and NM clients loaded from the plugin records of os1 and nm1
+func clientPlugin(t *testing.T, yc *fog05sdk.YaksConnector) *fog05sdk.FOSPlugin {
+	t.Helper()
+	lad := yc.Local.Actual
+	for _, p := range []fog05sdk.Plugin{{UUID: "os1", Name: "linux", Type: fog05sdk.OSPluginType}, {UUID: "nm1", Name: "linuxbridge", Type: fog05sdk.NetworkManagerPluginType}} {
+		if err := lad.AddNodePlugin("n1", p.UUID, p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	pl := fog05sdk.NewPluginWithConnector(1, "p1", yc, "n1")
+	if ok, err := pl.GetOSPlugin(); !ok || err != nil {
+		t.Fatalf("GetOSPlugin() = %v, %v", ok, err)
+	}
+	if ok, err := pl.GetNMPlugin(); !ok || err != nil {
+		t.Fatalf("GetNMPlugin() = %v, %v", ok, err)
+	}
+	return pl
+}
+
+func TestOSClientResults(t *testing.T) {
+	tests := []struct {
+		name  string
+		reply string
+		want  bool
+		fail  bool
+	}{
+		{"json bool", `true`, true, false},
+		{"json false", `false`, false, false},
+		{"string", `"true"`, true, false},
+		{"python string", `"True"`, true, false},
+		{"string false", `"False"`, false, false},
+		{"not a bool", `"maybe"`, false, true},
+		{"number", `1`, false, true},
+		{"no result", `null`, false, true},
+	}
+	for _, tt := range tests {
+		yc, _ := fostest.NewConnector()
+		pl := clientPlugin(t, yc)
+		reply(t, yc, func(f string, cb func(fog05sdk.Properties) interface{}) error {
+			return yc.Local.Actual.AddOSEval("n1", f, cb)
+		}, "dir_exists", tt.reply)
+		got, err := pl.OS.DirExists("/tmp")
+		if (err != nil) != tt.fail || got != tt.want {
+			t.Errorf("%s: DirExists() = %v, %v, want %v, fail %v", tt.name, got, err, tt.want, tt.fail)
+		}
+	}
+}
+
+func TestNMClientResults(t *testing.T) {
+	yc, _ := fostest.NewConnector()
+	pl := clientPlugin(t, yc)
+	add := func(f string, cb func(fog05sdk.Properties) interface{}) error {
+		return yc.Local.Actual.AddNMEval("n1", "nm1", f, cb)
+	}
+	reply(t, yc, add, "get_overlay_face", `"br-ovl"`)
+	reply(t, yc, add, "delete_port", `"true"`)
+	reply(t, yc, add, "create_floating_ip", `{"uuid":"ip1","ip_version":"IPV4"}`)
+	reply(t, yc, add, "move_interface_in_namespace", `{"name":"veth0"}`)
+	err := add("get_address", func(fog05sdk.Properties) interface{} {
+		return fog05sdk.NewEvalErrorResult(&fog05sdk.EvalError{Code: 2, Message: "no port"})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if face, err := pl.NM.GetOverlayFace(); err != nil || face != "br-ovl" {
+		t.Errorf("GetOverlayFace() = %q, %v", face, err)
+	}
+	if ok, err := pl.NM.DeletePort("cp1"); err != nil || !ok {
+		t.Errorf("DeletePort() = %v, %v", ok, err)
+	}
+	ip, err := pl.NM.CreateFloatingIP()
+	if err != nil || !reflect.DeepEqual(*ip, map[string]interface{}{"uuid": "ip1", "ip_version": "IPV4"}) {
+		t.Errorf("CreateFloatingIP() = %v, %v", ip, err)
+	}
+	if intf, err := pl.NM.MoveInterfaceInNamespace("veth0", "ns1"); err != nil || intf.Name != "veth0" {
+		t.Errorf("MoveInterfaceInNamespace() = %+v, %v", intf, err)
+	}
+	_, err = pl.NM.GetAddress("cp1")
+	var eerr *fog05sdk.EvalError
+	if !errors.As(err, &eerr) || eerr.Code != 2 {
+		t.Errorf("GetAddress() = %v, want the EvalError replied", err)
+	}
+	if _, err := pl.NM.GetVLANFace(); err == nil {
+		t.Errorf("GetVLANFace() without eval = nil error")
+	}
+}
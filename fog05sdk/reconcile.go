@@ -0,0 +1,233 @@
+/*
+* Copyright (c) 2014,2019 Contributors to the Eclipse Foundation
+* See the NOTICE file(s) distributed with this work for additional
+* information regarding copyright ownership.
+* This program and the accompanying materials are made available under the
+* terms of the Eclipse Public License 2.0 which is available at
+* http://www.eclipse.org/legal/epl-2.0, or the Apache License, Version 2.0
+* which is available at https://www.apache.org/licenses/LICENSE-2.0.
+* SPDX-License-Identifier: EPL-2.0 OR Apache-2.0
+* Contributors: Gabriele Baldoni, ADLINK Technology Inc.
+* golang APIs
+ */
+
+package fog05sdk
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// DefaultReconcileInterval is the default interval of the periodic reconciliation of a runtime plugin
+const DefaultReconcileInterval = time.Minute
+
+// actionStates are the states reached by the actions that reconciliation can drive again, UNDEFINE removes the instance
+var actionStates = map[string]string{
+	DEFINE:    DEFINE,
+	CONFIGURE: CONFIGURE,
+	CLEAN:     DEFINE,
+	STARTING:  RUN,
+	RUN:       RUN,
+	STOP:      CONFIGURE,
+	PAUSE:     PAUSE,
+	RESUME:    RUN,
+	SCALE:     RUN,
+	UNDEFINE:  "",
+}
+
+// Reconcile compares the instances reported by GetFDUs with their records in Local Actual and the desired records in Local Desired:
+//   - a record whose status differs from the runtime one takes the runtime status if the state machine allows it, otherwise it is marked ERROR,
+//     a record in ERROR state is left as it is
+//   - a record of an instance the runtime does not have is marked ERROR, or removed if the desired action is UNDEFINE
+//   - an instance of the runtime without record gets one if it is desired, otherwise it is an orphan and is marked ERROR
+//   - a desired action whose state was not reached is queued again if the current state allows it, otherwise the record is marked ERROR
+//
+// Each instance is reconciled in its action queue, after the actions already queued, reading its records again
+func (rt *FOSRuntimePluginAbstract) Reconcile() error {
+	return rt.reconcile(false)
+}
+
+// reconcile runs Reconcile, at startup the evals of the instances are also registered again
+func (rt *FOSRuntimePluginAbstract) reconcile(startup bool) error {
+	actual, err := rt.Connector.Local.Actual.GetNodeRuntimeFDUs(rt.Node, rt.FOSPlugin.UUID)
+	if err != nil {
+		return err
+	}
+	desired, err := rt.Connector.Local.Desired.GetNodeRuntimeFDUs(rt.Node, rt.FOSPlugin.UUID)
+	if err != nil {
+		return err
+	}
+	fduids := map[string]string{}
+	for _, records := range [][]FDURecord{actual, desired, reportedRecords(rt.GetFDUs())} {
+		for _, r := range records {
+			if _, found := fduids[r.UUID]; !found {
+				fduids[r.UUID] = r.FDUID
+			}
+		}
+	}
+
+	ids := []string{}
+	for id := range fduids {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		fduid, id := fduids[id], id
+		if rt.actions == nil {
+			rt.reconcileInstance(fduid, id, startup)
+			continue
+		}
+		rt.actions.enqueueFunc(id, func() { rt.reconcileInstance(fduid, id, startup) })
+	}
+	return nil
+}
+
+// reconcileInstance reads what the runtime reports, the record and the desired record of the instance, then reconciles them
+func (rt *FOSRuntimePluginAbstract) reconcileInstance(fduid string, instanceid string, startup bool) {
+	record, err := rt.Connector.Local.Actual.GetNodeFDU(rt.Node, rt.FOSPlugin.UUID, fduid, instanceid)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		rt.Logger.Error(fmt.Sprintf("Unable to get the record of %s: %s", instanceid, err.Error()))
+		return
+	}
+	desired, err := rt.Connector.Local.Desired.GetNodeFDU(rt.Node, rt.FOSPlugin.UUID, fduid, instanceid)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		rt.Logger.Error(fmt.Sprintf("Unable to get the desired record of %s: %s", instanceid, err.Error()))
+		return
+	}
+	var run *FDURecord
+	for _, r := range reportedRecords(rt.GetFDUs()) {
+		if r.UUID == instanceid {
+			r := r
+			run = &r
+			break
+		}
+	}
+	if run == nil && record == nil && desired == nil {
+		return
+	}
+	rt.reconcileRecords(run, record, desired, startup)
+}
+
+// reconcileRecords reconciles an instance given what the runtime reports, its record and its desired record, any of them can be nil
+func (rt *FOSRuntimePluginAbstract) reconcileRecords(run *FDURecord, record *FDURecord, desired *FDURecord, startup bool) {
+	switch {
+	case run == nil && record == nil:
+		switch desired.Status {
+		case DEFINE:
+			rt.redrive(*desired)
+		case UNDEFINE, ERROR, MIGRATE, LAND, TAKEOFF:
+			// a migrated instance has no record in the source node
+		default:
+			rt.markError(*desired, "Instance not found in the runtime")
+		}
+		return
+	case run == nil:
+		switch {
+		case record.Status == ERROR:
+		case desired != nil && desired.Status == UNDEFINE:
+			rt.Logger.Warn(fmt.Sprintf("Removing the record of %s, already undefined", record.UUID))
+			rt.removeInstance(*record)
+		case desired != nil && desired.Status == DEFINE:
+			rt.removeInstance(*record)
+			rt.redrive(*desired)
+		default:
+			rt.markError(*record, "Instance not found in the runtime")
+		}
+		return
+	case record == nil:
+		if desired == nil {
+			rt.markError(*run, "Instance not found in Local Actual")
+			return
+		}
+		rt.Logger.Warn(fmt.Sprintf("Restoring the record of %s", run.UUID))
+		if err := rt.AddFDURecord(run.UUID, run); err != nil {
+			rt.Logger.Error(fmt.Sprintf("Unable to restore the record of %s: %s", run.UUID, err.Error()))
+			return
+		}
+	case record.Status == ERROR:
+		return
+	case record.Status != run.Status:
+		rt.Logger.Warn(fmt.Sprintf("Status of %s is %s, the runtime has %s", record.UUID, record.Status, run.Status))
+		err := rt.UpdateFDUStatus(record.FDUID, record.UUID, run.Status)
+		var terr *FDUTransitionError
+		if errors.As(err, &terr) {
+			rt.markError(*record, err.Error())
+			return
+		}
+		if err != nil {
+			rt.Logger.Error(fmt.Sprintf("Unable to update the record of %s: %s", record.UUID, err.Error()))
+			return
+		}
+	}
+
+	if startup {
+		if err := rt.AddFDUEvals(run.FDUID, run.UUID); err != nil {
+			rt.Logger.Error(fmt.Sprintf("Unable to register the evals of %s: %s", run.UUID, err.Error()))
+		}
+	}
+	if desired == nil {
+		return
+	}
+	state, found := actionStates[desired.Status]
+	if !found || state == run.Status {
+		return
+	}
+	if FDUState(run.Status).CanTransition(FDUState(desired.Status)) {
+		rt.redrive(*desired)
+		return
+	}
+	rt.markError(*run, fmt.Sprintf("Instance in %s state cannot reach the desired action %s", run.Status, desired.Status))
+}
+
+// redrive queues again the action of the desired record
+func (rt *FOSRuntimePluginAbstract) redrive(desired FDURecord) {
+	rt.Logger.Warn(fmt.Sprintf("Queuing again %s for %s", desired.Status, desired.UUID))
+	if rt.actions == nil {
+		rt.react(desired)
+		return
+	}
+	rt.actions.enqueue(desired)
+}
+
+// markError writes the instance record in ERROR state with the given message
+func (rt *FOSRuntimePluginAbstract) markError(info FDURecord, msg string) {
+	rt.Logger.Warn(fmt.Sprintf("Marking %s as failed: %s", info.UUID, msg))
+	rt.writeError(info, &FError{msg, nil})
+}
+
+// removeInstance removes the record and the evals of the instance
+func (rt *FOSRuntimePluginAbstract) removeInstance(record FDURecord) {
+	if err := rt.RemoveFDUEvals(record.FDUID, record.UUID); err != nil {
+		rt.Logger.Error(fmt.Sprintf("Unable to remove the evals of %s: %s", record.UUID, err.Error()))
+	}
+	err := rt.Connector.Local.Actual.RemoveNodeFDU(rt.Node, rt.FOSPlugin.UUID, record.FDUID, record.UUID)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		rt.Logger.Error(fmt.Sprintf("Unable to remove the record of %s: %s", record.UUID, err.Error()))
+	}
+}
+
+// reconcileLoop runs Reconcile every ReconcileInterval until stop is closed
+func (rt *FOSRuntimePluginAbstract) reconcileLoop(stop chan struct{}) {
+	ticker := time.NewTicker(rt.ReconcileInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if err := rt.Reconcile(); err != nil {
+				rt.Logger.Error(fmt.Sprintf("Reconciliation failed: %s", err.Error()))
+			}
+		}
+	}
+}
+
+func reportedRecords(reported map[string]FDURecord) []FDURecord {
+	records := []FDURecord{}
+	for _, r := range reported {
+		records = append(records, r)
+	}
+	return records
+}
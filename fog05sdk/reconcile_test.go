@@ -0,0 +1,113 @@
+/*
+* Copyright (c) 2014,2019 Contributors to the Eclipse Foundation
+* See the NOTICE file(s) distributed with this work for additional
+* information regarding copyright ownership.
+* This program and the accompanying materials are made available under the
+* terms of the Eclipse Public License 2.0 which is available at
+* http://www.eclipse.org/legal/epl-2.0, or the Apache License, Version 2.0
+* which is available at https://www.apache.org/licenses/LICENSE-2.0.
+* SPDX-License-Identifier: EPL-2.0 OR Apache-2.0
+* Contributors: Gabriele Baldoni, ADLINK Technology Inc.
+* golang APIs
+ */
+
+package fog05sdk_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	fog05sdk "github.com/eclipse-fog05/sdk-go/fog05sdk"
+	"github.com/eclipse-fog05/sdk-go/fog05sdk/fostest"
+)
+
+func TestRuntimeReconcile(t *testing.T) {
+	yc, st := fostest.NewConnector()
+	rt, f := newRuntime(t, yc, "n1", "rt1")
+	record := func(instanceid string, status string) fog05sdk.FDURecord {
+		return fog05sdk.FDURecord{UUID: instanceid, FDUID: "f1", Status: status}
+	}
+	// for each instance: the runtime status, the record status and the desired action, "" for none
+	instances := []struct {
+		id, run, actual, desired string
+		status, calls            string
+	}{
+		{"drift", fog05sdk.CONFIGURE, fog05sdk.DEFINE, fog05sdk.CONFIGURE, fog05sdk.CONFIGURE, ""},
+		{"restart", fog05sdk.CONFIGURE, fog05sdk.RUN, fog05sdk.RUN, fog05sdk.RUN, "run restart"},
+		{"lost", "", fog05sdk.RUN, fog05sdk.RUN, fog05sdk.ERROR, ""},
+		{"orphan", fog05sdk.RUN, "", "", fog05sdk.ERROR, ""},
+		{"restore", fog05sdk.RUN, "", fog05sdk.RUN, fog05sdk.RUN, ""},
+		{"pending", "", "", fog05sdk.DEFINE, fog05sdk.DEFINE, "define pending"},
+		{"gone", "", fog05sdk.DEFINE, fog05sdk.UNDEFINE, "", ""},
+		{"stuck", fog05sdk.DEFINE, fog05sdk.DEFINE, fog05sdk.RUN, fog05sdk.ERROR, ""},
+		{"failed", fog05sdk.RUN, fog05sdk.ERROR, fog05sdk.RUN, fog05sdk.ERROR, ""},
+	}
+	for _, i := range instances {
+		if i.run != "" {
+			f.fdus[i.id] = record(i.id, i.run)
+		}
+		if i.actual != "" {
+			if err := yc.Local.Actual.AddNodeFDU("n1", "rt1", "f1", i.id, record(i.id, i.actual)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if i.desired != "" {
+			if err := yc.Local.Desired.AddNodeFDU("n1", "rt1", "f1", i.id, record(i.id, i.desired)); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	rt.Start()
+	defer rt.Close()
+	within(t, 2*time.Second, "the reconciliation", func() bool {
+		return rt.QueueStats().Instances == 0
+	})
+	for _, i := range instances {
+		status := ""
+		if r, err := yc.Local.Actual.GetNodeFDU("n1", "rt1", "f1", i.id); err == nil {
+			status = r.Status
+		}
+		if status != i.status {
+			t.Errorf("%s: status = %q, want %q", i.id, status, i.status)
+		}
+		calls := []string{}
+		for _, c := range strings.Split(f.history(), ",") {
+			if strings.HasSuffix(c, " "+i.id) {
+				calls = append(calls, c)
+			}
+		}
+		if strings.Join(calls, ",") != i.calls {
+			t.Errorf("%s: calls = %v, want %q", i.id, calls, i.calls)
+		}
+	}
+	// at startup the evals of the instances known to the runtime are registered again
+	if !strings.Contains(strings.Join(st.EvalPaths(), " "), "/restore/") {
+		t.Errorf("evals of the restored instance not registered: %v", st.EvalPaths())
+	}
+}
+
+func TestRuntimeReconcileInterval(t *testing.T) {
+	yc, _ := fostest.NewConnector()
+	rt, f := newRuntime(t, yc, "n1", "rt1")
+	rt.ReconcileInterval = 50 * time.Millisecond
+	rt.Start()
+	defer rt.Close()
+
+	if err := yc.Local.Desired.AddNodeFDU("n1", "rt1", "f1", "i1", fog05sdk.FDURecord{UUID: "i1", FDUID: "f1", Status: fog05sdk.DEFINE}); err != nil {
+		t.Fatal(err)
+	}
+	eventually(t, "the instance defined", func() bool {
+		r, err := yc.Local.Actual.GetNodeFDU("n1", "rt1", "f1", "i1")
+		return err == nil && r.Status == fog05sdk.DEFINE
+	})
+
+	// the runtime loses the instance, the next reconciliation defines it again
+	f.mu.Lock()
+	delete(f.fdus, "i1")
+	f.mu.Unlock()
+	eventually(t, "the lost instance defined again", func() bool {
+		return f.history() == "define i1,define i1"
+	})
+}
@@ -0,0 +1,483 @@
+/*
+* Copyright (c) 2014,2019 Contributors to the Eclipse Foundation
+* See the NOTICE file(s) distributed with this work for additional
+* information regarding copyright ownership.
+* This program and the accompanying materials are made available under the
+* terms of the Eclipse Public License 2.0 which is available at
+* http://www.eclipse.org/legal/epl-2.0, or the Apache License, Version 2.0
+* which is available at https://www.apache.org/licenses/LICENSE-2.0.
+* SPDX-License-Identifier: EPL-2.0 OR Apache-2.0
+* Contributors: Gabriele Baldoni, ADLINK Technology Inc.
+* golang APIs
+ */
+
+package fog05sdk
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+// ConnectionState is the state of the connection of a Store
+type ConnectionState int
+
+const (
+	// Connected the Store is connected
+	Connected ConnectionState = iota
+
+	// Disconnected the Store lost the connection and is reconnecting
+	Disconnected
+
+	// Closed the Store was closed
+	Closed
+)
+
+func (s ConnectionState) String() string {
+	switch s {
+	case Connected:
+		return "connected"
+	case Disconnected:
+		return "disconnected"
+	case Closed:
+		return "closed"
+	default:
+		return "unknown"
+	}
+}
+
+// ConnectionStateNotifier is implemented by the Stores able to notify the changes of their connection state
+type ConnectionStateNotifier interface {
+	// ObserveConnectionState registers a listener called on each change of the connection state
+	ObserveConnectionState(listener func(ConnectionState))
+}
+
+// ReconnectPolicy configures how a ReconnectingStore detects a disconnection and reconnects
+type ReconnectPolicy struct {
+	// InitialDelay is the delay before the first reconnection attempt
+	InitialDelay time.Duration
+
+	// MaxDelay is the maximum delay between two reconnection attempts
+	MaxDelay time.Duration
+
+	// Multiplier is the factor applied to the delay after each failed attempt
+	Multiplier float64
+
+	// CheckInterval is the interval between two checks of the connection
+	CheckInterval time.Duration
+
+	// CheckTimeout is the time after which a check of the connection is considered failed
+	CheckTimeout time.Duration
+}
+
+// DefaultReconnectPolicy is the default ReconnectPolicy of the connectors
+var DefaultReconnectPolicy = ReconnectPolicy{InitialDelay: 500 * time.Millisecond, MaxDelay: 30 * time.Second, Multiplier: 2, CheckInterval: 5 * time.Second, CheckTimeout: 3 * time.Second}
+
+type managedSubscription struct {
+	selector *Selector
+	listener func([]Change)
+	sid      *SubscriptionID
+}
+
+// ReconnectingStore is a Store that reconnects when the connection of the underlying Store is lost,
+// subscriptions and evals are re-established on the new Store and their SubscriptionID do not change.
+// Operations made while disconnected fail with ErrNotConnected
+type ReconnectingStore struct {
+	dial      func() (Store, error)
+	policy    ReconnectPolicy
+	logger    log.FieldLogger
+	mu        sync.Mutex
+	store     Store
+	state     ConnectionState
+	subs      map[*managedSubscription]bool
+	evals     map[string]EvalHandler
+	observers []func(ConnectionState)
+	probe     *Path
+	check     chan bool
+	done      chan bool
+}
+
+// NewReconnectingStore returns a ReconnectingStore creating the underlying Stores with dial, the first connection is made before returning.
+// The reconnect policy and the logger are taken from the options, zero fields of the policy are taken from DefaultReconnectPolicy
+func NewReconnectingStore(dial func() (Store, error), opts ...ConnectorOption) (*ReconnectingStore, error) {
+	o := NewConnectorOptions(opts...)
+	policy := o.ReconnectPolicy
+	if policy.InitialDelay <= 0 {
+		policy.InitialDelay = DefaultReconnectPolicy.InitialDelay
+	}
+	if policy.MaxDelay <= 0 {
+		policy.MaxDelay = DefaultReconnectPolicy.MaxDelay
+	}
+	if policy.Multiplier < 1 {
+		policy.Multiplier = DefaultReconnectPolicy.Multiplier
+	}
+	if policy.CheckInterval <= 0 {
+		policy.CheckInterval = DefaultReconnectPolicy.CheckInterval
+	}
+	if policy.CheckTimeout <= 0 {
+		policy.CheckTimeout = DefaultReconnectPolicy.CheckTimeout
+	}
+	probe, err := NewPath("/fos/connectors/" + uuid.UUID.String(uuid.New()) + "/alive")
+	if err != nil {
+		return nil, err
+	}
+	rs := ReconnectingStore{dial: dial, policy: policy, logger: o.Logger, state: Disconnected, subs: map[*managedSubscription]bool{}, evals: map[string]EvalHandler{}, probe: probe, check: make(chan bool, 1), done: make(chan bool)}
+
+	st, err := dial()
+	if err != nil {
+		return nil, err
+	}
+	err = rs.restore(st)
+	if err != nil {
+		st.Close()
+		return nil, err
+	}
+	rs.store = st
+	rs.state = Connected
+	go rs.monitor()
+	return &rs, nil
+}
+
+// State returns the current connection state
+func (rs *ReconnectingStore) State() ConnectionState {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	return rs.state
+}
+
+// ObserveConnectionState registers a listener called on each change of the connection state
+func (rs *ReconnectingStore) ObserveConnectionState(listener func(ConnectionState)) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	rs.observers = append(rs.observers, listener)
+}
+
+// Get ...
+func (rs *ReconnectingStore) Get(ctx context.Context, selector *Selector) ([]Entry, error) {
+	st, err := rs.current()
+	if err != nil {
+		return nil, err
+	}
+	kvs, err := st.Get(ctx, selector)
+	rs.failed(ctx, err)
+	return kvs, err
+}
+
+// Put ...
+func (rs *ReconnectingStore) Put(ctx context.Context, path *Path, value string) error {
+	st, err := rs.current()
+	if err != nil {
+		return err
+	}
+	err = st.Put(ctx, path, value)
+	rs.failed(ctx, err)
+	return err
+}
+
+// Remove ...
+func (rs *ReconnectingStore) Remove(ctx context.Context, path *Path) error {
+	st, err := rs.current()
+	if err != nil {
+		return err
+	}
+	err = st.Remove(ctx, path)
+	rs.failed(ctx, err)
+	return err
+}
+
+// Subscribe ...
+func (rs *ReconnectingStore) Subscribe(ctx context.Context, selector *Selector, listener func([]Change)) (*SubscriptionID, error) {
+	st, err := rs.current()
+	if err != nil {
+		return nil, err
+	}
+	sid, err := st.Subscribe(ctx, selector, listener)
+	if err != nil {
+		rs.failed(ctx, err)
+		return nil, err
+	}
+	ms := &managedSubscription{selector: selector, listener: listener, sid: sid}
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	if rs.store != st {
+		// the connection was lost meanwhile, it would not be restored
+		return nil, &FError{"Store is " + rs.state.String(), ErrNotConnected}
+	}
+	rs.subs[ms] = true
+	return &SubscriptionID{Handle: ms}, nil
+}
+
+// Unsubscribe ...
+func (rs *ReconnectingStore) Unsubscribe(ctx context.Context, sid *SubscriptionID) error {
+	ms, ok := sid.Handle.(*managedSubscription)
+	if !ok {
+		return &FError{"Subscription not found", ErrNotFound}
+	}
+	rs.mu.Lock()
+	if !rs.subs[ms] {
+		rs.mu.Unlock()
+		return &FError{"Subscription not found", ErrNotFound}
+	}
+	delete(rs.subs, ms)
+	st := rs.store
+	inner := ms.sid
+	rs.mu.Unlock()
+	if st == nil {
+		// the subscription is lost with the connection
+		return nil
+	}
+	err := st.Unsubscribe(ctx, inner)
+	rs.failed(ctx, err)
+	return err
+}
+
+// RegisterEval ...
+func (rs *ReconnectingStore) RegisterEval(ctx context.Context, path *Path, eval EvalHandler) error {
+	st, err := rs.current()
+	if err != nil {
+		return err
+	}
+	err = st.RegisterEval(ctx, path, eval)
+	if err != nil {
+		rs.failed(ctx, err)
+		return err
+	}
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	if rs.store != st {
+		// the connection was lost meanwhile, it would not be restored
+		return &FError{"Store is " + rs.state.String(), ErrNotConnected}
+	}
+	rs.evals[path.ToString()] = eval
+	return nil
+}
+
+// UnregisterEval ...
+func (rs *ReconnectingStore) UnregisterEval(ctx context.Context, path *Path) error {
+	rs.mu.Lock()
+	delete(rs.evals, path.ToString())
+	st := rs.store
+	rs.mu.Unlock()
+	if st == nil {
+		return nil
+	}
+	err := st.UnregisterEval(ctx, path)
+	rs.failed(ctx, err)
+	return err
+}
+
+// Close stops reconnecting and closes the underlying Store
+func (rs *ReconnectingStore) Close() error {
+	rs.mu.Lock()
+	if rs.state == Closed {
+		rs.mu.Unlock()
+		return nil
+	}
+	st := rs.store
+	rs.store = nil
+	rs.state = Closed
+	close(rs.done)
+	observers := append([]func(ConnectionState){}, rs.observers...)
+	rs.mu.Unlock()
+
+	rs.notifyState(Closed, observers)
+	if st == nil {
+		return nil
+	}
+	return st.Close()
+}
+
+func (rs *ReconnectingStore) current() (Store, error) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	if rs.store == nil {
+		return nil, &FError{"Store is " + rs.state.String(), ErrNotConnected}
+	}
+	return rs.store, nil
+}
+
+// failed requests a check of the connection when an operation failed for a reason other than the context
+func (rs *ReconnectingStore) failed(ctx context.Context, err error) {
+	if err == nil || ctx.Err() != nil {
+		return
+	}
+	var de *DecodeError
+	if errors.As(err, &de) || errors.Is(err, ErrNotFound) {
+		return
+	}
+	select {
+	case rs.check <- true:
+	default:
+	}
+}
+
+func (rs *ReconnectingStore) setState(state ConnectionState) {
+	rs.mu.Lock()
+	if rs.state == state || rs.state == Closed {
+		rs.mu.Unlock()
+		return
+	}
+	rs.state = state
+	observers := append([]func(ConnectionState){}, rs.observers...)
+	rs.mu.Unlock()
+	rs.notifyState(state, observers)
+}
+
+func (rs *ReconnectingStore) notifyState(state ConnectionState, observers []func(ConnectionState)) {
+	rs.logger.WithField("state", state.String()).Info("Store connection state changed")
+	for _, o := range observers {
+		o(state)
+	}
+}
+
+// alive checks the connection evaluating the probe eval registered by restore
+func (rs *ReconnectingStore) alive(st Store) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), rs.policy.CheckTimeout)
+	defer cancel()
+	s, err := NewSelector(rs.probe.ToString())
+	if err != nil {
+		return false
+	}
+	kvs, err := st.Get(ctx, s)
+	return err == nil && len(kvs) > 0
+}
+
+// restore registers the probe eval, the evals and the subscriptions on the given Store
+func (rs *ReconnectingStore) restore(st Store) error {
+	ctx, cancel := context.WithTimeout(context.Background(), rs.policy.CheckTimeout)
+	defer cancel()
+	err := st.RegisterEval(ctx, rs.probe, func(path *Path, props Properties) string { return "true" })
+	if err != nil {
+		return err
+	}
+
+	rs.mu.Lock()
+	evals := map[string]EvalHandler{}
+	for k, v := range rs.evals {
+		evals[k] = v
+	}
+	subs := []*managedSubscription{}
+	for ms := range rs.subs {
+		subs = append(subs, ms)
+	}
+	rs.mu.Unlock()
+
+	for k, eval := range evals {
+		p, err := NewPath(k)
+		if err != nil {
+			return err
+		}
+		err = st.RegisterEval(ctx, p, eval)
+		if err != nil {
+			return err
+		}
+		rs.mu.Lock()
+		_, ok := rs.evals[k]
+		rs.mu.Unlock()
+		if !ok {
+			// unregistered meanwhile, UnregisterEval did not find it on any Store
+			err = st.UnregisterEval(ctx, p)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	for _, ms := range subs {
+		sid, err := st.Subscribe(ctx, ms.selector, ms.listener)
+		if err != nil {
+			return err
+		}
+		rs.mu.Lock()
+		ok := rs.subs[ms]
+		if ok {
+			ms.sid = sid
+		}
+		rs.mu.Unlock()
+		if !ok {
+			// unsubscribed meanwhile, Unsubscribe did not find it on any Store
+			err = st.Unsubscribe(ctx, sid)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// monitor checks the connection periodically or when requested, and reconnects when it is lost
+func (rs *ReconnectingStore) monitor() {
+	ticker := time.NewTicker(rs.policy.CheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-rs.done:
+			return
+		case <-ticker.C:
+		case <-rs.check:
+		}
+		st, err := rs.current()
+		if err != nil {
+			continue
+		}
+		if rs.alive(st) {
+			continue
+		}
+
+		rs.mu.Lock()
+		if rs.store != st {
+			rs.mu.Unlock()
+			continue
+		}
+		rs.store = nil
+		rs.mu.Unlock()
+		rs.setState(Disconnected)
+		// closing a Store that lost its connection may block
+		go st.Close()
+
+		if !rs.reconnect() {
+			return
+		}
+	}
+}
+
+// reconnect dials until a new Store is connected and restored, returns false if the ReconnectingStore was closed meanwhile
+func (rs *ReconnectingStore) reconnect() bool {
+	delay := rs.policy.InitialDelay
+	for {
+		select {
+		case <-rs.done:
+			return false
+		case <-time.After(delay):
+		}
+
+		st, err := rs.dial()
+		if err == nil {
+			err = rs.restore(st)
+			if err != nil {
+				go st.Close()
+			}
+		}
+		if err == nil {
+			rs.mu.Lock()
+			if rs.state == Closed {
+				rs.mu.Unlock()
+				st.Close()
+				return false
+			}
+			rs.store = st
+			rs.mu.Unlock()
+			rs.setState(Connected)
+			return true
+		}
+
+		rs.logger.WithError(err).WithField("delay", delay.String()).Warn("Store reconnection failed")
+		delay = time.Duration(float64(delay) * rs.policy.Multiplier)
+		if delay > rs.policy.MaxDelay {
+			delay = rs.policy.MaxDelay
+		}
+	}
+}
@@ -0,0 +1,201 @@
+/*
+* Copyright (c) 2014,2019 Contributors to the Eclipse Foundation
+* See the NOTICE file(s) distributed with this work for additional
+* information regarding copyright ownership.
+* This program and the accompanying materials are made available under the
+* terms of the Eclipse Public License 2.0 which is available at
+* http://www.eclipse.org/legal/epl-2.0, or the Apache License, Version 2.0
+* which is available at https://www.apache.org/licenses/LICENSE-2.0.
+* SPDX-License-Identifier: EPL-2.0 OR Apache-2.0
+* Contributors: Gabriele Baldoni, ADLINK Technology Inc.
+* golang APIs
+ */
+
+package fog05sdk_test
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	fog05sdk "github.com/eclipse-fog05/sdk-go/fog05sdk"
+	"github.com/eclipse-fog05/sdk-go/fog05sdk/fostest"
+	"github.com/sirupsen/logrus/hooks/test"
+)
+
+// hookStore calls the hooks after registering a subscription or an eval on the fostest Store it wraps
+type hookStore struct {
+	*fostest.Store
+	afterSubscribe    func()
+	afterRegisterEval func(*fog05sdk.Path)
+}
+
+func (hs *hookStore) Subscribe(ctx context.Context, selector *fog05sdk.Selector, listener func([]fog05sdk.Change)) (*fog05sdk.SubscriptionID, error) {
+	sid, err := hs.Store.Subscribe(ctx, selector, listener)
+	if err == nil && hs.afterSubscribe != nil {
+		hs.afterSubscribe()
+	}
+	return sid, err
+}
+
+func (hs *hookStore) RegisterEval(ctx context.Context, path *fog05sdk.Path, handler fog05sdk.EvalHandler) error {
+	err := hs.Store.RegisterEval(ctx, path, handler)
+	if err == nil && hs.afterRegisterEval != nil {
+		hs.afterRegisterEval(path)
+	}
+	return err
+}
+
+// swappingDial returns a dial func returning the given Stores in order, and the number of dials made
+func swappingDial(stores ...fog05sdk.Store) (func() (fog05sdk.Store, error), func() int) {
+	var mu sync.Mutex
+	dials := 0
+	dial := func() (fog05sdk.Store, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if dials == len(stores) {
+			return nil, &fog05sdk.FError{Msg: "No more stores", Cause: fog05sdk.ErrNotConnected}
+		}
+		dials++
+		return stores[dials-1], nil
+	}
+	count := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return dials
+	}
+	return dial, count
+}
+
+// userEvals returns the eval paths of the Store but the connection probe
+func userEvals(st *fostest.Store) []string {
+	paths := []string{}
+	for _, p := range st.EvalPaths() {
+		if !strings.HasPrefix(p, "/fos/connectors/") {
+			paths = append(paths, p)
+		}
+	}
+	return paths
+}
+
+var quiet, _ = test.NewNullLogger()
+
+var testReconnectPolicy = fog05sdk.ReconnectPolicy{InitialDelay: time.Millisecond, CheckInterval: time.Hour}
+
+// disconnect closes the current Store and makes the ReconnectingStore notice it
+func disconnect(t *testing.T, rs *fog05sdk.ReconnectingStore, st *fostest.Store, dials func() int) {
+	t.Helper()
+	st.Close()
+	p, _ := fog05sdk.NewPath("/a/trigger")
+	if err := rs.Put(context.Background(), p, "v"); err == nil {
+		t.Fatal("Put on a closed Store succeeded")
+	}
+	eventually(t, "the reconnection", func() bool { return dials() == 2 && rs.State() == fog05sdk.Connected })
+}
+
+func TestReconnectingStoreRestores(t *testing.T) {
+	st1, st2 := fostest.NewStore(), fostest.NewStore()
+	dial, dials := swappingDial(st1, st2)
+	rs, err := fog05sdk.NewReconnectingStore(dial, fog05sdk.WithReconnectPolicy(testReconnectPolicy), fog05sdk.WithLogger(quiet))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rs.Close()
+	var mu sync.Mutex
+	states := []fog05sdk.ConnectionState{}
+	rs.ObserveConnectionState(func(s fog05sdk.ConnectionState) {
+		mu.Lock()
+		states = append(states, s)
+		mu.Unlock()
+	})
+
+	ctx := context.Background()
+	s, _ := fog05sdk.NewSelector("/a/*")
+	changes := make(chan fog05sdk.Change, 10)
+	sid, err := rs.Subscribe(ctx, s, func(cs []fog05sdk.Change) {
+		for _, c := range cs {
+			changes <- c
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ep, _ := fog05sdk.NewPath("/e/x")
+	err = rs.RegisterEval(ctx, ep, func(*fog05sdk.Path, fog05sdk.Properties) string { return `"pong"` })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	disconnect(t, rs, st1, dials)
+
+	mu.Lock()
+	if len(states) != 2 || states[0] != fog05sdk.Disconnected || states[1] != fog05sdk.Connected {
+		t.Errorf("connection states = %v, want disconnected then connected", states)
+	}
+	mu.Unlock()
+	if st2.Subscriptions() != 1 || len(userEvals(st2)) != 1 {
+		t.Fatalf("restored %d subscriptions and evals %v, want 1 and [/e/x]", st2.Subscriptions(), userEvals(st2))
+	}
+	p, _ := fog05sdk.NewPath("/a/b")
+	if err := rs.Put(ctx, p, "v"); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case c := <-changes:
+		if c.Path.ToString() != "/a/b" || c.Value != "v" {
+			t.Errorf("restored listener got %+v, want the put on /a/b", c)
+		}
+	case <-time.After(time.Second):
+		t.Error("restored listener not notified")
+	}
+	es, _ := fog05sdk.NewSelector("/e/x")
+	if kvs, err := rs.Get(ctx, es); err != nil || len(kvs) != 1 || kvs[0].Value != `"pong"` {
+		t.Errorf("restored eval = %v, %v, want pong", kvs, err)
+	}
+	if err := rs.Unsubscribe(ctx, sid); err != nil || st2.Subscriptions() != 0 {
+		t.Errorf("Unsubscribe with the SubscriptionID given before the reconnection = %v, %d subscriptions left", err, st2.Subscriptions())
+	}
+}
+
+func TestReconnectingStoreRemovedWhileRestoring(t *testing.T) {
+	st1 := fostest.NewStore()
+	st2 := &hookStore{Store: fostest.NewStore()}
+	dial, dials := swappingDial(st1, st2)
+	rs, err := fog05sdk.NewReconnectingStore(dial, fog05sdk.WithReconnectPolicy(testReconnectPolicy), fog05sdk.WithLogger(quiet))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rs.Close()
+
+	ctx := context.Background()
+	s, _ := fog05sdk.NewSelector("/a/*")
+	sid, err := rs.Subscribe(ctx, s, func([]fog05sdk.Change) {})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ep, _ := fog05sdk.NewPath("/e/x")
+	if err := rs.RegisterEval(ctx, ep, func(*fog05sdk.Path, fog05sdk.Properties) string { return "true" }); err != nil {
+		t.Fatal(err)
+	}
+	// the subscription and the eval are removed right after being re-registered on the new Store
+	st2.afterSubscribe = func() {
+		if err := rs.Unsubscribe(ctx, sid); err != nil {
+			t.Errorf("Unsubscribe while restoring = %v", err)
+		}
+	}
+	st2.afterRegisterEval = func(p *fog05sdk.Path) {
+		if p.ToString() == ep.ToString() {
+			if err := rs.UnregisterEval(ctx, ep); err != nil {
+				t.Errorf("UnregisterEval while restoring = %v", err)
+			}
+		}
+	}
+
+	disconnect(t, rs, st1, dials)
+
+	if st2.Subscriptions() != 0 || len(userEvals(st2.Store)) != 0 {
+		t.Errorf("new Store kept %d subscriptions and evals %v removed while restoring", st2.Subscriptions(), userEvals(st2.Store))
+	}
+}
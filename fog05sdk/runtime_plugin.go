@@ -1,6 +1,8 @@
 package fog05sdk
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"time"
@@ -9,6 +11,9 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
+// RuntimePluginType is the type of the runtime plugins in their manifest, the ones that manage the FDUs of an hypervisor
+const RuntimePluginType = "runtime"
+
 // FOSRuntimePluginInterface is the interface to be implenter for a Runtime Plugin
 type FOSRuntimePluginInterface interface {
 
@@ -42,7 +47,7 @@ type FOSRuntimePluginInterface interface {
 	//StopFDU stops the given FDU instance
 	StopFDU(string) error
 
-	//MigrateFDU migrates the given FDU instance
+	//MigrateFDU migrates the given FDU instance, called for MIGRATE, TAKEOFF and LAND if the plugin does not implement FOSRuntimeMigrationInterface
 	MigrateFDU(string) error
 
 	//ScaleFDU scales the given FDU instance
@@ -71,65 +76,202 @@ type FOSRuntimePluginAbstract struct {
 	Connector     *YaksConnector
 	Node          string
 	Configuration map[string]interface{}
+	Requirements  []string
 	Logger        *log.Logger
 	FOSRuntimePluginInterface
 	FOSPlugin
+
+	// MigrationTimeout is the time each side of a migration waits for the other one, 0 to wait forever
+	MigrationTimeout time.Duration
+
+	// Workers is the number of FDU instances whose actions run in parallel, the actions of an instance run in order.
+	// A migration does not hold a worker while it waits for the other node
+	Workers int
+
+	// ReconcileInterval is the interval of the periodic Reconcile, 0 to reconcile only at Start
+	ReconcileInterval time.Duration
+
+	actions       *actionQueue
+	stopReconcile chan struct{}
 }
 
 // NewFOSRuntimePluginAbstract returns a new FOSRuntimePluginFDU object
 func NewFOSRuntimePluginAbstract(name string, version int, pluginid string, manifest Plugin) (*FOSRuntimePluginAbstract, error) {
-	if pluginid == "" {
-		pluginid = uuid.UUID.String(uuid.New())
+	con, logger, err := connectorFromManifest(manifest)
+	if err != nil {
+		return nil, err
 	}
-	pl := NewPlugin(version, pluginid)
+	rt, err := NewFOSRuntimePluginAbstractWithConnector(name, version, pluginid, manifest, con)
+	if err != nil {
+		con.Close()
+		return nil, err
+	}
+	rt.Logger = logger
+	return rt, nil
+}
 
+// connectorFromManifest connects to the ylocator in the plugin configuration, returns the connector and the logger it uses
+func connectorFromManifest(manifest Plugin) (*YaksConnector, *log.Logger, error) {
+	if manifest.Configuration == nil {
+		return nil, nil, &FError{"Missing configuration in plugin manifest", nil}
+	}
 	conf := *manifest.Configuration
 	// json.Unmarshal([]byte(manifest.Configuration), &conf)
-	con, err := NewYaksConnector(conf["ylocator"].(string))
+	locator, ok := conf["ylocator"].(string)
+	if !ok {
+		return nil, nil, &FError{"Missing ylocator in plugin configuration", nil}
+	}
+	logger := log.New()
+	con, err := NewYaksConnector(locator, append(optionsFromConfiguration(conf), WithLogger(logger))...)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	return con, logger, nil
+}
+
+// optionsFromConfiguration returns the connector options set in the plugin configuration,
+// as user and passwd for the credentials, workspace for the workspace root, timeout in seconds and executor
+func optionsFromConfiguration(conf map[string]interface{}) []ConnectorOption {
+	opts := []ConnectorOption{}
+	if user, ok := conf["user"].(string); ok {
+		passwd, _ := conf["passwd"].(string)
+		opts = append(opts, WithCredentials(user, passwd))
+	}
+	if root, ok := conf["workspace"].(string); ok {
+		opts = append(opts, WithWorkspaceRoot(root))
+	}
+	if timeout, ok := conf["timeout"].(float64); ok {
+		opts = append(opts, WithTimeout(time.Duration(timeout*float64(time.Second))))
 	}
-	pl.connector = con
-	pl.node = conf["nodeid"].(string)
+	if executor, ok := conf["executor"].(bool); ok {
+		opts = append(opts, WithExecutor(executor))
+	}
+	return opts
+}
 
-	return &FOSRuntimePluginAbstract{Pid: os.Getpid(), Name: name, Connector: con, Node: conf["nodeid"].(string), FOSPlugin: *pl, Logger: log.New(), Configuration: conf}, nil
+// NewFOSRuntimePluginAbstractWithConnector returns a new FOSRuntimePluginAbstract object using the given connector instead of connecting to YAKS
+func NewFOSRuntimePluginAbstractWithConnector(name string, version int, pluginid string, manifest Plugin, con *YaksConnector) (*FOSRuntimePluginAbstract, error) {
+	if pluginid == "" {
+		pluginid = uuid.UUID.String(uuid.New())
+	}
+	if manifest.Configuration == nil {
+		return nil, &FError{"Missing configuration in plugin manifest", nil}
+	}
+	conf := *manifest.Configuration
+	node, ok := conf["nodeid"].(string)
+	if !ok {
+		return nil, &FError{"Missing nodeid in plugin configuration", nil}
+	}
+	pl := NewPluginWithConnector(version, pluginid, con, node)
+
+	return &FOSRuntimePluginAbstract{Pid: os.Getpid(), Name: name, Connector: con, Node: node, FOSPlugin: *pl, Logger: log.New(), Configuration: conf, Requirements: manifest.Requirements, MigrationTimeout: DefaultMigrationTimeout, Workers: DefaultRuntimeWorkers, ReconcileInterval: DefaultReconcileInterval}, nil
 }
 
-// Start starts the Plugin and calls StartRuntime of FOSRuntimePluginInterface
+// Start starts the Plugin, queues the desired records for react, calls StartRuntime of FOSRuntimePluginInterface,
+// then reconciles the instances and starts the periodic reconciliation
 func (rt *FOSRuntimePluginAbstract) Start() {
 	rt.WaitDependencies()
-	rt.Connector.Local.Desired.ObserveNodeRuntimeFDU(rt.Node, rt.FOSPlugin.UUID, rt.react)
-	err := rt.FOSRuntimePluginInterface.StartRuntime()
+	rt.actions = newActionQueue(rt.Workers, rt.react, rt.waitsPeer)
+	_, err := rt.Connector.Local.Desired.ObserveNodeRuntimeFDU(rt.Node, rt.FOSPlugin.UUID, rt.actions.enqueue)
+	if err != nil {
+		rt.Logger.Error(fmt.Sprintf("Unable to observe the desired FDU instances: %s", err.Error()))
+		rt.Close()
+		return
+	}
+	err = rt.FOSRuntimePluginInterface.StartRuntime()
 	if err != nil {
 		rt.Logger.Error(fmt.Sprintf("Plugin StartRuntime returned error %s", err.Error()))
 		rt.Close()
+		return
+	}
+	err = rt.reconcile(true)
+	if err != nil {
+		rt.Logger.Error(fmt.Sprintf("Reconciliation failed: %s", err.Error()))
+	}
+	if rt.ReconcileInterval > 0 {
+		rt.stopReconcile = make(chan struct{})
+		go rt.reconcileLoop(rt.stopReconcile)
 	}
 }
 
 // Close closes the Plugin, called by FOSRuntimePluginInterface.StopRuntime()
 func (rt *FOSRuntimePluginAbstract) Close() {
+	if rt.stopReconcile != nil {
+		close(rt.stopReconcile)
+		rt.stopReconcile = nil
+	}
+	if rt.actions != nil {
+		rt.actions.close()
+	}
 	rt.RemovePlugin()
-	rt.Connector.Close()
+	if err := rt.Connector.Close(); err != nil {
+		rt.Logger.Error(fmt.Sprintf("Unable to close the connector: %s", err.Error()))
+	}
 	rt.Logger.Info("Plugin closed")
 }
 
-// WaitDestinationReady waits for the destination node of a migration to be ready
+// QueueStats returns the metrics of the action queues
+func (rt *FOSRuntimePluginAbstract) QueueStats() RuntimeQueueStats {
+	if rt.actions == nil {
+		return RuntimeQueueStats{}
+	}
+	return rt.actions.statistics()
+}
+
+// QueueDepth returns the number of actions of the instance waiting to run
+func (rt *FOSRuntimePluginAbstract) QueueDepth(instanceid string) int {
+	if rt.actions == nil {
+		return 0
+	}
+	return rt.actions.depth(instanceid)
+}
+
+// WaitDestinationReady waits for the destination node of a migration to be ready, that is to have the instance in LAND state.
+// It returns false if the instance fails in the destination or MigrationTimeout expires
 func (rt *FOSRuntimePluginAbstract) WaitDestinationReady(fduid string, instanceid string, destinationid string) bool {
-	return false
+	err := rt.waitInstance(destinationid, fduid, instanceid, func(r *FDURecord) (bool, error) {
+		if r == nil {
+			return false, nil
+		}
+		switch r.Status {
+		case LAND:
+			return true, nil
+		case ERROR:
+			return false, &FError{"Instance failed in the destination node", nil}
+		}
+		return false, nil
+	})
+	if err != nil {
+		rt.Logger.Error(fmt.Sprintf("Destination %s of %s not ready: %s", destinationid, instanceid, err.Error()))
+		return false
+	}
+	return true
 }
 
-// WaitDependencies waits that the Agent, OS and NM Plugins are up and gets those from YAKS
+// WaitDependencies waits the plugins in Requirements, or if there are none that the Agent, OS and NM Plugins are up, and gets those from YAKS
 func (rt *FOSRuntimePluginAbstract) WaitDependencies() {
+	if len(rt.Requirements) > 0 {
+		if err := rt.FOSPlugin.WaitRequirements(context.Background(), rt.Requirements); err != nil {
+			rt.Logger.Error(fmt.Sprintf("Unable to get the requirements: %s", err.Error()))
+		}
+		return
+	}
 	for rt.FOSPlugin.Agent == nil {
-		rt.FOSPlugin.GetAgent()
+		if _, err := rt.FOSPlugin.GetAgent(); err != nil {
+			rt.Logger.Warn(fmt.Sprintf("Unable to get the Agent: %s", err.Error()))
+		}
 		time.Sleep(1 * time.Second)
 	}
 	for rt.FOSPlugin.OS == nil {
-		rt.FOSPlugin.GetOSPlugin()
+		if _, err := rt.FOSPlugin.GetOSPlugin(); err != nil {
+			rt.Logger.Warn(fmt.Sprintf("Unable to get the OS Plugin: %s", err.Error()))
+		}
 		time.Sleep(1 * time.Second)
 	}
 	for rt.FOSPlugin.NM == nil {
-		rt.FOSPlugin.GetNMPlugin()
+		if _, err := rt.FOSPlugin.GetNMPlugin(); err != nil {
+			rt.Logger.Warn(fmt.Sprintf("Unable to get the NM Plugin: %s", err.Error()))
+		}
 		time.Sleep(1 * time.Second)
 	}
 
@@ -142,7 +284,10 @@ func (rt *FOSRuntimePluginAbstract) WriteFDUError(fduid string, instanceid strin
 		return err
 	}
 
-	record.Status = ERROR
+	err = record.SetStatus(ERROR)
+	if err != nil {
+		return err
+	}
 	record.ErrorCode = &errno
 	record.ErrorMsg = &errmsg
 
@@ -150,14 +295,17 @@ func (rt *FOSRuntimePluginAbstract) WriteFDUError(fduid string, instanceid strin
 	return err
 }
 
-// UpdateFDUStatus given an fdu id, instance id and status updates the status in YAKS
+// UpdateFDUStatus given an fdu id, instance id and status updates the status in YAKS, returns an FDUTransitionError if the current status cannot move to status
 func (rt *FOSRuntimePluginAbstract) UpdateFDUStatus(fduid string, instanceid string, status string) error {
 	record, err := rt.Connector.Local.Actual.GetNodeFDU(rt.Node, rt.FOSPlugin.UUID, fduid, instanceid)
 	if err != nil {
 		return err
 	}
 
-	record.Status = status
+	err = record.SetStatus(status)
+	if err != nil {
+		return err
+	}
 
 	err = rt.Connector.Local.Actual.AddNodeFDU(rt.Node, rt.FOSPlugin.UUID, fduid, instanceid, *record)
 	return err
@@ -169,18 +317,27 @@ func (rt *FOSRuntimePluginAbstract) GetLocalInstances(fduid string) ([]string, e
 
 }
 
-// RegisterPlugin registers the plugin in the node
+// RegisterPlugin registers the plugin in the node and starts its heartbeats
 func (rt *FOSRuntimePluginAbstract) RegisterPlugin(manifest *Plugin) {
 	rt.Connector.Local.Actual.AddNodePlugin(rt.Node, rt.FOSPlugin.UUID, *manifest)
+	if err := rt.FOSPlugin.StartHeartbeat(); err != nil {
+		rt.Logger.Error(fmt.Sprintf("Unable to start the heartbeat: %s", err.Error()))
+	}
 }
 
-// RemovePlugin removes the plugin in the node
+// RemovePlugin stops the heartbeats and removes the plugin in the node
 func (rt *FOSRuntimePluginAbstract) RemovePlugin() {
+	if err := rt.FOSPlugin.StopHeartbeat(); err != nil {
+		rt.Logger.Error(fmt.Sprintf("Unable to remove the heartbeat: %s", err.Error()))
+	}
 	rt.Connector.Local.Actual.RemoveNodePlugin(rt.Node, rt.FOSPlugin.UUID)
 }
 
 // GetFDUDescriptor retrives an FDURecord for the plugin
 func (rt *FOSRuntimePluginAbstract) GetFDUDescriptor(fduid string, instanceid string) (*FDU, error) {
+	if rt.FOSPlugin.Agent == nil {
+		return nil, notLoaded("Agent")
+	}
 	return rt.FOSPlugin.Agent.GetFDUInfo(rt.Node, fduid, instanceid)
 }
 
@@ -207,30 +364,127 @@ func (rt *FOSRuntimePluginAbstract) RemoveFDURecord(instanceid string) error {
 	return rt.Connector.Local.Actual.RemoveNodeFDU(rt.Node, rt.FOSPlugin.UUID, record.FDUID, instanceid)
 }
 
-func (rt *FOSRuntimePluginAbstract) react(info FDURecord) {
+// AddFDUEvals registers the start, run, log, ls and file evals of the instance, backed by StartFDU, RunFDU, GetLogFDU, LsFDU and GetFileFDU
+func (rt *FOSRuntimePluginAbstract) AddFDUEvals(fduid string, instanceid string) error {
+	lad := rt.Connector.Local.Actual
+	bind := func(fn func(string, *string) EvalResult) func(*string) EvalResult {
+		return func(arg *string) EvalResult { return fn(instanceid, arg) }
+	}
+	err := lad.AddPluginFDUStartEval(rt.Node, rt.FOSPlugin.UUID, fduid, instanceid, bind(rt.StartFDU))
+	if err == nil {
+		err = lad.AddPluginFDURunEval(rt.Node, rt.FOSPlugin.UUID, fduid, instanceid, bind(rt.RunFDU))
+	}
+	if err == nil {
+		err = lad.AddPluginFDULogEval(rt.Node, rt.FOSPlugin.UUID, fduid, instanceid, bind(rt.GetLogFDU))
+	}
+	if err == nil {
+		err = lad.AddPluginFDULsEval(rt.Node, rt.FOSPlugin.UUID, fduid, instanceid, bind(rt.LsFDU))
+	}
+	if err == nil {
+		err = lad.AddPluginFDUFileEval(rt.Node, rt.FOSPlugin.UUID, fduid, instanceid, bind(rt.GetFileFDU))
+	}
+	if err != nil {
+		rt.RemoveFDUEvals(fduid, instanceid)
+		return err
+	}
+	return nil
+}
 
+// RemoveFDUEvals removes the evals of the instance registered by AddFDUEvals
+func (rt *FOSRuntimePluginAbstract) RemoveFDUEvals(fduid string, instanceid string) error {
+	lad := rt.Connector.Local.Actual
+	var errs []error
+	for _, remove := range []func(string, string, string, string) error{lad.RemovePluginFDUStartEval, lad.RemovePluginFDURunEval,
+		lad.RemovePluginFDULogEval, lad.RemovePluginFDULsEval, lad.RemovePluginFDUFileEval} {
+		err := remove(rt.Node, rt.FOSPlugin.UUID, fduid, instanceid)
+		if !errors.Is(err, ErrNotFound) {
+			errs = appendError(errs, err)
+		}
+	}
+	return multiError(errs)
+}
+
+// react calls the function of FOSRuntimePluginInterface for the action in the desired record, a failure is written in the instance record
+func (rt *FOSRuntimePluginAbstract) react(info FDURecord) {
 	action := info.Status
 	id := info.UUID
+	var err error
 	switch action {
 	case DEFINE:
-		rt.DefineFDU(info)
+		err = rt.DefineFDU(info)
+		if err == nil {
+			err = rt.AddFDUEvals(info.FDUID, id)
+		}
 	case UNDEFINE:
-		rt.UndefineFDU(id)
+		if rerr := rt.RemoveFDUEvals(info.FDUID, id); rerr != nil {
+			rt.Logger.Error(fmt.Sprintf("Unable to remove the evals of %s: %s", id, rerr.Error()))
+		}
+		err = rt.UndefineFDU(id)
 	case CLEAN:
-		rt.CleanFDU(id)
+		err = rt.CleanFDU(id)
 	case CONFIGURE:
-		rt.ConfigureFDU(id)
+		err = rt.ConfigureFDU(id)
+	case STARTING:
+		res := rt.StartFDU(id, nil)
+		err = res.Err()
+	case RUN:
+		// the instance may be already running, eg. started through the start eval
+		if record, rerr := rt.GetFDURecord(id); rerr != nil || record.Status != RUN {
+			res := rt.RunFDU(id, nil)
+			err = res.Err()
+		}
 	case STOP:
-		rt.StopFDU(id)
+		err = rt.StopFDU(id)
 	case PAUSE:
-		rt.PauseFDU(id)
+		err = rt.PauseFDU(id)
 	case RESUME:
-		rt.ResumeFDU(id)
-	case LAND:
-		rt.MigrateFDU(id)
-	case TAKEOFF:
-		rt.MigrateFDU(id)
+		err = rt.ResumeFDU(id)
+	case SCALE:
+		err = rt.ScaleFDU(id)
+	case MIGRATE, LAND, TAKEOFF:
+		m, ok := rt.FOSRuntimePluginInterface.(FOSRuntimeMigrationInterface)
+		if ok && info.MigrationProperties != nil {
+			// the migration holds the queue of the instance until it completes, so no other action runs meanwhile
+			rt.migrate(m, info)
+		} else {
+			err = rt.MigrateFDU(id)
+		}
+	case ERROR:
+		// the instance is already failed, nothing to do
 	default:
 		rt.Logger.Error(fmt.Sprintf("Action %s not recognized", action))
 	}
+	if err != nil {
+		rt.Logger.Error(fmt.Sprintf("Action %s of %s failed: %s", action, id, err.Error()))
+		rt.writeError(info, err)
+	}
+}
+
+// waitsPeer returns true for the desired records running the migration protocol, that wait for the other node of the migration
+func (rt *FOSRuntimePluginAbstract) waitsPeer(info FDURecord) bool {
+	switch info.Status {
+	case MIGRATE, LAND, TAKEOFF:
+		_, ok := rt.FOSRuntimePluginInterface.(FOSRuntimeMigrationInterface)
+		return ok && info.MigrationProperties != nil
+	}
+	return false
+}
+
+// writeError writes the error in the instance record, creating the record from the desired one if the instance has none
+func (rt *FOSRuntimePluginAbstract) writeError(info FDURecord, err error) {
+	code, msg := EvalErrorFailed, err.Error()
+	var eerr *EvalError
+	if errors.As(err, &eerr) {
+		code, msg = eerr.Code, eerr.Message
+	}
+	werr := rt.WriteFDUError(info.FDUID, info.UUID, code, msg)
+	if errors.Is(werr, ErrNotFound) {
+		info.Status = ERROR
+		info.ErrorCode = &code
+		info.ErrorMsg = &msg
+		werr = rt.AddFDURecord(info.UUID, &info)
+	}
+	if werr != nil {
+		rt.Logger.Error(fmt.Sprintf("Unable to write the error of %s: %s", info.UUID, werr.Error()))
+	}
 }
@@ -0,0 +1,200 @@
+/*
+* Copyright (c) 2014,2019 Contributors to the Eclipse Foundation
+* See the NOTICE file(s) distributed with this work for additional
+* information regarding copyright ownership.
+* This program and the accompanying materials are made available under the
+* terms of the Eclipse Public License 2.0 which is available at
+* http://www.eclipse.org/legal/epl-2.0, or the Apache License, Version 2.0
+* which is available at https://www.apache.org/licenses/LICENSE-2.0.
+* SPDX-License-Identifier: EPL-2.0 OR Apache-2.0
+* Contributors: Gabriele Baldoni, ADLINK Technology Inc.
+* golang APIs
+ */
+
+package fog05sdk_test
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	fog05sdk "github.com/eclipse-fog05/sdk-go/fog05sdk"
+	"github.com/eclipse-fog05/sdk-go/fog05sdk/fostest"
+	"github.com/sirupsen/logrus/hooks/test"
+)
+
+// fakeRuntime records the calls of the runtime functions used by the tests and updates its instances and their records, the others panic
+type fakeRuntime struct {
+	fog05sdk.FOSRuntimePluginInterface
+	rt    *fog05sdk.FOSRuntimePluginAbstract
+	mu    sync.Mutex
+	calls []string
+	fdus  map[string]fog05sdk.FDURecord
+}
+
+func (f *fakeRuntime) called(call string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, call)
+}
+
+func (f *fakeRuntime) history() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return strings.Join(f.calls, ",")
+}
+
+func (f *fakeRuntime) setStatus(instanceid string, status string) error {
+	r, err := f.rt.GetFDURecord(instanceid)
+	if err != nil {
+		return err
+	}
+	r.Status = status
+	f.mu.Lock()
+	f.fdus[instanceid] = *r
+	f.mu.Unlock()
+	return f.rt.AddFDURecord(instanceid, r)
+}
+
+func (f *fakeRuntime) GetFDUs() map[string]fog05sdk.FDURecord {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	fdus := map[string]fog05sdk.FDURecord{}
+	for k, v := range f.fdus {
+		fdus[k] = v
+	}
+	return fdus
+}
+
+func evalFailure(err error) fog05sdk.EvalResult {
+	code, msg := fog05sdk.EvalErrorFailed, err.Error()
+	return fog05sdk.EvalResult{Error: &code, ErrorMessage: &msg}
+}
+
+func (f *fakeRuntime) StartRuntime() error { return nil }
+
+func (f *fakeRuntime) DefineFDU(r fog05sdk.FDURecord) error {
+	f.called("define " + r.UUID)
+	if r.FDUID == "bad" {
+		return errors.New("bad FDU")
+	}
+	r.Status = fog05sdk.DEFINE
+	f.mu.Lock()
+	f.fdus[r.UUID] = r
+	f.mu.Unlock()
+	return f.rt.AddFDURecord(r.UUID, &r)
+}
+
+func (f *fakeRuntime) ConfigureFDU(instanceid string) error {
+	f.called("configure " + instanceid)
+	return f.setStatus(instanceid, fog05sdk.CONFIGURE)
+}
+
+func (f *fakeRuntime) StartFDU(instanceid string, env *string) fog05sdk.EvalResult {
+	f.called("start " + instanceid)
+	if err := f.setStatus(instanceid, fog05sdk.RUN); err != nil {
+		return evalFailure(err)
+	}
+	return fog05sdk.EvalResult{}
+}
+
+func (f *fakeRuntime) RunFDU(instanceid string, env *string) fog05sdk.EvalResult {
+	f.called("run " + instanceid)
+	if err := f.setStatus(instanceid, fog05sdk.RUN); err != nil {
+		return evalFailure(err)
+	}
+	return fog05sdk.EvalResult{}
+}
+
+func (f *fakeRuntime) StopFDU(instanceid string) error {
+	f.called("stop " + instanceid)
+	return &fog05sdk.EvalError{Code: 42, Message: "cannot stop"}
+}
+
+func (f *fakeRuntime) UndefineFDU(instanceid string) error {
+	f.called("undefine " + instanceid)
+	f.mu.Lock()
+	delete(f.fdus, instanceid)
+	f.mu.Unlock()
+	return f.rt.RemoveFDURecord(instanceid)
+}
+
+// newRuntime returns the fake runtime plugin of the node, not started, its dependencies are set so that it does not wait for them
+func newRuntime(t *testing.T, yc *fog05sdk.YaksConnector, nodeid string, pluginid string) (*fog05sdk.FOSRuntimePluginAbstract, *fakeRuntime) {
+	t.Helper()
+	conf := map[string]interface{}{"nodeid": nodeid}
+	rt, err := fog05sdk.NewFOSRuntimePluginAbstractWithConnector("fake", 1, pluginid, fog05sdk.Plugin{UUID: pluginid, Configuration: &conf}, yc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rt.Logger, _ = test.NewNullLogger()
+	rt.ReconcileInterval = 0
+	f := &fakeRuntime{rt: rt, fdus: map[string]fog05sdk.FDURecord{}}
+	rt.FOSRuntimePluginInterface = f
+	rt.FOSPlugin.Agent, rt.FOSPlugin.OS, rt.FOSPlugin.NM = &fog05sdk.Agent{}, &fog05sdk.OS{}, &fog05sdk.NM{}
+	return rt, f
+}
+
+func TestRuntimeReact(t *testing.T) {
+	yc, st := fostest.NewConnector()
+	rt, f := newRuntime(t, yc, "n1", "rt1")
+	rt.Start()
+	defer rt.Close()
+	desire := func(fduid string, instanceid string, status string) {
+		t.Helper()
+		err := yc.Local.Desired.AddNodeFDU("n1", "rt1", fduid, instanceid, fog05sdk.FDURecord{UUID: instanceid, FDUID: fduid, Status: status})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	status := func(instanceid string) string {
+		r, err := rt.GetFDURecord(instanceid)
+		if err != nil {
+			return err.Error()
+		}
+		return r.Status
+	}
+
+	steps := []struct {
+		status  string
+		history string
+		actual  string
+	}{
+		{fog05sdk.DEFINE, "define i1", fog05sdk.DEFINE},
+		{fog05sdk.CONFIGURE, "define i1,configure i1", fog05sdk.CONFIGURE},
+		{fog05sdk.RUN, "define i1,configure i1,run i1", fog05sdk.RUN},
+		// already running, RunFDU is not called again
+		{fog05sdk.RUN, "define i1,configure i1,run i1", fog05sdk.RUN},
+		{fog05sdk.STARTING, "define i1,configure i1,run i1,start i1", fog05sdk.RUN},
+		{fog05sdk.STOP, "define i1,configure i1,run i1,start i1,stop i1", fog05sdk.ERROR},
+	}
+	for _, s := range steps {
+		desire("f1", "i1", s.status)
+		eventually(t, "the "+s.status+" action", func() bool {
+			return rt.QueueDepth("i1") == 0 && f.history() == s.history && status("i1") == s.actual
+		})
+	}
+	if len(st.EvalPaths()) != 5 {
+		t.Errorf("evals of the defined instance = %v, want 5", st.EvalPaths())
+	}
+	r, err := rt.GetFDURecord("i1")
+	if err != nil || r.ErrorCode == nil || *r.ErrorCode != 42 || *r.ErrorMsg != "cannot stop" {
+		t.Errorf("record after the failed stop = %+v, %v, want error 42", r, err)
+	}
+
+	desire("f1", "i1", fog05sdk.UNDEFINE)
+	eventually(t, "the undefine action", func() bool {
+		_, err := rt.GetFDURecord("i1")
+		return errors.Is(err, fog05sdk.ErrNotFound)
+	})
+	if len(st.EvalPaths()) != 0 {
+		t.Errorf("evals of the undefined instance left: %v", st.EvalPaths())
+	}
+
+	desire("bad", "i2", fog05sdk.DEFINE)
+	eventually(t, "the failed define", func() bool { return status("i2") == fog05sdk.ERROR })
+	if r, _ := rt.GetFDURecord("i2"); r == nil || *r.ErrorCode != fog05sdk.EvalErrorFailed || *r.ErrorMsg != "bad FDU" {
+		t.Errorf("record of the failed define = %+v, want created with the error", r)
+	}
+}
@@ -0,0 +1,406 @@
+/*
+* Copyright (c) 2014,2019 Contributors to the Eclipse Foundation
+* See the NOTICE file(s) distributed with this work for additional
+* information regarding copyright ownership.
+* This program and the accompanying materials are made available under the
+* terms of the Eclipse Public License 2.0 which is available at
+* http://www.eclipse.org/legal/epl-2.0, or the Apache License, Version 2.0
+* which is available at https://www.apache.org/licenses/LICENSE-2.0.
+* SPDX-License-Identifier: EPL-2.0 OR Apache-2.0
+* Contributors: Gabriele Baldoni, ADLINK Technology Inc.
+* golang APIs
+ */
+
+package fog05sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// NodeResources is the information about a node used by the Scheduler to place FDUs
+type NodeResources struct {
+	NodeID string
+	Info   NodeInfo
+
+	// Status is the current status of the node, nil if the node does not publish it
+	Status *NodeStatus
+
+	// Plugins are the plugins running in the node
+	Plugins []Plugin
+
+	// Instances is the number of FDU instances in the node
+	Instances int
+}
+
+// NodeFilter returns nil if the node can host the FDU, or an error describing why it cannot
+type NodeFilter func(fdu *FDU, node *NodeResources) error
+
+// ScoringPolicy scores a node that can host the FDU, the node with the highest score is chosen
+type ScoringPolicy func(fdu *FDU, node *NodeResources) float64
+
+// Placement is a node that can host an FDU, with its score
+type Placement struct {
+	NodeID string
+	Score  float64
+}
+
+// PlacementError is returned when no node can host an FDU, Reasons has the reason each node was excluded
+type PlacementError struct {
+	FDUID   string
+	Reasons map[string]error
+}
+
+func (e *PlacementError) Error() string {
+	if len(e.Reasons) == 0 {
+		return "No node available for FDU " + e.FDUID
+	}
+	ids := []string{}
+	for id := range e.Reasons {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	msgs := []string{}
+	for _, id := range ids {
+		msgs = append(msgs, id+": "+e.Reasons[id].Error())
+	}
+	return "No node can host FDU " + e.FDUID + ": " + strings.Join(msgs, "; ")
+}
+
+// Scheduler chooses the node where an FDU is placed, it excludes the nodes that do not pass all its Filters and scores the others with its Policy
+type Scheduler struct {
+	connector *YaksConnector
+	sysid     string
+	tenantid  string
+
+	// Filters exclude the nodes that cannot host an FDU, by default the ones returned by DefaultFilters
+	Filters []NodeFilter
+
+	// Policy scores the nodes that can host an FDU
+	Policy ScoringPolicy
+}
+
+// NewScheduler returns a Scheduler for the nodes of the given system and tenant, using DefaultFilters and the given policy
+func NewScheduler(connector *YaksConnector, sysid string, tenantid string, policy ScoringPolicy) *Scheduler {
+	return &Scheduler{connector: connector, sysid: sysid, tenantid: tenantid, Filters: DefaultFilters(), Policy: policy}
+}
+
+// DefaultFilters returns the filters on computational requirements, hypervisor and I/O ports
+func DefaultFilters() []NodeFilter {
+	return []NodeFilter{FilterComputationalRequirements, FilterHypervisor, FilterIOPorts}
+}
+
+// Nodes returns the resources of all the nodes, reading them from Global Actual
+func (s *Scheduler) Nodes(ctx context.Context) ([]NodeResources, error) {
+	gad := s.connector.Global.Actual.WithContext(ctx)
+	ids, err := gad.GetAllNodes(s.sysid, s.tenantid)
+	if errors.Is(err, ErrNotFound) {
+		return []NodeResources{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(ids)
+
+	nodes := []NodeResources{}
+	for _, id := range ids {
+		n := NodeResources{NodeID: id, Plugins: []Plugin{}}
+		info, err := gad.GetNodeInfo(s.sysid, s.tenantid, id)
+		if err != nil {
+			return nil, err
+		}
+		n.Info = *info
+
+		n.Status, err = gad.GetNodeStatus(s.sysid, s.tenantid, id)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return nil, err
+		}
+
+		pids, err := gad.GetAllPluginsIDs(s.sysid, s.tenantid, id)
+		if err != nil {
+			return nil, err
+		}
+		seen := map[string]bool{}
+		for _, pid := range pids {
+			if seen[pid] {
+				continue
+			}
+			seen[pid] = true
+			pl, err := gad.GetPluginInfo(s.sysid, s.tenantid, id, pid)
+			if errors.Is(err, ErrNotFound) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			n.Plugins = append(n.Plugins, *pl)
+		}
+
+		instances, err := gad.GetNodeFDUs(s.sysid, s.tenantid, id)
+		if err != nil {
+			return nil, err
+		}
+		n.Instances = len(instances)
+
+		nodes = append(nodes, n)
+	}
+	return nodes, nil
+}
+
+// Candidates returns the nodes that can host the FDU sorted by decreasing score, the PlacementError lists why the other nodes were excluded.
+// When the FDU has geographical requirements the nodes are also filtered by GeographicalFilter
+func (s *Scheduler) Candidates(ctx context.Context, fdu *FDU) ([]Placement, *PlacementError, error) {
+	nodes, err := s.Nodes(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	filters := s.Filters
+	if fdu.GeographicalRequirements != nil {
+		g, err := s.GeographicalFilter(ctx, fdu, nodes)
+		if err != nil {
+			return nil, nil, err
+		}
+		filters = append(append([]NodeFilter{}, filters...), g.Filter)
+	}
+	placements, perr := place(fdu, nodes, filters, s.Policy)
+	return placements, perr, nil
+}
+
+// Place filters and scores the given nodes for the FDU, returning them sorted by decreasing score, ties are sorted by node id.
+// The PlacementError lists why the other nodes were excluded
+func (s *Scheduler) Place(fdu *FDU, nodes []NodeResources) ([]Placement, *PlacementError) {
+	return place(fdu, nodes, s.Filters, s.Policy)
+}
+
+func place(fdu *FDU, nodes []NodeResources, filters []NodeFilter, policy ScoringPolicy) ([]Placement, *PlacementError) {
+	perr := &PlacementError{FDUID: fdu.ID, Reasons: map[string]error{}}
+	placements := []Placement{}
+	for i := range nodes {
+		n := &nodes[i]
+		excluded := false
+		for _, f := range filters {
+			if err := f(fdu, n); err != nil {
+				perr.Reasons[n.NodeID] = err
+				excluded = true
+				break
+			}
+		}
+		if excluded {
+			continue
+		}
+		score := 0.0
+		if policy != nil {
+			score = policy(fdu, n)
+		}
+		placements = append(placements, Placement{NodeID: n.NodeID, Score: score})
+	}
+	sort.SliceStable(placements, func(i, j int) bool {
+		if placements[i].Score != placements[j].Score {
+			return placements[i].Score > placements[j].Score
+		}
+		return placements[i].NodeID < placements[j].NodeID
+	})
+	return placements, perr
+}
+
+// Schedule returns the node with the highest score that can host the FDU, or a PlacementError if there is none
+func (s *Scheduler) Schedule(ctx context.Context, fdu *FDU) (string, error) {
+	placements, perr, err := s.Candidates(ctx, fdu)
+	if err != nil {
+		return "", err
+	}
+	if len(placements) == 0 {
+		return "", perr
+	}
+	return placements[0].NodeID, nil
+}
+
+// Filters
+
+// FilterComputationalRequirements excludes the nodes without the CPU architecture, CPU count and frequency, RAM, storage,
+// GPUs and FPGAs required by the FDU. Free RAM and storage are taken from the node status, when available
+func FilterComputationalRequirements(fdu *FDU, node *NodeResources) error {
+	cr := fdu.ComputationRequirements
+
+	cpus := 0
+	maxFreq := 0.0
+	for _, cpu := range node.Info.CPU {
+		if cr.CPUArch != "" && !strings.EqualFold(cpu.Arch, cr.CPUArch) {
+			continue
+		}
+		cpus++
+		if cpu.Frequency > maxFreq {
+			maxFreq = cpu.Frequency
+		}
+	}
+	if cpus == 0 && cr.CPUArch != "" {
+		return &FError{"No CPU with architecture " + cr.CPUArch, nil}
+	}
+	if cpus < cr.CPUMinCount {
+		return &FError{fmt.Sprintf("%d CPUs, %d required", cpus, cr.CPUMinCount), nil}
+	}
+	if maxFreq < float64(cr.CPUMinFrequency) {
+		return &FError{fmt.Sprintf("CPU frequency %v, %d required", maxFreq, cr.CPUMinFrequency), nil}
+	}
+
+	ram := freeRAM(node)
+	if ram < cr.RAMSizeMB {
+		return &FError{fmt.Sprintf("%v MB of RAM, %v required", ram, cr.RAMSizeMB), nil}
+	}
+	storage := freeStorage(node)
+	if storage < cr.StorageSizeGB {
+		return &FError{fmt.Sprintf("%v GB of storage, %v required", storage, cr.StorageSizeGB), nil}
+	}
+
+	if cr.GPUMinCount != nil {
+		if gpus := countAccelerators(node, "gpu", "cuda"); gpus < *cr.GPUMinCount {
+			return &FError{fmt.Sprintf("%d GPUs, %d required", gpus, *cr.GPUMinCount), nil}
+		}
+	}
+	if cr.FPGAMinCount != nil {
+		if fpgas := countAccelerators(node, "fpga"); fpgas < *cr.FPGAMinCount {
+			return &FError{fmt.Sprintf("%d FPGAs, %d required", fpgas, *cr.FPGAMinCount), nil}
+		}
+	}
+	return nil
+}
+
+// FilterHypervisor excludes the nodes without a runtime plugin for the FDU hypervisor. The hypervisor of a plugin is the hypervisor
+// in the configuration of its manifest or, if missing, the plugin name, BARE FDUs are also managed by the native plugin
+func FilterHypervisor(fdu *FDU, node *NodeResources) error {
+	for _, pl := range node.Plugins {
+		if pl.Type != RuntimePluginType {
+			continue
+		}
+		hv := pluginHypervisor(pl)
+		if strings.EqualFold(hv, fdu.Hypervisor) || (fdu.Hypervisor == BARE && strings.EqualFold(hv, "native")) {
+			return nil
+		}
+	}
+	return &FError{"No runtime plugin for hypervisor " + fdu.Hypervisor, nil}
+}
+
+// pluginHypervisor returns the hypervisor configured in the manifest of the runtime plugin, or its name
+func pluginHypervisor(pl Plugin) string {
+	if pl.Configuration != nil {
+		if hv, ok := (*pl.Configuration)["hypervisor"].(string); ok && hv != "" {
+			return hv
+		}
+	}
+	return pl.Name
+}
+
+// FilterIOPorts excludes the nodes without enough available I/O ports of the kinds required by the FDU
+func FilterIOPorts(fdu *FDU, node *NodeResources) error {
+	required := map[string]int{}
+	for _, port := range fdu.IOPorts {
+		n := port.MinIOPorts
+		if n < 1 {
+			n = 1
+		}
+		required[strings.ToUpper(port.IOKind)] += n
+	}
+	available := map[string]int{}
+	for _, io := range node.Info.IO {
+		if io.Available {
+			available[strings.ToUpper(io.IOType)]++
+		}
+	}
+	for kind, n := range required {
+		if available[kind] < n {
+			return &FError{fmt.Sprintf("%d %s ports available, %d required", available[kind], kind, n), nil}
+		}
+	}
+	return nil
+}
+
+// Scoring policies
+
+// BinPackPolicy prefers the nodes with less free RAM, to fill a node before using the next one
+func BinPackPolicy(fdu *FDU, node *NodeResources) float64 {
+	total := node.Info.RAM.Size
+	if node.Status != nil && node.Status.RAM.Total > 0 {
+		total = node.Status.RAM.Total
+	}
+	if total <= 0 {
+		return 0
+	}
+	return (total - freeRAM(node) + fdu.ComputationRequirements.RAMSizeMB) / total
+}
+
+// SpreadPolicy prefers the nodes with less FDU instances, to distribute the instances across the nodes
+func SpreadPolicy(fdu *FDU, node *NodeResources) float64 {
+	return 1 / float64(1+node.Instances)
+}
+
+// LeastLoadedPolicy prefers the nodes with the higher fraction of free RAM and disk in their status, nodes without status have score 0
+func LeastLoadedPolicy(fdu *FDU, node *NodeResources) float64 {
+	if node.Status == nil {
+		return 0
+	}
+	score := 0.0
+	if node.Status.RAM.Total > 0 {
+		score += node.Status.RAM.Free / node.Status.RAM.Total
+	}
+	total := 0.0
+	free := 0.0
+	for _, d := range node.Status.Disk {
+		total += d.Total
+		free += d.Free
+	}
+	if total > 0 {
+		score += free / total
+	}
+	return score / 2
+}
+
+// freeRAM returns the free RAM in MB from the node status, or the node RAM size if there is no status
+func freeRAM(node *NodeResources) float64 {
+	if node.Status != nil {
+		return node.Status.RAM.Free
+	}
+	return node.Info.RAM.Size
+}
+
+// freeStorage returns the largest free disk space in GB from the node status, or the largest disk if there is no status
+func freeStorage(node *NodeResources) float64 {
+	max := 0.0
+	if node.Status != nil {
+		for _, d := range node.Status.Disk {
+			if d.Free > max {
+				max = d.Free
+			}
+		}
+		return max
+	}
+	for _, d := range node.Info.Disks {
+		if d.Dimension > max {
+			max = d.Dimension
+		}
+	}
+	return max
+}
+
+// countAccelerators counts the available accelerators whose name or supported libraries contain one of the keywords, eg. gpu or fpga
+func countAccelerators(node *NodeResources, keywords ...string) int {
+	n := 0
+	for _, a := range node.Info.Accelerator {
+		if !a.Available {
+			continue
+		}
+		names := append([]string{a.Name}, a.SupportedLibrary...)
+		match := false
+		for _, name := range names {
+			for _, k := range keywords {
+				match = match || strings.Contains(strings.ToLower(name), k)
+			}
+		}
+		if match {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,169 @@
+/*
+* Copyright (c) 2014,2019 Contributors to the Eclipse Foundation
+* See the NOTICE file(s) distributed with this work for additional
+* information regarding copyright ownership.
+* This program and the accompanying materials are made available under the
+* terms of the Eclipse Public License 2.0 which is available at
+* http://www.eclipse.org/legal/epl-2.0, or the Apache License, Version 2.0
+* which is available at https://www.apache.org/licenses/LICENSE-2.0.
+* SPDX-License-Identifier: EPL-2.0 OR Apache-2.0
+* Contributors: Gabriele Baldoni, ADLINK Technology Inc.
+* golang APIs
+ */
+
+package fog05sdk
+
+import (
+	"reflect"
+	"testing"
+)
+
+func testNode(id string, cpus int, ramMB float64, hypervisor string) NodeResources {
+	node := NodeResources{NodeID: id, Info: NodeInfo{UUID: id, RAM: RAMSpec{Size: ramMB}, Disks: []DiskSpec{{Dimension: 100}}}}
+	for i := 0; i < cpus; i++ {
+		node.Info.CPU = append(node.Info.CPU, CPUSpec{Arch: "x86_64", Frequency: 2000})
+	}
+	if hypervisor != "" {
+		node.Plugins = []Plugin{{UUID: id + "-rt", Name: hypervisor, Type: RuntimePluginType}}
+	}
+	return node
+}
+
+func TestFilterComputationalRequirements(t *testing.T) {
+	one := 1
+	tests := []struct {
+		name   string
+		req    FDUComputationalRequirements
+		modify func(*NodeResources)
+		ok     bool
+	}{
+		{"fits", FDUComputationalRequirements{CPUMinCount: 2, RAMSizeMB: 1024, StorageSizeGB: 10}, func(*NodeResources) {}, true},
+		{"cpu count", FDUComputationalRequirements{CPUMinCount: 3}, func(*NodeResources) {}, false},
+		{"cpu arch", FDUComputationalRequirements{CPUArch: "ARM64", CPUMinCount: 1}, func(*NodeResources) {}, false},
+		{"cpu arch case", FDUComputationalRequirements{CPUArch: "X86_64", CPUMinCount: 1}, func(*NodeResources) {}, true},
+		{"cpu frequency", FDUComputationalRequirements{CPUMinCount: 1, CPUMinFrequency: 2500}, func(*NodeResources) {}, false},
+		{"ram", FDUComputationalRequirements{CPUMinCount: 1, RAMSizeMB: 4096}, func(*NodeResources) {}, false},
+		{"free ram from status", FDUComputationalRequirements{CPUMinCount: 1, RAMSizeMB: 1024}, func(n *NodeResources) {
+			n.Status = &NodeStatus{RAM: RAMStatus{Total: 2048, Free: 512}, Disk: []DiskStatus{{Total: 100, Free: 100}}}
+		}, false},
+		{"free storage from status", FDUComputationalRequirements{CPUMinCount: 1, StorageSizeGB: 10}, func(n *NodeResources) {
+			n.Status = &NodeStatus{RAM: RAMStatus{Total: 2048, Free: 2048}, Disk: []DiskStatus{{Total: 100, Free: 5}}}
+		}, false},
+		{"no gpu", FDUComputationalRequirements{CPUMinCount: 1, GPUMinCount: &one}, func(*NodeResources) {}, false},
+		{"gpu", FDUComputationalRequirements{CPUMinCount: 1, GPUMinCount: &one}, func(n *NodeResources) {
+			n.Info.Accelerator = []AcceleratorSpec{{Name: "Tesla", SupportedLibrary: []string{"CUDA"}, Available: true}}
+		}, true},
+		{"gpu not available", FDUComputationalRequirements{CPUMinCount: 1, GPUMinCount: &one}, func(n *NodeResources) {
+			n.Info.Accelerator = []AcceleratorSpec{{Name: "GPU0"}}
+		}, false},
+		{"fpga", FDUComputationalRequirements{CPUMinCount: 1, FPGAMinCount: &one}, func(n *NodeResources) {
+			n.Info.Accelerator = []AcceleratorSpec{{Name: "FPGA0", Available: true}}
+		}, true},
+	}
+	for _, tt := range tests {
+		node := testNode("n1", 2, 2048, LXD)
+		tt.modify(&node)
+		fdu := FDU{ID: "fdu1", ComputationRequirements: tt.req}
+		if err := FilterComputationalRequirements(&fdu, &node); (err == nil) != tt.ok {
+			t.Errorf("%s: FilterComputationalRequirements() = %v, want ok %v", tt.name, err, tt.ok)
+		}
+	}
+}
+
+func TestFilterHypervisor(t *testing.T) {
+	tests := []struct {
+		name       string
+		hypervisor string
+		plugins    []Plugin
+		ok         bool
+	}{
+		{"name", LXD, []Plugin{{Name: "LXD", Type: RuntimePluginType}}, true},
+		{"name case", KVM, []Plugin{{Name: "kvm", Type: RuntimePluginType}}, true},
+		{"configured", DOCKER, []Plugin{{Name: "containers", Type: RuntimePluginType, Configuration: &jsont{"hypervisor": "docker"}}}, true},
+		{"configured overrides name", LXD, []Plugin{{Name: "lxd", Type: RuntimePluginType, Configuration: &jsont{"hypervisor": "docker"}}}, false},
+		{"native for bare", BARE, []Plugin{{Name: "native", Type: RuntimePluginType}}, true},
+		{"not a runtime", LXD, []Plugin{{Name: "lxd", Type: NetworkManagerPluginType}}, false},
+		{"other hypervisor", KVM, []Plugin{{Name: "lxd", Type: RuntimePluginType}}, false},
+		{"no plugins", LXD, nil, false},
+	}
+	for _, tt := range tests {
+		node := NodeResources{NodeID: "n1", Plugins: tt.plugins}
+		fdu := FDU{ID: "fdu1", Hypervisor: tt.hypervisor}
+		if err := FilterHypervisor(&fdu, &node); (err == nil) != tt.ok {
+			t.Errorf("%s: FilterHypervisor() = %v, want ok %v", tt.name, err, tt.ok)
+		}
+	}
+}
+
+func TestFilterIOPorts(t *testing.T) {
+	io := []IOSpec{{IOType: "gpio", Available: true}, {IOType: "GPIO", Available: true}, {IOType: "CAN", Available: false}}
+	tests := []struct {
+		name  string
+		ports []FDUIOPort
+		ok    bool
+	}{
+		{"none", nil, true},
+		{"one", []FDUIOPort{{IOKind: GPIO, MinIOPorts: 1}}, true},
+		{"at least one", []FDUIOPort{{IOKind: GPIO}}, true},
+		{"two", []FDUIOPort{{IOKind: GPIO, MinIOPorts: 2}}, true},
+		{"summed", []FDUIOPort{{IOKind: GPIO, MinIOPorts: 2}, {IOKind: GPIO, MinIOPorts: 1}}, false},
+		{"not available", []FDUIOPort{{IOKind: CAN, MinIOPorts: 1}}, false},
+		{"missing", []FDUIOPort{{IOKind: I2C, MinIOPorts: 1}}, false},
+	}
+	for _, tt := range tests {
+		node := NodeResources{NodeID: "n1", Info: NodeInfo{IO: io}}
+		fdu := FDU{ID: "fdu1", IOPorts: tt.ports}
+		if err := FilterIOPorts(&fdu, &node); (err == nil) != tt.ok {
+			t.Errorf("%s: FilterIOPorts() = %v, want ok %v", tt.name, err, tt.ok)
+		}
+	}
+}
+
+func TestPlace(t *testing.T) {
+	busy := testNode("n1", 4, 4096, LXD)
+	busy.Instances = 3
+	busy.Status = &NodeStatus{RAM: RAMStatus{Total: 4096, Free: 1024}}
+	idle := testNode("n2", 4, 4096, LXD)
+	idle.Status = &NodeStatus{RAM: RAMStatus{Total: 4096, Free: 3072}}
+	twin := testNode("n3", 4, 4096, LXD)
+	twin.Status = &NodeStatus{RAM: RAMStatus{Total: 4096, Free: 3072}}
+	small := testNode("n4", 1, 4096, LXD)
+	kvm := testNode("n5", 4, 4096, KVM)
+	nodes := []NodeResources{busy, idle, twin, small, kvm}
+	fdu := FDU{ID: "fdu1", Hypervisor: LXD, ComputationRequirements: FDUComputationalRequirements{CPUMinCount: 2, RAMSizeMB: 512}}
+
+	tests := []struct {
+		name     string
+		filters  []NodeFilter
+		policy   ScoringPolicy
+		want     []string
+		excluded []string
+	}{
+		{"no policy", DefaultFilters(), nil, []string{"n1", "n2", "n3"}, []string{"n4", "n5"}},
+		{"spread", DefaultFilters(), SpreadPolicy, []string{"n2", "n3", "n1"}, []string{"n4", "n5"}},
+		{"bin pack", DefaultFilters(), BinPackPolicy, []string{"n1", "n2", "n3"}, []string{"n4", "n5"}},
+		{"least loaded", DefaultFilters(), LeastLoadedPolicy, []string{"n2", "n3", "n1"}, []string{"n4", "n5"}},
+		{"no filters", nil, nil, []string{"n1", "n2", "n3", "n4", "n5"}, []string{}},
+		{"exclude all", []NodeFilter{func(*FDU, *NodeResources) error { return &FError{"excluded", nil} }}, nil,
+			[]string{}, []string{"n1", "n2", "n3", "n4", "n5"}},
+	}
+	for _, tt := range tests {
+		placements, perr := place(&fdu, nodes, tt.filters, tt.policy)
+		got := []string{}
+		for _, p := range placements {
+			got = append(got, p.NodeID)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: place() = %v, want %v", tt.name, got, tt.want)
+		}
+		excluded := []string{}
+		for _, n := range nodes {
+			if _, found := perr.Reasons[n.NodeID]; found {
+				excluded = append(excluded, n.NodeID)
+			}
+		}
+		if !reflect.DeepEqual(excluded, tt.excluded) {
+			t.Errorf("%s: excluded %v, want %v", tt.name, excluded, tt.excluded)
+		}
+	}
+}
@@ -0,0 +1,210 @@
+/*
+* Copyright (c) 2014,2019 Contributors to the Eclipse Foundation
+* See the NOTICE file(s) distributed with this work for additional
+* information regarding copyright ownership.
+* This program and the accompanying materials are made available under the
+* terms of the Eclipse Public License 2.0 which is available at
+* http://www.eclipse.org/legal/epl-2.0, or the Apache License, Version 2.0
+* which is available at https://www.apache.org/licenses/LICENSE-2.0.
+* SPDX-License-Identifier: EPL-2.0 OR Apache-2.0
+* Contributors: Gabriele Baldoni, ADLINK Technology Inc.
+* golang APIs
+ */
+
+package fog05sdk
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Store is the key-value store on which GAD and LAD are built, the default implementation is backed by YAKS.
+// Operations return an error when the given context is done before they complete, a Put or Remove that completes afterwards
+// is not rolled back, while a Subscribe or RegisterEval that completes afterwards is undone
+type Store interface {
+	// Get returns the entries matching the given selector, evals matching the selector are evaluated
+	Get(ctx context.Context, selector *Selector) ([]Entry, error)
+
+	// Put stores the given value at the given path
+	Put(ctx context.Context, path *Path, value string) error
+
+	// Remove removes the value stored at the given path
+	Remove(ctx context.Context, path *Path) error
+
+	// Subscribe registers a listener called for each change on the paths matching the given selector
+	Subscribe(ctx context.Context, selector *Selector, listener func([]Change)) (*SubscriptionID, error)
+
+	// Unsubscribe removes the given subscription
+	Unsubscribe(ctx context.Context, sid *SubscriptionID) error
+
+	// RegisterEval registers an eval at the given path
+	RegisterEval(ctx context.Context, path *Path, eval EvalHandler) error
+
+	// UnregisterEval removes the eval registered at the given path
+	UnregisterEval(ctx context.Context, path *Path) error
+
+	// Close closes the store
+	Close() error
+}
+
+// EvalHandler is the function called when an eval registered in a Store is evaluated, returns the eval value
+type EvalHandler func(path *Path, props Properties) string
+
+// Properties is a (string,string) map, used for eval parameters
+type Properties map[string]string
+
+// SubscriptionID identifies a subscription made on a Store, Handle is specific to the Store implementation
+type SubscriptionID struct {
+	Handle interface{}
+}
+
+// Entry is a path/value couple returned by a Store
+type Entry struct {
+	Path  *Path
+	Value string
+}
+
+// ChangeKind is the kind of a change notified by a Store
+type ChangeKind = uint8
+
+const (
+	// PUT is a change made by a put
+	PUT ChangeKind = 0x00
+
+	// UPDATE is a change made by an update
+	UPDATE ChangeKind = 0x01
+
+	// REMOVE is a change made by a remove
+	REMOVE ChangeKind = 0x02
+)
+
+// Change represents a change made on a path of a Store
+type Change struct {
+	Path  *Path
+	Kind  ChangeKind
+	Time  uint64
+	Value string
+}
+
+// Path is a path in the Store
+type Path struct {
+	path string
+}
+
+var slashesRegexp = regexp.MustCompile("/+")
+
+// NewPath returns a new Path from the given string, returns an error if it is not a valid path
+func NewPath(p string) (*Path, error) {
+	if len(p) == 0 {
+		return nil, &FError{"Invalid path (empty string)", nil}
+	}
+	for i, c := range p {
+		if c == '?' || c == '#' || c == '[' || c == ']' || c == '*' {
+			return nil, &FError{"Invalid path: " + p + " (forbidden character at index " + strconv.Itoa(i) + ")", nil}
+		}
+	}
+	return &Path{strings.TrimSuffix(slashesRegexp.ReplaceAllString(p, "/"), "/")}, nil
+}
+
+// ToString returns the Path as a string
+func (p *Path) ToString() string {
+	return p.path
+}
+
+// Selector is a selector in the Store, in the form path?predicate(properties)#fragment
+type Selector struct {
+	path       string
+	predicate  string
+	properties string
+	fragment   string
+	toString   string
+}
+
+var selectorRegexp = regexp.MustCompile("([^\\[\\]?#]+)(\\?([^\\[\\]\\(\\)#]+)?(\\((.*)\\))?)?(#(.*))?")
+
+// NewSelector returns a new Selector from the given string, returns an error if it is not a valid selector
+func NewSelector(s string) (*Selector, error) {
+	if len(s) == 0 {
+		return nil, &FError{"Invalid selector (empty string)", nil}
+	}
+	groups := selectorRegexp.FindStringSubmatch(s)
+	if groups == nil {
+		return nil, &FError{"Invalid selector: " + s, nil}
+	}
+
+	sel := Selector{path: groups[1], predicate: groups[3], properties: groups[5], fragment: groups[7]}
+	optional := sel.predicate
+	if len(sel.properties) > 0 {
+		optional = fmt.Sprintf("%s(%s)", optional, sel.properties)
+	}
+	if len(sel.fragment) > 0 {
+		optional = fmt.Sprintf("%s#%s", optional, sel.fragment)
+	}
+	sel.toString = sel.path
+	if len(optional) > 0 {
+		sel.toString = sel.toString + "?" + optional
+	}
+	return &sel, nil
+}
+
+// Path returns the path part of the Selector
+func (s *Selector) Path() string {
+	return s.path
+}
+
+// Predicate returns the predicate part of the Selector
+func (s *Selector) Predicate() string {
+	return s.predicate
+}
+
+// Properties returns the properties part of the Selector
+func (s *Selector) Properties() string {
+	return s.properties
+}
+
+// Fragment returns the fragment part of the Selector
+func (s *Selector) Fragment() string {
+	return s.fragment
+}
+
+// ToString returns the Selector as a string
+func (s *Selector) ToString() string {
+	return s.toString
+}
+
+// callWithContext calls f, returning early if the context is done before f returns. f keeps running in that case,
+// and what it writes after the caller gave up is not rolled back
+func callWithContext(ctx context.Context, f func() error) error {
+	return callWithUndo(ctx, f, nil)
+}
+
+// callWithUndo calls f as callWithContext, when the context is done before f returns and f then succeeds undo reverts it,
+// so that a registration the caller gave up is not left behind
+func callWithUndo(ctx context.Context, f func() error, undo func()) error {
+	if ctx.Err() != nil {
+		return &FError{"Context done", ctx.Err()}
+	}
+	if ctx.Done() == nil {
+		return f()
+	}
+	res := make(chan error, 1)
+	go func() {
+		res <- f()
+	}()
+	select {
+	case err := <-res:
+		return err
+	case <-ctx.Done():
+		if undo != nil {
+			go func() {
+				if <-res == nil {
+					undo()
+				}
+			}()
+		}
+		return &FError{"Context done", ctx.Err()}
+	}
+}
@@ -0,0 +1,73 @@
+/*
+* Copyright (c) 2014,2019 Contributors to the Eclipse Foundation
+* See the NOTICE file(s) distributed with this work for additional
+* information regarding copyright ownership.
+* This program and the accompanying materials are made available under the
+* terms of the Eclipse Public License 2.0 which is available at
+* http://www.eclipse.org/legal/epl-2.0, or the Apache License, Version 2.0
+* which is available at https://www.apache.org/licenses/LICENSE-2.0.
+* SPDX-License-Identifier: EPL-2.0 OR Apache-2.0
+* Contributors: Gabriele Baldoni, ADLINK Technology Inc.
+* golang APIs
+ */
+
+package fog05sdk
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCallWithUndo(t *testing.T) {
+	failure := errors.New("failure")
+	tests := []struct {
+		name    string
+		timeout bool
+		result  error
+		wantErr error
+		undone  bool
+	}{
+		{"completed", false, nil, nil, false},
+		{"failed", false, failure, failure, false},
+		{"completed after the timeout", true, nil, context.Canceled, true},
+		{"failed after the timeout", true, failure, context.Canceled, false},
+	}
+	for _, tt := range tests {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		started := make(chan struct{})
+		release := make(chan struct{})
+		undone := make(chan bool, 1)
+		f := func() error {
+			close(started)
+			<-release
+			return tt.result
+		}
+		undo := func() { undone <- true }
+		go func() {
+			if tt.timeout {
+				// cancelling before f starts would fail the call without running f
+				<-started
+				cancel()
+				time.Sleep(10 * time.Millisecond)
+			}
+			close(release)
+		}()
+		err := callWithUndo(ctx, f, undo)
+		if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+			t.Errorf("%s: callWithUndo() = %v, want %v", tt.name, err, tt.wantErr)
+		}
+		select {
+		case <-undone:
+			if !tt.undone {
+				t.Errorf("%s: undo called", tt.name)
+			}
+		case <-time.After(50 * time.Millisecond):
+			if tt.undone {
+				t.Errorf("%s: undo not called", tt.name)
+			}
+		}
+		cancel()
+	}
+}
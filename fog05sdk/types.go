@@ -13,6 +13,15 @@
 
 package fog05sdk
 
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
 // DefaultSysID constant for Default System ID
 const DefaultSysID = "0"
 
@@ -32,6 +41,11 @@ func (e *FError) Error() string {
 	return e.Msg
 }
 
+// Unwrap returns the cause of the FError
+func (e *FError) Unwrap() error {
+	return e.Cause
+}
+
 // SystemInfo rapresent system information
 type SystemInfo struct {
 	Name string `json:"name"`
@@ -215,6 +229,17 @@ type Plugin struct {
 	Configuration *jsont   `json:"configuration,omitempty"`
 }
 
+// PluginHeartbeat represents the liveness of a plugin, published periodically by the plugin
+type PluginHeartbeat struct {
+	Timestamp int64  `json:"timestamp"`
+	Status    string `json:"status"`
+}
+
+// Time returns the time of the heartbeat, Timestamp is the Unix time in milliseconds
+func (h *PluginHeartbeat) Time() time.Time {
+	return time.Unix(0, h.Timestamp*int64(time.Millisecond))
+}
+
 // InterfaceInfo represents the results of interface managements functions in the network manager plugin
 type InterfaceInfo struct {
 	Name      string `json:"name"`
@@ -238,3 +263,209 @@ type EvalResult struct {
 	Error        *int    `json:"error,omitempty"`
 	ErrorMessage *string `json:"error_msg,omitempty"`
 }
+
+// Err returns the error replied by the eval as an EvalError, nil if the eval did not fail
+func (r *EvalResult) Err() error {
+	return newEvalError(r.Error, r.ErrorMessage)
+}
+
+// Error codes replied by the evals of the plugins
+const (
+	// EvalErrorInvalidParameters is replied when the parameters of an eval are missing or cannot be decoded, it is EINVAL
+	EvalErrorInvalidParameters int = 22
+
+	// EvalErrorFailed is replied when an eval fails with an error that is not an EvalError, it is EIO
+	EvalErrorFailed int = 5
+)
+
+// NewEvalResult returns the reply of a successful eval with v encoded as JSON result, strings are replied as they are
+func NewEvalResult(v interface{}) RawEvalResult {
+	r, err := json.Marshal(v)
+	if err != nil {
+		return NewEvalErrorResult(&FError{"Unable to encode the result", err})
+	}
+	return RawEvalResult{Result: r}
+}
+
+// NewEvalErrorResult returns the reply of a failed eval, the code is the one of the EvalError in err, or EvalErrorFailed
+func NewEvalErrorResult(err error) RawEvalResult {
+	code := EvalErrorFailed
+	msg := err.Error()
+	var eerr *EvalError
+	if errors.As(err, &eerr) {
+		code = eerr.Code
+		if err == error(eerr) {
+			msg = eerr.Message
+		}
+	}
+	return RawEvalResult{Error: &code, ErrorMessage: &msg}
+}
+
+// evalArgs decodes the parameters of an eval, the first missing or invalid parameter is kept in err
+type evalArgs struct {
+	props Properties
+	err   error
+}
+
+// string returns the required parameter
+func (a *evalArgs) string(name string) string {
+	v, found := a.props[name]
+	if !found && a.err == nil {
+		a.err = &EvalError{Code: EvalErrorInvalidParameters, Message: "Missing parameter " + name}
+	}
+	return v
+}
+
+// optional returns the parameter, or def if it is missing or empty
+func (a *evalArgs) optional(name string, def string) string {
+	if v := a.props[name]; v != "" {
+		return v
+	}
+	return def
+}
+
+// bool returns the required boolean parameter
+func (a *evalArgs) bool(name string) bool {
+	s := a.string(name)
+	if a.err != nil {
+		return false
+	}
+	b, err := strconv.ParseBool(s)
+	if err != nil {
+		a.err = &EvalError{Code: EvalErrorInvalidParameters, Message: "Invalid parameter " + name + ": " + s + " is not a boolean"}
+	}
+	return b
+}
+
+// int returns the required integer parameter
+func (a *evalArgs) int(name string) int {
+	s := a.string(name)
+	if a.err != nil {
+		return 0
+	}
+	i, err := strconv.Atoi(s)
+	if err != nil {
+		a.err = &EvalError{Code: EvalErrorInvalidParameters, Message: "Invalid parameter " + name + ": " + s + " is not an integer"}
+	}
+	return i
+}
+
+// json decodes the required JSON parameter in v
+func (a *evalArgs) json(name string, v interface{}) {
+	s := a.string(name)
+	if a.err != nil {
+		return
+	}
+	if err := json.Unmarshal([]byte(s), v); err != nil {
+		a.err = &EvalError{Code: EvalErrorInvalidParameters, Message: "Invalid parameter " + name + ": " + err.Error()}
+	}
+}
+
+// strings returns the required list parameter, either a JSON array or a list formatted as [a b c]
+func (a *evalArgs) strings(name string) []string {
+	s := a.string(name)
+	if a.err != nil {
+		return nil
+	}
+	l := []string{}
+	if json.Unmarshal([]byte(s), &l) == nil {
+		return l
+	}
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, "[") || !strings.HasSuffix(s, "]") {
+		a.err = &EvalError{Code: EvalErrorInvalidParameters, Message: "Invalid parameter " + name + ": " + s + " is not a list"}
+		return nil
+	}
+	return strings.Fields(s[1 : len(s)-1])
+}
+
+// reply calls fn if all the parameters were decoded, and returns its result as eval reply
+func (a *evalArgs) reply(fn func() (interface{}, error)) RawEvalResult {
+	if a.err != nil {
+		return NewEvalErrorResult(a.err)
+	}
+	v, err := fn()
+	if err != nil {
+		return NewEvalErrorResult(err)
+	}
+	return NewEvalResult(v)
+}
+
+// evalHandler returns the eval callback decoding the parameters with handler and replying the result of the call it returns
+func evalHandler(handler func(*evalArgs) func() (interface{}, error)) func(Properties) interface{} {
+	return func(props Properties) interface{} {
+		args := evalArgs{props: props}
+		return args.reply(handler(&args))
+	}
+}
+
+// evalBool is a bool eval result, plugins reply either a JSON bool or its string form, eg. "true" or "True"
+type evalBool bool
+
+func (b *evalBool) UnmarshalJSON(data []byte) error {
+	var v interface{}
+	err := json.Unmarshal(data, &v)
+	if err != nil {
+		return err
+	}
+	switch x := v.(type) {
+	case nil:
+	case bool:
+		*b = evalBool(x)
+	case string:
+		pb, err := strconv.ParseBool(x)
+		if err != nil {
+			return err
+		}
+		*b = evalBool(pb)
+	default:
+		return &FError{"Not a bool: " + string(data), nil}
+	}
+	return nil
+}
+
+// RawEvalResult represents results of Eval, with the result kept as JSON so that it can be decoded in any Go type
+type RawEvalResult struct {
+	Result       json.RawMessage `json:"result,omitempty"`
+	Error        *int            `json:"error,omitempty"`
+	ErrorMessage *string         `json:"error_msg,omitempty"`
+}
+
+// Decode decodes the result in v, as json.Unmarshal does, a missing result leaves v untouched
+func (r *RawEvalResult) Decode(v interface{}) error {
+	if len(r.Result) == 0 {
+		return nil
+	}
+	return json.Unmarshal(r.Result, v)
+}
+
+// hasResult returns true if the eval replied a result other than null
+func (r *RawEvalResult) hasResult() bool {
+	return len(r.Result) > 0 && string(r.Result) != "null"
+}
+
+// Err returns the error replied by the eval as an EvalError, nil if the eval did not fail
+func (r *RawEvalResult) Err() error {
+	return newEvalError(r.Error, r.ErrorMessage)
+}
+
+// EvalResult converts the result to an EvalResult, string results are kept as they are while any other result is kept as JSON
+func (r *RawEvalResult) EvalResult() (*EvalResult, error) {
+	ev := EvalResult{Error: r.Error, ErrorMessage: r.ErrorMessage}
+	if len(r.Result) == 0 || string(r.Result) == "null" {
+		return &ev, nil
+	}
+	var s string
+	if json.Unmarshal(r.Result, &s) == nil {
+		ev.Result = &s
+		return &ev, nil
+	}
+	var buf bytes.Buffer
+	err := json.Compact(&buf, r.Result)
+	if err != nil {
+		return nil, err
+	}
+	s = buf.String()
+	ev.Result = &s
+	return &ev, nil
+}
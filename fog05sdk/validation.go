@@ -0,0 +1,97 @@
+/*
+* Copyright (c) 2014,2019 Contributors to the Eclipse Foundation
+* See the NOTICE file(s) distributed with this work for additional
+* information regarding copyright ownership.
+* This program and the accompanying materials are made available under the
+* terms of the Eclipse Public License 2.0 which is available at
+* http://www.eclipse.org/legal/epl-2.0, or the Apache License, Version 2.0
+* which is available at https://www.apache.org/licenses/LICENSE-2.0.
+* SPDX-License-Identifier: EPL-2.0 OR Apache-2.0
+* Contributors: Gabriele Baldoni, ADLINK Technology Inc.
+* golang APIs
+ */
+
+package fog05sdk
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FieldError is the error of a single descriptor field, Field is the path of the field using the JSON names, eg. interfaces[0].cp_id
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+func (e FieldError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// ValidationError is returned when a descriptor is not valid, it lists the errors of all the invalid fields
+type ValidationError struct {
+	Errors []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, fe := range e.Errors {
+		msgs[i] = fe.Error()
+	}
+	return "Invalid descriptor: " + strings.Join(msgs, "; ")
+}
+
+// validator collects the FieldErrors found while validating a descriptor
+type validator struct {
+	errs []FieldError
+}
+
+func (v *validator) add(field string, format string, args ...interface{}) {
+	v.errs = append(v.errs, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) required(field string, value string) {
+	if value == "" {
+		v.add(field, "is required")
+	}
+}
+
+func (v *validator) oneOf(field string, value string, allowed ...string) {
+	for _, a := range allowed {
+		if value == a {
+			return
+		}
+	}
+	v.add(field, "%q is not one of %s", value, strings.Join(allowed, ", "))
+}
+
+func (v *validator) atLeast(field string, value float64, min float64) {
+	if value < min {
+		v.add(field, "%v is less than %v", value, min)
+	}
+}
+
+func (v *validator) between(field string, value float64, min float64, max float64) {
+	if value < min || value > max {
+		v.add(field, "%v is not between %v and %v", value, min, max)
+	}
+}
+
+// unique checks that value was not already seen, adding it to seen
+func (v *validator) unique(field string, value string, seen map[string]bool) {
+	if value == "" {
+		return
+	}
+	if seen[value] {
+		v.add(field, "%q is duplicated", value)
+	}
+	seen[value] = true
+}
+
+// err returns nil if no errors were collected, a ValidationError otherwise
+func (v *validator) err() error {
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return &ValidationError{v.errs}
+}
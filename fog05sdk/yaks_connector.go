@@ -14,12 +14,12 @@
 package fog05sdk
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"reflect"
 	"strings"
-
-	"github.com/atolab/yaks-go"
+	"sync"
 
 	log "github.com/sirupsen/logrus"
 )
@@ -50,25 +50,45 @@ const LocalConstraintDesiredPrefix string = "/dclfos"
 const URISeparator string = "/"
 
 // CreatePath ...
-func CreatePath(tokens []string) *yaks.Path {
-	p, err := yaks.NewPath(strings.Join(tokens[:], URISeparator))
-	if err != nil {
-		panic(err.Error())
-	}
-	return p
+func CreatePath(tokens []string) (*Path, error) {
+	return NewPath(strings.Join(tokens[:], URISeparator))
 }
 
 // CreateSelector ...
-func CreateSelector(tokens []string) *yaks.Selector {
-	s, err := yaks.NewSelector(strings.Join(tokens[:], URISeparator))
+func CreateSelector(tokens []string) (*Selector, error) {
+	return NewSelector(strings.Join(tokens[:], URISeparator))
+}
+
+// toSelector returns a Selector matching exactly the given Path, it takes the results of the Path builders
+func toSelector(p *Path, err error) (*Selector, error) {
 	if err != nil {
-		panic(err.Error())
+		return nil, err
 	}
-	return s
+	return NewSelector(p.ToString())
+}
+
+// pathElement returns the element at the given index of the Path, or an error if the Path is too short
+func pathElement(path *Path, index int) (string, error) {
+	elems := strings.Split(path.ToString(), URISeparator)
+	if index >= len(elems) {
+		return "", &FError{"Invalid path " + path.ToString(), nil}
+	}
+	return elems[index], nil
 }
 
 // Dict2Args ...
 func Dict2Args(d map[string]interface{}) string {
+	// the map values that cannot be encoded in JSON are written as they are printed, MarshalArgs reports them
+	s, _ := dict2Args(d, false)
+	return s
+}
+
+// MarshalArgs is Dict2Args returning an error if a map value cannot be encoded in JSON
+func MarshalArgs(d map[string]interface{}) (string, error) {
+	return dict2Args(d, true)
+}
+
+func dict2Args(d map[string]interface{}, strict bool) (string, error) {
 
 	var s strings.Builder
 	var i int = 0
@@ -77,13 +97,17 @@ func Dict2Args(d map[string]interface{}) string {
 		_, ok := v.(map[string]interface{})
 		if ok {
 			jv, err := json.Marshal(v)
+			if err != nil && strict {
+				return "", err
+			}
+			var a interface{} = string(jv)
 			if err != nil {
-				panic(err)
+				a = v
 			}
 			if i == 0 {
-				s.WriteString(fmt.Sprintf("%s=%v", k, string(jv)))
+				s.WriteString(fmt.Sprintf("%s=%v", k, a))
 			} else {
-				s.WriteString(fmt.Sprintf(";%s=%v", k, string(jv)))
+				s.WriteString(fmt.Sprintf(";%s=%v", k, a))
 			}
 		} else {
 			if i == 0 {
@@ -96,413 +120,613 @@ func Dict2Args(d map[string]interface{}) string {
 		i++
 	}
 
-	return fmt.Sprintf("(%s)", s.String())
+	return fmt.Sprintf("(%s)", s.String()), nil
 }
 
-// GAD is Global Actual Desired
-type GAD struct {
-	ws        *yaks.Workspace
-	prefix    string
-	listeners []*yaks.SubscriptionID
-	evals     []*yaks.Path
+// handlers keeps track of the listeners and evals registered by a GAD or LAD, it is shared by the copies returned by WithContext.
+// The listeners and evals registered with a context that can be done are removed when it is done, stops ends the wait of the
+// ones removed before
+type handlers struct {
+	logger    log.FieldLogger
+	mu        sync.Mutex
+	listeners []*SubscriptionID
+	evals     []*Path
+	stops     map[interface{}]chan struct{}
 }
 
-// Unsubscribe ...
-func (gad *GAD) Unsubscribe(sid *yaks.SubscriptionID) error {
-	err := gad.ws.Unsubscribe(sid)
+func newHandlers(l log.FieldLogger) *handlers {
+	return &handlers{logger: l, listeners: []*SubscriptionID{}, evals: []*Path{}, stops: map[interface{}]chan struct{}{}}
+}
+
+// removeWhenDone calls remove when the context is done, unless stop is called before, it must be called with the lock held
+func (h *handlers) removeWhenDone(ctx context.Context, key interface{}, remove func()) {
+	if ctx.Done() == nil {
+		return
+	}
+	h.stop(key)
+	stop := make(chan struct{})
+	h.stops[key] = stop
+	go func() {
+		select {
+		case <-ctx.Done():
+			// stop may have been called too, when the wait is no longer current the removal already happened
+			h.mu.Lock()
+			current := h.stops[key] == stop
+			if current {
+				delete(h.stops, key)
+			}
+			h.mu.Unlock()
+			if current {
+				remove()
+			}
+		case <-stop:
+		}
+	}()
+}
+
+// stop ends the wait started by removeWhenDone for the key, it must be called with the lock held
+func (h *handlers) stop(key interface{}) {
+	if stop, found := h.stops[key]; found {
+		close(stop)
+		delete(h.stops, key)
+	}
+}
+
+func (h *handlers) subscribe(ctx context.Context, ws Store, s *Selector, cb func([]Change)) (*SubscriptionID, error) {
+	listener := func(changes []Change) {
+		if ctx.Err() == nil {
+			cb(changes)
+		}
+	}
+	sid, err := ws.Subscribe(ctx, s, listener)
+	if err != nil {
+		return nil, err
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.listeners = append(h.listeners, sid)
+	h.removeWhenDone(ctx, sid, func() {
+		h.unsubscribe(context.Background(), ws, sid)
+	})
+	return sid, nil
+}
+
+func (h *handlers) unsubscribe(ctx context.Context, ws Store, sid *SubscriptionID) error {
+	err := ws.Unsubscribe(ctx, sid)
 	if err != nil {
 		return err
 	}
 
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.stop(sid)
 	p := -1
-
-	for i, e := range gad.listeners {
+	for i, e := range h.listeners {
 		if e == sid {
 			p = i
 		}
 	}
 	if p == -1 {
-		return &FError{"Subscriber not found!!", nil}
+		return &FError{"Subscriber not found!!", ErrNotFound}
 	}
-	gad.listeners = append(gad.listeners[:p], gad.listeners[p+1:]...)
+	h.listeners = append(h.listeners[:p], h.listeners[p+1:]...)
 	return nil
 }
 
-// RemoveEval ...
-func (gad *GAD) RemoveEval(sid *yaks.Path) error {
-	err := gad.ws.UnregisterEval(sid)
+func (h *handlers) registerEval(ctx context.Context, ws Store, s *Path, cb EvalHandler) error {
+	err := ws.RegisterEval(ctx, s, cb)
 	if err != nil {
 		return err
 	}
-	p := -1
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.evals = append(h.evals, s)
+	h.removeWhenDone(ctx, s.ToString(), func() {
+		h.removeEval(context.Background(), ws, s)
+	})
+	return nil
+}
 
-	for i, e := range gad.evals {
-		if e == sid {
+func (h *handlers) removeEval(ctx context.Context, ws Store, s *Path) error {
+	err := ws.UnregisterEval(ctx, s)
+	if err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.stop(s.ToString())
+	p := -1
+	for i, e := range h.evals {
+		if e.ToString() == s.ToString() {
 			p = i
 		}
 	}
 	if p == -1 {
-		return &FError{"Eval not found!!", nil}
+		return &FError{"Eval not found!!", ErrNotFound}
 	}
-	gad.evals = append(gad.evals[:p], gad.evals[p+1:]...)
+	h.evals = append(h.evals[:p], h.evals[p+1:]...)
 	return nil
+}
+
+// close unsubscribes all the listeners and removes all the evals, also the ones registered by other copies, collecting the errors
+func (h *handlers) close(ctx context.Context, ws Store) error {
+	h.mu.Lock()
+	listeners := h.listeners
+	evals := h.evals
+	h.listeners = []*SubscriptionID{}
+	h.evals = []*Path{}
+	for key := range h.stops {
+		h.stop(key)
+	}
+	h.mu.Unlock()
+
+	var errs []error
+	for _, sid := range listeners {
+		errs = appendError(errs, ws.Unsubscribe(ctx, sid))
+	}
+	for _, p := range evals {
+		errs = appendError(errs, ws.UnregisterEval(ctx, p))
+	}
+	return multiError(errs)
+}
+
+// execEval evaluates the eval matching the selector, if it did not fail its result is decoded in result, when not nil.
+// If the eval failed the result is returned together with an EvalError
+func execEval(ctx context.Context, ws Store, s *Selector, name string, result interface{}) (*RawEvalResult, error) {
+	kvs, err := ws.Get(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+	if len(kvs) == 0 {
+		return nil, &FError{name + " function replied nil", ErrNotFound}
+	}
+
+	r := RawEvalResult{}
+	err = decode(kvs[0].Value, &r)
+	if err != nil {
+		return nil, err
+	}
+	if r.Error != nil {
+		return &r, r.Err()
+	}
+	if result != nil {
+		err = r.Decode(result)
+		if err != nil {
+			return nil, &DecodeError{err}
+		}
+	}
+	return &r, nil
+}
+
+// GAD is Global Actual Desired
+type GAD struct {
+	ws     Store
+	prefix string
+	ctx    context.Context
+	errh   func(error)
+	*handlers
+}
+
+// WithErrorHandler returns a copy of the GAD whose listeners deliver to the handler the changes that cannot be decoded, by default they are logged and dropped
+func (gad *GAD) WithErrorHandler(handler func(error)) *GAD {
+	c := *gad
+	c.errh = handler
+	return &c
+}
+
+func (gad *GAD) handleError(err error) {
+	if gad.errh != nil {
+		gad.errh(err)
+		return
+	}
+	gad.logger.WithError(err).Error("Unable to decode change")
+}
+
+// WithContext returns a copy of the GAD whose operations use the given context, listeners and evals registered through it are removed when the context is done
+func (gad *GAD) WithContext(ctx context.Context) *GAD {
+	c := *gad
+	c.ctx = ctx
+	return &c
+}
+
+func (gad *GAD) context() context.Context {
+	if gad.ctx == nil {
+		return context.Background()
+	}
+	return gad.ctx
+}
+
+// Eval evaluates the eval matching the selector and decodes its result in result, that can be a pointer to any type accepted by json.Unmarshal.
+// If the eval failed the result is not decoded and the RawEvalResult is returned together with an EvalError
+func (gad *GAD) Eval(s *Selector, result interface{}) (*RawEvalResult, error) {
+	return execEval(gad.context(), gad.ws, s, "Eval", result)
+}
+
+func (gad *GAD) evalResult(s *Selector, name string) (*EvalResult, error) {
+	r, err := execEval(gad.context(), gad.ws, s, name, nil)
+	if r == nil {
+		return nil, err
+	}
+	ev, cerr := r.EvalResult()
+	if cerr != nil {
+		return nil, &DecodeError{cerr}
+	}
+	return ev, err
+}
+
+// Unsubscribe ...
+func (gad *GAD) Unsubscribe(sid *SubscriptionID) error {
+	return gad.unsubscribe(gad.context(), gad.ws, sid)
+}
+
+// RemoveEval ...
+func (gad *GAD) RemoveEval(sid *Path) error {
+	return gad.removeEval(gad.context(), gad.ws, sid)
+}
 
+// Close unsubscribes all the listeners and removes all the evals registered through the GAD and its copies
+func (gad *GAD) Close() error {
+	return gad.close(context.Background(), gad.ws)
 }
 
 // GetSysInfoPath ...
-func (gad *GAD) GetSysInfoPath(sysid string) *yaks.Path {
+func (gad *GAD) GetSysInfoPath(sysid string) (*Path, error) {
 	return CreatePath([]string{gad.prefix, sysid, "info"})
 }
 
 // GetSysConfigurationPath ...
-func (gad *GAD) GetSysConfigurationPath(sysid string) *yaks.Path {
+func (gad *GAD) GetSysConfigurationPath(sysid string) (*Path, error) {
 	return CreatePath([]string{gad.prefix, sysid, "configuration"})
 }
 
 // System
 
 // GetAllUsersSelector ...
-func (gad *GAD) GetAllUsersSelector(sysid string) *yaks.Selector {
+func (gad *GAD) GetAllUsersSelector(sysid string) (*Selector, error) {
 	return CreateSelector([]string{gad.prefix, sysid, "users", "*"})
 }
 
 // GetUserInfoPath ...
-func (gad *GAD) GetUserInfoPath(sysid string, userid string) *yaks.Path {
+func (gad *GAD) GetUserInfoPath(sysid string, userid string) (*Path, error) {
 	return CreatePath([]string{gad.prefix, sysid, "users", userid, "info"})
 }
 
 // Tenants
 
 // GetAllTenantsSelector ...
-func (gad *GAD) GetAllTenantsSelector(sysid string) *yaks.Selector {
+func (gad *GAD) GetAllTenantsSelector(sysid string) (*Selector, error) {
 	return CreateSelector([]string{gad.prefix, sysid, "tenants", "*"})
 }
 
 // GetTenantInfoPath ...
-func (gad *GAD) GetTenantInfoPath(sysid string, tenantid string) *yaks.Path {
+func (gad *GAD) GetTenantInfoPath(sysid string, tenantid string) (*Path, error) {
 	return CreatePath([]string{gad.prefix, sysid, "tenants", tenantid, "info"})
 }
 
 // GetTenantConfigurationPath ...
-func (gad *GAD) GetTenantConfigurationPath(sysid string, tenantid string) *yaks.Path {
+func (gad *GAD) GetTenantConfigurationPath(sysid string, tenantid string) (*Path, error) {
 	return CreatePath([]string{gad.prefix, sysid, "tenants", tenantid, "configuration"})
 }
 
 // Catalog
 
 // GetCatalogAtomicEntityInfoPath ...
-func (gad *GAD) GetCatalogAtomicEntityInfoPath(sysid string, tenantid string, aeid string) *yaks.Path {
+func (gad *GAD) GetCatalogAtomicEntityInfoPath(sysid string, tenantid string, aeid string) (*Path, error) {
 	return CreatePath([]string{gad.prefix, sysid, "tenants", tenantid, "catalog", "atomic-entities", aeid, "info"})
 }
 
 // GetCatalogAllAtomicEntitiesSelector ...
-func (gad *GAD) GetCatalogAllAtomicEntitiesSelector(sysid string, tenantid string) *yaks.Selector {
+func (gad *GAD) GetCatalogAllAtomicEntitiesSelector(sysid string, tenantid string) (*Selector, error) {
 	return CreateSelector([]string{gad.prefix, sysid, "tenants", tenantid, "catalog", "atomic-entities", "*", "info"})
 }
 
 // GetCatalogFDUInfoPath ...
-func (gad *GAD) GetCatalogFDUInfoPath(sysid string, tenantid string, fduid string) *yaks.Path {
+func (gad *GAD) GetCatalogFDUInfoPath(sysid string, tenantid string, fduid string) (*Path, error) {
 	return CreatePath([]string{gad.prefix, sysid, "tenants", tenantid, "catalog", "fdu", fduid, "info"})
 }
 
 // GetCatalogAllFDUSelector ...
-func (gad *GAD) GetCatalogAllFDUSelector(sysid string, tenantid string) *yaks.Selector {
+func (gad *GAD) GetCatalogAllFDUSelector(sysid string, tenantid string) (*Selector, error) {
 	return CreateSelector([]string{gad.prefix, sysid, "tenants", tenantid, "catalog", "fdu", "*", "info"})
 }
 
 // GetCatalogEntityInfoPath ...
-func (gad *GAD) GetCatalogEntityInfoPath(sysid string, tenantid string, eid string) *yaks.Path {
+func (gad *GAD) GetCatalogEntityInfoPath(sysid string, tenantid string, eid string) (*Path, error) {
 	return CreatePath([]string{gad.prefix, sysid, "tenants", tenantid, "catalog", "entities", eid, "info"})
 }
 
 // GetCatalogAllEntitiesSelector ...
-func (gad *GAD) GetCatalogAllEntitiesSelector(sysid string, tenantid string) *yaks.Selector {
+func (gad *GAD) GetCatalogAllEntitiesSelector(sysid string, tenantid string) (*Selector, error) {
 	return CreateSelector([]string{gad.prefix, sysid, "tenants", tenantid, "catalog", "entities", "*", "info"})
 }
 
 // Records
 
 // GetRecordsAtomicEntityInstanceInfoPath ...
-func (gad *GAD) GetRecordsAtomicEntityInstanceInfoPath(sysid string, tenantid string, aeid string, instanceid string) *yaks.Path {
+func (gad *GAD) GetRecordsAtomicEntityInstanceInfoPath(sysid string, tenantid string, aeid string, instanceid string) (*Path, error) {
 	return CreatePath([]string{gad.prefix, sysid, "tenants", tenantid, "records", "atomic-entities", aeid, "instances", instanceid, "info"})
 }
 
 // GetRecordsAllAtomicEntityInstancesSelector ...
-func (gad *GAD) GetRecordsAllAtomicEntityInstancesSelector(sysid string, tenantid string, aeid string) *yaks.Selector {
+func (gad *GAD) GetRecordsAllAtomicEntityInstancesSelector(sysid string, tenantid string, aeid string) (*Selector, error) {
 	return CreateSelector([]string{gad.prefix, sysid, "tenants", tenantid, "records", "atomic-entities", aeid, "instances", "*", "info"})
 }
 
 // GetRecordsAllAtomicEntitiesInstancesSelector ...
-func (gad *GAD) GetRecordsAllAtomicEntitiesInstancesSelector(sysid string, tenantid string) *yaks.Selector {
+func (gad *GAD) GetRecordsAllAtomicEntitiesInstancesSelector(sysid string, tenantid string) (*Selector, error) {
 	return CreateSelector([]string{gad.prefix, sysid, "tenants", tenantid, "records", "atomic-entities", "*", "instances", "*", "info"})
 }
 
 // GetRecordsEntityInstanceInfoPath ...
-func (gad *GAD) GetRecordsEntityInstanceInfoPath(sysid string, tenantid string, eid string, instanceid string) *yaks.Path {
+func (gad *GAD) GetRecordsEntityInstanceInfoPath(sysid string, tenantid string, eid string, instanceid string) (*Path, error) {
 	return CreatePath([]string{gad.prefix, sysid, "tenants", tenantid, "records", "entities", eid, "instances", instanceid, "info"})
 }
 
 // GetRecordsAllEntityInstancesSelector ..
-func (gad *GAD) GetRecordsAllEntityInstancesSelector(sysid string, tenantid string, eid string) *yaks.Selector {
+func (gad *GAD) GetRecordsAllEntityInstancesSelector(sysid string, tenantid string, eid string) (*Selector, error) {
 	return CreateSelector([]string{gad.prefix, sysid, "tenants", tenantid, "records", "entities", eid, "instances", "*", "info"})
 }
 
 // GetRecordsAllEntitiesInstancesSelector ...
-func (gad *GAD) GetRecordsAllEntitiesInstancesSelector(sysid string, tenantid string) *yaks.Selector {
+func (gad *GAD) GetRecordsAllEntitiesInstancesSelector(sysid string, tenantid string) (*Selector, error) {
 	return CreateSelector([]string{gad.prefix, sysid, "tenants", tenantid, "records", "entities", "*", "instances", "*", "info"})
 }
 
 // Nodes
 
 // GetAllNodesSelector ...
-func (gad *GAD) GetAllNodesSelector(sysid string, tenantid string) *yaks.Selector {
+func (gad *GAD) GetAllNodesSelector(sysid string, tenantid string) (*Selector, error) {
 	return CreateSelector([]string{gad.prefix, sysid, "tenants", tenantid, "nodes", "*", "info"})
 }
 
 // GetNodeInfoPath ...
-func (gad *GAD) GetNodeInfoPath(sysid string, tenantid string, nodeid string) *yaks.Path {
+func (gad *GAD) GetNodeInfoPath(sysid string, tenantid string, nodeid string) (*Path, error) {
 	return CreatePath([]string{gad.prefix, sysid, "tenants", tenantid, "nodes", nodeid, "info"})
 }
 
 // GetNodeConfigurationPath ...
-func (gad *GAD) GetNodeConfigurationPath(sysid string, tenantid string, nodeid string) *yaks.Path {
+func (gad *GAD) GetNodeConfigurationPath(sysid string, tenantid string, nodeid string) (*Path, error) {
 	return CreatePath([]string{gad.prefix, sysid, "tenants", tenantid, "nodes", nodeid, "configuration"})
 }
 
 // GetNodeStatusPath ...
-func (gad *GAD) GetNodeStatusPath(sysid string, tenantid string, nodeid string) *yaks.Path {
+func (gad *GAD) GetNodeStatusPath(sysid string, tenantid string, nodeid string) (*Path, error) {
 	return CreatePath([]string{gad.prefix, sysid, "tenants", tenantid, "nodes", nodeid, "status"})
 }
 
 // GetNodePluginsSelector ...
-func (gad *GAD) GetNodePluginsSelector(sysid string, tenantid string, nodeid string) *yaks.Selector {
+func (gad *GAD) GetNodePluginsSelector(sysid string, tenantid string, nodeid string) (*Selector, error) {
 	return CreateSelector([]string{gad.prefix, sysid, "tenants", tenantid, "nodes", nodeid, "plugins", "**"})
 }
 
 // GetNodePluginInfoPath ...
-func (gad *GAD) GetNodePluginInfoPath(sysid string, tenantid string, nodeid string, plugind string) *yaks.Path {
+func (gad *GAD) GetNodePluginInfoPath(sysid string, tenantid string, nodeid string, plugind string) (*Path, error) {
 	return CreatePath([]string{gad.prefix, sysid, "tenants", tenantid, "nodes", nodeid, "plugins", plugind, "info"})
 }
 
 // GetNodePluginEvalPath ...
-func (gad *GAD) GetNodePluginEvalPath(sysid string, tenantid string, nodeid string, plugind string, funcname string) *yaks.Path {
+func (gad *GAD) GetNodePluginEvalPath(sysid string, tenantid string, nodeid string, plugind string, funcname string) (*Path, error) {
 	return CreatePath([]string{gad.prefix, sysid, "tenants", tenantid, "nodes", nodeid, "plugins", plugind, "exec", funcname})
 }
 
 // Node FDU or FDU Records
 
 // GetNodeFDUInfoPath ...
-func (gad *GAD) GetNodeFDUInfoPath(sysid string, tenantid string, nodeid string, fduid string, instanceid string) *yaks.Path {
+func (gad *GAD) GetNodeFDUInfoPath(sysid string, tenantid string, nodeid string, fduid string, instanceid string) (*Path, error) {
 	return CreatePath([]string{gad.prefix, sysid, "tenants", tenantid, "nodes", nodeid, "fdu", fduid, "instances", instanceid, "info"})
 }
 
 // GetNodeFDUSelector ...
-func (gad *GAD) GetNodeFDUSelector(sysid string, tenantid string, nodeid string) *yaks.Selector {
+func (gad *GAD) GetNodeFDUSelector(sysid string, tenantid string, nodeid string) (*Selector, error) {
 	return CreateSelector([]string{gad.prefix, sysid, "tenants", tenantid, "nodes", nodeid, "fdu", "*", "instances", "*", "info"})
 }
 
 // GetNodeFDUInstancesSelector ...
-func (gad *GAD) GetNodeFDUInstancesSelector(sysid string, tenantid string, nodeid string, fduid string) *yaks.Selector {
+func (gad *GAD) GetNodeFDUInstancesSelector(sysid string, tenantid string, nodeid string, fduid string) (*Selector, error) {
 	return CreateSelector([]string{gad.prefix, sysid, "tenants", tenantid, "nodes", nodeid, "fdu", fduid, "instances", "*", "info"})
 }
 
 // GetNodeFDUInstanceSelector ...
-func (gad *GAD) GetNodeFDUInstanceSelector(sysid string, tenantid string, nodeid string, instanceid string) *yaks.Selector {
+func (gad *GAD) GetNodeFDUInstanceSelector(sysid string, tenantid string, nodeid string, instanceid string) (*Selector, error) {
 	return CreateSelector([]string{gad.prefix, sysid, "tenants", tenantid, "nodes", nodeid, "fdu", "*", "instances", instanceid, "info"})
 }
 
 // GetFDUInstanceSelector ...
-func (gad *GAD) GetFDUInstanceSelector(sysid string, tenantid string, instanceid string) *yaks.Selector {
+func (gad *GAD) GetFDUInstanceSelector(sysid string, tenantid string, instanceid string) (*Selector, error) {
 	return CreateSelector([]string{gad.prefix, sysid, "tenants", tenantid, "nodes", "*", "fdu", "*", "instances", instanceid, "info"})
 }
 
 // GetFDUStartEvalSelector ...
-func (gad *GAD) GetFDUStartEvalSelector(sysid string, tenantid string, instanceid string, env string) *yaks.Selector {
+func (gad *GAD) GetFDUStartEvalSelector(sysid string, tenantid string, instanceid string, env string) (*Selector, error) {
 	e := fmt.Sprintf("?(env=%s)", env)
 	return CreateSelector([]string{gad.prefix, sysid, "tenants", tenantid, "nodes", "*", "fdu", "*", "instances", instanceid, "start", e})
 }
 
 // GetFDURunEvalSelector ...
-func (gad *GAD) GetFDURunEvalSelector(sysid string, tenantid string, instanceid string, env string) *yaks.Selector {
+func (gad *GAD) GetFDURunEvalSelector(sysid string, tenantid string, instanceid string, env string) (*Selector, error) {
 	e := fmt.Sprintf("?(env=%s)", env)
 	return CreateSelector([]string{gad.prefix, sysid, "tenants", tenantid, "nodes", "*", "fdu", "*", "instances", instanceid, "run", e})
 }
 
 // GetFDULogEvalSelector ...
-func (gad *GAD) GetFDULogEvalSelector(sysid string, tenantid string, instanceid string) *yaks.Selector {
+func (gad *GAD) GetFDULogEvalSelector(sysid string, tenantid string, instanceid string) (*Selector, error) {
 	return CreateSelector([]string{gad.prefix, sysid, "tenants", tenantid, "nodes", "*", "fdu", "*", "instances", instanceid, "log"})
 }
 
 // GetFDULsEvalSelector ...
-func (gad *GAD) GetFDULsEvalSelector(sysid string, tenantid string, instanceid string) *yaks.Selector {
+func (gad *GAD) GetFDULsEvalSelector(sysid string, tenantid string, instanceid string) (*Selector, error) {
 	return CreateSelector([]string{gad.prefix, sysid, "tenants", tenantid, "nodes", "*", "fdu", "*", "instances", instanceid, "ls"})
 }
 
 // GetFDUFileEvalSelector ...
-func (gad *GAD) GetFDUFileEvalSelector(sysid string, tenantid string, instanceid string, filename string) *yaks.Selector {
+func (gad *GAD) GetFDUFileEvalSelector(sysid string, tenantid string, instanceid string, filename string) (*Selector, error) {
 	f := fmt.Sprintf("?(filename=%s)", filename)
 	return CreateSelector([]string{gad.prefix, sysid, "tenants", tenantid, "nodes", "*", "fdu", "*", "instances", instanceid, "get", f})
 }
 
 // GetFDUStartEvalPath ...
-func (gad *GAD) GetFDUStartEvalPath(sysid string, tenantid string, nodeid string, fduid string, instanceid string) *yaks.Path {
+func (gad *GAD) GetFDUStartEvalPath(sysid string, tenantid string, nodeid string, fduid string, instanceid string) (*Path, error) {
 	return CreatePath([]string{gad.prefix, sysid, "tenants", tenantid, "nodes", nodeid, "fdu", fduid, "instances", instanceid, "start"})
 }
 
 // GetFDURunEvalPath ...
-func (gad *GAD) GetFDURunEvalPath(sysid string, tenantid string, nodeid string, fduid string, instanceid string) *yaks.Path {
+func (gad *GAD) GetFDURunEvalPath(sysid string, tenantid string, nodeid string, fduid string, instanceid string) (*Path, error) {
 	return CreatePath([]string{gad.prefix, sysid, "tenants", tenantid, "nodes", nodeid, "fdu", fduid, "instances", instanceid, "run"})
 }
 
 // GetFDULogEvalPath ...
-func (gad *GAD) GetFDULogEvalPath(sysid string, tenantid string, nodeid string, fduid string, instanceid string) *yaks.Path {
+func (gad *GAD) GetFDULogEvalPath(sysid string, tenantid string, nodeid string, fduid string, instanceid string) (*Path, error) {
 	return CreatePath([]string{gad.prefix, sysid, "tenants", tenantid, "nodes", nodeid, "fdu", fduid, "instances", instanceid, "log"})
 }
 
 // GetFDULsEvalPath ...
-func (gad *GAD) GetFDULsEvalPath(sysid string, tenantid string, nodeid string, fduid string, instanceid string) *yaks.Path {
+func (gad *GAD) GetFDULsEvalPath(sysid string, tenantid string, nodeid string, fduid string, instanceid string) (*Path, error) {
 	return CreatePath([]string{gad.prefix, sysid, "tenants", tenantid, "nodes", nodeid, "fdu", fduid, "instances", instanceid, "ls"})
 }
 
 // GetFDUFileEvalPath ...
-func (gad *GAD) GetFDUFileEvalPath(sysid string, tenantid string, nodeid string, fduid string, instanceid string) *yaks.Path {
+func (gad *GAD) GetFDUFileEvalPath(sysid string, tenantid string, nodeid string, fduid string, instanceid string) (*Path, error) {
 	return CreatePath([]string{gad.prefix, sysid, "tenants", tenantid, "nodes", nodeid, "fdu", fduid, "instances", instanceid, "get"})
 }
 
 // Network
 
 // GetAllNetworksSelector ...
-func (gad *GAD) GetAllNetworksSelector(sysid string, tenantid string) *yaks.Selector {
+func (gad *GAD) GetAllNetworksSelector(sysid string, tenantid string) (*Selector, error) {
 	return CreateSelector([]string{gad.prefix, sysid, "tenants", tenantid, "networks", "*", "info"})
 }
 
 // GetNetworkInfoPath ...
-func (gad *GAD) GetNetworkInfoPath(sysid string, tenantid string, networkid string) *yaks.Path {
+func (gad *GAD) GetNetworkInfoPath(sysid string, tenantid string, networkid string) (*Path, error) {
 	return CreatePath([]string{gad.prefix, sysid, "tenants", tenantid, "networks", networkid, "info"})
 }
 
 // GetNetworkPortInfoPath ...
-func (gad *GAD) GetNetworkPortInfoPath(sysid string, tenantid string, portid string) *yaks.Path {
+func (gad *GAD) GetNetworkPortInfoPath(sysid string, tenantid string, portid string) (*Path, error) {
 	return CreatePath([]string{gad.prefix, sysid, "tenants", tenantid, "networks", "ports", portid, "info"})
 }
 
 // GetAllPortsSelector ...
-func (gad *GAD) GetAllPortsSelector(sysid string, tenantid string) *yaks.Selector {
+func (gad *GAD) GetAllPortsSelector(sysid string, tenantid string) (*Selector, error) {
 	return CreateSelector([]string{gad.prefix, sysid, "tenants", tenantid, "networks", "ports", "*", "info"})
 }
 
 // GetNetworkRouterInfoPath ..
-func (gad *GAD) GetNetworkRouterInfoPath(sysid string, tenantid string, routerid string) *yaks.Path {
+func (gad *GAD) GetNetworkRouterInfoPath(sysid string, tenantid string, routerid string) (*Path, error) {
 	return CreatePath([]string{gad.prefix, sysid, "tenants", tenantid, "networks", "routers", routerid, "info"})
 }
 
 // GetAllRoutersSelector ...
-func (gad *GAD) GetAllRoutersSelector(sysid string, tenantid string) *yaks.Selector {
+func (gad *GAD) GetAllRoutersSelector(sysid string, tenantid string) (*Selector, error) {
 	return CreateSelector([]string{gad.prefix, sysid, "tenants", tenantid, "networks", "routers", "*", "info"})
 }
 
 // Images
 
 // GetImageInfoPath ...
-func (gad *GAD) GetImageInfoPath(sysid string, tenantid string, imageid string) *yaks.Path {
+func (gad *GAD) GetImageInfoPath(sysid string, tenantid string, imageid string) (*Path, error) {
 	return CreatePath([]string{gad.prefix, sysid, "tenants", tenantid, "image", imageid, "info"})
 }
 
 // GetAllImageSelector ...
-func (gad *GAD) GetAllImageSelector(sysid string, tenantid string) *yaks.Selector {
+func (gad *GAD) GetAllImageSelector(sysid string, tenantid string) (*Selector, error) {
 	return CreateSelector([]string{gad.prefix, sysid, "tenants", tenantid, "image", "*", "info"})
 }
 
 // Node Images
 
 // GetNodeImageInfoPath ...
-func (gad *GAD) GetNodeImageInfoPath(sysid string, tenantid string, nodeid string, imageid string) *yaks.Path {
+func (gad *GAD) GetNodeImageInfoPath(sysid string, tenantid string, nodeid string, imageid string) (*Path, error) {
 	return CreatePath([]string{gad.prefix, sysid, "tenants", tenantid, "nodes", nodeid, "image", imageid, "info"})
 }
 
 // GetAllNodeImageSelector ...
-func (gad *GAD) GetAllNodeImageSelector(sysid string, tenantid string, nodeid string) *yaks.Selector {
+func (gad *GAD) GetAllNodeImageSelector(sysid string, tenantid string, nodeid string) (*Selector, error) {
 	return CreateSelector([]string{gad.prefix, sysid, "tenants", tenantid, "nodes", nodeid, "image", "*", "info"})
 }
 
 // Flavor
 
 // GetFlavorInfoPath ...
-func (gad *GAD) GetFlavorInfoPath(sysid string, tenantid string, flavorid string) *yaks.Path {
+func (gad *GAD) GetFlavorInfoPath(sysid string, tenantid string, flavorid string) (*Path, error) {
 	return CreatePath([]string{gad.prefix, sysid, "tenants", tenantid, "flavor", flavorid, "info"})
 }
 
 // GetAllFlavorSelector ...
-func (gad *GAD) GetAllFlavorSelector(sysid string, tenantid string) *yaks.Selector {
+func (gad *GAD) GetAllFlavorSelector(sysid string, tenantid string) (*Selector, error) {
 	return CreateSelector([]string{gad.prefix, sysid, "tenants", tenantid, "flavor", "*", "info"})
 }
 
 // Node Flavor
 
 // GetNodeFlavorInfoPath ...
-func (gad *GAD) GetNodeFlavorInfoPath(sysid string, tenantid string, nodeid string, flavorid string) *yaks.Path {
+func (gad *GAD) GetNodeFlavorInfoPath(sysid string, tenantid string, nodeid string, flavorid string) (*Path, error) {
 	return CreatePath([]string{gad.prefix, sysid, "tenants", tenantid, "nodes", nodeid, "flavor", flavorid, "info"})
 }
 
 // GetAllNodeFlavorSelector ...
-func (gad *GAD) GetAllNodeFlavorSelector(sysid string, tenantid string, nodeid string) *yaks.Selector {
+func (gad *GAD) GetAllNodeFlavorSelector(sysid string, tenantid string, nodeid string) (*Selector, error) {
 	return CreateSelector([]string{gad.prefix, sysid, "tenants", tenantid, "nodes", nodeid, "flavor", "*", "info"})
 }
 
 // Node Network
 
 // GetNodeNetworkFloatingIPInfoPath ...
-func (gad *GAD) GetNodeNetworkFloatingIPInfoPath(sysid string, tenantid string, nodeid string, ipid string) *yaks.Path {
+func (gad *GAD) GetNodeNetworkFloatingIPInfoPath(sysid string, tenantid string, nodeid string, ipid string) (*Path, error) {
 	return CreatePath([]string{gad.prefix, sysid, "tenants", tenantid, "nodes", nodeid, "networks", "floating-ips", ipid, "info"})
 }
 
 // GetNodeAllNetworkFloatingIPsSelector ...
-func (gad *GAD) GetNodeAllNetworkFloatingIPsSelector(sysid string, tenantid string, nodeid string) *yaks.Selector {
+func (gad *GAD) GetNodeAllNetworkFloatingIPsSelector(sysid string, tenantid string, nodeid string) (*Selector, error) {
 	return CreateSelector([]string{gad.prefix, sysid, "tenants", tenantid, "nodes", nodeid, "networks", "floating-ips", "*", "info"})
 }
 
 // GetNodeNetworkPortsSelector ...
-func (gad *GAD) GetNodeNetworkPortsSelector(sysid string, tenantid string, nodeid string) *yaks.Selector {
+func (gad *GAD) GetNodeNetworkPortsSelector(sysid string, tenantid string, nodeid string) (*Selector, error) {
 	return CreateSelector([]string{gad.prefix, sysid, "tenants", tenantid, "nodes", nodeid, "networks", "ports", "*", "info"})
 }
 
 // GetNodeNetworkPortInfoPath ...
-func (gad *GAD) GetNodeNetworkPortInfoPath(sysid string, tenantid string, nodeid string, portid string) *yaks.Path {
+func (gad *GAD) GetNodeNetworkPortInfoPath(sysid string, tenantid string, nodeid string, portid string) (*Path, error) {
 	return CreatePath([]string{gad.prefix, sysid, "tenants", tenantid, "nodes", nodeid, "networks", "ports", portid, "info"})
 }
 
 // GetNodeNetworkRoutersSelector ...
-func (gad *GAD) GetNodeNetworkRoutersSelector(sysid string, tenantid string, nodeid string) *yaks.Selector {
+func (gad *GAD) GetNodeNetworkRoutersSelector(sysid string, tenantid string, nodeid string) (*Selector, error) {
 	return CreateSelector([]string{gad.prefix, sysid, "tenants", tenantid, "nodes", nodeid, "networks", "routers", "*", "info"})
 }
 
 // GetNodeNetworkRouterInfoPath ...
-func (gad *GAD) GetNodeNetworkRouterInfoPath(sysid string, tenantid string, nodeid string, routerid string) *yaks.Path {
+func (gad *GAD) GetNodeNetworkRouterInfoPath(sysid string, tenantid string, nodeid string, routerid string) (*Path, error) {
 	return CreatePath([]string{gad.prefix, sysid, "tenants", tenantid, "nodes", nodeid, "networks", "routers", routerid, "info"})
 }
 
 // GetNodeNetworkInfoPath ...
-func (gad *GAD) GetNodeNetworkInfoPath(sysid string, tenantid string, nodeid string, networkid string) *yaks.Path {
+func (gad *GAD) GetNodeNetworkInfoPath(sysid string, tenantid string, nodeid string, networkid string) (*Path, error) {
 	return CreatePath([]string{gad.prefix, sysid, "tenants", tenantid, "nodes", nodeid, "networks", networkid, "info"})
 }
 
 // GetNodeNetworSelector ...
-func (gad *GAD) GetNodeNetworSelector(sysid string, tenantid string, nodeid string) *yaks.Selector {
+func (gad *GAD) GetNodeNetworSelector(sysid string, tenantid string, nodeid string) (*Selector, error) {
 	return CreateSelector([]string{gad.prefix, sysid, "tenants", tenantid, "nodes", nodeid, "networks", "*", "info"})
 }
 
 // Evals
 
 // GetAgentExecPath ...
-func (gad *GAD) GetAgentExecPath(sysid string, tenantid string, nodeid string, funcname string) *yaks.Path {
+func (gad *GAD) GetAgentExecPath(sysid string, tenantid string, nodeid string, funcname string) (*Path, error) {
 	return CreatePath([]string{gad.prefix, sysid, "tenants", tenantid, "nodes", nodeid, "agent", "exec", funcname})
 }
 
 // GetAgentExecSelectorWithParams ...
-func (gad *GAD) GetAgentExecSelectorWithParams(sysid string, tenantid string, nodeid string, funcname string, params map[string]interface{}) *yaks.Selector {
+func (gad *GAD) GetAgentExecSelectorWithParams(sysid string, tenantid string, nodeid string, funcname string, params map[string]interface{}) (*Selector, error) {
 	var f string
 	if len(params) > 0 {
-		p := Dict2Args(params)
+		p, err := MarshalArgs(params)
+		if err != nil {
+			return nil, err
+		}
 		f = fmt.Sprintf("%s?%s", funcname, p)
 	} else {
 		f = funcname
@@ -513,122 +737,128 @@ func (gad *GAD) GetAgentExecSelectorWithParams(sysid string, tenantid string, no
 // ID Extraction
 
 // ExtractUserIDFromPath ...
-func (gad *GAD) ExtractUserIDFromPath(path *yaks.Path) string {
-	return strings.Split(path.ToString(), URISeparator)[4]
+func (gad *GAD) ExtractUserIDFromPath(path *Path) (string, error) {
+	return pathElement(path, 4)
 }
 
 // ExtractTenantIDFromPath ...
-func (gad *GAD) ExtractTenantIDFromPath(path *yaks.Path) string {
-	return strings.Split(path.ToString(), URISeparator)[4]
+func (gad *GAD) ExtractTenantIDFromPath(path *Path) (string, error) {
+	return pathElement(path, 4)
 }
 
 // ExtractEntityIDFromPath ...
-func (gad *GAD) ExtractEntityIDFromPath(path *yaks.Path) string {
-	return strings.Split(path.ToString(), URISeparator)[7]
+func (gad *GAD) ExtractEntityIDFromPath(path *Path) (string, error) {
+	return pathElement(path, 7)
 }
 
 // ExtractAtomicEntityIDFromPath ...
-func (gad *GAD) ExtractAtomicEntityIDFromPath(path *yaks.Path) string {
-	return strings.Split(path.ToString(), URISeparator)[7]
+func (gad *GAD) ExtractAtomicEntityIDFromPath(path *Path) (string, error) {
+	return pathElement(path, 7)
 }
 
 // ExtractAtomicEntityInstanceIDFromPath ...
-func (gad *GAD) ExtractAtomicEntityInstanceIDFromPath(path *yaks.Path) string {
-	return strings.Split(path.ToString(), URISeparator)[9]
+func (gad *GAD) ExtractAtomicEntityInstanceIDFromPath(path *Path) (string, error) {
+	return pathElement(path, 9)
 }
 
 // ExtractFDUIDFromPath ...
-func (gad *GAD) ExtractFDUIDFromPath(path *yaks.Path) string {
-	return strings.Split(path.ToString(), URISeparator)[7]
+func (gad *GAD) ExtractFDUIDFromPath(path *Path) (string, error) {
+	return pathElement(path, 7)
 }
 
 // ExtractNodeIDFromPath ...
-func (gad *GAD) ExtractNodeIDFromPath(path *yaks.Path) string {
-	return strings.Split(path.ToString(), URISeparator)[6]
+func (gad *GAD) ExtractNodeIDFromPath(path *Path) (string, error) {
+	return pathElement(path, 6)
 }
 
 // ExtractPluginIDFromPath ...
-func (gad *GAD) ExtractPluginIDFromPath(path *yaks.Path) string {
-	return strings.Split(path.ToString(), URISeparator)[8]
+func (gad *GAD) ExtractPluginIDFromPath(path *Path) (string, error) {
+	return pathElement(path, 8)
 }
 
 // ExtractPortIDFromPath ...
-func (gad *GAD) ExtractPortIDFromPath(path *yaks.Path) string {
-	return strings.Split(path.ToString(), URISeparator)[6]
+func (gad *GAD) ExtractPortIDFromPath(path *Path) (string, error) {
+	return pathElement(path, 6)
 }
 
 // ExtractRouterIDFromPath ...
-func (gad *GAD) ExtractRouterIDFromPath(path *yaks.Path) string {
-	return strings.Split(path.ToString(), URISeparator)[6]
+func (gad *GAD) ExtractRouterIDFromPath(path *Path) (string, error) {
+	return pathElement(path, 6)
 }
 
 // ExtractNetworkIDFromPath ...
-func (gad *GAD) ExtractNetworkIDFromPath(path *yaks.Path) string {
-	return strings.Split(path.ToString(), URISeparator)[5]
+func (gad *GAD) ExtractNetworkIDFromPath(path *Path) (string, error) {
+	return pathElement(path, 5)
 }
 
 // ExtractImageIDFromPath ...
-func (gad *GAD) ExtractImageIDFromPath(path *yaks.Path) string {
-	return strings.Split(path.ToString(), URISeparator)[5]
+func (gad *GAD) ExtractImageIDFromPath(path *Path) (string, error) {
+	return pathElement(path, 5)
 }
 
 // ExtractFlavorIDFromPath ...
-func (gad *GAD) ExtractFlavorIDFromPath(path *yaks.Path) string {
-	return strings.Split(path.ToString(), URISeparator)[5]
+func (gad *GAD) ExtractFlavorIDFromPath(path *Path) (string, error) {
+	return pathElement(path, 5)
 }
 
 // ExtractNodeFDUIDFromPath ...
-func (gad *GAD) ExtractNodeFDUIDFromPath(path *yaks.Path) string {
-	return strings.Split(path.ToString(), URISeparator)[8]
+func (gad *GAD) ExtractNodeFDUIDFromPath(path *Path) (string, error) {
+	return pathElement(path, 8)
 }
 
 // ExtractNodeImageIDFromPath ...
-func (gad *GAD) ExtractNodeImageIDFromPath(path *yaks.Path) string {
-	return strings.Split(path.ToString(), URISeparator)[7]
+func (gad *GAD) ExtractNodeImageIDFromPath(path *Path) (string, error) {
+	return pathElement(path, 7)
 }
 
 // ExtractNodeFlavorIDFromPath ...
-func (gad *GAD) ExtractNodeFlavorIDFromPath(path *yaks.Path) string {
-	return strings.Split(path.ToString(), URISeparator)[7]
+func (gad *GAD) ExtractNodeFlavorIDFromPath(path *Path) (string, error) {
+	return pathElement(path, 7)
 }
 
 // ExtractNodeInstanceIDFromPath ...
-func (gad *GAD) ExtractNodeInstanceIDFromPath(path *yaks.Path) string {
-	return strings.Split(path.ToString(), URISeparator)[10]
+func (gad *GAD) ExtractNodeInstanceIDFromPath(path *Path) (string, error) {
+	return pathElement(path, 10)
 }
 
 // ExtractNodePortIDFromPath ...
-func (gad *GAD) ExtractNodePortIDFromPath(path *yaks.Path) string {
-	return strings.Split(path.ToString(), URISeparator)[9]
+func (gad *GAD) ExtractNodePortIDFromPath(path *Path) (string, error) {
+	return pathElement(path, 9)
 }
 
 // ExtractNodeRouterIDFromPath ...
-func (gad *GAD) ExtractNodeRouterIDFromPath(path *yaks.Path) string {
-	return strings.Split(path.ToString(), URISeparator)[9]
+func (gad *GAD) ExtractNodeRouterIDFromPath(path *Path) (string, error) {
+	return pathElement(path, 9)
 }
 
 // ExtractNodeFloatingIDFromPath ...
-func (gad *GAD) ExtractNodeFloatingIDFromPath(path *yaks.Path) string {
-	return strings.Split(path.ToString(), URISeparator)[9]
+func (gad *GAD) ExtractNodeFloatingIDFromPath(path *Path) (string, error) {
+	return pathElement(path, 9)
 }
 
 // ExtractNodeNetworkIDFromPath ...
-func (gad *GAD) ExtractNodeNetworkIDFromPath(path *yaks.Path) string {
-	return strings.Split(path.ToString(), URISeparator)[7]
+func (gad *GAD) ExtractNodeNetworkIDFromPath(path *Path) (string, error) {
+	return pathElement(path, 7)
 }
 
 // System
 
 // GetSysInfo ...
 func (gad *GAD) GetSysInfo(sysid string) (*SystemInfo, error) {
-	s, _ := yaks.NewSelector(gad.GetSysInfoPath(sysid).ToString())
-	kvs := gad.ws.Get(s)
+	s, err := toSelector(gad.GetSysInfoPath(sysid))
+	if err != nil {
+		return nil, err
+	}
+	kvs, err := gad.ws.Get(gad.context(), s)
+	if err != nil {
+		return nil, err
+	}
 	if len(kvs) == 0 {
-		return nil, &FError{"Empty sys info", nil}
+		return nil, &FError{"Empty sys info", ErrNotFound}
 	}
-	v := kvs[0].Value().ToString()
+	v := kvs[0].Value
 	sv := SystemInfo{}
-	err := json.Unmarshal([]byte(v), &sv)
+	err = decode(v, &sv)
 	if err != nil {
 		return nil, err
 	}
@@ -637,14 +867,20 @@ func (gad *GAD) GetSysInfo(sysid string) (*SystemInfo, error) {
 
 // GetSysConfig ...
 func (gad *GAD) GetSysConfig(sysid string) (*SystemConfig, error) {
-	s, _ := yaks.NewSelector(gad.GetSysConfigurationPath(sysid).ToString())
-	kvs := gad.ws.Get(s)
+	s, err := toSelector(gad.GetSysConfigurationPath(sysid))
+	if err != nil {
+		return nil, err
+	}
+	kvs, err := gad.ws.Get(gad.context(), s)
+	if err != nil {
+		return nil, err
+	}
 	if len(kvs) == 0 {
-		return nil, &FError{"Empty sys config", nil}
+		return nil, &FError{"Empty sys config", ErrNotFound}
 	}
-	v := kvs[0].Value().ToString()
+	v := kvs[0].Value
 	sv := SystemConfig{}
-	err := json.Unmarshal([]byte(v), &sv)
+	err = decode(v, &sv)
 	if err != nil {
 		return nil, err
 	}
@@ -653,15 +889,25 @@ func (gad *GAD) GetSysConfig(sysid string) (*SystemConfig, error) {
 
 // GetAllUserIDs ...
 func (gad *GAD) GetAllUserIDs(sysid string) ([]string, error) {
-	s := gad.GetAllUsersSelector(sysid)
-	kvs := gad.ws.Get(s)
+	s, err := gad.GetAllUsersSelector(sysid)
+	if err != nil {
+		return nil, err
+	}
+	kvs, err := gad.ws.Get(gad.context(), s)
+	if err != nil {
+		return nil, err
+	}
 	if len(kvs) == 0 {
 		return []string{}, nil
 	}
 	var ids []string = []string{}
 	for _, kv := range kvs {
-		p := kv.Path()
-		ids = append(ids, gad.ExtractUserIDFromPath(p))
+		p := kv.Path
+		id, err := gad.ExtractUserIDFromPath(p)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
 	}
 	return ids, nil
 }
@@ -670,44 +916,70 @@ func (gad *GAD) GetAllUserIDs(sysid string) ([]string, error) {
 
 // GetAllTenantsIDs ...
 func (gad *GAD) GetAllTenantsIDs(sysid string) ([]string, error) {
-	s := gad.GetAllTenantsSelector(sysid)
-	kvs := gad.ws.Get(s)
+	s, err := gad.GetAllTenantsSelector(sysid)
+	if err != nil {
+		return nil, err
+	}
+	kvs, err := gad.ws.Get(gad.context(), s)
+	if err != nil {
+		return nil, err
+	}
 	if len(kvs) == 0 {
-		return []string{}, &FError{"Empty Tenants", nil}
+		return []string{}, &FError{"Empty Tenants", ErrNotFound}
 	}
 	var ids []string = []string{}
 	for _, kv := range kvs {
-		p := kv.Path()
-		ids = append(ids, gad.ExtractTenantIDFromPath(p))
+		p := kv.Path
+		id, err := gad.ExtractTenantIDFromPath(p)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
 	}
 	return ids, nil
 }
 
 // GetAllNodes ...
 func (gad *GAD) GetAllNodes(sysid string, tenantid string) ([]string, error) {
-	s := gad.GetAllNodesSelector(sysid, tenantid)
-	kvs := gad.ws.Get(s)
+	s, err := gad.GetAllNodesSelector(sysid, tenantid)
+	if err != nil {
+		return nil, err
+	}
+	kvs, err := gad.ws.Get(gad.context(), s)
+	if err != nil {
+		return nil, err
+	}
 	if len(kvs) == 0 {
-		return []string{}, &FError{"Empty Node List", nil}
+		return []string{}, &FError{"Empty Node List", ErrNotFound}
 	}
 	var ids []string = []string{}
 	for _, kv := range kvs {
-		p := kv.Path()
-		ids = append(ids, gad.ExtractNodeIDFromPath(p))
+		p := kv.Path
+		id, err := gad.ExtractNodeIDFromPath(p)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
 	}
 	return ids, nil
 }
 
 // GetNodeInfo ...
 func (gad *GAD) GetNodeInfo(sysid string, tenantid string, nodeid string) (*NodeInfo, error) {
-	s, _ := yaks.NewSelector(gad.GetNodeInfoPath(sysid, tenantid, nodeid).ToString())
-	kvs := gad.ws.Get(s)
+	s, err := toSelector(gad.GetNodeInfoPath(sysid, tenantid, nodeid))
+	if err != nil {
+		return nil, err
+	}
+	kvs, err := gad.ws.Get(gad.context(), s)
+	if err != nil {
+		return nil, err
+	}
 	if len(kvs) == 0 {
-		return nil, &FError{"Empty Node Info", nil}
+		return nil, &FError{"Empty Node Info", ErrNotFound}
 	}
-	v := kvs[0].Value().ToString()
+	v := kvs[0].Value
 	sv := NodeInfo{}
-	err := json.Unmarshal([]byte(v), &sv)
+	err = decode(v, &sv)
 	if err != nil {
 		return nil, err
 	}
@@ -716,33 +988,44 @@ func (gad *GAD) GetNodeInfo(sysid string, tenantid string, nodeid string) (*Node
 
 // AddNodeInfo ...
 func (gad *GAD) AddNodeInfo(sysid string, tenantid string, nodeid string, info NodeInfo) error {
-	s := gad.GetNodeInfoPath(sysid, tenantid, nodeid)
+	s, err := gad.GetNodeInfoPath(sysid, tenantid, nodeid)
+	if err != nil {
+		return err
+	}
 	v, err := json.Marshal(info)
 	if err != nil {
 		return err
 	}
-	sv := yaks.NewStringValue(string(v))
-	err = gad.ws.Put(s, sv)
+	err = gad.ws.Put(gad.context(), s, string(v))
 	return err
 }
 
 // RemoveNodeInfo ...
 func (gad *GAD) RemoveNodeInfo(sysid string, tenantid string, nodeid string) error {
-	s := gad.GetNodeInfoPath(sysid, tenantid, nodeid)
-	err := gad.ws.Remove(s)
+	s, err := gad.GetNodeInfoPath(sysid, tenantid, nodeid)
+	if err != nil {
+		return err
+	}
+	err = gad.ws.Remove(gad.context(), s)
 	return err
 }
 
 // GetNodeConfiguration ...
 func (gad *GAD) GetNodeConfiguration(sysid string, tenantid string, nodeid string) (*NodeConfiguration, error) {
-	s, _ := yaks.NewSelector(gad.GetNodeConfigurationPath(sysid, tenantid, nodeid).ToString())
-	kvs := gad.ws.Get(s)
+	s, err := toSelector(gad.GetNodeConfigurationPath(sysid, tenantid, nodeid))
+	if err != nil {
+		return nil, err
+	}
+	kvs, err := gad.ws.Get(gad.context(), s)
+	if err != nil {
+		return nil, err
+	}
 	if len(kvs) == 0 {
-		return nil, &FError{"Empty Node Configuration", nil}
+		return nil, &FError{"Empty Node Configuration", ErrNotFound}
 	}
-	v := kvs[0].Value().ToString()
+	v := kvs[0].Value
 	sv := NodeConfiguration{}
-	err := json.Unmarshal([]byte(v), &sv)
+	err = decode(v, &sv)
 	if err != nil {
 		return nil, err
 	}
@@ -751,33 +1034,44 @@ func (gad *GAD) GetNodeConfiguration(sysid string, tenantid string, nodeid strin
 
 // AddNodeConfiguration ...
 func (gad *GAD) AddNodeConfiguration(sysid string, tenantid string, nodeid string, conf NodeConfiguration) error {
-	s := gad.GetNodeConfigurationPath(sysid, tenantid, nodeid)
+	s, err := gad.GetNodeConfigurationPath(sysid, tenantid, nodeid)
+	if err != nil {
+		return err
+	}
 	v, err := json.Marshal(conf)
 	if err != nil {
 		return err
 	}
-	sv := yaks.NewStringValue(string(v))
-	err = gad.ws.Put(s, sv)
+	err = gad.ws.Put(gad.context(), s, string(v))
 	return err
 }
 
 // RemoveNodeConfiguration ...
 func (gad *GAD) RemoveNodeConfiguration(sysid string, tenantid string, nodeid string) error {
-	s := gad.GetNodeConfigurationPath(sysid, tenantid, nodeid)
-	err := gad.ws.Remove(s)
+	s, err := gad.GetNodeConfigurationPath(sysid, tenantid, nodeid)
+	if err != nil {
+		return err
+	}
+	err = gad.ws.Remove(gad.context(), s)
 	return err
 }
 
 // GetNodeStatus ...
 func (gad *GAD) GetNodeStatus(sysid string, tenantid string, nodeid string) (*NodeStatus, error) {
-	s, _ := yaks.NewSelector(gad.GetNodeStatusPath(sysid, tenantid, nodeid).ToString())
-	kvs := gad.ws.Get(s)
+	s, err := toSelector(gad.GetNodeStatusPath(sysid, tenantid, nodeid))
+	if err != nil {
+		return nil, err
+	}
+	kvs, err := gad.ws.Get(gad.context(), s)
+	if err != nil {
+		return nil, err
+	}
 	if len(kvs) == 0 {
-		return nil, &FError{"Empty Node Status", nil}
+		return nil, &FError{"Empty Node Status", ErrNotFound}
 	}
-	v := kvs[0].Value().ToString()
+	v := kvs[0].Value
 	sv := NodeStatus{}
-	err := json.Unmarshal([]byte(v), &sv)
+	err = decode(v, &sv)
 	if err != nil {
 		return nil, err
 	}
@@ -786,74 +1080,94 @@ func (gad *GAD) GetNodeStatus(sysid string, tenantid string, nodeid string) (*No
 
 // AddNodeStatus ...
 func (gad *GAD) AddNodeStatus(sysid string, tenantid string, nodeid string, info NodeStatus) error {
-	s := gad.GetNodeStatusPath(sysid, tenantid, nodeid)
+	s, err := gad.GetNodeStatusPath(sysid, tenantid, nodeid)
+	if err != nil {
+		return err
+	}
 	v, err := json.Marshal(info)
 	if err != nil {
 		return err
 	}
-	sv := yaks.NewStringValue(string(v))
-	err = gad.ws.Put(s, sv)
+	err = gad.ws.Put(gad.context(), s, string(v))
 	return err
 }
 
 // RemoveNodeStatus ...
 func (gad *GAD) RemoveNodeStatus(sysid string, tenantid string, nodeid string) error {
-	s := gad.GetNodeStatusPath(sysid, tenantid, nodeid)
-	err := gad.ws.Remove(s)
+	s, err := gad.GetNodeStatusPath(sysid, tenantid, nodeid)
+	if err != nil {
+		return err
+	}
+	err = gad.ws.Remove(gad.context(), s)
 	return err
 }
 
 // ObserveNodeStatus ...
-func (gad *GAD) ObserveNodeStatus(sysid string, tenantid string, nodeid string, listener func(NodeStatus)) (*yaks.SubscriptionID, error) {
-	s, _ := yaks.NewSelector(gad.GetNodeStatusPath(sysid, tenantid, nodeid).ToString())
+func (gad *GAD) ObserveNodeStatus(sysid string, tenantid string, nodeid string, listener func(NodeStatus)) (*SubscriptionID, error) {
+	s, err := toSelector(gad.GetNodeStatusPath(sysid, tenantid, nodeid))
+	if err != nil {
+		return nil, err
+	}
 
-	cb := func(kvs []yaks.Change) {
-		if len(kvs) > 0 {
-			v := kvs[0].Value().ToString()
+	cb := func(kvs []Change) {
+		if len(kvs) > 0 && kvs[0].Kind != REMOVE {
+			v := kvs[0].Value
 			sv := NodeStatus{}
-			err := json.Unmarshal([]byte(v), &sv)
+			err := decode(v, &sv)
 			if err != nil {
-				panic(err.Error())
+				gad.handleError(err)
+				return
 			}
 			listener(sv)
 		}
 	}
 
-	sid, err := gad.ws.Subscribe(s, cb)
-	if err != nil {
-		return nil, err
-	}
-	gad.listeners = append(gad.listeners, sid)
-	return sid, nil
+	return gad.subscribe(gad.context(), gad.ws, s, cb)
 }
 
 // Omiting Entities and Atomic Entities
 
 // GetCatalogAllFDUs ...
 func (gad *GAD) GetCatalogAllFDUs(sysid string, tenantid string) ([]string, error) {
-	s := gad.GetCatalogAllFDUSelector(sysid, tenantid)
-	kvs := gad.ws.Get(s)
+	s, err := gad.GetCatalogAllFDUSelector(sysid, tenantid)
+	if err != nil {
+		return nil, err
+	}
+	kvs, err := gad.ws.Get(gad.context(), s)
+	if err != nil {
+		return nil, err
+	}
 	if len(kvs) == 0 {
 		return []string{}, nil
 	}
 	var ids []string = []string{}
 	for _, kv := range kvs {
-		p := kv.Path()
-		ids = append(ids, gad.ExtractFDUIDFromPath(p))
+		p := kv.Path
+		id, err := gad.ExtractFDUIDFromPath(p)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
 	}
 	return ids, nil
 }
 
 // GetCatalogFDUInfo ...
 func (gad *GAD) GetCatalogFDUInfo(sysid string, tenantid string, fduid string) (*FDU, error) {
-	s, _ := yaks.NewSelector(gad.GetCatalogFDUInfoPath(sysid, tenantid, fduid).ToString())
-	kvs := gad.ws.Get(s)
+	s, err := toSelector(gad.GetCatalogFDUInfoPath(sysid, tenantid, fduid))
+	if err != nil {
+		return nil, err
+	}
+	kvs, err := gad.ws.Get(gad.context(), s)
+	if err != nil {
+		return nil, err
+	}
 	if len(kvs) == 0 {
-		return nil, &FError{"FDU Not Found in catalog", nil}
+		return nil, &FError{"FDU Not Found in catalog", ErrNotFound}
 	}
-	v := kvs[0].Value().ToString()
+	v := kvs[0].Value
 	sv := FDU{}
-	err := json.Unmarshal([]byte(v), &sv)
+	err = decode(v, &sv)
 	if err != nil {
 		return nil, err
 	}
@@ -862,104 +1176,177 @@ func (gad *GAD) GetCatalogFDUInfo(sysid string, tenantid string, fduid string) (
 
 // AddCatalogFDUInfo ...
 func (gad *GAD) AddCatalogFDUInfo(sysid string, tenantid string, fduid string, info FDU) error {
-	s := gad.GetCatalogFDUInfoPath(sysid, tenantid, fduid)
+	err := gad.validateFDU(sysid, tenantid, &info)
+	if err != nil {
+		return err
+	}
+	s, err := gad.GetCatalogFDUInfoPath(sysid, tenantid, fduid)
+	if err != nil {
+		return err
+	}
 	v, err := json.Marshal(info)
 	if err != nil {
 		return err
 	}
-	sv := yaks.NewStringValue(string(v))
-	err = gad.ws.Put(s, sv)
+	err = gad.ws.Put(gad.context(), s, string(v))
 	return err
 }
 
+// validateFDU validates the FDU descriptor, checking its dependencies against the catalog
+func (gad *GAD) validateFDU(sysid string, tenantid string, info *FDU) error {
+	if len(info.DependsOn) == 0 {
+		return info.Validate()
+	}
+	fduids, err := gad.GetCatalogAllFDUs(sysid, tenantid)
+	if err != nil {
+		return err
+	}
+	// DependsOn has descriptor ids, the catalog is keyed by the UUIDs assigned at onboard
+	catalog := []string{}
+	for _, fduid := range fduids {
+		fdu, err := gad.GetCatalogFDUInfo(sysid, tenantid, fduid)
+		if errors.Is(err, ErrNotFound) {
+			// removed meanwhile
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		catalog = append(catalog, fdu.ID)
+	}
+	return info.ValidateDependencies(catalog)
+}
+
 // RemoveCatalogFDUInfo ...
 func (gad *GAD) RemoveCatalogFDUInfo(sysid string, tenantid string, fduid string) error {
-	s := gad.GetNodeStatusPath(sysid, tenantid, fduid)
-	err := gad.ws.Remove(s)
+	s, err := gad.GetCatalogFDUInfoPath(sysid, tenantid, fduid)
+	if err != nil {
+		return err
+	}
+	err = gad.ws.Remove(gad.context(), s)
 	return err
 }
 
 // ObserveCatalogFDUs ...
-func (gad *GAD) ObserveCatalogFDUs(sysid string, tenantid string, fduid string, listener func(FDU)) (*yaks.SubscriptionID, error) {
-	s, _ := yaks.NewSelector(gad.GetCatalogFDUInfoPath(sysid, tenantid, fduid).ToString())
+func (gad *GAD) ObserveCatalogFDUs(sysid string, tenantid string, fduid string, listener func(FDU)) (*SubscriptionID, error) {
+	s, err := toSelector(gad.GetCatalogFDUInfoPath(sysid, tenantid, fduid))
+	if err != nil {
+		return nil, err
+	}
 
-	cb := func(kvs []yaks.Change) {
-		if len(kvs) > 0 {
-			v := kvs[0].Value().ToString()
+	cb := func(kvs []Change) {
+		if len(kvs) > 0 && kvs[0].Kind != REMOVE {
+			v := kvs[0].Value
 			sv := FDU{}
-			err := json.Unmarshal([]byte(v), &sv)
+			err := decode(v, &sv)
 			if err != nil {
-				panic(err.Error())
+				gad.handleError(err)
+				return
 			}
 			listener(sv)
 		}
 	}
 
-	sid, err := gad.ws.Subscribe(s, cb)
-	if err != nil {
-		return nil, err
-	}
-	gad.listeners = append(gad.listeners, sid)
-	return sid, nil
+	return gad.subscribe(gad.context(), gad.ws, s, cb)
 }
 
 // NodeFDU
 
 // GetNodeFDUs ...
 func (gad *GAD) GetNodeFDUs(sysid string, tenantid string, nodeid string) ([]string, error) {
-	s := gad.GetNodeFDUSelector(sysid, tenantid, nodeid)
-	kvs := gad.ws.Get(s)
+	s, err := gad.GetNodeFDUSelector(sysid, tenantid, nodeid)
+	if err != nil {
+		return nil, err
+	}
+	kvs, err := gad.ws.Get(gad.context(), s)
+	if err != nil {
+		return nil, err
+	}
 	if len(kvs) == 0 {
 		return []string{}, nil
 	}
 	var ids []string = []string{}
 	for _, kv := range kvs {
-		p := kv.Path()
-		ids = append(ids, gad.ExtractNodeFDUIDFromPath(p))
+		p := kv.Path
+		id, err := gad.ExtractNodeFDUIDFromPath(p)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
 	}
 	return ids, nil
 }
 
 // GetFDUNodes ...
 func (gad *GAD) GetFDUNodes(sysid string, tenantid string, fduid string) ([]string, error) {
-	s := gad.GetNodeFDUInstancesSelector(sysid, tenantid, "*", fduid)
-	kvs := gad.ws.Get(s)
+	s, err := gad.GetNodeFDUInstancesSelector(sysid, tenantid, "*", fduid)
+	if err != nil {
+		return nil, err
+	}
+	kvs, err := gad.ws.Get(gad.context(), s)
+	if err != nil {
+		return nil, err
+	}
 	if len(kvs) == 0 {
 		return []string{}, nil
 	}
 	var ids []string = []string{}
 	for _, kv := range kvs {
-		p := kv.Path()
-		ids = append(ids, gad.ExtractNodeIDFromPath(p))
+		p := kv.Path
+		id, err := gad.ExtractNodeIDFromPath(p)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
 	}
 	return ids, nil
 }
 
 // GetNodeFDUInstances ...
 func (gad *GAD) GetNodeFDUInstances(sysid string, tenantid string, nodeid string, fduid string) ([]Couple, error) {
-	s := gad.GetNodeFDUInstancesSelector(sysid, tenantid, nodeid, fduid)
-	kvs := gad.ws.Get(s)
+	s, err := gad.GetNodeFDUInstancesSelector(sysid, tenantid, nodeid, fduid)
+	if err != nil {
+		return nil, err
+	}
+	kvs, err := gad.ws.Get(gad.context(), s)
+	if err != nil {
+		return nil, err
+	}
 	var ids []Couple = []Couple{}
 	if len(kvs) == 0 {
 		return []Couple{}, nil
 	}
 	for _, kv := range kvs {
-		p := kv.Path()
-		ids = append(ids, Couple{gad.ExtractNodeIDFromPath(p), gad.ExtractNodeInstanceIDFromPath(p)})
+		p := kv.Path
+		nodeid, err := gad.ExtractNodeIDFromPath(p)
+		if err != nil {
+			return nil, err
+		}
+		id, err := gad.ExtractNodeInstanceIDFromPath(p)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, Couple{nodeid, id})
 	}
 	return ids, nil
 }
 
 // GetNodeFDUInstance ...
 func (gad *GAD) GetNodeFDUInstance(sysid string, tenantid string, nodeid string, instanceid string) (*FDURecord, error) {
-	s := gad.GetNodeFDUInstanceSelector(sysid, tenantid, nodeid, instanceid)
-	kvs := gad.ws.Get(s)
-	if len(kvs) == 0 {
-		return nil, &FError{"FDU Instance Not Found", nil}
-	}
-	v := kvs[0].Value().ToString()
+	s, err := gad.GetNodeFDUInstanceSelector(sysid, tenantid, nodeid, instanceid)
+	if err != nil {
+		return nil, err
+	}
+	kvs, err := gad.ws.Get(gad.context(), s)
+	if err != nil {
+		return nil, err
+	}
+	if len(kvs) == 0 {
+		return nil, &FError{"FDU Instance Not Found", ErrNotFound}
+	}
+	v := kvs[0].Value
 	sv := FDURecord{}
-	err := json.Unmarshal([]byte(v), &sv)
+	err = decode(v, &sv)
 	if err != nil {
 		return nil, err
 	}
@@ -968,50 +1355,63 @@ func (gad *GAD) GetNodeFDUInstance(sysid string, tenantid string, nodeid string,
 
 // GetFDUInstanceNode ...
 func (gad *GAD) GetFDUInstanceNode(sysid string, tenantid string, instanceid string) (string, error) {
-	s := gad.GetFDUInstanceSelector(sysid, tenantid, instanceid)
-	kvs := gad.ws.Get(s)
+	s, err := gad.GetFDUInstanceSelector(sysid, tenantid, instanceid)
+	if err != nil {
+		return "", err
+	}
+	kvs, err := gad.ws.Get(gad.context(), s)
+	if err != nil {
+		return "", err
+	}
 	if len(kvs) == 0 {
-		return "", &FError{"FDU Instance Not Found", nil}
+		return "", &FError{"FDU Instance Not Found", ErrNotFound}
 	}
-	p := kvs[0].Path()
-
-	return gad.ExtractNodeIDFromPath(p), nil
+	return gad.ExtractNodeIDFromPath(kvs[0].Path)
 }
 
 // AddNodeFDU ...
 func (gad *GAD) AddNodeFDU(sysid string, tenantid string, nodeid string, fduid string, instanceid string, info FDURecord) error {
-	s := gad.GetNodeFDUInfoPath(sysid, tenantid, nodeid, fduid, instanceid)
+	s, err := gad.GetNodeFDUInfoPath(sysid, tenantid, nodeid, fduid, instanceid)
+	if err != nil {
+		return err
+	}
 	v, err := json.Marshal(info)
 	if err != nil {
 		return err
 	}
-	sv := yaks.NewStringValue(string(v))
-	err = gad.ws.Put(s, sv)
+	err = gad.ws.Put(gad.context(), s, string(v))
 	return err
 }
 
 // RemoveNodeFDU ...
 func (gad *GAD) RemoveNodeFDU(sysid string, tenantid string, nodeid string, fduid string, instanceid string) error {
-	s := gad.GetNodeFDUInfoPath(sysid, tenantid, nodeid, fduid, instanceid)
-	err := gad.ws.Remove(s)
+	s, err := gad.GetNodeFDUInfoPath(sysid, tenantid, nodeid, fduid, instanceid)
+	if err != nil {
+		return err
+	}
+	err = gad.ws.Remove(gad.context(), s)
 	return err
 }
 
 // ObserveNodeFDU ...
-func (gad *GAD) ObserveNodeFDU(sysid string, tenantid string, nodeid string, listener func(*FDURecord, bool)) (*yaks.SubscriptionID, error) {
-	s, _ := yaks.NewSelector(gad.GetNodeFDUSelector(sysid, tenantid, nodeid).ToString())
+func (gad *GAD) ObserveNodeFDU(sysid string, tenantid string, nodeid string, listener func(*FDURecord, bool)) (*SubscriptionID, error) {
+	s, err := gad.GetNodeFDUSelector(sysid, tenantid, nodeid)
+	if err != nil {
+		return nil, err
+	}
 
-	cb := func(kvs []yaks.Change) {
+	cb := func(kvs []Change) {
 		for _, v := range kvs {
-			switch v.Kind() {
-			case yaks.REMOVE:
+			switch v.Kind {
+			case REMOVE:
 				listener(nil, true)
 			default:
-				v := v.Value().ToString()
+				v := v.Value
 				sv := FDURecord{}
-				err := json.Unmarshal([]byte(v), &sv)
+				err := decode(v, &sv)
 				if err != nil {
-					panic(err.Error())
+					gad.handleError(err)
+					continue
 				}
 				listener(&sv, false)
 			}
@@ -1019,41 +1419,81 @@ func (gad *GAD) ObserveNodeFDU(sysid string, tenantid string, nodeid string, lis
 		}
 	}
 
-	sid, err := gad.ws.Subscribe(s, cb)
+	return gad.subscribe(gad.context(), gad.ws, s, cb)
+}
+
+// ObserveNodeFDUInstance observes the record of a single FDU instance, the listener receives nil and true when the record is removed
+func (gad *GAD) ObserveNodeFDUInstance(sysid string, tenantid string, nodeid string, instanceid string, listener func(*FDURecord, bool)) (*SubscriptionID, error) {
+	s, err := gad.GetNodeFDUInstanceSelector(sysid, tenantid, nodeid, instanceid)
 	if err != nil {
 		return nil, err
 	}
-	gad.listeners = append(gad.listeners, sid)
-	return sid, nil
+
+	cb := func(kvs []Change) {
+		for _, v := range kvs {
+			switch v.Kind {
+			case REMOVE:
+				listener(nil, true)
+			default:
+				v := v.Value
+				sv := FDURecord{}
+				err := decode(v, &sv)
+				if err != nil {
+					gad.handleError(err)
+					continue
+				}
+				listener(&sv, false)
+			}
+
+		}
+	}
+
+	return gad.subscribe(gad.context(), gad.ws, s, cb)
 }
 
 // Plugins
 
 // GetAllPluginsIDs ...
 func (gad *GAD) GetAllPluginsIDs(sysid string, tenantid string, nodeid string) ([]string, error) {
-	s := gad.GetNodePluginsSelector(sysid, tenantid, nodeid)
-	kvs := gad.ws.Get(s)
+	s, err := gad.GetNodePluginsSelector(sysid, tenantid, nodeid)
+	if err != nil {
+		return nil, err
+	}
+	kvs, err := gad.ws.Get(gad.context(), s)
+	if err != nil {
+		return nil, err
+	}
 	if len(kvs) == 0 {
 		return []string{}, nil
 	}
 	var ids []string = []string{}
 	for _, kv := range kvs {
-		p := kv.Path()
-		ids = append(ids, gad.ExtractPluginIDFromPath(p))
+		p := kv.Path
+		id, err := gad.ExtractPluginIDFromPath(p)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
 	}
 	return ids, nil
 }
 
 // GetPluginInfo ...
 func (gad *GAD) GetPluginInfo(sysid string, tenantid string, nodeid string, pluginid string) (*Plugin, error) {
-	s, _ := yaks.NewSelector(gad.GetNodePluginInfoPath(sysid, tenantid, nodeid, pluginid).ToString())
-	kvs := gad.ws.Get(s)
+	s, err := toSelector(gad.GetNodePluginInfoPath(sysid, tenantid, nodeid, pluginid))
+	if err != nil {
+		return nil, err
+	}
+	kvs, err := gad.ws.Get(gad.context(), s)
+	if err != nil {
+		return nil, err
+	}
 	if len(kvs) == 0 {
-		return nil, &FError{"Plugin Not found", nil}
+		return nil, &FError{"Plugin Not found", ErrNotFound}
 	}
-	v := kvs[0].Value().ToString()
+	v := kvs[0].Value
 	sv := Plugin{}
-	err := json.Unmarshal([]byte(v), &sv)
+	err = decode(v, &sv)
 	if err != nil {
 		return nil, err
 	}
@@ -1062,67 +1502,87 @@ func (gad *GAD) GetPluginInfo(sysid string, tenantid string, nodeid string, plug
 
 // AddNodePlugin ...
 func (gad *GAD) AddNodePlugin(sysid string, tenantid string, nodeid string, pluginid string, info Plugin) error {
-	s := gad.GetNodePluginInfoPath(sysid, tenantid, nodeid, pluginid)
+	s, err := gad.GetNodePluginInfoPath(sysid, tenantid, nodeid, pluginid)
+	if err != nil {
+		return err
+	}
 	v, err := json.Marshal(info)
 	if err != nil {
 		return err
 	}
-	sv := yaks.NewStringValue(string(v))
-	err = gad.ws.Put(s, sv)
+	err = gad.ws.Put(gad.context(), s, string(v))
 	return err
 }
 
 // AddNodePluginEval ...
-func (gad *GAD) AddNodePluginEval(sysid string, tenantid string, nodeid string, plugind string, funcname string, evalcb func(yaks.Properties) interface{}) error {
-	s := gad.GetNodePluginEvalPath(sysid, tenantid, nodeid, plugind, funcname)
+func (gad *GAD) AddNodePluginEval(sysid string, tenantid string, nodeid string, plugind string, funcname string, evalcb func(Properties) interface{}) error {
+	s, err := gad.GetNodePluginEvalPath(sysid, tenantid, nodeid, plugind, funcname)
+	if err != nil {
+		return err
+	}
 
-	cb := func(path *yaks.Path, props yaks.Properties) yaks.Value {
-		v, _ := json.Marshal(evalcb(props))
-		sv := yaks.NewStringValue(string(v))
-		return sv
+	cb := func(path *Path, props Properties) string {
+		return evalReply(evalcb(props))
 	}
 
-	err := gad.ws.RegisterEval(s, cb)
-	gad.evals = append(gad.evals, s)
-	return err
+	return gad.registerEval(gad.context(), gad.ws, s, cb)
+}
+
+// AddAgentEval ...
+func (gad *GAD) AddAgentEval(sysid string, tenantid string, nodeid string, funcname string, evalcb func(Properties) interface{}) error {
+	s, err := gad.GetAgentExecPath(sysid, tenantid, nodeid, funcname)
+	if err != nil {
+		return err
+	}
+
+	cb := func(path *Path, props Properties) string {
+		return evalReply(evalcb(props))
+	}
+
+	return gad.registerEval(gad.context(), gad.ws, s, cb)
 }
 
 // ObserveNodePlugins ...
-func (gad *GAD) ObserveNodePlugins(sysid string, tenantid string, nodeid string, listener func(Plugin)) (*yaks.SubscriptionID, error) {
-	s := gad.GetNodePluginsSelector(sysid, tenantid, nodeid)
+func (gad *GAD) ObserveNodePlugins(sysid string, tenantid string, nodeid string, listener func(Plugin)) (*SubscriptionID, error) {
+	s, err := gad.GetNodePluginsSelector(sysid, tenantid, nodeid)
+	if err != nil {
+		return nil, err
+	}
 
-	cb := func(kvs []yaks.Change) {
-		if len(kvs) > 0 {
-			v := kvs[0].Value().ToString()
+	cb := func(kvs []Change) {
+		if len(kvs) > 0 && kvs[0].Kind != REMOVE {
+			v := kvs[0].Value
 			sv := Plugin{}
-			err := json.Unmarshal([]byte(v), &sv)
+			err := decode(v, &sv)
 			if err != nil {
-				panic(err.Error())
+				gad.handleError(err)
+				return
 			}
 			listener(sv)
 		}
 	}
 
-	sid, err := gad.ws.Subscribe(s, cb)
-	if err != nil {
-		return nil, err
-	}
-	gad.listeners = append(gad.listeners, sid)
-	return sid, nil
+	return gad.subscribe(gad.context(), gad.ws, s, cb)
 }
 
 // Network
 
 // GetNetworkPort ...
 func (gad *GAD) GetNetworkPort(sysid string, tenantid string, portid string) (*ConnectionPointDescriptor, error) {
-	s, _ := yaks.NewSelector(gad.GetNetworkPortInfoPath(sysid, tenantid, portid).ToString())
-	kvs := gad.ws.Get(s)
+	s, err := toSelector(gad.GetNetworkPortInfoPath(sysid, tenantid, portid))
+	if err != nil {
+		return nil, err
+	}
+	kvs, err := gad.ws.Get(gad.context(), s)
+	if err != nil {
+		return nil, err
+	}
 	if len(kvs) == 0 {
-		return nil, &FError{"Network Port not found", nil}
+		return nil, &FError{"Network Port not found", ErrNotFound}
 	}
-	v := kvs[0].Value().ToString()
+	v := kvs[0].Value
 	sv := ConnectionPointDescriptor{}
-	err := json.Unmarshal([]byte(v), &sv)
+	err = decode(v, &sv)
 	if err != nil {
 		return nil, err
 	}
@@ -1131,49 +1591,74 @@ func (gad *GAD) GetNetworkPort(sysid string, tenantid string, portid string) (*C
 
 // AddNetworkPort ...
 func (gad *GAD) AddNetworkPort(sysid string, tenantid string, portid string, info ConnectionPointDescriptor) error {
-	s := gad.GetNetworkPortInfoPath(sysid, tenantid, portid)
+	s, err := gad.GetNetworkPortInfoPath(sysid, tenantid, portid)
+	if err != nil {
+		return err
+	}
 	v, err := json.Marshal(info)
 	if err != nil {
 		return err
 	}
-	sv := yaks.NewStringValue(string(v))
-	err = gad.ws.Put(s, sv)
+	err = gad.ws.Put(gad.context(), s, string(v))
 	return err
 }
 
 // RemoveNetworkPort ...
 func (gad *GAD) RemoveNetworkPort(sysid string, tenantid string, portid string) error {
-	s := gad.GetNetworkPortInfoPath(sysid, tenantid, portid)
-	err := gad.ws.Remove(s)
+	s, err := gad.GetNetworkPortInfoPath(sysid, tenantid, portid)
+	if err != nil {
+		return err
+	}
+	err = gad.ws.Remove(gad.context(), s)
 	return err
 }
 
 // GetAllNetworkPorts ...
 func (gad *GAD) GetAllNetworkPorts(sysid string, tenantid string) ([]Couple, error) {
-	s := gad.GetAllPortsSelector(sysid, tenantid)
-	kvs := gad.ws.Get(s)
+	s, err := gad.GetAllPortsSelector(sysid, tenantid)
+	if err != nil {
+		return nil, err
+	}
+	kvs, err := gad.ws.Get(gad.context(), s)
+	if err != nil {
+		return nil, err
+	}
 	var ids []Couple = [](Couple){}
 	if len(kvs) == 0 {
 		return ids, nil
 	}
 
 	for _, kv := range kvs {
-		p := kv.Path()
-		ids = append(ids, Couple{gad.ExtractNodeIDFromPath(p), gad.ExtractPortIDFromPath(p)})
+		p := kv.Path
+		nodeid, err := gad.ExtractNodeIDFromPath(p)
+		if err != nil {
+			return nil, err
+		}
+		id, err := gad.ExtractPortIDFromPath(p)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, Couple{nodeid, id})
 	}
 	return ids, nil
 }
 
 // GetNetworkRouter ...
 func (gad *GAD) GetNetworkRouter(sysid string, tenantid string, portid string) (*RouterDescriptor, error) {
-	s, _ := yaks.NewSelector(gad.GetNetworkPortInfoPath(sysid, tenantid, portid).ToString())
-	kvs := gad.ws.Get(s)
+	s, err := toSelector(gad.GetNetworkPortInfoPath(sysid, tenantid, portid))
+	if err != nil {
+		return nil, err
+	}
+	kvs, err := gad.ws.Get(gad.context(), s)
+	if err != nil {
+		return nil, err
+	}
 	if len(kvs) == 0 {
-		return nil, &FError{"Network Router not found", nil}
+		return nil, &FError{"Network Router not found", ErrNotFound}
 	}
-	v := kvs[0].Value().ToString()
+	v := kvs[0].Value
 	sv := RouterDescriptor{}
-	err := json.Unmarshal([]byte(v), &sv)
+	err = decode(v, &sv)
 	if err != nil {
 		return nil, err
 	}
@@ -1182,27 +1667,38 @@ func (gad *GAD) GetNetworkRouter(sysid string, tenantid string, portid string) (
 
 // AddNetWorkRouter ...
 func (gad *GAD) AddNetWorkRouter(sysid string, tenantid string, routerid string, info RouterDescriptor) error {
-	s := gad.GetNetworkPortInfoPath(sysid, tenantid, routerid)
+	s, err := gad.GetNetworkPortInfoPath(sysid, tenantid, routerid)
+	if err != nil {
+		return err
+	}
 	v, err := json.Marshal(info)
 	if err != nil {
 		return err
 	}
-	sv := yaks.NewStringValue(string(v))
-	err = gad.ws.Put(s, sv)
+	err = gad.ws.Put(gad.context(), s, string(v))
 	return err
 }
 
 // RemoveNetworkRouter ...
 func (gad *GAD) RemoveNetworkRouter(sysid string, tenantid string, routerid string) error {
-	s := gad.GetNetworkPortInfoPath(sysid, tenantid, routerid)
-	err := gad.ws.Remove(s)
+	s, err := gad.GetNetworkPortInfoPath(sysid, tenantid, routerid)
+	if err != nil {
+		return err
+	}
+	err = gad.ws.Remove(gad.context(), s)
 	return err
 }
 
 // GetAllNetworkRouters ...
 func (gad *GAD) GetAllNetworkRouters(sysid string, tenantid string) ([]Couple, error) {
-	s := gad.GetAllRoutersSelector(sysid, tenantid)
-	kvs := gad.ws.Get(s)
+	s, err := gad.GetAllRoutersSelector(sysid, tenantid)
+	if err != nil {
+		return nil, err
+	}
+	kvs, err := gad.ws.Get(gad.context(), s)
+	if err != nil {
+		return nil, err
+	}
 	var ids []Couple = []Couple{}
 
 	if len(kvs) == 0 {
@@ -1210,22 +1706,36 @@ func (gad *GAD) GetAllNetworkRouters(sysid string, tenantid string) ([]Couple, e
 	}
 
 	for _, kv := range kvs {
-		p := kv.Path()
-		ids = append(ids, Couple{gad.ExtractNodeIDFromPath(p), gad.ExtractPortIDFromPath(p)})
+		p := kv.Path
+		nodeid, err := gad.ExtractNodeIDFromPath(p)
+		if err != nil {
+			return nil, err
+		}
+		id, err := gad.ExtractPortIDFromPath(p)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, Couple{nodeid, id})
 	}
 	return ids, nil
 }
 
 // GetNetwork ...
 func (gad *GAD) GetNetwork(sysid string, tenantid string, netid string) (*VirtualNetwork, error) {
-	s, _ := yaks.NewSelector(gad.GetNetworkInfoPath(sysid, tenantid, netid).ToString())
-	kvs := gad.ws.Get(s)
+	s, err := toSelector(gad.GetNetworkInfoPath(sysid, tenantid, netid))
+	if err != nil {
+		return nil, err
+	}
+	kvs, err := gad.ws.Get(gad.context(), s)
+	if err != nil {
+		return nil, err
+	}
 	if len(kvs) == 0 {
-		return nil, &FError{"Network not found", nil}
+		return nil, &FError{"Network not found", ErrNotFound}
 	}
-	v := kvs[0].Value().ToString()
+	v := kvs[0].Value
 	sv := VirtualNetwork{}
-	err := json.Unmarshal([]byte(v), &sv)
+	err = decode(v, &sv)
 	if err != nil {
 		return nil, err
 	}
@@ -1234,34 +1744,49 @@ func (gad *GAD) GetNetwork(sysid string, tenantid string, netid string) (*Virtua
 
 // AddNetwork ...
 func (gad *GAD) AddNetwork(sysid string, tenantid string, netid string, info VirtualNetwork) error {
-	s := gad.GetNetworkInfoPath(sysid, tenantid, netid)
+	s, err := gad.GetNetworkInfoPath(sysid, tenantid, netid)
+	if err != nil {
+		return err
+	}
 	v, err := json.Marshal(info)
 	if err != nil {
 		return err
 	}
-	sv := yaks.NewStringValue(string(v))
-	err = gad.ws.Put(s, sv)
+	err = gad.ws.Put(gad.context(), s, string(v))
 	return err
 }
 
 // RemoveNetwork ...
 func (gad *GAD) RemoveNetwork(sysid string, tenantid string, netid string) error {
-	s := gad.GetNetworkInfoPath(sysid, tenantid, netid)
-	err := gad.ws.Remove(s)
+	s, err := gad.GetNetworkInfoPath(sysid, tenantid, netid)
+	if err != nil {
+		return err
+	}
+	err = gad.ws.Remove(gad.context(), s)
 	return err
 }
 
 // GetAllNetwork ...
 func (gad *GAD) GetAllNetwork(sysid string, tenantid string) ([]string, error) {
-	s := gad.GetAllNetworksSelector(sysid, tenantid)
-	kvs := gad.ws.Get(s)
+	s, err := gad.GetAllNetworksSelector(sysid, tenantid)
+	if err != nil {
+		return nil, err
+	}
+	kvs, err := gad.ws.Get(gad.context(), s)
+	if err != nil {
+		return nil, err
+	}
 	if len(kvs) == 0 {
 		return []string{}, nil
 	}
 	var ids []string = []string{}
 	for _, kv := range kvs {
-		p := kv.Path()
-		ids = append(ids, gad.ExtractNetworkIDFromPath(p))
+		p := kv.Path
+		id, err := gad.ExtractNetworkIDFromPath(p)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
 	}
 	return ids, nil
 }
@@ -1270,14 +1795,20 @@ func (gad *GAD) GetAllNetwork(sysid string, tenantid string) ([]string, error) {
 
 // GetImage ...
 func (gad *GAD) GetImage(sysid string, tenantid string, imageid string) (*FDUImage, error) {
-	s, _ := yaks.NewSelector(gad.GetImageInfoPath(sysid, tenantid, imageid).ToString())
-	kvs := gad.ws.Get(s)
+	s, err := toSelector(gad.GetImageInfoPath(sysid, tenantid, imageid))
+	if err != nil {
+		return nil, err
+	}
+	kvs, err := gad.ws.Get(gad.context(), s)
+	if err != nil {
+		return nil, err
+	}
 	if len(kvs) == 0 {
-		return nil, &FError{"Image not found", nil}
+		return nil, &FError{"Image not found", ErrNotFound}
 	}
-	v := kvs[0].Value().ToString()
+	v := kvs[0].Value
 	sv := FDUImage{}
-	err := json.Unmarshal([]byte(v), &sv)
+	err = decode(v, &sv)
 	if err != nil {
 		return nil, err
 	}
@@ -1286,34 +1817,49 @@ func (gad *GAD) GetImage(sysid string, tenantid string, imageid string) (*FDUIma
 
 // AddImage ...
 func (gad *GAD) AddImage(sysid string, tenantid string, imageid string, info FDUImage) error {
-	s := gad.GetImageInfoPath(sysid, tenantid, imageid)
+	s, err := gad.GetImageInfoPath(sysid, tenantid, imageid)
+	if err != nil {
+		return err
+	}
 	v, err := json.Marshal(info)
 	if err != nil {
 		return err
 	}
-	sv := yaks.NewStringValue(string(v))
-	err = gad.ws.Put(s, sv)
+	err = gad.ws.Put(gad.context(), s, string(v))
 	return err
 }
 
 // RemoveImage ...
 func (gad *GAD) RemoveImage(sysid string, tenantid string, imageid string) error {
-	s := gad.GetImageInfoPath(sysid, tenantid, imageid)
-	err := gad.ws.Remove(s)
+	s, err := gad.GetImageInfoPath(sysid, tenantid, imageid)
+	if err != nil {
+		return err
+	}
+	err = gad.ws.Remove(gad.context(), s)
 	return err
 }
 
 // GetAllImages ...
 func (gad *GAD) GetAllImages(sysid string, tenantid string) ([]string, error) {
-	s := gad.GetAllImageSelector(sysid, tenantid)
-	kvs := gad.ws.Get(s)
+	s, err := gad.GetAllImageSelector(sysid, tenantid)
+	if err != nil {
+		return nil, err
+	}
+	kvs, err := gad.ws.Get(gad.context(), s)
+	if err != nil {
+		return nil, err
+	}
 	if len(kvs) == 0 {
 		return []string{}, nil
 	}
 	var ids []string = []string{}
 	for _, kv := range kvs {
-		p := kv.Path()
-		ids = append(ids, gad.ExtractImageIDFromPath(p))
+		p := kv.Path
+		id, err := gad.ExtractImageIDFromPath(p)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
 	}
 	return ids, nil
 }
@@ -1322,14 +1868,20 @@ func (gad *GAD) GetAllImages(sysid string, tenantid string) ([]string, error) {
 
 // GetNodeImage ...
 func (gad *GAD) GetNodeImage(sysid string, tenantid string, nodeid string, imageid string) (*FDUImage, error) {
-	s, _ := yaks.NewSelector(gad.GetNodeImageInfoPath(sysid, tenantid, nodeid, imageid).ToString())
-	kvs := gad.ws.Get(s)
+	s, err := toSelector(gad.GetNodeImageInfoPath(sysid, tenantid, nodeid, imageid))
+	if err != nil {
+		return nil, err
+	}
+	kvs, err := gad.ws.Get(gad.context(), s)
+	if err != nil {
+		return nil, err
+	}
 	if len(kvs) == 0 {
-		return nil, &FError{"Image not found", nil}
+		return nil, &FError{"Image not found", ErrNotFound}
 	}
-	v := kvs[0].Value().ToString()
+	v := kvs[0].Value
 	sv := FDUImage{}
-	err := json.Unmarshal([]byte(v), &sv)
+	err = decode(v, &sv)
 	if err != nil {
 		return nil, err
 	}
@@ -1338,34 +1890,49 @@ func (gad *GAD) GetNodeImage(sysid string, tenantid string, nodeid string, image
 
 // AddNodeImage ...
 func (gad *GAD) AddNodeImage(sysid string, tenantid string, nodeid string, imageid string, info FDUImage) error {
-	s := gad.GetNodeImageInfoPath(sysid, tenantid, nodeid, imageid)
+	s, err := gad.GetNodeImageInfoPath(sysid, tenantid, nodeid, imageid)
+	if err != nil {
+		return err
+	}
 	v, err := json.Marshal(info)
 	if err != nil {
 		return err
 	}
-	sv := yaks.NewStringValue(string(v))
-	err = gad.ws.Put(s, sv)
+	err = gad.ws.Put(gad.context(), s, string(v))
 	return err
 }
 
 // RemoveNodeImage ...
 func (gad *GAD) RemoveNodeImage(sysid string, tenantid string, nodeid string, imageid string) error {
-	s := gad.GetNodeImageInfoPath(sysid, tenantid, nodeid, imageid)
-	err := gad.ws.Remove(s)
+	s, err := gad.GetNodeImageInfoPath(sysid, tenantid, nodeid, imageid)
+	if err != nil {
+		return err
+	}
+	err = gad.ws.Remove(gad.context(), s)
 	return err
 }
 
 // GetNodeAllImages ...
 func (gad *GAD) GetNodeAllImages(sysid string, tenantid string, nodeid string) ([]string, error) {
-	s := gad.GetAllNodeImageSelector(sysid, tenantid, nodeid)
-	kvs := gad.ws.Get(s)
+	s, err := gad.GetAllNodeImageSelector(sysid, tenantid, nodeid)
+	if err != nil {
+		return nil, err
+	}
+	kvs, err := gad.ws.Get(gad.context(), s)
+	if err != nil {
+		return nil, err
+	}
 	if len(kvs) == 0 {
 		return []string{}, nil
 	}
 	var ids []string = []string{}
 	for _, kv := range kvs {
-		p := kv.Path()
-		ids = append(ids, gad.ExtractNodeImageIDFromPath(p))
+		p := kv.Path
+		id, err := gad.ExtractNodeImageIDFromPath(p)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
 	}
 	return ids, nil
 }
@@ -1374,14 +1941,20 @@ func (gad *GAD) GetNodeAllImages(sysid string, tenantid string, nodeid string) (
 
 // GetFlavor ...
 func (gad *GAD) GetFlavor(sysid string, tenantid string, flvid string) (*FDUComputationalRequirements, error) {
-	s, _ := yaks.NewSelector(gad.GetFlavorInfoPath(sysid, tenantid, flvid).ToString())
-	kvs := gad.ws.Get(s)
+	s, err := toSelector(gad.GetFlavorInfoPath(sysid, tenantid, flvid))
+	if err != nil {
+		return nil, err
+	}
+	kvs, err := gad.ws.Get(gad.context(), s)
+	if err != nil {
+		return nil, err
+	}
 	if len(kvs) == 0 {
-		return nil, &FError{"Flavor not found", nil}
+		return nil, &FError{"Flavor not found", ErrNotFound}
 	}
-	v := kvs[0].Value().ToString()
+	v := kvs[0].Value
 	sv := FDUComputationalRequirements{}
-	err := json.Unmarshal([]byte(v), &sv)
+	err = decode(v, &sv)
 	if err != nil {
 		return nil, err
 	}
@@ -1390,34 +1963,49 @@ func (gad *GAD) GetFlavor(sysid string, tenantid string, flvid string) (*FDUComp
 
 // AddFlavor ...
 func (gad *GAD) AddFlavor(sysid string, tenantid string, flvid string, info FDUComputationalRequirements) error {
-	s := gad.GetFlavorInfoPath(sysid, tenantid, flvid)
+	s, err := gad.GetFlavorInfoPath(sysid, tenantid, flvid)
+	if err != nil {
+		return err
+	}
 	v, err := json.Marshal(info)
 	if err != nil {
 		return err
 	}
-	sv := yaks.NewStringValue(string(v))
-	err = gad.ws.Put(s, sv)
+	err = gad.ws.Put(gad.context(), s, string(v))
 	return err
 }
 
 // RemoveFlavor ...
 func (gad *GAD) RemoveFlavor(sysid string, tenantid string, flvid string) error {
-	s := gad.GetFlavorInfoPath(sysid, tenantid, flvid)
-	err := gad.ws.Remove(s)
+	s, err := gad.GetFlavorInfoPath(sysid, tenantid, flvid)
+	if err != nil {
+		return err
+	}
+	err = gad.ws.Remove(gad.context(), s)
 	return err
 }
 
 // GetAllFlavors ...
 func (gad *GAD) GetAllFlavors(sysid string, tenantid string) ([]string, error) {
-	s := gad.GetAllFlavorSelector(sysid, tenantid)
-	kvs := gad.ws.Get(s)
+	s, err := gad.GetAllFlavorSelector(sysid, tenantid)
+	if err != nil {
+		return nil, err
+	}
+	kvs, err := gad.ws.Get(gad.context(), s)
+	if err != nil {
+		return nil, err
+	}
 	if len(kvs) == 0 {
 		return []string{}, nil
 	}
 	var ids []string = []string{}
 	for _, kv := range kvs {
-		p := kv.Path()
-		ids = append(ids, gad.ExtractFlavorIDFromPath(p))
+		p := kv.Path
+		id, err := gad.ExtractFlavorIDFromPath(p)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
 	}
 	return ids, nil
 }
@@ -1426,14 +2014,20 @@ func (gad *GAD) GetAllFlavors(sysid string, tenantid string) ([]string, error) {
 
 // GetNodeFlavor ...
 func (gad *GAD) GetNodeFlavor(sysid string, tenantid string, nodeid string, flvid string) (*FDUComputationalRequirements, error) {
-	s, _ := yaks.NewSelector(gad.GetNodeFlavorInfoPath(sysid, tenantid, nodeid, flvid).ToString())
-	kvs := gad.ws.Get(s)
+	s, err := toSelector(gad.GetNodeFlavorInfoPath(sysid, tenantid, nodeid, flvid))
+	if err != nil {
+		return nil, err
+	}
+	kvs, err := gad.ws.Get(gad.context(), s)
+	if err != nil {
+		return nil, err
+	}
 	if len(kvs) == 0 {
-		return nil, &FError{"Flavort not found", nil}
+		return nil, &FError{"Flavort not found", ErrNotFound}
 	}
-	v := kvs[0].Value().ToString()
+	v := kvs[0].Value
 	sv := FDUComputationalRequirements{}
-	err := json.Unmarshal([]byte(v), &sv)
+	err = decode(v, &sv)
 	if err != nil {
 		return nil, err
 	}
@@ -1442,34 +2036,49 @@ func (gad *GAD) GetNodeFlavor(sysid string, tenantid string, nodeid string, flvi
 
 // AddNodeFlavor ...
 func (gad *GAD) AddNodeFlavor(sysid string, tenantid string, nodeid string, flvid string, info FDUComputationalRequirements) error {
-	s := gad.GetNodeFlavorInfoPath(sysid, tenantid, nodeid, flvid)
+	s, err := gad.GetNodeFlavorInfoPath(sysid, tenantid, nodeid, flvid)
+	if err != nil {
+		return err
+	}
 	v, err := json.Marshal(info)
 	if err != nil {
 		return err
 	}
-	sv := yaks.NewStringValue(string(v))
-	err = gad.ws.Put(s, sv)
+	err = gad.ws.Put(gad.context(), s, string(v))
 	return err
 }
 
 // RemoveNodeFlavor ...
 func (gad *GAD) RemoveNodeFlavor(sysid string, tenantid string, nodeid string, flvid string) error {
-	s := gad.GetNodeFlavorInfoPath(sysid, tenantid, nodeid, flvid)
-	err := gad.ws.Remove(s)
+	s, err := gad.GetNodeFlavorInfoPath(sysid, tenantid, nodeid, flvid)
+	if err != nil {
+		return err
+	}
+	err = gad.ws.Remove(gad.context(), s)
 	return err
 }
 
 // GetNodeAllFlavors ...
 func (gad *GAD) GetNodeAllFlavors(sysid string, tenantid string, nodeid string) ([]string, error) {
-	s := gad.GetAllNodeFlavorSelector(sysid, tenantid, nodeid)
-	kvs := gad.ws.Get(s)
+	s, err := gad.GetAllNodeFlavorSelector(sysid, tenantid, nodeid)
+	if err != nil {
+		return nil, err
+	}
+	kvs, err := gad.ws.Get(gad.context(), s)
+	if err != nil {
+		return nil, err
+	}
 	if len(kvs) == 0 {
 		return []string{}, nil
 	}
 	var ids []string = []string{}
 	for _, kv := range kvs {
-		p := kv.Path()
-		ids = append(ids, gad.ExtractNodeFlavorIDFromPath(p))
+		p := kv.Path
+		id, err := gad.ExtractNodeFlavorIDFromPath(p)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
 	}
 	return ids, nil
 }
@@ -1478,14 +2087,20 @@ func (gad *GAD) GetNodeAllFlavors(sysid string, tenantid string, nodeid string)
 
 // GetNodeNetwork ...
 func (gad *GAD) GetNodeNetwork(sysid string, tenantid string, nodeid string, netid string) (*VirtualNetwork, error) {
-	s, _ := yaks.NewSelector(gad.GetNodeNetworkInfoPath(sysid, tenantid, nodeid, netid).ToString())
-	kvs := gad.ws.Get(s)
+	s, err := toSelector(gad.GetNodeNetworkInfoPath(sysid, tenantid, nodeid, netid))
+	if err != nil {
+		return nil, err
+	}
+	kvs, err := gad.ws.Get(gad.context(), s)
+	if err != nil {
+		return nil, err
+	}
 	if len(kvs) == 0 {
-		return nil, &FError{"Network not found", nil}
+		return nil, &FError{"Network not found", ErrNotFound}
 	}
-	v := kvs[0].Value().ToString()
+	v := kvs[0].Value
 	sv := VirtualNetwork{}
-	err := json.Unmarshal([]byte(v), &sv)
+	err = decode(v, &sv)
 	if err != nil {
 		return nil, err
 	}
@@ -1494,48 +2109,69 @@ func (gad *GAD) GetNodeNetwork(sysid string, tenantid string, nodeid string, net
 
 // AddNodeNetwork ...
 func (gad *GAD) AddNodeNetwork(sysid string, tenantid string, nodeid string, netid string, info VirtualNetwork) error {
-	s := gad.GetNodeNetworkInfoPath(sysid, tenantid, nodeid, netid)
+	s, err := gad.GetNodeNetworkInfoPath(sysid, tenantid, nodeid, netid)
+	if err != nil {
+		return err
+	}
 	v, err := json.Marshal(info)
 	if err != nil {
 		return err
 	}
-	sv := yaks.NewStringValue(string(v))
-	err = gad.ws.Put(s, sv)
+	err = gad.ws.Put(gad.context(), s, string(v))
 	return err
 }
 
 // RemoveNodeNetwork ...
 func (gad *GAD) RemoveNodeNetwork(sysid string, tenantid string, nodeid string, netid string) error {
-	s := gad.GetNodeNetworkInfoPath(sysid, tenantid, nodeid, netid)
-	err := gad.ws.Remove(s)
+	s, err := gad.GetNodeNetworkInfoPath(sysid, tenantid, nodeid, netid)
+	if err != nil {
+		return err
+	}
+	err = gad.ws.Remove(gad.context(), s)
 	return err
 }
 
 // GetNodeAllNetworks ...
 func (gad *GAD) GetNodeAllNetworks(sysid string, tenantid string, nodeid string) ([]string, error) {
-	s := gad.GetNodeNetworSelector(sysid, tenantid, nodeid)
-	kvs := gad.ws.Get(s)
+	s, err := gad.GetNodeNetworSelector(sysid, tenantid, nodeid)
+	if err != nil {
+		return nil, err
+	}
+	kvs, err := gad.ws.Get(gad.context(), s)
+	if err != nil {
+		return nil, err
+	}
 	if len(kvs) == 0 {
 		return []string{}, nil
 	}
 	var ids []string = []string{}
 	for _, kv := range kvs {
-		p := kv.Path()
-		ids = append(ids, gad.ExtractNodeNetworkIDFromPath(p))
+		p := kv.Path
+		id, err := gad.ExtractNodeNetworkIDFromPath(p)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
 	}
 	return ids, nil
 }
 
 // GetNodeFlatingIP ...
 func (gad *GAD) GetNodeFlatingIP(sysid string, tenantid string, nodeid string, floatingid string) (*FloatingIPRecord, error) {
-	s, _ := yaks.NewSelector(gad.GetNodeNetworkFloatingIPInfoPath(sysid, tenantid, nodeid, floatingid).ToString())
-	kvs := gad.ws.Get(s)
+	s, err := toSelector(gad.GetNodeNetworkFloatingIPInfoPath(sysid, tenantid, nodeid, floatingid))
+	if err != nil {
+		return nil, err
+	}
+	kvs, err := gad.ws.Get(gad.context(), s)
+	if err != nil {
+		return nil, err
+	}
 	if len(kvs) == 0 {
-		return nil, &FError{"Network Floating IP not found", nil}
+		return nil, &FError{"Network Floating IP not found", ErrNotFound}
 	}
-	v := kvs[0].Value().ToString()
+	v := kvs[0].Value
 	sv := FloatingIPRecord{}
-	err := json.Unmarshal([]byte(v), &sv)
+	err = decode(v, &sv)
 	if err != nil {
 		return nil, err
 	}
@@ -1544,48 +2180,69 @@ func (gad *GAD) GetNodeFlatingIP(sysid string, tenantid string, nodeid string, f
 
 // AddNodeFloatingIP ...
 func (gad *GAD) AddNodeFloatingIP(sysid string, tenantid string, nodeid string, floatingid string, info FloatingIPRecord) error {
-	s := gad.GetNodeNetworkFloatingIPInfoPath(sysid, tenantid, nodeid, floatingid)
-	v, err := json.Marshal(info)
+	s, err := gad.GetNodeNetworkFloatingIPInfoPath(sysid, tenantid, nodeid, floatingid)
 	if err != nil {
 		return err
 	}
-	sv := yaks.NewStringValue(string(v))
-	err = gad.ws.Put(s, sv)
+	v, err := json.Marshal(info)
+	if err != nil {
+		return err
+	}
+	err = gad.ws.Put(gad.context(), s, string(v))
 	return err
 }
 
 // RemoveNodeFloatingIP ...
 func (gad *GAD) RemoveNodeFloatingIP(sysid string, tenantid string, nodeid string, floatingid string) error {
-	s := gad.GetNodeNetworkFloatingIPInfoPath(sysid, tenantid, nodeid, floatingid)
-	err := gad.ws.Remove(s)
+	s, err := gad.GetNodeNetworkFloatingIPInfoPath(sysid, tenantid, nodeid, floatingid)
+	if err != nil {
+		return err
+	}
+	err = gad.ws.Remove(gad.context(), s)
 	return err
 }
 
 // GetNodeAllFlatingIPs ...
 func (gad *GAD) GetNodeAllFlatingIPs(sysid string, tenantid string, nodeid string) ([]string, error) {
-	s := gad.GetNodeAllNetworkFloatingIPsSelector(sysid, tenantid, nodeid)
-	kvs := gad.ws.Get(s)
+	s, err := gad.GetNodeAllNetworkFloatingIPsSelector(sysid, tenantid, nodeid)
+	if err != nil {
+		return nil, err
+	}
+	kvs, err := gad.ws.Get(gad.context(), s)
+	if err != nil {
+		return nil, err
+	}
 	if len(kvs) == 0 {
 		return []string{}, nil
 	}
 	var ids []string = []string{}
 	for _, kv := range kvs {
-		p := kv.Path()
-		ids = append(ids, gad.ExtractNodeFloatingIDFromPath(p))
+		p := kv.Path
+		id, err := gad.ExtractNodeFloatingIDFromPath(p)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
 	}
 	return ids, nil
 }
 
 // GetNodeNetworkPort ...
 func (gad *GAD) GetNodeNetworkPort(sysid string, tenantid string, nodeid string, portid string) (*ConnectionPointRecord, error) {
-	s, _ := yaks.NewSelector(gad.GetNodeNetworkPortInfoPath(sysid, tenantid, nodeid, portid).ToString())
-	kvs := gad.ws.Get(s)
+	s, err := toSelector(gad.GetNodeNetworkPortInfoPath(sysid, tenantid, nodeid, portid))
+	if err != nil {
+		return nil, err
+	}
+	kvs, err := gad.ws.Get(gad.context(), s)
+	if err != nil {
+		return nil, err
+	}
 	if len(kvs) == 0 {
-		return nil, &FError{"Network Port not found", nil}
+		return nil, &FError{"Network Port not found", ErrNotFound}
 	}
-	v := kvs[0].Value().ToString()
+	v := kvs[0].Value
 	sv := ConnectionPointRecord{}
-	err := json.Unmarshal([]byte(v), &sv)
+	err = decode(v, &sv)
 	if err != nil {
 		return nil, err
 	}
@@ -1594,48 +2251,69 @@ func (gad *GAD) GetNodeNetworkPort(sysid string, tenantid string, nodeid string,
 
 // AddNodeNetworkPort ...
 func (gad *GAD) AddNodeNetworkPort(sysid string, tenantid string, nodeid string, portid string, info ConnectionPointRecord) error {
-	s := gad.GetNodeNetworkPortInfoPath(sysid, tenantid, nodeid, portid)
+	s, err := gad.GetNodeNetworkPortInfoPath(sysid, tenantid, nodeid, portid)
+	if err != nil {
+		return err
+	}
 	v, err := json.Marshal(info)
 	if err != nil {
 		return err
 	}
-	sv := yaks.NewStringValue(string(v))
-	err = gad.ws.Put(s, sv)
+	err = gad.ws.Put(gad.context(), s, string(v))
 	return err
 }
 
 // RemoveNodeNetworkPort ...
 func (gad *GAD) RemoveNodeNetworkPort(sysid string, tenantid string, nodeid string, portid string) error {
-	s := gad.GetNodeNetworkPortInfoPath(sysid, tenantid, nodeid, portid)
-	err := gad.ws.Remove(s)
+	s, err := gad.GetNodeNetworkPortInfoPath(sysid, tenantid, nodeid, portid)
+	if err != nil {
+		return err
+	}
+	err = gad.ws.Remove(gad.context(), s)
 	return err
 }
 
 // GetNodeAllNetworkPorts ...
 func (gad *GAD) GetNodeAllNetworkPorts(sysid string, tenantid string, nodeid string) ([]string, error) {
-	s := gad.GetNodeNetworkPortsSelector(sysid, tenantid, nodeid)
-	kvs := gad.ws.Get(s)
+	s, err := gad.GetNodeNetworkPortsSelector(sysid, tenantid, nodeid)
+	if err != nil {
+		return nil, err
+	}
+	kvs, err := gad.ws.Get(gad.context(), s)
+	if err != nil {
+		return nil, err
+	}
 	if len(kvs) == 0 {
 		return []string{}, nil
 	}
 	var ids []string = []string{}
 	for _, kv := range kvs {
-		p := kv.Path()
-		ids = append(ids, gad.ExtractNodePortIDFromPath(p))
+		p := kv.Path
+		id, err := gad.ExtractNodePortIDFromPath(p)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
 	}
 	return ids, nil
 }
 
 // GetNodeNetworkRouter ...
 func (gad *GAD) GetNodeNetworkRouter(sysid string, tenantid string, nodeid string, routerid string) (*RouterRecord, error) {
-	s, _ := yaks.NewSelector(gad.GetNodeNetworkRouterInfoPath(sysid, tenantid, nodeid, routerid).ToString())
-	kvs := gad.ws.Get(s)
+	s, err := toSelector(gad.GetNodeNetworkRouterInfoPath(sysid, tenantid, nodeid, routerid))
+	if err != nil {
+		return nil, err
+	}
+	kvs, err := gad.ws.Get(gad.context(), s)
+	if err != nil {
+		return nil, err
+	}
 	if len(kvs) == 0 {
-		return nil, &FError{"Network Router not found", nil}
+		return nil, &FError{"Network Router not found", ErrNotFound}
 	}
-	v := kvs[0].Value().ToString()
+	v := kvs[0].Value
 	sv := RouterRecord{}
-	err := json.Unmarshal([]byte(v), &sv)
+	err = decode(v, &sv)
 	if err != nil {
 		return nil, err
 	}
@@ -1644,64 +2322,94 @@ func (gad *GAD) GetNodeNetworkRouter(sysid string, tenantid string, nodeid strin
 
 // AddNodeNetworkRouter ...
 func (gad *GAD) AddNodeNetworkRouter(sysid string, tenantid string, nodeid string, routerid string, info RouterRecord) error {
-	s := gad.GetNodeNetworkRouterInfoPath(sysid, tenantid, nodeid, routerid)
+	s, err := gad.GetNodeNetworkRouterInfoPath(sysid, tenantid, nodeid, routerid)
+	if err != nil {
+		return err
+	}
 	v, err := json.Marshal(info)
 	if err != nil {
 		return err
 	}
-	sv := yaks.NewStringValue(string(v))
-	err = gad.ws.Put(s, sv)
+	err = gad.ws.Put(gad.context(), s, string(v))
 	return err
 }
 
 // RemoveNodeNetworkRouter ...
 func (gad *GAD) RemoveNodeNetworkRouter(sysid string, tenantid string, nodeid string, routerid string) error {
-	s := gad.GetNodeNetworkRouterInfoPath(sysid, tenantid, nodeid, routerid)
-	err := gad.ws.Remove(s)
+	s, err := gad.GetNodeNetworkRouterInfoPath(sysid, tenantid, nodeid, routerid)
+	if err != nil {
+		return err
+	}
+	err = gad.ws.Remove(gad.context(), s)
 	return err
 }
 
 // GetNodeAllNetworkRouters ...
 func (gad *GAD) GetNodeAllNetworkRouters(sysid string, tenantid string, nodeid string) ([]string, error) {
-	s := gad.GetNodeNetworkRoutersSelector(sysid, tenantid, nodeid)
-	kvs := gad.ws.Get(s)
+	s, err := gad.GetNodeNetworkRoutersSelector(sysid, tenantid, nodeid)
+	if err != nil {
+		return nil, err
+	}
+	kvs, err := gad.ws.Get(gad.context(), s)
+	if err != nil {
+		return nil, err
+	}
 	if len(kvs) == 0 {
 		return []string{}, nil
 	}
 	var ids []string = []string{}
 	for _, kv := range kvs {
-		p := kv.Path()
-		ids = append(ids, gad.ExtractNodeRouterIDFromPath(p))
+		p := kv.Path
+		id, err := gad.ExtractNodeRouterIDFromPath(p)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
 	}
 	return ids, nil
 }
 
 // ObserveNodeNetworkRouters ...
-func (gad *GAD) ObserveNodeNetworkRouters(sysid string, tenantid string, nodeid string, listener func(RouterRecord)) (*yaks.SubscriptionID, error) {
-	s, _ := yaks.NewSelector(gad.GetNodeNetworkRoutersSelector(sysid, tenantid, nodeid).ToString())
+func (gad *GAD) ObserveNodeNetworkRouters(sysid string, tenantid string, nodeid string, listener func(RouterRecord)) (*SubscriptionID, error) {
+	s, err := gad.GetNodeNetworkRoutersSelector(sysid, tenantid, nodeid)
+	if err != nil {
+		return nil, err
+	}
 
-	cb := func(kvs []yaks.Change) {
-		if len(kvs) > 0 {
-			v := kvs[0].Value().ToString()
+	cb := func(kvs []Change) {
+		if len(kvs) > 0 && kvs[0].Kind != REMOVE {
+			v := kvs[0].Value
 			sv := RouterRecord{}
-			err := json.Unmarshal([]byte(v), &sv)
+			err := decode(v, &sv)
 			if err != nil {
-				panic(err.Error())
+				gad.handleError(err)
+				return
 			}
 			listener(sv)
 		}
 	}
 
-	sid, err := gad.ws.Subscribe(s, cb)
+	return gad.subscribe(gad.context(), gad.ws, s, cb)
+}
+
+// Agent Evals
+
+// EvalAgent evaluates the function of the node Agent with the given parameters and decodes its result in result, as Eval.
+// The other Agent evals return the result as an EvalResult
+func (gad *GAD) EvalAgent(sysid string, tenantid string, nodeid string, fname string, props map[string]interface{}, result interface{}) (*RawEvalResult, error) {
+	var s *Selector
+	var err error
+	if len(props) == 0 {
+		s, err = toSelector(gad.GetAgentExecPath(sysid, tenantid, nodeid, fname))
+	} else {
+		s, err = gad.GetAgentExecSelectorWithParams(sysid, tenantid, nodeid, fname, props)
+	}
 	if err != nil {
 		return nil, err
 	}
-	gad.listeners = append(gad.listeners, sid)
-	return sid, nil
+	return execEval(gad.context(), gad.ws, s, "EvalAgent", result)
 }
 
-// Agent Evals
-
 // AddNodePortToNetwork ...
 func (gad *GAD) AddNodePortToNetwork(sysid string, tenantid string, nodeid string, portid string, netid string) (*EvalResult, error) {
 
@@ -1711,57 +2419,12 @@ func (gad *GAD) AddNodePortToNetwork(sysid string, tenantid string, nodeid strin
 	params["cp_uuid"] = portid
 	params["network_uuid"] = netid
 
-	s, _ := yaks.NewSelector(gad.GetAgentExecSelectorWithParams(sysid, tenantid, nodeid, fname, params).ToString())
-
-	kvs := gad.ws.Get(s)
-	if len(kvs) == 0 {
-		return nil, &FError{"AddNodePortToNetwork function replied nil", nil}
-	}
-	v := kvs[0].Value().ToString()
-
-	var genericJSON map[string]interface{}
-	err := json.Unmarshal([]byte(v), &genericJSON)
+	s, err := gad.GetAgentExecSelectorWithParams(sysid, tenantid, nodeid, fname, params)
 	if err != nil {
 		return nil, err
 	}
 
-	switch t := genericJSON["result"].(type) {
-
-	case map[string]interface{}:
-		msi := genericJSON["result"].(map[string]interface{})
-		js, err := json.Marshal(msi)
-		if err != nil {
-			return nil, err
-		}
-		s := string(js)
-		genericJSON["result"] = s
-
-		evjs, err := json.Marshal(genericJSON)
-		if err != nil {
-			return nil, err
-		}
-		evs := string(evjs)
-
-		sv := EvalResult{}
-		err = json.Unmarshal([]byte(evs), &sv)
-		if err != nil {
-			return nil, err
-		}
-
-		return &sv, nil
-
-	case string:
-		sv := EvalResult{}
-		err = json.Unmarshal([]byte(v), &sv)
-		if err != nil {
-			return nil, err
-		}
-		return &sv, nil
-	default:
-		er := FError{"Unexpected type: " + t.(string), nil}
-		return nil, &er
-	}
-
+	return gad.evalResult(s, "AddNodePortToNetwork")
 }
 
 // RemoveNodePortFromNetwork ...
@@ -1772,57 +2435,12 @@ func (gad *GAD) RemoveNodePortFromNetwork(sysid string, tenantid string, nodeid
 
 	params["cp_uuid"] = portid
 
-	s, _ := yaks.NewSelector(gad.GetAgentExecSelectorWithParams(sysid, tenantid, nodeid, fname, params).ToString())
-
-	kvs := gad.ws.Get(s)
-	if len(kvs) == 0 {
-		return nil, &FError{"RemoveNodePortFromNetwork function replied nil", nil}
-	}
-	v := kvs[0].Value().ToString()
-
-	var genericJSON map[string]interface{}
-	err := json.Unmarshal([]byte(v), &genericJSON)
+	s, err := gad.GetAgentExecSelectorWithParams(sysid, tenantid, nodeid, fname, params)
 	if err != nil {
 		return nil, err
 	}
 
-	switch t := genericJSON["result"].(type) {
-
-	case map[string]interface{}:
-		msi := genericJSON["result"].(map[string]interface{})
-		js, err := json.Marshal(msi)
-		if err != nil {
-			return nil, err
-		}
-		s := string(js)
-		genericJSON["result"] = s
-
-		evjs, err := json.Marshal(genericJSON)
-		if err != nil {
-			return nil, err
-		}
-		evs := string(evjs)
-
-		sv := EvalResult{}
-		err = json.Unmarshal([]byte(evs), &sv)
-		if err != nil {
-			return nil, err
-		}
-
-		return &sv, nil
-
-	case string:
-		sv := EvalResult{}
-		err = json.Unmarshal([]byte(v), &sv)
-		if err != nil {
-			return nil, err
-		}
-		return &sv, nil
-	default:
-		er := FError{"Unexpected type: " + t.(string), nil}
-		return nil, &er
-	}
-
+	return gad.evalResult(s, "RemoveNodePortFromNetwork")
 }
 
 // CrateFloatingIPInNode ...
@@ -1830,57 +2448,12 @@ func (gad *GAD) CrateFloatingIPInNode(sysid string, tenantid string, nodeid stri
 
 	fname := "create_floating_ip"
 
-	s, _ := yaks.NewSelector(gad.GetAgentExecPath(sysid, tenantid, nodeid, fname).ToString())
-
-	kvs := gad.ws.Get(s)
-	if len(kvs) == 0 {
-		return nil, &FError{"CrateFloatingIPInNode function replied nil", nil}
-	}
-	v := kvs[0].Value().ToString()
-
-	var genericJSON map[string]interface{}
-	err := json.Unmarshal([]byte(v), &genericJSON)
+	s, err := toSelector(gad.GetAgentExecPath(sysid, tenantid, nodeid, fname))
 	if err != nil {
 		return nil, err
 	}
 
-	switch t := genericJSON["result"].(type) {
-
-	case map[string]interface{}:
-		msi := genericJSON["result"].(map[string]interface{})
-		js, err := json.Marshal(msi)
-		if err != nil {
-			return nil, err
-		}
-		s := string(js)
-		genericJSON["result"] = s
-
-		evjs, err := json.Marshal(genericJSON)
-		if err != nil {
-			return nil, err
-		}
-		evs := string(evjs)
-
-		sv := EvalResult{}
-		err = json.Unmarshal([]byte(evs), &sv)
-		if err != nil {
-			return nil, err
-		}
-
-		return &sv, nil
-
-	case string:
-		sv := EvalResult{}
-		err = json.Unmarshal([]byte(v), &sv)
-		if err != nil {
-			return nil, err
-		}
-		return &sv, nil
-	default:
-		er := FError{"Unexpected type: " + t.(string), nil}
-		return nil, &er
-	}
-
+	return gad.evalResult(s, "CrateFloatingIPInNode")
 }
 
 // RemoveFloatingIPFromNode ...
@@ -1891,119 +2464,29 @@ func (gad *GAD) RemoveFloatingIPFromNode(sysid string, tenantid string, nodeid s
 
 	params["floating_uuid"] = ipid
 
-	s, _ := yaks.NewSelector(gad.GetAgentExecSelectorWithParams(sysid, tenantid, nodeid, fname, params).ToString())
-
-	kvs := gad.ws.Get(s)
-	if len(kvs) == 0 {
-		return nil, &FError{"RemoveFloatingIPFromNode function replied nil", nil}
-	}
-	v := kvs[0].Value().ToString()
-
-	var genericJSON map[string]interface{}
-	err := json.Unmarshal([]byte(v), &genericJSON)
+	s, err := gad.GetAgentExecSelectorWithParams(sysid, tenantid, nodeid, fname, params)
 	if err != nil {
 		return nil, err
 	}
 
-	switch t := genericJSON["result"].(type) {
-
-	case map[string]interface{}:
-		msi := genericJSON["result"].(map[string]interface{})
-		js, err := json.Marshal(msi)
-		if err != nil {
-			return nil, err
-		}
-		s := string(js)
-		genericJSON["result"] = s
-
-		evjs, err := json.Marshal(genericJSON)
-		if err != nil {
-			return nil, err
-		}
-		evs := string(evjs)
-
-		sv := EvalResult{}
-		err = json.Unmarshal([]byte(evs), &sv)
-		if err != nil {
-			return nil, err
-		}
-
-		return &sv, nil
-
-	case string:
-		sv := EvalResult{}
-		err = json.Unmarshal([]byte(v), &sv)
-		if err != nil {
-			return nil, err
-		}
-		return &sv, nil
-	default:
-		er := FError{"Unexpected type: " + t.(string), nil}
-		return nil, &er
-	}
-
+	return gad.evalResult(s, "RemoveFloatingIPFromNode")
 }
 
 // AssignNodeFloatingIP ...
 func (gad *GAD) AssignNodeFloatingIP(sysid string, tenantid string, nodeid string, ipid string, cpid string) (*EvalResult, error) {
 
-	fname := "remove_floating_ip"
+	fname := "assign_floating_ip"
 	params := make(map[string]interface{})
 
 	params["floating_uuid"] = ipid
 	params["cp_uuid"] = cpid
 
-	s, _ := yaks.NewSelector(gad.GetAgentExecSelectorWithParams(sysid, tenantid, nodeid, fname, params).ToString())
-
-	kvs := gad.ws.Get(s)
-	if len(kvs) == 0 {
-		return nil, &FError{"AssignNodeFloatingIP function replied nil", nil}
-	}
-	v := kvs[0].Value().ToString()
-
-	var genericJSON map[string]interface{}
-	err := json.Unmarshal([]byte(v), &genericJSON)
+	s, err := gad.GetAgentExecSelectorWithParams(sysid, tenantid, nodeid, fname, params)
 	if err != nil {
 		return nil, err
 	}
 
-	switch t := genericJSON["result"].(type) {
-
-	case map[string]interface{}:
-		msi := genericJSON["result"].(map[string]interface{})
-		js, err := json.Marshal(msi)
-		if err != nil {
-			return nil, err
-		}
-		s := string(js)
-		genericJSON["result"] = s
-
-		evjs, err := json.Marshal(genericJSON)
-		if err != nil {
-			return nil, err
-		}
-		evs := string(evjs)
-
-		sv := EvalResult{}
-		err = json.Unmarshal([]byte(evs), &sv)
-		if err != nil {
-			return nil, err
-		}
-
-		return &sv, nil
-
-	case string:
-		sv := EvalResult{}
-		err = json.Unmarshal([]byte(v), &sv)
-		if err != nil {
-			return nil, err
-		}
-		return &sv, nil
-	default:
-		er := FError{"Unexpected type: " + t.(string), nil}
-		return nil, &er
-	}
-
+	return gad.evalResult(s, "AssignNodeFloatingIP")
 }
 
 // RetainNodeFloatingIP ...
@@ -2015,57 +2498,12 @@ func (gad *GAD) RetainNodeFloatingIP(sysid string, tenantid string, nodeid strin
 	params["floating_uuid"] = ipid
 	params["cp_uuid"] = cpid
 
-	s, _ := yaks.NewSelector(gad.GetAgentExecSelectorWithParams(sysid, tenantid, nodeid, fname, params).ToString())
-
-	kvs := gad.ws.Get(s)
-	if len(kvs) == 0 {
-		return nil, &FError{"RetainNodeFloatingIP function replied nil", nil}
-	}
-	v := kvs[0].Value().ToString()
-
-	var genericJSON map[string]interface{}
-	err := json.Unmarshal([]byte(v), &genericJSON)
+	s, err := gad.GetAgentExecSelectorWithParams(sysid, tenantid, nodeid, fname, params)
 	if err != nil {
 		return nil, err
 	}
 
-	switch t := genericJSON["result"].(type) {
-
-	case map[string]interface{}:
-		msi := genericJSON["result"].(map[string]interface{})
-		js, err := json.Marshal(msi)
-		if err != nil {
-			return nil, err
-		}
-		s := string(js)
-		genericJSON["result"] = s
-
-		evjs, err := json.Marshal(genericJSON)
-		if err != nil {
-			return nil, err
-		}
-		evs := string(evjs)
-
-		sv := EvalResult{}
-		err = json.Unmarshal([]byte(evs), &sv)
-		if err != nil {
-			return nil, err
-		}
-
-		return &sv, nil
-
-	case string:
-		sv := EvalResult{}
-		err = json.Unmarshal([]byte(v), &sv)
-		if err != nil {
-			return nil, err
-		}
-		return &sv, nil
-	default:
-		er := FError{"Unexpected type: " + t.(string), nil}
-		return nil, &er
-	}
-
+	return gad.evalResult(s, "RetainNodeFloatingIP")
 }
 
 // AddPortToRouter ...
@@ -2083,57 +2521,12 @@ func (gad *GAD) AddPortToRouter(sysid string, tenantid string, nodeid string, ro
 		params["ip_address"] = *ipaddress
 	}
 
-	s, _ := yaks.NewSelector(gad.GetAgentExecSelectorWithParams(sysid, tenantid, nodeid, fname, params).ToString())
-
-	kvs := gad.ws.Get(s)
-	if len(kvs) == 0 {
-		return nil, &FError{"AddPortToRouter function replied nil", nil}
-	}
-	v := kvs[0].Value().ToString()
-
-	var genericJSON map[string]interface{}
-	err := json.Unmarshal([]byte(v), &genericJSON)
+	s, err := gad.GetAgentExecSelectorWithParams(sysid, tenantid, nodeid, fname, params)
 	if err != nil {
 		return nil, err
 	}
 
-	switch t := genericJSON["result"].(type) {
-
-	case map[string]interface{}:
-		msi := genericJSON["result"].(map[string]interface{})
-		js, err := json.Marshal(msi)
-		if err != nil {
-			return nil, err
-		}
-		s := string(js)
-		genericJSON["result"] = s
-
-		evjs, err := json.Marshal(genericJSON)
-		if err != nil {
-			return nil, err
-		}
-		evs := string(evjs)
-
-		sv := EvalResult{}
-		err = json.Unmarshal([]byte(evs), &sv)
-		if err != nil {
-			return nil, err
-		}
-
-		return &sv, nil
-
-	case string:
-		sv := EvalResult{}
-		err = json.Unmarshal([]byte(v), &sv)
-		if err != nil {
-			return nil, err
-		}
-		return &sv, nil
-	default:
-		er := FError{"Unexpected type: " + t.(string), nil}
-		return nil, &er
-	}
-
+	return gad.evalResult(s, "AddPortToRouter")
 }
 
 // RemovePortFromRouter ...
@@ -2145,271 +2538,119 @@ func (gad *GAD) RemovePortFromRouter(sysid string, tenantid string, nodeid strin
 	params["router_id"] = routerid
 	params["vnet_id"] = vnetid
 
-	s, _ := yaks.NewSelector(gad.GetAgentExecSelectorWithParams(sysid, tenantid, nodeid, fname, params).ToString())
-
-	kvs := gad.ws.Get(s)
-	if len(kvs) == 0 {
-		return nil, &FError{"RemovePortFromRouter function replied nil", nil}
-	}
-	v := kvs[0].Value().ToString()
-
-	var genericJSON map[string]interface{}
-	err := json.Unmarshal([]byte(v), &genericJSON)
+	s, err := gad.GetAgentExecSelectorWithParams(sysid, tenantid, nodeid, fname, params)
 	if err != nil {
 		return nil, err
 	}
 
-	switch t := genericJSON["result"].(type) {
-
-	case map[string]interface{}:
-		msi := genericJSON["result"].(map[string]interface{})
-		js, err := json.Marshal(msi)
-		if err != nil {
-			return nil, err
-		}
-		s := string(js)
-		genericJSON["result"] = s
-
-		evjs, err := json.Marshal(genericJSON)
-		if err != nil {
-			return nil, err
-		}
-		evs := string(evjs)
-
-		sv := EvalResult{}
-		err = json.Unmarshal([]byte(evs), &sv)
-		if err != nil {
-			return nil, err
-		}
-
-		return &sv, nil
+	return gad.evalResult(s, "RemovePortFromRouter")
+}
 
-	case string:
-		sv := EvalResult{}
-		err = json.Unmarshal([]byte(v), &sv)
-		if err != nil {
-			return nil, err
-		}
-		return &sv, nil
-	default:
-		er := FError{"Unexpected type: " + t.(string), nil}
-		return nil, &er
+// OnboardFDUFromNode ...
+func (gad *GAD) OnboardFDUFromNode(sysid string, tenantid string, nodeid string, info FDU) (*EvalResult, error) {
+	s, err := gad.onboardFDUSelector(sysid, tenantid, nodeid, info)
+	if err != nil {
+		return nil, err
 	}
 
+	return gad.evalResult(s, "OnboardFDUFromNode")
 }
 
-// OnboardFDUFromNode ...
-func (gad *GAD) OnboardFDUFromNode(sysid string, tenantid string, nodeid string, info FDU) (*EvalResult, error) {
+// onboardFDUSelector validates the FDU and returns the selector of the onboard_fdu eval of the node Agent
+func (gad *GAD) onboardFDUSelector(sysid string, tenantid string, nodeid string, info FDU) (*Selector, error) {
 
 	fname := "onboard_fdu"
 	params := make(map[string]interface{})
 
-	d, err := json.Marshal(info)
-
-	params["descriptor"] = string(d)
-
-	s, _ := yaks.NewSelector(gad.GetAgentExecSelectorWithParams(sysid, tenantid, nodeid, fname, params).ToString())
-
-	kvs := gad.ws.Get(s)
-	if len(kvs) == 0 {
-		return nil, &FError{"OnboardFDUFromNode function replied nil", nil}
+	err := gad.validateFDU(sysid, tenantid, &info)
+	if err != nil {
+		return nil, err
 	}
-	v := kvs[0].Value().ToString()
 
-	var genericJSON map[string]interface{}
-	err = json.Unmarshal([]byte(v), &genericJSON)
+	d, err := json.Marshal(info)
 	if err != nil {
 		return nil, err
 	}
 
-	switch t := genericJSON["result"].(type) {
-
-	case map[string]interface{}:
-		msi := genericJSON["result"].(map[string]interface{})
-		js, err := json.Marshal(msi)
-		if err != nil {
-			return nil, err
-		}
-		s := string(js)
-		genericJSON["result"] = s
-
-		evjs, err := json.Marshal(genericJSON)
-		if err != nil {
-			return nil, err
-		}
-		evs := string(evjs)
-
-		sv := EvalResult{}
-		err = json.Unmarshal([]byte(evs), &sv)
-		if err != nil {
-			return nil, err
-		}
-
-		return &sv, nil
-
-	case string:
-		sv := EvalResult{}
-		err = json.Unmarshal([]byte(v), &sv)
-		if err != nil {
-			return nil, err
-		}
-		return &sv, nil
-	default:
-		er := FError{"Unexpected type: " + t.(string), nil}
-		return nil, &er
-	}
+	params["descriptor"] = string(d)
 
+	return gad.GetAgentExecSelectorWithParams(sysid, tenantid, nodeid, fname, params)
 }
 
 // DefineFDUInNode ...
 func (gad *GAD) DefineFDUInNode(sysid string, tenantid string, nodeid string, fduid string) (*EvalResult, error) {
-
-	fname := "define_fdu"
-	params := make(map[string]interface{})
-
-	params["fdu_id"] = fduid
-
-	s, _ := yaks.NewSelector(gad.GetAgentExecSelectorWithParams(sysid, tenantid, nodeid, fname, params).ToString())
-
-	kvs := gad.ws.Get(s)
-	if len(kvs) == 0 {
-		return nil, &FError{"DefineFDUInNode function replied nil", nil}
-	}
-	v := kvs[0].Value().ToString()
-
-	var genericJSON map[string]interface{}
-	err := json.Unmarshal([]byte(v), &genericJSON)
+	s, err := gad.defineFDUSelector(sysid, tenantid, nodeid, fduid)
 	if err != nil {
 		return nil, err
 	}
 
-	switch t := genericJSON["result"].(type) {
-
-	case map[string]interface{}:
-		msi := genericJSON["result"].(map[string]interface{})
-		js, err := json.Marshal(msi)
-		if err != nil {
-			return nil, err
-		}
-		s := string(js)
-		genericJSON["result"] = s
-
-		evjs, err := json.Marshal(genericJSON)
-		if err != nil {
-			return nil, err
-		}
-		evs := string(evjs)
+	return gad.evalResult(s, "DefineFDUInNode")
+}
 
-		sv := EvalResult{}
-		err = json.Unmarshal([]byte(evs), &sv)
-		if err != nil {
-			return nil, err
-		}
+// defineFDUSelector returns the selector of the define_fdu eval of the node Agent
+func (gad *GAD) defineFDUSelector(sysid string, tenantid string, nodeid string, fduid string) (*Selector, error) {
 
-		return &sv, nil
+	fname := "define_fdu"
+	params := make(map[string]interface{})
 
-	case string:
-		sv := EvalResult{}
-		err = json.Unmarshal([]byte(v), &sv)
-		if err != nil {
-			return nil, err
-		}
-		return &sv, nil
-	default:
-		er := FError{"Unexpected type: " + t.(string), nil}
-		return nil, &er
-	}
+	params["fdu_id"] = fduid
 
+	return gad.GetAgentExecSelectorWithParams(sysid, tenantid, nodeid, fname, params)
 }
 
 // StartFDUInNode ...
 func (gad *GAD) StartFDUInNode(sysid string, tenantid string, instanceid string, env string) (*EvalResult, error) {
 
-	s := gad.GetFDUStartEvalSelector(sysid, tenantid, instanceid, env)
-
-	kvs := gad.ws.Get(s)
-	if len(kvs) == 0 {
-		return nil, &FError{"StartFDUInNode function replied nil", nil}
-	}
-	v := kvs[0].Value().ToString()
-	sv := EvalResult{}
-	err := json.Unmarshal([]byte(v), &sv)
+	s, err := gad.GetFDUStartEvalSelector(sysid, tenantid, instanceid, env)
 	if err != nil {
 		return nil, err
 	}
-	return &sv, nil
+
+	return gad.evalResult(s, "StartFDUInNode")
 }
 
 // RunFDUInNode ...
 func (gad *GAD) RunFDUInNode(sysid string, tenantid string, instanceid string, env string) (*EvalResult, error) {
 
-	s := gad.GetFDURunEvalSelector(sysid, tenantid, instanceid, env)
-
-	kvs := gad.ws.Get(s)
-	if len(kvs) == 0 {
-		return nil, &FError{"RunFDUInNode function replied nil", nil}
-	}
-	v := kvs[0].Value().ToString()
-	sv := EvalResult{}
-	err := json.Unmarshal([]byte(v), &sv)
+	s, err := gad.GetFDURunEvalSelector(sysid, tenantid, instanceid, env)
 	if err != nil {
 		return nil, err
 	}
-	return &sv, nil
+
+	return gad.evalResult(s, "RunFDUInNode")
 }
 
 // LogFDUInNode ...
 func (gad *GAD) LogFDUInNode(sysid string, tenantid string, instanceid string) (*EvalResult, error) {
 
-	s := gad.GetFDULogEvalSelector(sysid, tenantid, instanceid)
-
-	kvs := gad.ws.Get(s)
-	if len(kvs) == 0 {
-		return nil, &FError{"LogFDUInNode function replied nil", nil}
-	}
-	v := kvs[0].Value().ToString()
-	sv := EvalResult{}
-	err := json.Unmarshal([]byte(v), &sv)
+	s, err := gad.GetFDULogEvalSelector(sysid, tenantid, instanceid)
 	if err != nil {
 		return nil, err
 	}
-	return &sv, nil
+
+	return gad.evalResult(s, "LogFDUInNode")
 }
 
 // LsFDUInNode ...
 func (gad *GAD) LsFDUInNode(sysid string, tenantid string, instanceid string) (*EvalResult, error) {
 
-	s := gad.GetFDULsEvalSelector(sysid, tenantid, instanceid)
-
-	kvs := gad.ws.Get(s)
-	if len(kvs) == 0 {
-		return nil, &FError{"LsFDUInNode function replied nil", nil}
-	}
-	v := kvs[0].Value().ToString()
-	sv := EvalResult{}
-	err := json.Unmarshal([]byte(v), &sv)
+	s, err := gad.GetFDULsEvalSelector(sysid, tenantid, instanceid)
 	if err != nil {
 		return nil, err
 	}
-	return &sv, nil
+
+	return gad.evalResult(s, "LsFDUInNode")
 }
 
 // GetFileFDUInNode ...
 func (gad *GAD) GetFileFDUInNode(sysid string, tenantid string, instanceid string, filename string) (*EvalResult, error) {
 
-	s := gad.GetFDUFileEvalSelector(sysid, tenantid, instanceid, filename)
-
-	kvs := gad.ws.Get(s)
-	if len(kvs) == 0 {
-		return nil, &FError{"GetFileFDUInNode function replied nil", nil}
-	}
-	v := kvs[0].Value().ToString()
-	sv := EvalResult{}
-	err := json.Unmarshal([]byte(v), &sv)
+	s, err := gad.GetFDUFileEvalSelector(sysid, tenantid, instanceid, filename)
 	if err != nil {
 		return nil, err
 	}
-	return &sv, nil
+
+	return gad.evalResult(s, "GetFileFDUInNode")
 }
 
 // CreateNetworkInNode ...
@@ -2419,60 +2660,18 @@ func (gad *GAD) CreateNetworkInNode(sysid string, tenantid string, nodeid string
 	params := make(map[string]interface{})
 
 	d, err := json.Marshal(info)
-
-	params["descriptor"] = string(d)
-
-	s, _ := yaks.NewSelector(gad.GetAgentExecSelectorWithParams(sysid, tenantid, nodeid, fname, params).ToString())
-
-	kvs := gad.ws.Get(s)
-	if len(kvs) == 0 {
-		return nil, &FError{"CreateNetworkInNode function replied nil", nil}
-	}
-	v := kvs[0].Value().ToString()
-
-	var genericJSON map[string]interface{}
-	err = json.Unmarshal([]byte(v), &genericJSON)
 	if err != nil {
 		return nil, err
 	}
 
-	switch t := genericJSON["result"].(type) {
-
-	case map[string]interface{}:
-		msi := genericJSON["result"].(map[string]interface{})
-		js, err := json.Marshal(msi)
-		if err != nil {
-			return nil, err
-		}
-		s := string(js)
-		genericJSON["result"] = s
-
-		evjs, err := json.Marshal(genericJSON)
-		if err != nil {
-			return nil, err
-		}
-		evs := string(evjs)
-
-		sv := EvalResult{}
-		err = json.Unmarshal([]byte(evs), &sv)
-		if err != nil {
-			return nil, err
-		}
-
-		return &sv, nil
+	params["descriptor"] = string(d)
 
-	case string:
-		sv := EvalResult{}
-		err = json.Unmarshal([]byte(v), &sv)
-		if err != nil {
-			return nil, err
-		}
-		return &sv, nil
-	default:
-		er := FError{"Unexpected type: " + t.(string), nil}
-		return nil, &er
+	s, err := gad.GetAgentExecSelectorWithParams(sysid, tenantid, nodeid, fname, params)
+	if err != nil {
+		return nil, err
 	}
 
+	return gad.evalResult(s, "CreateNetworkInNode")
 }
 
 // RemoveNetworkFromNode ...
@@ -2483,319 +2682,308 @@ func (gad *GAD) RemoveNetworkFromNode(sysid string, tenantid string, nodeid stri
 
 	params["net_id"] = netid
 
-	s, _ := yaks.NewSelector(gad.GetAgentExecSelectorWithParams(sysid, tenantid, nodeid, fname, params).ToString())
-
-	kvs := gad.ws.Get(s)
-	if len(kvs) == 0 {
-		return nil, &FError{"RemoveNetworkFromNode function replied nil", nil}
-	}
-	v := kvs[0].Value().ToString()
-
-	var genericJSON map[string]interface{}
-	err := json.Unmarshal([]byte(v), &genericJSON)
+	s, err := gad.GetAgentExecSelectorWithParams(sysid, tenantid, nodeid, fname, params)
 	if err != nil {
 		return nil, err
 	}
 
-	switch t := genericJSON["result"].(type) {
-
-	case map[string]interface{}:
-		msi := genericJSON["result"].(map[string]interface{})
-		js, err := json.Marshal(msi)
-		if err != nil {
-			return nil, err
-		}
-		s := string(js)
-		genericJSON["result"] = s
-
-		evjs, err := json.Marshal(genericJSON)
-		if err != nil {
-			return nil, err
-		}
-		evs := string(evjs)
+	return gad.evalResult(s, "RemoveNetworkFromNode")
+}
 
-		sv := EvalResult{}
-		err = json.Unmarshal([]byte(evs), &sv)
-		if err != nil {
-			return nil, err
-		}
+// LAD is Local Actual Desired
+type LAD struct {
+	ws     Store
+	prefix string
+	ctx    context.Context
+	errh   func(error)
+	*handlers
+}
 
-		return &sv, nil
+// WithErrorHandler returns a copy of the LAD whose listeners deliver to the handler the changes that cannot be decoded, by default they are logged and dropped
+func (lad *LAD) WithErrorHandler(handler func(error)) *LAD {
+	c := *lad
+	c.errh = handler
+	return &c
+}
 
-	case string:
-		sv := EvalResult{}
-		err = json.Unmarshal([]byte(v), &sv)
-		if err != nil {
-			return nil, err
-		}
-		return &sv, nil
-	default:
-		er := FError{"Unexpected type: " + t.(string), nil}
-		return nil, &er
+func (lad *LAD) handleError(err error) {
+	if lad.errh != nil {
+		lad.errh(err)
+		return
 	}
-
+	lad.logger.WithError(err).Error("Unable to decode change")
 }
 
-// LAD is Local Actual Desired
-type LAD struct {
-	ws        *yaks.Workspace
-	prefix    string
-	listeners []*yaks.SubscriptionID
-	evals     []*yaks.Path
+// WithContext returns a copy of the LAD whose operations use the given context, listeners and evals registered through it are removed when the context is done
+func (lad *LAD) WithContext(ctx context.Context) *LAD {
+	c := *lad
+	c.ctx = ctx
+	return &c
 }
 
-// Unsubscribe ...
-func (lad *LAD) Unsubscribe(sid *yaks.SubscriptionID) error {
-	err := lad.ws.Unsubscribe(sid)
-	if err != nil {
-		return err
+func (lad *LAD) context() context.Context {
+	if lad.ctx == nil {
+		return context.Background()
 	}
+	return lad.ctx
+}
 
-	p := -1
+// Eval evaluates the eval matching the selector and decodes its result in result, that can be a pointer to any type accepted by json.Unmarshal.
+// If the eval failed the result is not decoded and the RawEvalResult is returned together with an EvalError
+func (lad *LAD) Eval(s *Selector, result interface{}) (*RawEvalResult, error) {
+	return execEval(lad.context(), lad.ws, s, "Eval", result)
+}
 
-	for i, e := range lad.listeners {
-		if e == sid {
-			p = i
-		}
+func (lad *LAD) evalResult(s *Selector, name string) (*EvalResult, error) {
+	r, err := execEval(lad.context(), lad.ws, s, name, nil)
+	if r == nil {
+		return nil, err
 	}
-	if p == -1 {
-		return &FError{"Subscriber not found!!", nil}
+	ev, cerr := r.EvalResult()
+	if cerr != nil {
+		return nil, &DecodeError{cerr}
 	}
-	lad.listeners = append(lad.listeners[:p], lad.listeners[p+1:]...)
-	return nil
+	return ev, err
 }
 
-// RemoveEval ...
-func (lad *LAD) RemoveEval(sid *yaks.Path) error {
-	err := lad.ws.UnregisterEval(sid)
-	if err != nil {
-		return err
-	}
-	p := -1
+// Unsubscribe ...
+func (lad *LAD) Unsubscribe(sid *SubscriptionID) error {
+	return lad.unsubscribe(lad.context(), lad.ws, sid)
+}
 
-	for i, e := range lad.evals {
-		if e == sid {
-			p = i
-		}
-	}
-	if p == -1 {
-		return &FError{"Eval not found!!", nil}
-	}
-	lad.evals = append(lad.evals[:p], lad.evals[p+1:]...)
-	return nil
+// RemoveEval ...
+func (lad *LAD) RemoveEval(sid *Path) error {
+	return lad.removeEval(lad.context(), lad.ws, sid)
+}
 
+// Close unsubscribes all the listeners and removes all the evals registered through the LAD and its copies, FDU instance evals included
+func (lad *LAD) Close() error {
+	return lad.close(context.Background(), lad.ws)
 }
 
 // Node
 
 // GetNodeInfoPath ...
-func (lad *LAD) GetNodeInfoPath(nodeid string) *yaks.Path {
+func (lad *LAD) GetNodeInfoPath(nodeid string) (*Path, error) {
 	return CreatePath([]string{lad.prefix, nodeid, "info"})
 }
 
 // GetNodeConfigurationPath ...
-func (lad *LAD) GetNodeConfigurationPath(nodeid string) *yaks.Path {
+func (lad *LAD) GetNodeConfigurationPath(nodeid string) (*Path, error) {
 	return CreatePath([]string{lad.prefix, nodeid, "configuration"})
 }
 
 // GetNodeStatusPath ...
-func (lad *LAD) GetNodeStatusPath(nodeid string) *yaks.Path {
+func (lad *LAD) GetNodeStatusPath(nodeid string) (*Path, error) {
 	return CreatePath([]string{lad.prefix, nodeid, "status"})
 }
 
 // GetNodePlguinsSelector ...
-func (lad *LAD) GetNodePlguinsSelector(nodeid string) *yaks.Selector {
+func (lad *LAD) GetNodePlguinsSelector(nodeid string) (*Selector, error) {
 	return CreateSelector([]string{lad.prefix, nodeid, "plugins", "*", "info"})
 }
 
 // GetNodePlguinsSubscriberSelector ...
-func (lad *LAD) GetNodePlguinsSubscriberSelector(nodeid string) *yaks.Selector {
+func (lad *LAD) GetNodePlguinsSubscriberSelector(nodeid string) (*Selector, error) {
 	return CreateSelector([]string{lad.prefix, nodeid, "plugins", "**"})
 }
 
 // GetNodePlguinInfoPath ...
-func (lad *LAD) GetNodePlguinInfoPath(nodeid string, pluginid string) *yaks.Path {
+func (lad *LAD) GetNodePlguinInfoPath(nodeid string, pluginid string) (*Path, error) {
 	return CreatePath([]string{lad.prefix, nodeid, "plugins", pluginid, "info"})
 }
 
 // GetNodePlguinStatePath ...
-func (lad *LAD) GetNodePlguinStatePath(nodeid string, pluginid string) *yaks.Path {
+func (lad *LAD) GetNodePlguinStatePath(nodeid string, pluginid string) (*Path, error) {
 	return CreatePath([]string{lad.prefix, nodeid, "plugins", pluginid, "state"})
 }
 
+// GetNodePluginHeartbeatPath ...
+func (lad *LAD) GetNodePluginHeartbeatPath(nodeid string, pluginid string) (*Path, error) {
+	return CreatePath([]string{lad.prefix, nodeid, "plugins", pluginid, "heartbeat"})
+}
+
+// GetNodePluginHeartbeatsSelector ...
+func (lad *LAD) GetNodePluginHeartbeatsSelector(nodeid string) (*Selector, error) {
+	return CreateSelector([]string{lad.prefix, nodeid, "plugins", "*", "heartbeat"})
+}
+
 // GetNodeRuntimesSelector ...
-func (lad *LAD) GetNodeRuntimesSelector(nodeid string) *yaks.Selector {
+func (lad *LAD) GetNodeRuntimesSelector(nodeid string) (*Selector, error) {
 	return CreateSelector([]string{lad.prefix, nodeid, "runtimes", "**"})
 }
 
 // GetNodeNetworkManagersSelector ...
-func (lad *LAD) GetNodeNetworkManagersSelector(nodeid string) *yaks.Selector {
+func (lad *LAD) GetNodeNetworkManagersSelector(nodeid string) (*Selector, error) {
 	return CreateSelector([]string{lad.prefix, nodeid, "network_managers", "*"})
 }
 
 // Node FDU
 
 // GetNodeRuntimeFDUsSelector ...
-func (lad *LAD) GetNodeRuntimeFDUsSelector(nodeid string, pluginid string) *yaks.Selector {
+func (lad *LAD) GetNodeRuntimeFDUsSelector(nodeid string, pluginid string) (*Selector, error) {
 	return CreateSelector([]string{lad.prefix, nodeid, "runtimes", pluginid, "fdu", "*", "instances", "*", "info"})
 }
 
 // GetNodeRuntimeFDUsSubcrinerSelector ...
-func (lad *LAD) GetNodeRuntimeFDUsSubcrinerSelector(nodeid string, pluginid string) *yaks.Selector {
+func (lad *LAD) GetNodeRuntimeFDUsSubcrinerSelector(nodeid string, pluginid string) (*Selector, error) {
 	return CreateSelector([]string{lad.prefix, nodeid, "runtimes", pluginid, "fdu", "*", "instances", "*", "info"})
 }
 
 // GetNodeRuntimeFDUInfoPath ...
-func (lad *LAD) GetNodeRuntimeFDUInfoPath(nodeid string, pluginid string, fduid string, instanceid string) *yaks.Path {
+func (lad *LAD) GetNodeRuntimeFDUInfoPath(nodeid string, pluginid string, fduid string, instanceid string) (*Path, error) {
 	return CreatePath([]string{lad.prefix, nodeid, "runtimes", pluginid, "fdu", fduid, "instances", instanceid, "info"})
 }
 
 // GetNodeRuntimeFDUInfoSelector ...
-func (lad *LAD) GetNodeRuntimeFDUInfoSelector(nodeid string, pluginid string, fduid string, instanceid string) *yaks.Selector {
+func (lad *LAD) GetNodeRuntimeFDUInfoSelector(nodeid string, pluginid string, fduid string, instanceid string) (*Selector, error) {
 	return CreateSelector([]string{lad.prefix, nodeid, "runtimes", pluginid, "fdu", fduid, "instances", instanceid, "info"})
 }
 
 // GetNodeFDUInstancesSelector ...
-func (lad *LAD) GetNodeFDUInstancesSelector(nodeid string, fduid string) *yaks.Selector {
+func (lad *LAD) GetNodeFDUInstancesSelector(nodeid string, fduid string) (*Selector, error) {
 	return CreateSelector([]string{lad.prefix, nodeid, "runtimes", "*", "fdu", fduid, "instances", "*", "info"})
 }
 
 // GetNodeFDUInstanceSelector ...
-func (lad *LAD) GetNodeFDUInstanceSelector(nodeid string, instanceid string) *yaks.Selector {
+func (lad *LAD) GetNodeFDUInstanceSelector(nodeid string, instanceid string) (*Selector, error) {
 	return CreateSelector([]string{lad.prefix, nodeid, "runtimes", "*", "fdu", "*", "instances", instanceid, "info"})
 }
 
 // GetNodeFDUIAllnstancesSelector ...
-func (lad *LAD) GetNodeFDUIAllnstancesSelector(nodeid string) *yaks.Selector {
+func (lad *LAD) GetNodeFDUIAllnstancesSelector(nodeid string) (*Selector, error) {
 	return CreateSelector([]string{lad.prefix, nodeid, "runtimes", "*", "fdu", "*", "instances", "*", "info"})
 }
 
 // GetNoneFDUStartEvalSelector ...
-func (lad *LAD) GetNoneFDUStartEvalSelector(nodeid string, instanceid string, env string) *yaks.Selector {
+func (lad *LAD) GetNoneFDUStartEvalSelector(nodeid string, instanceid string, env string) (*Selector, error) {
 	e := fmt.Sprintf("?(env=%s)", env)
 	return CreateSelector([]string{lad.prefix, nodeid, "runtimes", "*", "fdu", "*", "instances", instanceid, "start", e})
 }
 
 // GetNodeFDURunEvalSelector ...
-func (lad *LAD) GetNodeFDURunEvalSelector(nodeid string, instanceid string, env string) *yaks.Selector {
+func (lad *LAD) GetNodeFDURunEvalSelector(nodeid string, instanceid string, env string) (*Selector, error) {
 	e := fmt.Sprintf("?(env=%s)", env)
 	return CreateSelector([]string{lad.prefix, nodeid, "runtimes", "*", "fdu", "*", "instances", instanceid, "start", e})
 }
 
 // GetNodeFDULogEvalSelector ...
-func (lad *LAD) GetNodeFDULogEvalSelector(nodeid string, instanceid string) *yaks.Selector {
+func (lad *LAD) GetNodeFDULogEvalSelector(nodeid string, instanceid string) (*Selector, error) {
 	return CreateSelector([]string{lad.prefix, nodeid, "runtimes", "*", "fdu", "*", "instances", instanceid, "log"})
 }
 
 // GetNodeFDULsEvalSelector ...
-func (lad *LAD) GetNodeFDULsEvalSelector(nodeid string, instanceid string) *yaks.Selector {
+func (lad *LAD) GetNodeFDULsEvalSelector(nodeid string, instanceid string) (*Selector, error) {
 	return CreateSelector([]string{lad.prefix, nodeid, "runtimes", "*", "fdu", "*", "instances", instanceid, "ls"})
 }
 
 // GetNodeFDUFileEvalSelector ...
-func (lad *LAD) GetNodeFDUFileEvalSelector(nodeid string, instanceid string, filename string) *yaks.Selector {
+func (lad *LAD) GetNodeFDUFileEvalSelector(nodeid string, instanceid string, filename string) (*Selector, error) {
 	f := fmt.Sprintf("?(filename=%s)", filename)
 	return CreateSelector([]string{lad.prefix, nodeid, "runtimes", "*", "fdu", "*", "instances", instanceid, "get", f})
 }
 
 // GetNodeFDUStartEvalPath ...
-func (lad *LAD) GetNodeFDUStartEvalPath(nodeid string, pluginid string, fduid string, instanceid string) *yaks.Path {
+func (lad *LAD) GetNodeFDUStartEvalPath(nodeid string, pluginid string, fduid string, instanceid string) (*Path, error) {
 	return CreatePath([]string{lad.prefix, nodeid, "runtimes", pluginid, "fdu", fduid, "instances", instanceid, "start"})
 }
 
 // GetNodeFDURunEvalPath ...
-func (lad *LAD) GetNodeFDURunEvalPath(nodeid string, pluginid string, fduid string, instanceid string) *yaks.Path {
+func (lad *LAD) GetNodeFDURunEvalPath(nodeid string, pluginid string, fduid string, instanceid string) (*Path, error) {
 	return CreatePath([]string{lad.prefix, nodeid, "runtimes", pluginid, "fdu", fduid, "instances", instanceid, "run"})
 }
 
 // GetNodeFDULogEvalPath ...
-func (lad *LAD) GetNodeFDULogEvalPath(nodeid string, pluginid string, fduid string, instanceid string) *yaks.Path {
+func (lad *LAD) GetNodeFDULogEvalPath(nodeid string, pluginid string, fduid string, instanceid string) (*Path, error) {
 	return CreatePath([]string{lad.prefix, nodeid, "runtimes", pluginid, "fdu", fduid, "instances", instanceid, "log"})
 }
 
 // GetNodeFDULsEvalPath ...
-func (lad *LAD) GetNodeFDULsEvalPath(nodeid string, pluginid string, fduid string, instanceid string) *yaks.Path {
+func (lad *LAD) GetNodeFDULsEvalPath(nodeid string, pluginid string, fduid string, instanceid string) (*Path, error) {
 	return CreatePath([]string{lad.prefix, nodeid, "runtimes", pluginid, "fdu", fduid, "instances", instanceid, "ls"})
 }
 
 // GetNodeFDUFileEvalPath ...
-func (lad *LAD) GetNodeFDUFileEvalPath(nodeid string, pluginid string, fduid string, instanceid string) *yaks.Path {
+func (lad *LAD) GetNodeFDUFileEvalPath(nodeid string, pluginid string, fduid string, instanceid string) (*Path, error) {
 	return CreatePath([]string{lad.prefix, nodeid, "runtimes", pluginid, "fdu", fduid, "instances", instanceid, "get"})
 }
 
 // Node Images
 
 // GetNodeIimageInfoPath ...
-func (lad *LAD) GetNodeIimageInfoPath(nodeid string, pluginid string, imgid string) *yaks.Path {
+func (lad *LAD) GetNodeIimageInfoPath(nodeid string, pluginid string, imgid string) (*Path, error) {
 	return CreatePath([]string{lad.prefix, nodeid, "runtimes", pluginid, "images", imgid, "info"})
 }
 
 // Node Flavors
 
 // GetNodeFlavorInfoPath ...
-func (lad *LAD) GetNodeFlavorInfoPath(nodeid string, pluginid string, flvid string) *yaks.Path {
+func (lad *LAD) GetNodeFlavorInfoPath(nodeid string, pluginid string, flvid string) (*Path, error) {
 	return CreatePath([]string{lad.prefix, nodeid, "runtimes", pluginid, "flavors", flvid, "info"})
 }
 
 // Node Networks
 
 // GetNodeNetworksSelector ...
-func (lad *LAD) GetNodeNetworksSelector(nodeid string, pluginid string) *yaks.Selector {
+func (lad *LAD) GetNodeNetworksSelector(nodeid string, pluginid string) (*Selector, error) {
 	return CreateSelector([]string{lad.prefix, nodeid, "network_manager", pluginid, "networks", "*", "info"})
 }
 
 // GetNodeNetworksFindSelector ...
-func (lad *LAD) GetNodeNetworksFindSelector(nodeid string, netid string) *yaks.Selector {
+func (lad *LAD) GetNodeNetworksFindSelector(nodeid string, netid string) (*Selector, error) {
 	return CreateSelector([]string{lad.prefix, nodeid, "network_manager", "*", "networks", netid, "info"})
 }
 
 // GetNodeNetworkInfoPath ...
-func (lad *LAD) GetNodeNetworkInfoPath(nodeid string, pluginid string, netid string) *yaks.Path {
+func (lad *LAD) GetNodeNetworkInfoPath(nodeid string, pluginid string, netid string) (*Path, error) {
 	return CreatePath([]string{lad.prefix, nodeid, "network_manager", pluginid, "networks", netid, "info"})
 }
 
 // GetNodeNetworkPortInfoPath ...
-func (lad *LAD) GetNodeNetworkPortInfoPath(nodeid string, pluginid string, portid string) *yaks.Path {
+func (lad *LAD) GetNodeNetworkPortInfoPath(nodeid string, pluginid string, portid string) (*Path, error) {
 	return CreatePath([]string{lad.prefix, nodeid, "network_manager", pluginid, "ports", portid, "info"})
 }
 
 // GetNodeNetworkPortsSelector ...
-func (lad *LAD) GetNodeNetworkPortsSelector(nodeid string, pluginid string) *yaks.Selector {
+func (lad *LAD) GetNodeNetworkPortsSelector(nodeid string, pluginid string) (*Selector, error) {
 	return CreateSelector([]string{lad.prefix, nodeid, "network_manager", pluginid, "ports", "*", "info"})
 }
 
 // GetNodeNetworkRouterInfoPath ...
-func (lad *LAD) GetNodeNetworkRouterInfoPath(nodeid string, pluginid string, routerid string) *yaks.Path {
+func (lad *LAD) GetNodeNetworkRouterInfoPath(nodeid string, pluginid string, routerid string) (*Path, error) {
 	return CreatePath([]string{lad.prefix, nodeid, "network_manager", pluginid, "routers", routerid, "info"})
 }
 
 // GetNodeNetworkRoutersSelector ...
-func (lad *LAD) GetNodeNetworkRoutersSelector(nodeid string, pluginid string) *yaks.Selector {
+func (lad *LAD) GetNodeNetworkRoutersSelector(nodeid string, pluginid string) (*Selector, error) {
 	return CreateSelector([]string{lad.prefix, nodeid, "network_manager", pluginid, "routers", "*", "info"})
 }
 
 // GetNodeNetworkFloatingIPInfoPath ...
-func (lad *LAD) GetNodeNetworkFloatingIPInfoPath(nodeid string, pluginid string, ipid string) *yaks.Path {
+func (lad *LAD) GetNodeNetworkFloatingIPInfoPath(nodeid string, pluginid string, ipid string) (*Path, error) {
 	return CreatePath([]string{lad.prefix, nodeid, "network_manager", pluginid, "floating-ips", ipid, "info"})
 }
 
 // GetNodeNetworkFloatingIPsSelector ...
-func (lad *LAD) GetNodeNetworkFloatingIPsSelector(nodeid string, pluginid string) *yaks.Selector {
+func (lad *LAD) GetNodeNetworkFloatingIPsSelector(nodeid string, pluginid string) (*Selector, error) {
 	return CreateSelector([]string{lad.prefix, nodeid, "network_manager", pluginid, "floating-ips", "*", "info"})
 }
 
 // Node Evals
 
 // GetAgentExecPath ...
-func (lad *LAD) GetAgentExecPath(nodeid string, funcname string) *yaks.Path {
+func (lad *LAD) GetAgentExecPath(nodeid string, funcname string) (*Path, error) {
 	return CreatePath([]string{lad.prefix, nodeid, "agent", "exec", funcname})
 }
 
 // GetAgentExecSelectorWithParams ...
-func (lad *LAD) GetAgentExecSelectorWithParams(nodeid string, funcname string, params map[string]interface{}) *yaks.Selector {
+func (lad *LAD) GetAgentExecSelectorWithParams(nodeid string, funcname string, params map[string]interface{}) (*Selector, error) {
 	var f string
 	if len(params) > 0 {
-		p := Dict2Args(params)
+		p, err := MarshalArgs(params)
+		if err != nil {
+			return nil, err
+		}
 		f = fmt.Sprintf("%s?%s", funcname, p)
 	} else {
 		f = funcname
@@ -2804,15 +2992,18 @@ func (lad *LAD) GetAgentExecSelectorWithParams(nodeid string, funcname string, p
 }
 
 // GetNodeOSExecPath ...
-func (lad *LAD) GetNodeOSExecPath(nodeid string, funcname string) *yaks.Path {
+func (lad *LAD) GetNodeOSExecPath(nodeid string, funcname string) (*Path, error) {
 	return CreatePath([]string{lad.prefix, nodeid, "os", "exec", funcname})
 }
 
 // GetNodeOSExecSelectorWithParams ...
-func (lad *LAD) GetNodeOSExecSelectorWithParams(nodeid string, funcname string, params map[string]interface{}) *yaks.Selector {
+func (lad *LAD) GetNodeOSExecSelectorWithParams(nodeid string, funcname string, params map[string]interface{}) (*Selector, error) {
 	var f string
 	if len(params) > 0 {
-		p := Dict2Args(params)
+		p, err := MarshalArgs(params)
+		if err != nil {
+			return nil, err
+		}
 		f = fmt.Sprintf("%s?%s", funcname, p)
 	} else {
 		f = funcname
@@ -2821,15 +3012,18 @@ func (lad *LAD) GetNodeOSExecSelectorWithParams(nodeid string, funcname string,
 }
 
 // GetNodeNMExecPath ...
-func (lad *LAD) GetNodeNMExecPath(nodeid string, pluginid string, funcname string) *yaks.Path {
+func (lad *LAD) GetNodeNMExecPath(nodeid string, pluginid string, funcname string) (*Path, error) {
 	return CreatePath([]string{lad.prefix, nodeid, "network_managers", pluginid, "exec", funcname})
 }
 
 // GetNodeNMExecSelectorWithParams ...
-func (lad *LAD) GetNodeNMExecSelectorWithParams(nodeid string, pluginid string, funcname string, params map[string]interface{}) *yaks.Selector {
+func (lad *LAD) GetNodeNMExecSelectorWithParams(nodeid string, pluginid string, funcname string, params map[string]interface{}) (*Selector, error) {
 	var f string
 	if len(params) > 0 {
-		p := Dict2Args(params)
+		p, err := MarshalArgs(params)
+		if err != nil {
+			return nil, err
+		}
 		f = fmt.Sprintf("%s?%s", funcname, p)
 	} else {
 		f = funcname
@@ -2838,15 +3032,18 @@ func (lad *LAD) GetNodeNMExecSelectorWithParams(nodeid string, pluginid string,
 }
 
 // GetNodePluginEvalPath ...
-func (lad *LAD) GetNodePluginEvalPath(nodeid string, pluginid string, funcname string) *yaks.Path {
+func (lad *LAD) GetNodePluginEvalPath(nodeid string, pluginid string, funcname string) (*Path, error) {
 	return CreatePath([]string{lad.prefix, nodeid, "plugins", pluginid, "exec", funcname})
 }
 
 // GetNodePluginEvalSelectorWithParams ...
-func (lad *LAD) GetNodePluginEvalSelectorWithParams(nodeid string, pluginid string, funcname string, params map[string]interface{}) *yaks.Selector {
+func (lad *LAD) GetNodePluginEvalSelectorWithParams(nodeid string, pluginid string, funcname string, params map[string]interface{}) (*Selector, error) {
 	var f string
 	if len(params) > 0 {
-		p := Dict2Args(params)
+		p, err := MarshalArgs(params)
+		if err != nil {
+			return nil, err
+		}
 		f = fmt.Sprintf("%s?%s", funcname, p)
 	} else {
 		f = funcname
@@ -2855,519 +3052,426 @@ func (lad *LAD) GetNodePluginEvalSelectorWithParams(nodeid string, pluginid stri
 }
 
 // GetNodeOSInfoPath ...
-func (lad *LAD) GetNodeOSInfoPath(nodeid string) *yaks.Path {
+func (lad *LAD) GetNodeOSInfoPath(nodeid string) (*Path, error) {
 	return CreatePath([]string{lad.prefix, nodeid, "os", "info"})
 }
 
 // ID Extraction
 
 // ExtractNodeIDFromPath ...
-func (lad *LAD) ExtractNodeIDFromPath(path *yaks.Path) string {
-	return strings.Split(path.ToString(), URISeparator)[2]
+func (lad *LAD) ExtractNodeIDFromPath(path *Path) (string, error) {
+	return pathElement(path, 2)
 }
 
 // ExtractPluginIDFromPath ...
-func (lad *LAD) ExtractPluginIDFromPath(path *yaks.Path) string {
-	return strings.Split(path.ToString(), URISeparator)[4]
+func (lad *LAD) ExtractPluginIDFromPath(path *Path) (string, error) {
+	return pathElement(path, 4)
 }
 
 // ExtractNodeFDUIDFromPath ...
-func (lad *LAD) ExtractNodeFDUIDFromPath(path *yaks.Path) string {
-	return strings.Split(path.ToString(), URISeparator)[6]
+func (lad *LAD) ExtractNodeFDUIDFromPath(path *Path) (string, error) {
+	return pathElement(path, 6)
 }
 
 // ExtractNodeInstanceIDFromPath ...
-func (lad *LAD) ExtractNodeInstanceIDFromPath(path *yaks.Path) string {
-	return strings.Split(path.ToString(), URISeparator)[8]
+func (lad *LAD) ExtractNodeInstanceIDFromPath(path *Path) (string, error) {
+	return pathElement(path, 8)
 }
 
 // ExtractNodeRouterIDFromPath ...
-func (lad *LAD) ExtractNodeRouterIDFromPath(path *yaks.Path) string {
-	return strings.Split(path.ToString(), URISeparator)[6]
+func (lad *LAD) ExtractNodeRouterIDFromPath(path *Path) (string, error) {
+	return pathElement(path, 6)
 }
 
 // ExtractNodeNetworkIDFromPath ...
-func (lad *LAD) ExtractNodeNetworkIDFromPath(path *yaks.Path) string {
-	return strings.Split(path.ToString(), URISeparator)[6]
+func (lad *LAD) ExtractNodeNetworkIDFromPath(path *Path) (string, error) {
+	return pathElement(path, 6)
 }
 
 // ExtractNodePortIDFromPath ...
-func (lad *LAD) ExtractNodePortIDFromPath(path *yaks.Path) string {
-	return strings.Split(path.ToString(), URISeparator)[6]
+func (lad *LAD) ExtractNodePortIDFromPath(path *Path) (string, error) {
+	return pathElement(path, 6)
 }
 
 // ExtractNodeFloatingIPIDFromPath ...
-func (lad *LAD) ExtractNodeFloatingIPIDFromPath(path *yaks.Path) string {
-	return strings.Split(path.ToString(), URISeparator)[6]
+func (lad *LAD) ExtractNodeFloatingIPIDFromPath(path *Path) (string, error) {
+	return pathElement(path, 6)
 }
 
 // Node Evals
 
+// AddAgentEval ...
+func (lad *LAD) AddAgentEval(nodeid string, funcname string, evalcb func(Properties) interface{}) error {
+	s, err := lad.GetAgentExecPath(nodeid, funcname)
+	if err != nil {
+		return err
+	}
+
+	cb := func(path *Path, props Properties) string {
+		return evalReply(evalcb(props))
+	}
+
+	return lad.registerEval(lad.context(), lad.ws, s, cb)
+}
+
 // AddOSEval ...
-func (lad *LAD) AddOSEval(nodeid string, funcname string, evalcb func(yaks.Properties) interface{}) error {
-	s := lad.GetNodeOSExecPath(nodeid, funcname)
+func (lad *LAD) AddOSEval(nodeid string, funcname string, evalcb func(Properties) interface{}) error {
+	s, err := lad.GetNodeOSExecPath(nodeid, funcname)
+	if err != nil {
+		return err
+	}
 
-	cb := func(path *yaks.Path, props yaks.Properties) yaks.Value {
-		v, _ := json.Marshal(evalcb(props))
-		sv := yaks.NewStringValue(string(v))
-		return sv
+	cb := func(path *Path, props Properties) string {
+		return evalReply(evalcb(props))
 	}
 
-	err := lad.ws.RegisterEval(s, cb)
-	lad.evals = append(lad.evals, s)
-	return err
+	return lad.registerEval(lad.context(), lad.ws, s, cb)
 }
 
 // AddNMEval ...
-func (lad *LAD) AddNMEval(nodeid string, pluginid string, funcname string, evalcb func(yaks.Properties) interface{}) error {
-	s := lad.GetNodeNMExecPath(nodeid, pluginid, funcname)
+func (lad *LAD) AddNMEval(nodeid string, pluginid string, funcname string, evalcb func(Properties) interface{}) error {
+	s, err := lad.GetNodeNMExecPath(nodeid, pluginid, funcname)
+	if err != nil {
+		return err
+	}
 
-	cb := func(path *yaks.Path, props yaks.Properties) yaks.Value {
-		v, _ := json.Marshal(evalcb(props))
-		sv := yaks.NewStringValue(string(v))
-		return sv
+	cb := func(path *Path, props Properties) string {
+		return evalReply(evalcb(props))
 	}
 
-	err := lad.ws.RegisterEval(s, cb)
-	lad.evals = append(lad.evals, s)
-	return err
+	return lad.registerEval(lad.context(), lad.ws, s, cb)
 }
 
 // AddPluginEval ...
-func (lad *LAD) AddPluginEval(nodeid string, pluginid string, funcname string, evalcb func(yaks.Properties) interface{}) error {
-	s := lad.GetNodePluginEvalPath(nodeid, pluginid, funcname)
+func (lad *LAD) AddPluginEval(nodeid string, pluginid string, funcname string, evalcb func(Properties) interface{}) error {
+	s, err := lad.GetNodePluginEvalPath(nodeid, pluginid, funcname)
+	if err != nil {
+		return err
+	}
 
-	cb := func(path *yaks.Path, props yaks.Properties) yaks.Value {
-		v, _ := json.Marshal(evalcb(props))
-		sv := yaks.NewStringValue(string(v))
-		return sv
+	cb := func(path *Path, props Properties) string {
+		return evalReply(evalcb(props))
 	}
 
-	err := lad.ws.RegisterEval(s, cb)
-	lad.evals = append(lad.evals, s)
-	return err
+	return lad.registerEval(lad.context(), lad.ws, s, cb)
+}
+
+// missingParameterReply is the reply of an eval called without a required parameter
+func missingParameterReply(name string) string {
+	return evalReply(NewEvalErrorResult(&EvalError{Code: EvalErrorInvalidParameters, Message: "Missing parameter " + name}))
+}
+
+// evalReply encodes the reply of an eval, a value that cannot be encoded is replied as a failed eval
+func evalReply(v interface{}) string {
+	js, err := json.Marshal(v)
+	if err != nil {
+		js, _ = json.Marshal(NewEvalErrorResult(&FError{"Unable to encode the result", err}))
+	}
+	return string(js)
 }
 
 // AddPluginFDUStartEval ...
 func (lad *LAD) AddPluginFDUStartEval(nodeid string, pluginid string, fduid string, instanceid string, evalcb func(*string) EvalResult) error {
-	s := lad.GetNodeFDUStartEvalPath(nodeid, pluginid, fduid, instanceid)
+	s, err := lad.GetNodeFDUStartEvalPath(nodeid, pluginid, fduid, instanceid)
+	if err != nil {
+		return err
+	}
 
-	cb := func(path *yaks.Path, props yaks.Properties) yaks.Value {
+	cb := func(path *Path, props Properties) string {
 		env, found := props["env"]
 		if found {
 			v := evalcb(&env)
-			yv, _ := json.Marshal(v)
-			sv := yaks.NewStringValue(string(yv))
-			return sv
+			return evalReply(v)
 		}
-		return yaks.NewStringValue(fmt.Sprintf("{\"error\":\"Missing parameter Env\""))
+		return missingParameterReply("env")
 	}
 
-	err := lad.ws.RegisterEval(s, cb)
-	lad.evals = append(lad.evals, s)
-	return err
+	return lad.registerEval(lad.context(), lad.ws, s, cb)
 }
 
 // AddPluginFDURunEval ...
 func (lad *LAD) AddPluginFDURunEval(nodeid string, pluginid string, fduid string, instanceid string, evalcb func(*string) EvalResult) error {
-	s := lad.GetNodeFDURunEvalPath(nodeid, pluginid, fduid, instanceid)
+	s, err := lad.GetNodeFDURunEvalPath(nodeid, pluginid, fduid, instanceid)
+	if err != nil {
+		return err
+	}
 
-	cb := func(path *yaks.Path, props yaks.Properties) yaks.Value {
+	cb := func(path *Path, props Properties) string {
 		env, found := props["env"]
 		if found {
 			v := evalcb(&env)
-			yv, _ := json.Marshal(v)
-			sv := yaks.NewStringValue(string(yv))
-			return sv
+			return evalReply(v)
 		}
-		return yaks.NewStringValue(fmt.Sprintf("{\"error\":\"Missing parameter Env\""))
+		return missingParameterReply("env")
 	}
 
-	err := lad.ws.RegisterEval(s, cb)
-	lad.evals = append(lad.evals, s)
-	return err
+	return lad.registerEval(lad.context(), lad.ws, s, cb)
 }
 
 // AddPluginFDULogEval ...
 func (lad *LAD) AddPluginFDULogEval(nodeid string, pluginid string, fduid string, instanceid string, evalcb func(*string) EvalResult) error {
-	s := lad.GetNodeFDULogEvalPath(nodeid, pluginid, fduid, instanceid)
+	s, err := lad.GetNodeFDULogEvalPath(nodeid, pluginid, fduid, instanceid)
+	if err != nil {
+		return err
+	}
 
-	cb := func(path *yaks.Path, props yaks.Properties) yaks.Value {
+	cb := func(path *Path, props Properties) string {
 
 		v := evalcb(nil)
-		yv, _ := json.Marshal(v)
-		sv := yaks.NewStringValue(string(yv))
-		return sv
+		return evalReply(v)
 
 	}
 
-	err := lad.ws.RegisterEval(s, cb)
-	lad.evals = append(lad.evals, s)
-	return err
+	return lad.registerEval(lad.context(), lad.ws, s, cb)
 }
 
 // AddPluginFDULsEval ...
 func (lad *LAD) AddPluginFDULsEval(nodeid string, pluginid string, fduid string, instanceid string, evalcb func(*string) EvalResult) error {
-	s := lad.GetNodeFDULsEvalPath(nodeid, pluginid, fduid, instanceid)
+	s, err := lad.GetNodeFDULsEvalPath(nodeid, pluginid, fduid, instanceid)
+	if err != nil {
+		return err
+	}
 
-	cb := func(path *yaks.Path, props yaks.Properties) yaks.Value {
+	cb := func(path *Path, props Properties) string {
 
 		v := evalcb(nil)
-		yv, _ := json.Marshal(v)
-		sv := yaks.NewStringValue(string(yv))
-		return sv
+		return evalReply(v)
 
 	}
 
-	err := lad.ws.RegisterEval(s, cb)
-	lad.evals = append(lad.evals, s)
-	return err
+	return lad.registerEval(lad.context(), lad.ws, s, cb)
 }
 
 // AddPluginFDUFileEval ...
 func (lad *LAD) AddPluginFDUFileEval(nodeid string, pluginid string, fduid string, instanceid string, evalcb func(*string) EvalResult) error {
-	s := lad.GetNodeFDUFileEvalPath(nodeid, pluginid, fduid, instanceid)
+	s, err := lad.GetNodeFDUFileEvalPath(nodeid, pluginid, fduid, instanceid)
+	if err != nil {
+		return err
+	}
 
-	cb := func(path *yaks.Path, props yaks.Properties) yaks.Value {
+	cb := func(path *Path, props Properties) string {
 		fName, found := props["filename"]
 		if found {
 			v := evalcb(&fName)
-			yv, _ := json.Marshal(v)
-			sv := yaks.NewStringValue(string(yv))
-			return sv
+			return evalReply(v)
 		}
-		return yaks.NewStringValue(fmt.Sprintf("{\"error\":\"Missing parameter filename\""))
+		return missingParameterReply("filename")
 	}
 
-	err := lad.ws.RegisterEval(s, cb)
-	lad.evals = append(lad.evals, s)
-	return err
+	return lad.registerEval(lad.context(), lad.ws, s, cb)
 }
 
 // RemovePluginFDUStartEval ...
 func (lad *LAD) RemovePluginFDUStartEval(nodeid string, pluginid string, fduid string, instanceid string) error {
-	s := lad.GetNodeFDUStartEvalPath(nodeid, pluginid, fduid, instanceid)
-	r := lad.ws.UnregisterEval(s)
-	return r
+	s, err := lad.GetNodeFDUStartEvalPath(nodeid, pluginid, fduid, instanceid)
+	if err != nil {
+		return err
+	}
+	return lad.RemoveEval(s)
 }
 
 // RemovePluginFDURunEval ...
 func (lad *LAD) RemovePluginFDURunEval(nodeid string, pluginid string, fduid string, instanceid string) error {
-	s := lad.GetNodeFDURunEvalPath(nodeid, pluginid, fduid, instanceid)
-	r := lad.ws.UnregisterEval(s)
-	return r
+	s, err := lad.GetNodeFDURunEvalPath(nodeid, pluginid, fduid, instanceid)
+	if err != nil {
+		return err
+	}
+	return lad.RemoveEval(s)
 }
 
 // RemovePluginFDULogEval ...
 func (lad *LAD) RemovePluginFDULogEval(nodeid string, pluginid string, fduid string, instanceid string) error {
-	s := lad.GetNodeFDULogEvalPath(nodeid, pluginid, fduid, instanceid)
-	r := lad.ws.UnregisterEval(s)
-	return r
+	s, err := lad.GetNodeFDULogEvalPath(nodeid, pluginid, fduid, instanceid)
+	if err != nil {
+		return err
+	}
+	return lad.RemoveEval(s)
 }
 
 // RemovePluginFDULsEval ...
 func (lad *LAD) RemovePluginFDULsEval(nodeid string, pluginid string, fduid string, instanceid string) error {
-	s := lad.GetNodeFDULsEvalPath(nodeid, pluginid, fduid, instanceid)
-	r := lad.ws.UnregisterEval(s)
-	return r
+	s, err := lad.GetNodeFDULsEvalPath(nodeid, pluginid, fduid, instanceid)
+	if err != nil {
+		return err
+	}
+	return lad.RemoveEval(s)
 }
 
 // RemovePluginFDUFileEval ...
 func (lad *LAD) RemovePluginFDUFileEval(nodeid string, pluginid string, fduid string, instanceid string) error {
-	s := lad.GetNodeFDUFileEvalPath(nodeid, pluginid, fduid, instanceid)
-	r := lad.ws.UnregisterEval(s)
-	return r
+	s, err := lad.GetNodeFDUFileEvalPath(nodeid, pluginid, fduid, instanceid)
+	if err != nil {
+		return err
+	}
+	return lad.RemoveEval(s)
 }
 
 // ExecAgentEval ...
 func (lad *LAD) ExecAgentEval(nodeid string, fname string, props map[string]interface{}) (*EvalResult, error) {
-
-	var s *yaks.Selector
-	if len(props) == 0 {
-		s, _ = yaks.NewSelector(lad.GetAgentExecPath(nodeid, fname).ToString())
-	} else {
-		s = lad.GetAgentExecSelectorWithParams(nodeid, fname, props)
-	}
-
-	kvs := lad.ws.Get(s)
-	if len(kvs) == 0 {
-		return nil, &FError{"ExecAgentEval function replied nil", nil}
+	s, err := lad.agentEvalSelector(nodeid, fname, props)
+	if err != nil {
+		return nil, err
 	}
-	v := kvs[0].Value().ToString()
+	return lad.evalResult(s, "ExecAgentEval")
+}
 
-	var genericJSON map[string]interface{}
-	err := json.Unmarshal([]byte(v), &genericJSON)
+// EvalAgent is ExecAgentEval decoding the result in result, as Eval
+func (lad *LAD) EvalAgent(nodeid string, fname string, props map[string]interface{}, result interface{}) (*RawEvalResult, error) {
+	s, err := lad.agentEvalSelector(nodeid, fname, props)
 	if err != nil {
 		return nil, err
 	}
+	return execEval(lad.context(), lad.ws, s, "EvalAgent", result)
+}
 
-	switch t := genericJSON["result"].(type) {
-
-	case map[string]interface{}:
-		msi := genericJSON["result"].(map[string]interface{})
-		js, err := json.Marshal(msi)
-		if err != nil {
-			return nil, err
-		}
-		s := string(js)
-		genericJSON["result"] = s
-
-		evjs, err := json.Marshal(genericJSON)
-		if err != nil {
-			return nil, err
-		}
-		evs := string(evjs)
-
-		sv := EvalResult{}
-		err = json.Unmarshal([]byte(evs), &sv)
-		if err != nil {
-			return nil, err
-		}
-
-		return &sv, nil
-
-	case string:
-		sv := EvalResult{}
-		err = json.Unmarshal([]byte(v), &sv)
-		if err != nil {
-			return nil, err
-		}
-		return &sv, nil
-	default:
-		er := FError{"Unexpected type: " + t.(string), nil}
-		return nil, &er
+func (lad *LAD) agentEvalSelector(nodeid string, fname string, props map[string]interface{}) (*Selector, error) {
+	if len(props) == 0 {
+		return toSelector(lad.GetAgentExecPath(nodeid, fname))
 	}
-
+	return lad.GetAgentExecSelectorWithParams(nodeid, fname, props)
 }
 
 // ExecOSEval ...
 func (lad *LAD) ExecOSEval(nodeid string, fname string, props map[string]interface{}) (*EvalResult, error) {
-
-	var s *yaks.Selector
-	if len(props) == 0 {
-		s, _ = yaks.NewSelector(lad.GetNodeOSExecPath(nodeid, fname).ToString())
-	} else {
-		s = lad.GetNodeOSExecSelectorWithParams(nodeid, fname, props)
-	}
-
-	kvs := lad.ws.Get(s)
-	if len(kvs) == 0 {
-		return nil, &FError{"ExecOSEval function replied nil", nil}
+	s, err := lad.osEvalSelector(nodeid, fname, props)
+	if err != nil {
+		return nil, err
 	}
-	v := kvs[0].Value().ToString()
+	return lad.evalResult(s, "ExecOSEval")
+}
 
-	var genericJSON map[string]interface{}
-	err := json.Unmarshal([]byte(v), &genericJSON)
+// EvalOS is ExecOSEval decoding the result in result, as Eval
+func (lad *LAD) EvalOS(nodeid string, fname string, props map[string]interface{}, result interface{}) (*RawEvalResult, error) {
+	s, err := lad.osEvalSelector(nodeid, fname, props)
 	if err != nil {
 		return nil, err
 	}
+	return execEval(lad.context(), lad.ws, s, "EvalOS", result)
+}
 
-	switch genericJSON["result"].(type) {
-
-	case map[string]interface{}:
-		msi := genericJSON["result"].(map[string]interface{})
-		js, err := json.Marshal(msi)
-		if err != nil {
-			return nil, err
-		}
-		s := string(js)
-		genericJSON["result"] = s
-
-		evjs, err := json.Marshal(genericJSON)
-		if err != nil {
-			return nil, err
-		}
-		evs := string(evjs)
-
-		sv := EvalResult{}
-		err = json.Unmarshal([]byte(evs), &sv)
-		if err != nil {
-			return nil, err
-		}
-
-		return &sv, nil
-
-	case string:
-		sv := EvalResult{}
-		err = json.Unmarshal([]byte(v), &sv)
-		if err != nil {
-			return nil, err
-		}
-		return &sv, nil
-	default:
-		er := FError{"Unexpected type:  " + (reflect.TypeOf(genericJSON["result"]).String()), nil}
-		return nil, &er
+func (lad *LAD) osEvalSelector(nodeid string, fname string, props map[string]interface{}) (*Selector, error) {
+	if len(props) == 0 {
+		return toSelector(lad.GetNodeOSExecPath(nodeid, fname))
 	}
-
+	return lad.GetNodeOSExecSelectorWithParams(nodeid, fname, props)
 }
 
 // ExecNMEval ...
 func (lad *LAD) ExecNMEval(nodeid string, pluginid string, fname string, props map[string]interface{}) (*EvalResult, error) {
-
-	var s *yaks.Selector
-	if len(props) == 0 {
-		s, _ = yaks.NewSelector(lad.GetNodeNMExecPath(nodeid, pluginid, fname).ToString())
-	} else {
-		s = lad.GetNodeNMExecSelectorWithParams(nodeid, pluginid, fname, props)
-	}
-
-	kvs := lad.ws.Get(s)
-	if len(kvs) == 0 {
-		return nil, &FError{"ExecNMEval function replied nil", nil}
+	s, err := lad.nmEvalSelector(nodeid, pluginid, fname, props)
+	if err != nil {
+		return nil, err
 	}
-	v := kvs[0].Value().ToString()
+	return lad.evalResult(s, "ExecNMEval")
+}
 
-	var genericJSON map[string]interface{}
-	err := json.Unmarshal([]byte(v), &genericJSON)
+// EvalNM is ExecNMEval decoding the result in result, as Eval
+func (lad *LAD) EvalNM(nodeid string, pluginid string, fname string, props map[string]interface{}, result interface{}) (*RawEvalResult, error) {
+	s, err := lad.nmEvalSelector(nodeid, pluginid, fname, props)
 	if err != nil {
 		return nil, err
 	}
+	return execEval(lad.context(), lad.ws, s, "EvalNM", result)
+}
 
-	switch t := genericJSON["result"].(type) {
-
-	case map[string]interface{}:
-		msi := genericJSON["result"].(map[string]interface{})
-		js, err := json.Marshal(msi)
-		if err != nil {
-			return nil, err
-		}
-		s := string(js)
-		genericJSON["result"] = s
-
-		evjs, err := json.Marshal(genericJSON)
-		if err != nil {
-			return nil, err
-		}
-		evs := string(evjs)
-
-		sv := EvalResult{}
-		err = json.Unmarshal([]byte(evs), &sv)
-		if err != nil {
-			return nil, err
-		}
-
-		return &sv, nil
-
-	case string:
-		sv := EvalResult{}
-		err = json.Unmarshal([]byte(v), &sv)
-		if err != nil {
-			return nil, err
-		}
-		return &sv, nil
-	default:
-		er := FError{"Unexpected type: " + t.(string), nil}
-		return nil, &er
+func (lad *LAD) nmEvalSelector(nodeid string, pluginid string, fname string, props map[string]interface{}) (*Selector, error) {
+	if len(props) == 0 {
+		return toSelector(lad.GetNodeNMExecPath(nodeid, pluginid, fname))
 	}
-
+	return lad.GetNodeNMExecSelectorWithParams(nodeid, pluginid, fname, props)
 }
 
 // ExecPluginEval ...
 func (lad *LAD) ExecPluginEval(nodeid string, pluginid string, fname string, props map[string]interface{}) (*EvalResult, error) {
-
-	var s *yaks.Selector
-	if len(props) == 0 {
-		s, _ = yaks.NewSelector(lad.GetNodePluginEvalPath(nodeid, pluginid, fname).ToString())
-	} else {
-		s = lad.GetNodePluginEvalSelectorWithParams(nodeid, pluginid, fname, props)
-	}
-
-	kvs := lad.ws.Get(s)
-	if len(kvs) == 0 {
-		return nil, &FError{"ExecPluginEval function replied nil", nil}
+	s, err := lad.pluginEvalSelector(nodeid, pluginid, fname, props)
+	if err != nil {
+		return nil, err
 	}
-	v := kvs[0].Value().ToString()
+	return lad.evalResult(s, "ExecPluginEval")
+}
 
-	var genericJSON map[string]interface{}
-	err := json.Unmarshal([]byte(v), &genericJSON)
+// EvalPlugin is ExecPluginEval decoding the result in result, as Eval
+func (lad *LAD) EvalPlugin(nodeid string, pluginid string, fname string, props map[string]interface{}, result interface{}) (*RawEvalResult, error) {
+	s, err := lad.pluginEvalSelector(nodeid, pluginid, fname, props)
 	if err != nil {
 		return nil, err
 	}
+	return execEval(lad.context(), lad.ws, s, "EvalPlugin", result)
+}
 
-	switch t := genericJSON["result"].(type) {
-
-	case map[string]interface{}:
-		msi := genericJSON["result"].(map[string]interface{})
-		js, err := json.Marshal(msi)
-		if err != nil {
-			return nil, err
-		}
-		s := string(js)
-		genericJSON["result"] = s
-
-		evjs, err := json.Marshal(genericJSON)
-		if err != nil {
-			return nil, err
-		}
-		evs := string(evjs)
-
-		sv := EvalResult{}
-		err = json.Unmarshal([]byte(evs), &sv)
-		if err != nil {
-			return nil, err
-		}
-
-		return &sv, nil
-
-	case string:
-		sv := EvalResult{}
-		err = json.Unmarshal([]byte(v), &sv)
-		if err != nil {
-			return nil, err
-		}
-		return &sv, nil
-	default:
-		er := FError{"Unexpected type: " + t.(string), nil}
-		return nil, &er
+func (lad *LAD) pluginEvalSelector(nodeid string, pluginid string, fname string, props map[string]interface{}) (*Selector, error) {
+	if len(props) == 0 {
+		return toSelector(lad.GetNodePluginEvalPath(nodeid, pluginid, fname))
 	}
-
+	return lad.GetNodePluginEvalSelectorWithParams(nodeid, pluginid, fname, props)
 }
 
 // Node
 
 // AddNodePlugin ...
 func (lad *LAD) AddNodePlugin(nodeid string, pluginid string, info Plugin) error {
-	s := lad.GetNodePlguinInfoPath(nodeid, pluginid)
+	s, err := lad.GetNodePlguinInfoPath(nodeid, pluginid)
+	if err != nil {
+		return err
+	}
 	v, err := json.Marshal(info)
 	if err != nil {
 		return err
 	}
-	sv := yaks.NewStringValue(string(v))
-	err = lad.ws.Put(s, sv)
+	err = lad.ws.Put(lad.context(), s, string(v))
 	return err
 }
 
 // RemoveNodePlugin ...
 func (lad *LAD) RemoveNodePlugin(nodeid string, pluginid string) error {
-	s := lad.GetNodePlguinInfoPath(nodeid, pluginid)
-	return lad.ws.Remove(s)
+	s, err := lad.GetNodePlguinInfoPath(nodeid, pluginid)
+	if err != nil {
+		return err
+	}
+	return lad.ws.Remove(lad.context(), s)
 }
 
 // GetAllPlugins ...
 func (lad *LAD) GetAllPlugins(nodeid string) ([]string, error) {
-	s := lad.GetNodePlguinsSelector(nodeid)
-	kvs := lad.ws.Get(s)
+	s, err := lad.GetNodePlguinsSelector(nodeid)
+	if err != nil {
+		return nil, err
+	}
+	kvs, err := lad.ws.Get(lad.context(), s)
+	if err != nil {
+		return nil, err
+	}
 	if len(kvs) == 0 {
 		return []string{}, nil
 	}
 	var ids []string = []string{}
 	for _, kv := range kvs {
-		p := kv.Path()
-		ids = append(ids, lad.ExtractPluginIDFromPath(p))
+		p := kv.Path
+		id, err := lad.ExtractPluginIDFromPath(p)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
 	}
 	return ids, nil
 }
 
 // GetNodePlugin ...
 func (lad *LAD) GetNodePlugin(nodeid string, pluginid string) (*Plugin, error) {
-	s, _ := yaks.NewSelector(lad.GetNodePlguinInfoPath(nodeid, pluginid).ToString())
-	kvs := lad.ws.Get(s)
+	s, err := toSelector(lad.GetNodePlguinInfoPath(nodeid, pluginid))
+	if err != nil {
+		return nil, err
+	}
+	kvs, err := lad.ws.Get(lad.context(), s)
+	if err != nil {
+		return nil, err
+	}
 	if len(kvs) == 0 {
-		return nil, &FError{"Plugin not Found", nil}
+		return nil, &FError{"Plugin not Found", ErrNotFound}
 	}
-	v := kvs[0].Value().ToString()
+	v := kvs[0].Value
 	sv := Plugin{}
-	err := json.Unmarshal([]byte(v), &sv)
+	err = decode(v, &sv)
 	if err != nil {
 		return nil, err
 	}
@@ -3376,26 +3480,34 @@ func (lad *LAD) GetNodePlugin(nodeid string, pluginid string) (*Plugin, error) {
 
 // AddNodePluginState ...
 func (lad *LAD) AddNodePluginState(nodeid string, pluginid string, state map[string]interface{}) error {
-	s := lad.GetNodePlguinStatePath(nodeid, pluginid)
+	s, err := lad.GetNodePlguinStatePath(nodeid, pluginid)
+	if err != nil {
+		return err
+	}
 	v, err := json.Marshal(state)
 	if err != nil {
 		return err
 	}
-	sv := yaks.NewStringValue(string(v))
-	err = lad.ws.Put(s, sv)
+	err = lad.ws.Put(lad.context(), s, string(v))
 	return err
 }
 
 // GetNodePluginState ...
 func (lad *LAD) GetNodePluginState(nodeid string, pluginid string) (*map[string]interface{}, error) {
-	s, _ := yaks.NewSelector(lad.GetNodePlguinInfoPath(nodeid, pluginid).ToString())
-	kvs := lad.ws.Get(s)
+	s, err := toSelector(lad.GetNodePlguinInfoPath(nodeid, pluginid))
+	if err != nil {
+		return nil, err
+	}
+	kvs, err := lad.ws.Get(lad.context(), s)
+	if err != nil {
+		return nil, err
+	}
 	if len(kvs) == 0 {
-		return nil, &FError{"Plugin not Found", nil}
+		return nil, &FError{"Plugin not Found", ErrNotFound}
 	}
-	v := kvs[0].Value().ToString()
+	v := kvs[0].Value
 	sv := map[string]interface{}{}
-	err := json.Unmarshal([]byte(v), &sv)
+	err = decode(v, &sv)
 	if err != nil {
 		return nil, err
 	}
@@ -3404,40 +3516,123 @@ func (lad *LAD) GetNodePluginState(nodeid string, pluginid string) (*map[string]
 
 // RemoveNodePluginState ...
 func (lad *LAD) RemoveNodePluginState(nodeid string, pluginid string) error {
-	s := lad.GetNodePlguinInfoPath(nodeid, pluginid)
-	err := lad.ws.Remove(s)
+	s, err := lad.GetNodePlguinInfoPath(nodeid, pluginid)
+	if err != nil {
+		return err
+	}
+	err = lad.ws.Remove(lad.context(), s)
 	return err
 }
 
+// AddNodePluginHeartbeat ...
+func (lad *LAD) AddNodePluginHeartbeat(nodeid string, pluginid string, heartbeat PluginHeartbeat) error {
+	s, err := lad.GetNodePluginHeartbeatPath(nodeid, pluginid)
+	if err != nil {
+		return err
+	}
+	v, err := json.Marshal(heartbeat)
+	if err != nil {
+		return err
+	}
+	return lad.ws.Put(lad.context(), s, string(v))
+}
+
+// GetNodePluginHeartbeat ...
+func (lad *LAD) GetNodePluginHeartbeat(nodeid string, pluginid string) (*PluginHeartbeat, error) {
+	s, err := toSelector(lad.GetNodePluginHeartbeatPath(nodeid, pluginid))
+	if err != nil {
+		return nil, err
+	}
+	kvs, err := lad.ws.Get(lad.context(), s)
+	if err != nil {
+		return nil, err
+	}
+	if len(kvs) == 0 {
+		return nil, &FError{"Heartbeat not Found", ErrNotFound}
+	}
+	sv := PluginHeartbeat{}
+	err = decode(kvs[0].Value, &sv)
+	if err != nil {
+		return nil, err
+	}
+	return &sv, nil
+}
+
+// GetNodePluginHeartbeats returns the heartbeats of the plugins of the node by plugin id
+func (lad *LAD) GetNodePluginHeartbeats(nodeid string) (map[string]PluginHeartbeat, error) {
+	s, err := lad.GetNodePluginHeartbeatsSelector(nodeid)
+	if err != nil {
+		return nil, err
+	}
+	kvs, err := lad.ws.Get(lad.context(), s)
+	if err != nil {
+		return nil, err
+	}
+	heartbeats := map[string]PluginHeartbeat{}
+	for _, kv := range kvs {
+		sv := PluginHeartbeat{}
+		err := decode(kv.Value, &sv)
+		if err != nil {
+			return nil, err
+		}
+		pid, err := lad.ExtractPluginIDFromPath(kv.Path)
+		if err != nil {
+			return nil, err
+		}
+		heartbeats[pid] = sv
+	}
+	return heartbeats, nil
+}
+
+// RemoveNodePluginHeartbeat ...
+func (lad *LAD) RemoveNodePluginHeartbeat(nodeid string, pluginid string) error {
+	s, err := lad.GetNodePluginHeartbeatPath(nodeid, pluginid)
+	if err != nil {
+		return err
+	}
+	return lad.ws.Remove(lad.context(), s)
+}
+
 // AddNodeInformation ...
 func (lad *LAD) AddNodeInformation(nodeid string, info NodeInfo) error {
-	s := lad.GetNodeInfoPath(nodeid)
+	s, err := lad.GetNodeInfoPath(nodeid)
+	if err != nil {
+		return err
+	}
 	v, err := json.Marshal(info)
 	if err != nil {
 		return err
 	}
-	sv := yaks.NewStringValue(string(v))
-	err = lad.ws.Put(s, sv)
+	err = lad.ws.Put(lad.context(), s, string(v))
 	return err
 }
 
 // RemoveNodeInformation ...
 func (lad *LAD) RemoveNodeInformation(nodeid string) error {
-	s := lad.GetNodeInfoPath(nodeid)
-	err := lad.ws.Remove(s)
+	s, err := lad.GetNodeInfoPath(nodeid)
+	if err != nil {
+		return err
+	}
+	err = lad.ws.Remove(lad.context(), s)
 	return err
 }
 
 // GetNodeInformation ...
 func (lad *LAD) GetNodeInformation(nodeid string) (*NodeInfo, error) {
-	s, _ := yaks.NewSelector(lad.GetNodeInfoPath(nodeid).ToString())
-	kvs := lad.ws.Get(s)
+	s, err := toSelector(lad.GetNodeInfoPath(nodeid))
+	if err != nil {
+		return nil, err
+	}
+	kvs, err := lad.ws.Get(lad.context(), s)
+	if err != nil {
+		return nil, err
+	}
 	if len(kvs) == 0 {
-		return nil, &FError{"Node information emtpy", nil}
+		return nil, &FError{"Node information emtpy", ErrNotFound}
 	}
-	v := kvs[0].Value().ToString()
+	v := kvs[0].Value
 	sv := NodeInfo{}
-	err := json.Unmarshal([]byte(v), &sv)
+	err = decode(v, &sv)
 	if err != nil {
 		return nil, err
 	}
@@ -3445,58 +3640,68 @@ func (lad *LAD) GetNodeInformation(nodeid string) (*NodeInfo, error) {
 }
 
 // ObserveNodeInformation ...
-func (lad *LAD) ObserveNodeInformation(nodeid string, listener func(NodeInfo)) (*yaks.SubscriptionID, error) {
-	s, _ := yaks.NewSelector(lad.GetNodeInfoPath(nodeid).ToString())
+func (lad *LAD) ObserveNodeInformation(nodeid string, listener func(NodeInfo)) (*SubscriptionID, error) {
+	s, err := toSelector(lad.GetNodeInfoPath(nodeid))
+	if err != nil {
+		return nil, err
+	}
 
-	cb := func(kvs []yaks.Change) {
-		if len(kvs) > 0 {
-			v := kvs[0].Value().ToString()
+	cb := func(kvs []Change) {
+		if len(kvs) > 0 && kvs[0].Kind != REMOVE {
+			v := kvs[0].Value
 			sv := NodeInfo{}
-			err := json.Unmarshal([]byte(v), &sv)
+			err := decode(v, &sv)
 			if err != nil {
-				panic(err.Error())
+				lad.handleError(err)
+				return
 			}
 			listener(sv)
 		}
 	}
 
-	sid, err := lad.ws.Subscribe(s, cb)
-	if err != nil {
-		return nil, err
-	}
-	lad.listeners = append(lad.listeners, sid)
-	return sid, nil
+	return lad.subscribe(lad.context(), lad.ws, s, cb)
 }
 
 // AddNodeStatus ...
 func (lad *LAD) AddNodeStatus(nodeid string, info NodeStatus) error {
-	s := lad.GetNodeStatusPath(nodeid)
+	s, err := lad.GetNodeStatusPath(nodeid)
+	if err != nil {
+		return err
+	}
 	v, err := json.Marshal(info)
 	if err != nil {
 		return err
 	}
-	sv := yaks.NewStringValue(string(v))
-	err = lad.ws.Put(s, sv)
+	err = lad.ws.Put(lad.context(), s, string(v))
 	return err
 }
 
 // RemoveNodeStatus ...
 func (lad *LAD) RemoveNodeStatus(nodeid string) error {
-	s := lad.GetNodeStatusPath(nodeid)
-	err := lad.ws.Remove(s)
+	s, err := lad.GetNodeStatusPath(nodeid)
+	if err != nil {
+		return err
+	}
+	err = lad.ws.Remove(lad.context(), s)
 	return err
 }
 
 // GetNodeStatus ...
 func (lad *LAD) GetNodeStatus(nodeid string) (*NodeStatus, error) {
-	s, _ := yaks.NewSelector(lad.GetNodeStatusPath(nodeid).ToString())
-	kvs := lad.ws.Get(s)
+	s, err := toSelector(lad.GetNodeStatusPath(nodeid))
+	if err != nil {
+		return nil, err
+	}
+	kvs, err := lad.ws.Get(lad.context(), s)
+	if err != nil {
+		return nil, err
+	}
 	if len(kvs) == 0 {
-		return nil, &FError{"Node status emtpy", nil}
+		return nil, &FError{"Node status emtpy", ErrNotFound}
 	}
-	v := kvs[0].Value().ToString()
+	v := kvs[0].Value
 	sv := NodeStatus{}
-	err := json.Unmarshal([]byte(v), &sv)
+	err = decode(v, &sv)
 	if err != nil {
 		return nil, err
 	}
@@ -3504,58 +3709,68 @@ func (lad *LAD) GetNodeStatus(nodeid string) (*NodeStatus, error) {
 }
 
 // ObserveNodeStatus ...
-func (lad *LAD) ObserveNodeStatus(nodeid string, listener func(NodeStatus)) (*yaks.SubscriptionID, error) {
-	s, _ := yaks.NewSelector(lad.GetNodeStatusPath(nodeid).ToString())
+func (lad *LAD) ObserveNodeStatus(nodeid string, listener func(NodeStatus)) (*SubscriptionID, error) {
+	s, err := toSelector(lad.GetNodeStatusPath(nodeid))
+	if err != nil {
+		return nil, err
+	}
 
-	cb := func(kvs []yaks.Change) {
-		if len(kvs) > 0 {
-			v := kvs[0].Value().ToString()
+	cb := func(kvs []Change) {
+		if len(kvs) > 0 && kvs[0].Kind != REMOVE {
+			v := kvs[0].Value
 			sv := NodeStatus{}
-			err := json.Unmarshal([]byte(v), &sv)
+			err := decode(v, &sv)
 			if err != nil {
-				panic(err.Error())
+				lad.handleError(err)
+				return
 			}
 			listener(sv)
 		}
 	}
 
-	sid, err := lad.ws.Subscribe(s, cb)
-	if err != nil {
-		return nil, err
-	}
-	lad.listeners = append(lad.listeners, sid)
-	return sid, nil
+	return lad.subscribe(lad.context(), lad.ws, s, cb)
 }
 
 // AddNodeConfiguration ...
 func (lad *LAD) AddNodeConfiguration(nodeid string, info NodeConfiguration) error {
-	s := lad.GetNodeConfigurationPath(nodeid)
+	s, err := lad.GetNodeConfigurationPath(nodeid)
+	if err != nil {
+		return err
+	}
 	v, err := json.Marshal(info)
 	if err != nil {
 		return err
 	}
-	sv := yaks.NewStringValue(string(v))
-	err = lad.ws.Put(s, sv)
+	err = lad.ws.Put(lad.context(), s, string(v))
 	return err
 }
 
 // RemoveNodeConfiguration ...
 func (lad *LAD) RemoveNodeConfiguration(nodeid string) error {
-	s := lad.GetNodeConfigurationPath(nodeid)
-	err := lad.ws.Remove(s)
+	s, err := lad.GetNodeConfigurationPath(nodeid)
+	if err != nil {
+		return err
+	}
+	err = lad.ws.Remove(lad.context(), s)
 	return err
 }
 
 // GetNodeConfiguration ...
 func (lad *LAD) GetNodeConfiguration(nodeid string) (*NodeConfiguration, error) {
-	s, _ := yaks.NewSelector(lad.GetNodeConfigurationPath(nodeid).ToString())
-	kvs := lad.ws.Get(s)
+	s, err := toSelector(lad.GetNodeConfigurationPath(nodeid))
+	if err != nil {
+		return nil, err
+	}
+	kvs, err := lad.ws.Get(lad.context(), s)
+	if err != nil {
+		return nil, err
+	}
 	if len(kvs) == 0 {
-		return nil, &FError{"Node configuration emtpy", nil}
+		return nil, &FError{"Node configuration emtpy", ErrNotFound}
 	}
-	v := kvs[0].Value().ToString()
+	v := kvs[0].Value
 	sv := NodeConfiguration{}
-	err := json.Unmarshal([]byte(v), &sv)
+	err = decode(v, &sv)
 	if err != nil {
 		return nil, err
 	}
@@ -3563,82 +3778,123 @@ func (lad *LAD) GetNodeConfiguration(nodeid string) (*NodeConfiguration, error)
 }
 
 // ObserveNodeConfiguration ...
-func (lad *LAD) ObserveNodeConfiguration(nodeid string, listener func(NodeConfiguration)) (*yaks.SubscriptionID, error) {
-	s, _ := yaks.NewSelector(lad.GetNodeConfigurationPath(nodeid).ToString())
+func (lad *LAD) ObserveNodeConfiguration(nodeid string, listener func(NodeConfiguration)) (*SubscriptionID, error) {
+	s, err := toSelector(lad.GetNodeConfigurationPath(nodeid))
+	if err != nil {
+		return nil, err
+	}
 
-	cb := func(kvs []yaks.Change) {
-		if len(kvs) > 0 {
-			v := kvs[0].Value().ToString()
+	cb := func(kvs []Change) {
+		if len(kvs) > 0 && kvs[0].Kind != REMOVE {
+			v := kvs[0].Value
 			sv := NodeConfiguration{}
-			err := json.Unmarshal([]byte(v), &sv)
+			err := decode(v, &sv)
 			if err != nil {
-				panic(err.Error())
+				lad.handleError(err)
+				return
 			}
 			listener(sv)
 		}
 	}
 
-	sid, err := lad.ws.Subscribe(s, cb)
-	if err != nil {
-		return nil, err
-	}
-	lad.listeners = append(lad.listeners, sid)
-	return sid, nil
+	return lad.subscribe(lad.context(), lad.ws, s, cb)
 }
 
 // ObserveNodePlugins ...
-func (lad *LAD) ObserveNodePlugins(nodeid string, listener func(Plugin)) (*yaks.SubscriptionID, error) {
-	s := lad.GetNodePlguinsSelector(nodeid)
+func (lad *LAD) ObserveNodePlugins(nodeid string, listener func(Plugin)) (*SubscriptionID, error) {
+	s, err := lad.GetNodePlguinsSelector(nodeid)
+	if err != nil {
+		return nil, err
+	}
 
-	cb := func(kvs []yaks.Change) {
-		if len(kvs) > 0 {
-			v := kvs[0].Value().ToString()
+	cb := func(kvs []Change) {
+		if len(kvs) > 0 && kvs[0].Kind != REMOVE {
+			v := kvs[0].Value
 			sv := Plugin{}
-			err := json.Unmarshal([]byte(v), &sv)
+			err := decode(v, &sv)
 			if err != nil {
-				panic(err.Error())
+				lad.handleError(err)
+				return
 			}
 			listener(sv)
 		}
 	}
 
-	sid, err := lad.ws.Subscribe(s, cb)
+	return lad.subscribe(lad.context(), lad.ws, s, cb)
+}
+
+// ObserveNodePluginsChanges calls listener with the id and the record of the plugins registered or updated in the node,
+// and with a nil record for the removed ones
+func (lad *LAD) ObserveNodePluginsChanges(nodeid string, listener func(string, *Plugin)) (*SubscriptionID, error) {
+	s, err := lad.GetNodePlguinsSelector(nodeid)
 	if err != nil {
 		return nil, err
 	}
-	lad.listeners = append(lad.listeners, sid)
-	return sid, nil
+
+	cb := func(kvs []Change) {
+		for _, kv := range kvs {
+			pid, err := lad.ExtractPluginIDFromPath(kv.Path)
+			if err != nil {
+				lad.handleError(err)
+				continue
+			}
+			if kv.Kind == REMOVE {
+				listener(pid, nil)
+				continue
+			}
+			sv := Plugin{}
+			err = decode(kv.Value, &sv)
+			if err != nil {
+				lad.handleError(err)
+				continue
+			}
+			listener(pid, &sv)
+		}
+	}
+
+	return lad.subscribe(lad.context(), lad.ws, s, cb)
 }
 
 // AddNodeOSInfo ...
 func (lad *LAD) AddNodeOSInfo(nodeid string, info map[string]interface{}) error {
-	s := lad.GetNodeOSInfoPath(nodeid)
+	s, err := lad.GetNodeOSInfoPath(nodeid)
+	if err != nil {
+		return err
+	}
 	v, err := json.Marshal(info)
 	if err != nil {
 		return err
 	}
-	sv := yaks.NewStringValue(string(v))
-	err = lad.ws.Put(s, sv)
+	err = lad.ws.Put(lad.context(), s, string(v))
 	return err
 }
 
 // RemoveNodeOSInfo ...
 func (lad *LAD) RemoveNodeOSInfo(nodeid string) error {
-	s := lad.GetNodeOSInfoPath(nodeid)
-	err := lad.ws.Remove(s)
+	s, err := lad.GetNodeOSInfoPath(nodeid)
+	if err != nil {
+		return err
+	}
+	err = lad.ws.Remove(lad.context(), s)
 	return err
 }
 
 // GetNodeOSInfo ...
 func (lad *LAD) GetNodeOSInfo(nodeid string) (*map[string]interface{}, error) {
-	s, _ := yaks.NewSelector(lad.GetNodeOSInfoPath(nodeid).ToString())
-	kvs := lad.ws.Get(s)
+	s, err := toSelector(lad.GetNodeOSInfoPath(nodeid))
+	if err != nil {
+		return nil, err
+	}
+	kvs, err := lad.ws.Get(lad.context(), s)
+	if err != nil {
+		return nil, err
+	}
 	if len(kvs) == 0 {
-		return nil, &FError{"Node OS info emtpy", nil}
+		return nil, &FError{"Node OS info emtpy", ErrNotFound}
 	}
-	v := kvs[0].Value().ToString()
+	v := kvs[0].Value
 	sv := map[string]interface{}{}
-	err := json.Unmarshal([]byte(v), &sv)
+	err = decode(v, &sv)
 	if err != nil {
 		return nil, err
 	}
@@ -3646,60 +3902,70 @@ func (lad *LAD) GetNodeOSInfo(nodeid string) (*map[string]interface{}, error) {
 }
 
 // ObserveNodeOSInfo ...
-func (lad *LAD) ObserveNodeOSInfo(nodeid string, listener func(map[string]interface{})) (*yaks.SubscriptionID, error) {
-	s, _ := yaks.NewSelector(lad.GetNodeInfoPath(nodeid).ToString())
+func (lad *LAD) ObserveNodeOSInfo(nodeid string, listener func(map[string]interface{})) (*SubscriptionID, error) {
+	s, err := toSelector(lad.GetNodeInfoPath(nodeid))
+	if err != nil {
+		return nil, err
+	}
 
-	cb := func(kvs []yaks.Change) {
-		if len(kvs) > 0 {
-			v := kvs[0].Value().ToString()
+	cb := func(kvs []Change) {
+		if len(kvs) > 0 && kvs[0].Kind != REMOVE {
+			v := kvs[0].Value
 			sv := map[string]interface{}{}
-			err := json.Unmarshal([]byte(v), &sv)
+			err := decode(v, &sv)
 			if err != nil {
-				panic(err.Error())
+				lad.handleError(err)
+				return
 			}
 			listener(sv)
 		}
 	}
 
-	sid, err := lad.ws.Subscribe(s, cb)
-	if err != nil {
-		return nil, err
-	}
-	lad.listeners = append(lad.listeners, sid)
-	return sid, nil
+	return lad.subscribe(lad.context(), lad.ws, s, cb)
 }
 
 // Node FDU
 
 // AddNodeFDU ...
 func (lad *LAD) AddNodeFDU(nodeid string, pluginid string, fduid string, instanceid string, info FDURecord) error {
-	s := lad.GetNodeRuntimeFDUInfoPath(nodeid, pluginid, fduid, instanceid)
+	s, err := lad.GetNodeRuntimeFDUInfoPath(nodeid, pluginid, fduid, instanceid)
+	if err != nil {
+		return err
+	}
 	v, err := json.Marshal(info)
 	if err != nil {
 		return err
 	}
-	sv := yaks.NewStringValue(string(v))
-	err = lad.ws.Put(s, sv)
+	err = lad.ws.Put(lad.context(), s, string(v))
 	return err
 }
 
 // RemoveNodeFDU ...
 func (lad *LAD) RemoveNodeFDU(nodeid string, pluginid string, fduid string, instanceid string) error {
-	s := lad.GetNodeRuntimeFDUInfoPath(nodeid, pluginid, fduid, instanceid)
-	err := lad.ws.Remove(s)
+	s, err := lad.GetNodeRuntimeFDUInfoPath(nodeid, pluginid, fduid, instanceid)
+	if err != nil {
+		return err
+	}
+	err = lad.ws.Remove(lad.context(), s)
 	return err
 }
 
 // GetNodeFDU ...
 func (lad *LAD) GetNodeFDU(nodeid string, pluginid string, fduid string, instanceid string) (*FDURecord, error) {
-	s := lad.GetNodeRuntimeFDUInfoSelector(nodeid, pluginid, fduid, instanceid)
-	kvs := lad.ws.Get(s)
+	s, err := lad.GetNodeRuntimeFDUInfoSelector(nodeid, pluginid, fduid, instanceid)
+	if err != nil {
+		return nil, err
+	}
+	kvs, err := lad.ws.Get(lad.context(), s)
+	if err != nil {
+		return nil, err
+	}
 	if len(kvs) == 0 {
-		return nil, &FError{"FDU Not found", nil}
+		return nil, &FError{"FDU Not found", ErrNotFound}
 	}
-	v := kvs[0].Value().ToString()
+	v := kvs[0].Value
 	sv := FDURecord{}
-	err := json.Unmarshal([]byte(v), &sv)
+	err = decode(v, &sv)
 	if err != nil {
 		return nil, err
 	}
@@ -3708,31 +3974,69 @@ func (lad *LAD) GetNodeFDU(nodeid string, pluginid string, fduid string, instanc
 
 // GetNodeFDUInstances ...
 func (lad *LAD) GetNodeFDUInstances(nodeid string, fduid string) ([]string, error) {
-	s := lad.GetNodeFDUInstancesSelector(nodeid, fduid)
-	kvs := lad.ws.Get(s)
+	s, err := lad.GetNodeFDUInstancesSelector(nodeid, fduid)
+	if err != nil {
+		return nil, err
+	}
+	kvs, err := lad.ws.Get(lad.context(), s)
+	if err != nil {
+		return nil, err
+	}
 	if len(kvs) == 0 {
 		return []string{}, nil
 	}
 	var ids []string = []string{}
 	for _, kv := range kvs {
-		p := kv.Path()
-		ids = append(ids, lad.ExtractNodeInstanceIDFromPath(p))
+		p := kv.Path
+		id, err := lad.ExtractNodeInstanceIDFromPath(p)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
 	}
 	return ids, nil
 }
 
 // GetNodeAllFDUsInstances ...
 func (lad *LAD) GetNodeAllFDUsInstances(nodeid string) ([]FDURecord, error) {
-	s := lad.GetNodeFDUIAllnstancesSelector(nodeid)
-	kvs := lad.ws.Get(s)
+	s, err := lad.GetNodeFDUIAllnstancesSelector(nodeid)
+	if err != nil {
+		return nil, err
+	}
+	kvs, err := lad.ws.Get(lad.context(), s)
+	if err != nil {
+		return nil, err
+	}
 	if len(kvs) == 0 {
 		return []FDURecord{}, nil
 	}
 	var instances []FDURecord = []FDURecord{}
 	for _, kv := range kvs {
-		v := kv.Value().ToString()
+		v := kv.Value
+		sv := FDURecord{}
+		err := decode(v, &sv)
+		if err != nil {
+			return nil, err
+		}
+		instances = append(instances, sv)
+	}
+	return instances, nil
+}
+
+// GetNodeRuntimeFDUs returns the FDU instance records of the runtime plugin in the node
+func (lad *LAD) GetNodeRuntimeFDUs(nodeid string, pluginid string) ([]FDURecord, error) {
+	s, err := lad.GetNodeRuntimeFDUsSelector(nodeid, pluginid)
+	if err != nil {
+		return nil, err
+	}
+	kvs, err := lad.ws.Get(lad.context(), s)
+	if err != nil {
+		return nil, err
+	}
+	instances := []FDURecord{}
+	for _, kv := range kvs {
 		sv := FDURecord{}
-		err := json.Unmarshal([]byte(v), &sv)
+		err := decode(kv.Value, &sv)
 		if err != nil {
 			return nil, err
 		}
@@ -3742,60 +4046,70 @@ func (lad *LAD) GetNodeAllFDUsInstances(nodeid string) ([]FDURecord, error) {
 }
 
 // ObserveNodeRuntimeFDU ...
-func (lad *LAD) ObserveNodeRuntimeFDU(nodeid string, pluginid string, listener func(FDURecord)) (*yaks.SubscriptionID, error) {
-	s := lad.GetNodeRuntimeFDUsSelector(nodeid, pluginid)
+func (lad *LAD) ObserveNodeRuntimeFDU(nodeid string, pluginid string, listener func(FDURecord)) (*SubscriptionID, error) {
+	s, err := lad.GetNodeRuntimeFDUsSelector(nodeid, pluginid)
+	if err != nil {
+		return nil, err
+	}
 
-	cb := func(kvs []yaks.Change) {
-		if len(kvs) > 0 {
-			v := kvs[0].Value().ToString()
+	cb := func(kvs []Change) {
+		if len(kvs) > 0 && kvs[0].Kind != REMOVE {
+			v := kvs[0].Value
 			sv := FDURecord{}
-			err := json.Unmarshal([]byte(v), &sv)
+			err := decode(v, &sv)
 			if err != nil {
-				panic(err.Error())
+				lad.handleError(err)
+				return
 			}
 			listener(sv)
 		}
 	}
 
-	sid, err := lad.ws.Subscribe(s, cb)
-	if err != nil {
-		return nil, err
-	}
-	lad.listeners = append(lad.listeners, sid)
-	return sid, nil
+	return lad.subscribe(lad.context(), lad.ws, s, cb)
 }
 
 // Node Images
 
 // AddNodeImage ...
 func (lad *LAD) AddNodeImage(nodeid string, pluginid string, imgid string, info FDUImage) error {
-	s := lad.GetNodeIimageInfoPath(nodeid, pluginid, imgid)
+	s, err := lad.GetNodeIimageInfoPath(nodeid, pluginid, imgid)
+	if err != nil {
+		return err
+	}
 	v, err := json.Marshal(info)
 	if err != nil {
 		return err
 	}
-	sv := yaks.NewStringValue(string(v))
-	err = lad.ws.Put(s, sv)
+	err = lad.ws.Put(lad.context(), s, string(v))
 	return err
 }
 
 // RemoveNodeImage ...
 func (lad *LAD) RemoveNodeImage(nodeid string, pluginid string, imgid string) error {
-	s := lad.GetNodeIimageInfoPath(nodeid, pluginid, imgid)
-	err := lad.ws.Remove(s)
+	s, err := lad.GetNodeIimageInfoPath(nodeid, pluginid, imgid)
+	if err != nil {
+		return err
+	}
+	err = lad.ws.Remove(lad.context(), s)
 	return err
 }
 
 // GetNodeImage ...
 func (lad *LAD) GetNodeImage(nodeid string, pluginid string, imgid string) (*FDUImage, error) {
-	s, _ := yaks.NewSelector(lad.GetNodeIimageInfoPath(nodeid, pluginid, imgid).ToString())
-	kvs := lad.ws.Get(s)
+	s, err := toSelector(lad.GetNodeIimageInfoPath(nodeid, pluginid, imgid))
+	if err != nil {
+		return nil, err
+	}
+	kvs, err := lad.ws.Get(lad.context(), s)
+	if err != nil {
+		return nil, err
+	}
 	if len(kvs) == 0 {
-		return nil, &FError{"Image Not found", nil}
+		return nil, &FError{"Image Not found", ErrNotFound}
 	}
-	v := kvs[0].Value().ToString()
+	v := kvs[0].Value
 	sv := FDUImage{}
-	err := json.Unmarshal([]byte(v), &sv)
+	err = decode(v, &sv)
 	if err != nil {
 		return nil, err
 	}
@@ -3806,33 +4120,44 @@ func (lad *LAD) GetNodeImage(nodeid string, pluginid string, imgid string) (*FDU
 
 // AddNodeFlavor ...
 func (lad *LAD) AddNodeFlavor(nodeid string, pluginid string, flvid string, info FDUComputationalRequirements) error {
-	s := lad.GetNodeFlavorInfoPath(nodeid, pluginid, flvid)
+	s, err := lad.GetNodeFlavorInfoPath(nodeid, pluginid, flvid)
+	if err != nil {
+		return err
+	}
 	v, err := json.Marshal(info)
 	if err != nil {
 		return err
 	}
-	sv := yaks.NewStringValue(string(v))
-	err = lad.ws.Put(s, sv)
+	err = lad.ws.Put(lad.context(), s, string(v))
 	return err
 }
 
 // RemoveNodeFlavor ...
 func (lad *LAD) RemoveNodeFlavor(nodeid string, pluginid string, flvid string) error {
-	s := lad.GetNodeFlavorInfoPath(nodeid, pluginid, flvid)
-	err := lad.ws.Remove(s)
+	s, err := lad.GetNodeFlavorInfoPath(nodeid, pluginid, flvid)
+	if err != nil {
+		return err
+	}
+	err = lad.ws.Remove(lad.context(), s)
 	return err
 }
 
 // GetNodeFlavor ...
 func (lad *LAD) GetNodeFlavor(nodeid string, pluginid string, flvid string) (*FDUComputationalRequirements, error) {
-	s, _ := yaks.NewSelector(lad.GetNodeIimageInfoPath(nodeid, pluginid, flvid).ToString())
-	kvs := lad.ws.Get(s)
+	s, err := toSelector(lad.GetNodeIimageInfoPath(nodeid, pluginid, flvid))
+	if err != nil {
+		return nil, err
+	}
+	kvs, err := lad.ws.Get(lad.context(), s)
+	if err != nil {
+		return nil, err
+	}
 	if len(kvs) == 0 {
-		return nil, &FError{"Flavor Not found", nil}
+		return nil, &FError{"Flavor Not found", ErrNotFound}
 	}
-	v := kvs[0].Value().ToString()
+	v := kvs[0].Value
 	sv := FDUComputationalRequirements{}
-	err := json.Unmarshal([]byte(v), &sv)
+	err = decode(v, &sv)
 	if err != nil {
 		return nil, err
 	}
@@ -3843,33 +4168,44 @@ func (lad *LAD) GetNodeFlavor(nodeid string, pluginid string, flvid string) (*FD
 
 // AddNodeNetwork ...
 func (lad *LAD) AddNodeNetwork(nodeid string, pluginid string, netid string, info VirtualNetwork) error {
-	s := lad.GetNodeNetworkInfoPath(nodeid, pluginid, netid)
+	s, err := lad.GetNodeNetworkInfoPath(nodeid, pluginid, netid)
+	if err != nil {
+		return err
+	}
 	v, err := json.Marshal(info)
 	if err != nil {
 		return err
 	}
-	sv := yaks.NewStringValue(string(v))
-	err = lad.ws.Put(s, sv)
+	err = lad.ws.Put(lad.context(), s, string(v))
 	return err
 }
 
 // RemoveNodeNetwork ...
 func (lad *LAD) RemoveNodeNetwork(nodeid string, pluginid string, netid string) error {
-	s := lad.GetNodeNetworkInfoPath(nodeid, pluginid, netid)
-	err := lad.ws.Remove(s)
+	s, err := lad.GetNodeNetworkInfoPath(nodeid, pluginid, netid)
+	if err != nil {
+		return err
+	}
+	err = lad.ws.Remove(lad.context(), s)
 	return err
 }
 
 // GetNodeNetwork ...
 func (lad *LAD) GetNodeNetwork(nodeid string, pluginid string, netid string) (*VirtualNetwork, error) {
-	s, _ := yaks.NewSelector(lad.GetNodeNetworkInfoPath(nodeid, pluginid, netid).ToString())
-	kvs := lad.ws.Get(s)
+	s, err := toSelector(lad.GetNodeNetworkInfoPath(nodeid, pluginid, netid))
+	if err != nil {
+		return nil, err
+	}
+	kvs, err := lad.ws.Get(lad.context(), s)
+	if err != nil {
+		return nil, err
+	}
 	if len(kvs) == 0 {
-		return nil, &FError{"Network Not found", nil}
+		return nil, &FError{"Network Not found", ErrNotFound}
 	}
-	v := kvs[0].Value().ToString()
+	v := kvs[0].Value
 	sv := VirtualNetwork{}
-	err := json.Unmarshal([]byte(v), &sv)
+	err = decode(v, &sv)
 	if err != nil {
 		return nil, err
 	}
@@ -3878,14 +4214,20 @@ func (lad *LAD) GetNodeNetwork(nodeid string, pluginid string, netid string) (*V
 
 // FindNodeNetwork ...
 func (lad *LAD) FindNodeNetwork(nodeid string, netid string) (*VirtualNetwork, error) {
-	s := lad.GetNodeNetworksFindSelector(nodeid, netid)
-	kvs := lad.ws.Get(s)
+	s, err := lad.GetNodeNetworksFindSelector(nodeid, netid)
+	if err != nil {
+		return nil, err
+	}
+	kvs, err := lad.ws.Get(lad.context(), s)
+	if err != nil {
+		return nil, err
+	}
 	if len(kvs) == 0 {
-		return nil, &FError{"Network Not found", nil}
+		return nil, &FError{"Network Not found", ErrNotFound}
 	}
-	v := kvs[0].Value().ToString()
+	v := kvs[0].Value
 	sv := VirtualNetwork{}
-	err := json.Unmarshal([]byte(v), &sv)
+	err = decode(v, &sv)
 	if err != nil {
 		return nil, err
 	}
@@ -3895,16 +4237,22 @@ func (lad *LAD) FindNodeNetwork(nodeid string, netid string) (*VirtualNetwork, e
 // GetAllNodeNetworks ...
 func (lad *LAD) GetAllNodeNetworks(nodeid string, plugindid string) ([]VirtualNetwork, error) {
 	var nets []VirtualNetwork = []VirtualNetwork{}
-	s := lad.GetNodeNetworksSelector(nodeid, plugindid)
-	kvs := lad.ws.Get(s)
+	s, err := lad.GetNodeNetworksSelector(nodeid, plugindid)
+	if err != nil {
+		return nil, err
+	}
+	kvs, err := lad.ws.Get(lad.context(), s)
+	if err != nil {
+		return nil, err
+	}
 	if len(kvs) == 0 {
 		return nets, nil
 	}
 
 	for _, kv := range kvs {
-		v := kv.Value().ToString()
+		v := kv.Value
 		sv := VirtualNetwork{}
-		err := json.Unmarshal([]byte(v), &sv)
+		err := decode(v, &sv)
 		if err != nil {
 			return nil, err
 		}
@@ -3914,58 +4262,68 @@ func (lad *LAD) GetAllNodeNetworks(nodeid string, plugindid string) ([]VirtualNe
 }
 
 // ObserveNodeNetworks ...
-func (lad *LAD) ObserveNodeNetworks(nodeid string, pluginid string, listener func(VirtualNetwork)) (*yaks.SubscriptionID, error) {
-	s := lad.GetNodeNetworksSelector(nodeid, pluginid)
+func (lad *LAD) ObserveNodeNetworks(nodeid string, pluginid string, listener func(VirtualNetwork)) (*SubscriptionID, error) {
+	s, err := lad.GetNodeNetworksSelector(nodeid, pluginid)
+	if err != nil {
+		return nil, err
+	}
 
-	cb := func(kvs []yaks.Change) {
-		if len(kvs) > 0 {
-			v := kvs[0].Value().ToString()
+	cb := func(kvs []Change) {
+		if len(kvs) > 0 && kvs[0].Kind != REMOVE {
+			v := kvs[0].Value
 			sv := VirtualNetwork{}
-			err := json.Unmarshal([]byte(v), &sv)
+			err := decode(v, &sv)
 			if err != nil {
-				panic(err.Error())
+				lad.handleError(err)
+				return
 			}
 			listener(sv)
 		}
 	}
 
-	sid, err := lad.ws.Subscribe(s, cb)
-	if err != nil {
-		return nil, err
-	}
-	lad.listeners = append(lad.listeners, sid)
-	return sid, nil
+	return lad.subscribe(lad.context(), lad.ws, s, cb)
 }
 
 // AddNodePort ...
 func (lad *LAD) AddNodePort(nodeid string, pluginid string, portid string, info ConnectionPointRecord) error {
-	s := lad.GetNodeNetworkPortInfoPath(nodeid, pluginid, portid)
+	s, err := lad.GetNodeNetworkPortInfoPath(nodeid, pluginid, portid)
+	if err != nil {
+		return err
+	}
 	v, err := json.Marshal(info)
 	if err != nil {
 		return err
 	}
-	sv := yaks.NewStringValue(string(v))
-	err = lad.ws.Put(s, sv)
+	err = lad.ws.Put(lad.context(), s, string(v))
 	return err
 }
 
 // RemoveNodePort ...
 func (lad *LAD) RemoveNodePort(nodeid string, pluginid string, portid string) error {
-	s := lad.GetNodeNetworkPortInfoPath(nodeid, pluginid, portid)
-	err := lad.ws.Remove(s)
+	s, err := lad.GetNodeNetworkPortInfoPath(nodeid, pluginid, portid)
+	if err != nil {
+		return err
+	}
+	err = lad.ws.Remove(lad.context(), s)
 	return err
 }
 
 // GetNodePort ...
 func (lad *LAD) GetNodePort(nodeid string, pluginid string, portid string) (*ConnectionPointRecord, error) {
-	s, _ := yaks.NewSelector(lad.GetNodeNetworkInfoPath(nodeid, pluginid, portid).ToString())
-	kvs := lad.ws.Get(s)
+	s, err := toSelector(lad.GetNodeNetworkPortInfoPath(nodeid, pluginid, portid))
+	if err != nil {
+		return nil, err
+	}
+	kvs, err := lad.ws.Get(lad.context(), s)
+	if err != nil {
+		return nil, err
+	}
 	if len(kvs) == 0 {
-		return nil, &FError{"Port Not found", nil}
+		return nil, &FError{"Port Not found", ErrNotFound}
 	}
-	v := kvs[0].Value().ToString()
+	v := kvs[0].Value
 	sv := ConnectionPointRecord{}
-	err := json.Unmarshal([]byte(v), &sv)
+	err = decode(v, &sv)
 	if err != nil {
 		return nil, err
 	}
@@ -3974,17 +4332,23 @@ func (lad *LAD) GetNodePort(nodeid string, pluginid string, portid string) (*Con
 
 // GetAllNodePorts ...
 func (lad *LAD) GetAllNodePorts(nodeid string, plugindid string) ([]ConnectionPointRecord, error) {
-	s := lad.GetNodeNetworksSelector(nodeid, plugindid)
+	s, err := lad.GetNodeNetworkPortsSelector(nodeid, plugindid)
+	if err != nil {
+		return nil, err
+	}
 	var ports []ConnectionPointRecord = []ConnectionPointRecord{}
-	kvs := lad.ws.Get(s)
+	kvs, err := lad.ws.Get(lad.context(), s)
+	if err != nil {
+		return nil, err
+	}
 	if len(kvs) == 0 {
 		return ports, nil
 	}
 
 	for _, kv := range kvs {
-		v := kv.Value().ToString()
+		v := kv.Value
 		sv := ConnectionPointRecord{}
-		err := json.Unmarshal([]byte(v), &sv)
+		err := decode(v, &sv)
 		if err != nil {
 			return nil, err
 		}
@@ -3994,58 +4358,68 @@ func (lad *LAD) GetAllNodePorts(nodeid string, plugindid string) ([]ConnectionPo
 }
 
 // ObserveNodePorts ...
-func (lad *LAD) ObserveNodePorts(nodeid string, pluginid string, listener func(ConnectionPointRecord)) (*yaks.SubscriptionID, error) {
-	s := lad.GetNodeNetworkPortsSelector(nodeid, pluginid)
+func (lad *LAD) ObserveNodePorts(nodeid string, pluginid string, listener func(ConnectionPointRecord)) (*SubscriptionID, error) {
+	s, err := lad.GetNodeNetworkPortsSelector(nodeid, pluginid)
+	if err != nil {
+		return nil, err
+	}
 
-	cb := func(kvs []yaks.Change) {
-		if len(kvs) > 0 {
-			v := kvs[0].Value().ToString()
+	cb := func(kvs []Change) {
+		if len(kvs) > 0 && kvs[0].Kind != REMOVE {
+			v := kvs[0].Value
 			sv := ConnectionPointRecord{}
-			err := json.Unmarshal([]byte(v), &sv)
+			err := decode(v, &sv)
 			if err != nil {
-				panic(err.Error())
+				lad.handleError(err)
+				return
 			}
 			listener(sv)
 		}
 	}
 
-	sid, err := lad.ws.Subscribe(s, cb)
-	if err != nil {
-		return nil, err
-	}
-	lad.listeners = append(lad.listeners, sid)
-	return sid, nil
+	return lad.subscribe(lad.context(), lad.ws, s, cb)
 }
 
 // AddNodeRouter ...
 func (lad *LAD) AddNodeRouter(nodeid string, pluginid string, routerid string, info RouterRecord) error {
-	s := lad.GetNodeNetworkRouterInfoPath(nodeid, pluginid, routerid)
+	s, err := lad.GetNodeNetworkRouterInfoPath(nodeid, pluginid, routerid)
+	if err != nil {
+		return err
+	}
 	v, err := json.Marshal(info)
 	if err != nil {
 		return err
 	}
-	sv := yaks.NewStringValue(string(v))
-	err = lad.ws.Put(s, sv)
+	err = lad.ws.Put(lad.context(), s, string(v))
 	return err
 }
 
 // RemoveNodeRouter ...
 func (lad *LAD) RemoveNodeRouter(nodeid string, pluginid string, routerid string) error {
-	s := lad.GetNodeNetworkRouterInfoPath(nodeid, pluginid, routerid)
-	err := lad.ws.Remove(s)
+	s, err := lad.GetNodeNetworkRouterInfoPath(nodeid, pluginid, routerid)
+	if err != nil {
+		return err
+	}
+	err = lad.ws.Remove(lad.context(), s)
 	return err
 }
 
 // GetNodeRouter ...
 func (lad *LAD) GetNodeRouter(nodeid string, pluginid string, routerid string) (*RouterRecord, error) {
-	s, _ := yaks.NewSelector(lad.GetNodeNetworkRouterInfoPath(nodeid, pluginid, routerid).ToString())
-	kvs := lad.ws.Get(s)
+	s, err := toSelector(lad.GetNodeNetworkRouterInfoPath(nodeid, pluginid, routerid))
+	if err != nil {
+		return nil, err
+	}
+	kvs, err := lad.ws.Get(lad.context(), s)
+	if err != nil {
+		return nil, err
+	}
 	if len(kvs) == 0 {
-		return nil, &FError{"Router Not found", nil}
+		return nil, &FError{"Router Not found", ErrNotFound}
 	}
-	v := kvs[0].Value().ToString()
+	v := kvs[0].Value
 	sv := RouterRecord{}
-	err := json.Unmarshal([]byte(v), &sv)
+	err = decode(v, &sv)
 	if err != nil {
 		return nil, err
 	}
@@ -4054,17 +4428,23 @@ func (lad *LAD) GetNodeRouter(nodeid string, pluginid string, routerid string) (
 
 // GetAllNodeRouters ...
 func (lad *LAD) GetAllNodeRouters(nodeid string, plugindid string) ([]RouterRecord, error) {
-	s := lad.GetNodeNetworkRoutersSelector(nodeid, plugindid)
+	s, err := lad.GetNodeNetworkRoutersSelector(nodeid, plugindid)
+	if err != nil {
+		return nil, err
+	}
 	var routers []RouterRecord = []RouterRecord{}
-	kvs := lad.ws.Get(s)
+	kvs, err := lad.ws.Get(lad.context(), s)
+	if err != nil {
+		return nil, err
+	}
 	if len(kvs) == 0 {
 		return routers, nil
 	}
 
 	for _, kv := range kvs {
-		v := kv.Value().ToString()
+		v := kv.Value
 		sv := RouterRecord{}
-		err := json.Unmarshal([]byte(v), &sv)
+		err := decode(v, &sv)
 		if err != nil {
 			return nil, err
 		}
@@ -4074,58 +4454,68 @@ func (lad *LAD) GetAllNodeRouters(nodeid string, plugindid string) ([]RouterReco
 }
 
 // ObserveNodeRouters ...
-func (lad *LAD) ObserveNodeRouters(nodeid string, pluginid string, listener func(RouterRecord)) (*yaks.SubscriptionID, error) {
-	s := lad.GetNodeNetworkRoutersSelector(nodeid, pluginid)
+func (lad *LAD) ObserveNodeRouters(nodeid string, pluginid string, listener func(RouterRecord)) (*SubscriptionID, error) {
+	s, err := lad.GetNodeNetworkRoutersSelector(nodeid, pluginid)
+	if err != nil {
+		return nil, err
+	}
 
-	cb := func(kvs []yaks.Change) {
-		if len(kvs) > 0 {
-			v := kvs[0].Value().ToString()
+	cb := func(kvs []Change) {
+		if len(kvs) > 0 && kvs[0].Kind != REMOVE {
+			v := kvs[0].Value
 			sv := RouterRecord{}
-			err := json.Unmarshal([]byte(v), &sv)
+			err := decode(v, &sv)
 			if err != nil {
-				panic(err.Error())
+				lad.handleError(err)
+				return
 			}
 			listener(sv)
 		}
 	}
 
-	sid, err := lad.ws.Subscribe(s, cb)
-	if err != nil {
-		return nil, err
-	}
-	lad.listeners = append(lad.listeners, sid)
-	return sid, nil
+	return lad.subscribe(lad.context(), lad.ws, s, cb)
 }
 
 // AddNodeFloatingIP ...
 func (lad *LAD) AddNodeFloatingIP(nodeid string, pluginid string, ipid string, info FloatingIPRecord) error {
-	s := lad.GetNodeNetworkFloatingIPInfoPath(nodeid, pluginid, ipid)
+	s, err := lad.GetNodeNetworkFloatingIPInfoPath(nodeid, pluginid, ipid)
+	if err != nil {
+		return err
+	}
 	v, err := json.Marshal(info)
 	if err != nil {
 		return err
 	}
-	sv := yaks.NewStringValue(string(v))
-	err = lad.ws.Put(s, sv)
+	err = lad.ws.Put(lad.context(), s, string(v))
 	return err
 }
 
 // RemoveNodeFloatingIP ...
 func (lad *LAD) RemoveNodeFloatingIP(nodeid string, pluginid string, ipid string) error {
-	s := lad.GetNodeNetworkFloatingIPInfoPath(nodeid, pluginid, ipid)
-	err := lad.ws.Remove(s)
+	s, err := lad.GetNodeNetworkFloatingIPInfoPath(nodeid, pluginid, ipid)
+	if err != nil {
+		return err
+	}
+	err = lad.ws.Remove(lad.context(), s)
 	return err
 }
 
 // GetNodeFloatingIP ...
 func (lad *LAD) GetNodeFloatingIP(nodeid string, pluginid string, ipid string) (*FloatingIPRecord, error) {
-	s, _ := yaks.NewSelector(lad.GetNodeNetworkFloatingIPInfoPath(nodeid, pluginid, ipid).ToString())
-	kvs := lad.ws.Get(s)
+	s, err := toSelector(lad.GetNodeNetworkFloatingIPInfoPath(nodeid, pluginid, ipid))
+	if err != nil {
+		return nil, err
+	}
+	kvs, err := lad.ws.Get(lad.context(), s)
+	if err != nil {
+		return nil, err
+	}
 	if len(kvs) == 0 {
-		return nil, &FError{"Floating IP not found", nil}
+		return nil, &FError{"Floating IP not found", ErrNotFound}
 	}
-	v := kvs[0].Value().ToString()
+	v := kvs[0].Value
 	sv := FloatingIPRecord{}
-	err := json.Unmarshal([]byte(v), &sv)
+	err = decode(v, &sv)
 	if err != nil {
 		return nil, err
 	}
@@ -4134,17 +4524,23 @@ func (lad *LAD) GetNodeFloatingIP(nodeid string, pluginid string, ipid string) (
 
 // GetAllNodeFloatingIPs ...
 func (lad *LAD) GetAllNodeFloatingIPs(nodeid string, plugindid string) ([]FloatingIPRecord, error) {
-	s := lad.GetNodeNetworkFloatingIPsSelector(nodeid, plugindid)
+	s, err := lad.GetNodeNetworkFloatingIPsSelector(nodeid, plugindid)
+	if err != nil {
+		return nil, err
+	}
 	var ips []FloatingIPRecord = []FloatingIPRecord{}
-	kvs := lad.ws.Get(s)
+	kvs, err := lad.ws.Get(lad.context(), s)
+	if err != nil {
+		return nil, err
+	}
 	if len(kvs) == 0 {
 		return ips, nil
 	}
 
 	for _, kv := range kvs {
-		v := kv.Value().ToString()
+		v := kv.Value
 		sv := FloatingIPRecord{}
-		err := json.Unmarshal([]byte(v), &sv)
+		err := decode(v, &sv)
 		if err != nil {
 			return nil, err
 		}
@@ -4154,93 +4550,93 @@ func (lad *LAD) GetAllNodeFloatingIPs(nodeid string, plugindid string) ([]Floati
 }
 
 // ObserveNodeFloatingIPs ...
-func (lad *LAD) ObserveNodeFloatingIPs(nodeid string, pluginid string, listener func(FloatingIPRecord)) (*yaks.SubscriptionID, error) {
-	s := lad.GetNodeNetworkFloatingIPsSelector(nodeid, pluginid)
+func (lad *LAD) ObserveNodeFloatingIPs(nodeid string, pluginid string, listener func(FloatingIPRecord)) (*SubscriptionID, error) {
+	s, err := lad.GetNodeNetworkFloatingIPsSelector(nodeid, pluginid)
+	if err != nil {
+		return nil, err
+	}
 
-	cb := func(kvs []yaks.Change) {
-		if len(kvs) > 0 {
-			v := kvs[0].Value().ToString()
+	cb := func(kvs []Change) {
+		if len(kvs) > 0 && kvs[0].Kind != REMOVE {
+			v := kvs[0].Value
 			sv := FloatingIPRecord{}
-			err := json.Unmarshal([]byte(v), &sv)
+			err := decode(v, &sv)
 			if err != nil {
-				panic(err.Error())
+				lad.handleError(err)
+				return
 			}
 			listener(sv)
 		}
 	}
 
-	sid, err := lad.ws.Subscribe(s, cb)
-	if err != nil {
-		return nil, err
-	}
-	lad.listeners = append(lad.listeners, sid)
-	return sid, nil
+	return lad.subscribe(lad.context(), lad.ws, s, cb)
 }
 
 // Global and Local
 
 // Global is Global Actual and Desired
 type Global struct {
-	ws      *yaks.Workspace
+	ws      Store
 	Actual  GAD
 	Desired GAD
 }
 
 // NewGlobal ...
-func NewGlobal(wspace *yaks.Workspace) Global {
-	ac := GAD{evals: []*yaks.Path{}, listeners: []*yaks.SubscriptionID{}, prefix: GlobalActualPrefix, ws: wspace}
-	ds := GAD{evals: []*yaks.Path{}, listeners: []*yaks.SubscriptionID{}, prefix: GlobalDesiredPrefix, ws: wspace}
-	return Global{ws: wspace, Actual: ac, Desired: ds}
+func NewGlobal(store Store, opts ...ConnectorOption) Global {
+	o := NewConnectorOptions(opts...)
+	ac := GAD{handlers: newHandlers(o.Logger), prefix: GlobalActualPrefix, ws: store}
+	ds := GAD{handlers: newHandlers(o.Logger), prefix: GlobalDesiredPrefix, ws: store}
+	return Global{ws: store, Actual: ac, Desired: ds}
 
 }
 
 // Local is Global Actual and Desired
 type Local struct {
-	ws      *yaks.Workspace
+	ws      Store
 	Actual  LAD
 	Desired LAD
 }
 
 // NewLocal ...
-func NewLocal(wspace *yaks.Workspace) Local {
-	ac := LAD{evals: []*yaks.Path{}, listeners: []*yaks.SubscriptionID{}, prefix: LocalActualPrefix, ws: wspace}
-	ds := LAD{evals: []*yaks.Path{}, listeners: []*yaks.SubscriptionID{}, prefix: LocalDesiredPrefix, ws: wspace}
-	return Local{ws: wspace, Actual: ac, Desired: ds}
+func NewLocal(store Store, opts ...ConnectorOption) Local {
+	o := NewConnectorOptions(opts...)
+	ac := LAD{handlers: newHandlers(o.Logger), prefix: LocalActualPrefix, ws: store}
+	ds := LAD{handlers: newHandlers(o.Logger), prefix: LocalDesiredPrefix, ws: store}
+	return Local{ws: store, Actual: ac, Desired: ds}
 }
 
 // YaksConnector is Yaks Connector
 type YaksConnector struct {
-	yclient *yaks.Yaks
-	yadmin  *yaks.Admin
-	ws      *yaks.Workspace
-	Global  Global
-	Local   Local
+	ws     Store
+	Global Global
+	Local  Local
 }
 
-// Close ...
+// Close unsubscribes all the listeners and removes all the evals of the Global and Local GADs and LADs, then closes the Store.
+// The Store is closed also when the cleanup fails, all the errors are returned in a MultiError
 func (yc *YaksConnector) Close() error {
-	return yc.yclient.Logout()
+	var errs []error
+	errs = appendError(errs, yc.Global.Actual.Close())
+	errs = appendError(errs, yc.Global.Desired.Close())
+	errs = appendError(errs, yc.Local.Actual.Close())
+	errs = appendError(errs, yc.Local.Desired.Close())
+	errs = appendError(errs, yc.ws.Close())
+	return multiError(errs)
+}
+
+// ObserveConnectionState registers a listener called on each change of the connection state, fails if the Store does not notify them
+func (yc *YaksConnector) ObserveConnectionState(listener func(ConnectionState)) error {
+	n, ok := yc.ws.(ConnectionStateNotifier)
+	if !ok {
+		return &FError{"Store does not notify connection state changes", nil}
+	}
+	n.ObserveConnectionState(listener)
+	return nil
 }
 
-// NewYaksConnector ...
-func NewYaksConnector(locator string) (*YaksConnector, error) {
-
-	y, err := yaks.Login(&locator, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	ad := y.Admin()
-
-	wpath, err := yaks.NewPath("/")
-	if err != nil {
-		return nil, err
-	}
-
-	ws := y.WorkspaceWithExecutor(wpath)
-
-	g := NewGlobal(ws)
-	l := NewLocal(ws)
-
-	return &YaksConnector{ws: ws, Global: g, Local: l, yadmin: ad, yclient: y}, nil
+// NewConnector returns a YaksConnector built on the given Store, only the logger is taken from the options
+func NewConnector(store Store, opts ...ConnectorOption) *YaksConnector {
+	g := NewGlobal(store, opts...)
+	l := NewLocal(store, opts...)
+	return &YaksConnector{ws: store, Global: g, Local: l}
 }
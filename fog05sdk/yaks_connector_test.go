@@ -0,0 +1,399 @@
+/*
+* Copyright (c) 2014,2019 Contributors to the Eclipse Foundation
+* See the NOTICE file(s) distributed with this work for additional
+* information regarding copyright ownership.
+* This program and the accompanying materials are made available under the
+* terms of the Eclipse Public License 2.0 which is available at
+* http://www.eclipse.org/legal/epl-2.0, or the Apache License, Version 2.0
+* which is available at https://www.apache.org/licenses/LICENSE-2.0.
+* SPDX-License-Identifier: EPL-2.0 OR Apache-2.0
+* Contributors: Gabriele Baldoni, ADLINK Technology Inc.
+* golang APIs
+ */
+
+package fog05sdk_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	fog05sdk "github.com/eclipse-fog05/sdk-go/fog05sdk"
+	"github.com/eclipse-fog05/sdk-go/fog05sdk/fostest"
+)
+
+// countingStore counts the listeners and evals removed from the fostest Store it wraps
+type countingStore struct {
+	*fostest.Store
+	mu              sync.Mutex
+	unsubscribes    int
+	unregistrations int
+}
+
+func (cs *countingStore) Unsubscribe(ctx context.Context, sid *fog05sdk.SubscriptionID) error {
+	cs.mu.Lock()
+	cs.unsubscribes++
+	cs.mu.Unlock()
+	return cs.Store.Unsubscribe(ctx, sid)
+}
+
+func (cs *countingStore) UnregisterEval(ctx context.Context, path *fog05sdk.Path) error {
+	cs.mu.Lock()
+	cs.unregistrations++
+	cs.mu.Unlock()
+	return cs.Store.UnregisterEval(ctx, path)
+}
+
+func (cs *countingStore) removals() (int, int) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	return cs.unsubscribes, cs.unregistrations
+}
+
+// eventually waits for cond to be true, failing the test after a second
+func eventually(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	within(t, time.Second, what, cond)
+}
+
+// within waits for cond to be true, failing the test after the timeout
+func within(t *testing.T, timeout time.Duration, what string, cond func() bool) {
+	t.Helper()
+	for deadline := time.Now().Add(timeout); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		if cond() {
+			return
+		}
+	}
+	t.Fatalf("timeout waiting for %s", what)
+}
+
+func TestContextRemovesListenersAndEvals(t *testing.T) {
+	tests := []struct {
+		name string
+		// remove removes the listener and eval before the context is cancelled, if not nil
+		remove func(yc *fog05sdk.YaksConnector, sid *fog05sdk.SubscriptionID, p *fog05sdk.Path) error
+	}{
+		{"cancel", nil},
+		{"unsubscribe then cancel", func(yc *fog05sdk.YaksConnector, sid *fog05sdk.SubscriptionID, p *fog05sdk.Path) error {
+			if err := yc.Global.Actual.Unsubscribe(sid); err != nil {
+				return err
+			}
+			return yc.Local.Actual.RemoveEval(p)
+		}},
+		{"close then cancel", func(yc *fog05sdk.YaksConnector, sid *fog05sdk.SubscriptionID, p *fog05sdk.Path) error {
+			return yc.Close()
+		}},
+	}
+	for _, tt := range tests {
+		st := &countingStore{Store: fostest.NewStore()}
+		yc := fog05sdk.NewConnector(st)
+		ctx, cancel := context.WithCancel(context.Background())
+		sid, err := yc.Global.Actual.WithContext(ctx).ObserveNodeStatus("s1", "t1", "n1", func(fog05sdk.NodeStatus) {})
+		if err != nil {
+			t.Fatalf("%s: ObserveNodeStatus() = %v", tt.name, err)
+		}
+		if err := yc.Local.Actual.WithContext(ctx).AddOSEval("n1", "dir_exists", func(fog05sdk.Properties) interface{} { return true }); err != nil {
+			t.Fatalf("%s: AddOSEval() = %v", tt.name, err)
+		}
+		p, _ := yc.Local.Actual.GetNodeOSExecPath("n1", "dir_exists")
+		if tt.remove != nil {
+			if err := tt.remove(yc, sid, p); err != nil {
+				t.Fatalf("%s: %v", tt.name, err)
+			}
+		}
+		cancel()
+		eventually(t, tt.name+" removals", func() bool { u, r := st.removals(); return u == 1 && r == 1 })
+		time.Sleep(20 * time.Millisecond)
+		if u, r := st.removals(); u != 1 || r != 1 {
+			t.Errorf("%s: %d unsubscribes and %d eval removals, want 1 each", tt.name, u, r)
+		}
+		if n := st.Subscriptions(); n != 0 {
+			t.Errorf("%s: %d subscriptions left", tt.name, n)
+		}
+		if n := len(st.EvalPaths()); n != 0 {
+			t.Errorf("%s: %d evals left", tt.name, n)
+		}
+	}
+}
+
+func TestBackgroundContextKeepsListeners(t *testing.T) {
+	yc, st := fostest.NewConnector()
+	if _, err := yc.Global.Actual.ObserveNodeStatus("s1", "t1", "n1", func(fog05sdk.NodeStatus) {}); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if n := st.Subscriptions(); n != 1 {
+		t.Errorf("%d subscriptions, want 1", n)
+	}
+	if err := yc.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if n := st.Subscriptions(); n != 0 {
+		t.Errorf("%d subscriptions after Close, want 0", n)
+	}
+}
+
+func TestDoneContext(t *testing.T) {
+	yc, st := fostest.NewConnector()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	gad := yc.Global.Actual.WithContext(ctx)
+	lad := yc.Local.Actual.WithContext(ctx)
+	tests := []struct {
+		name string
+		op   func() error
+	}{
+		{"GAD.AddNodeStatus", func() error { return gad.AddNodeStatus("s1", "t1", "n1", fog05sdk.NodeStatus{}) }},
+		{"GAD.GetNodeInfo", func() error { _, err := gad.GetNodeInfo("s1", "t1", "n1"); return err }},
+		{"GAD.ObserveNodeStatus", func() error {
+			_, err := gad.ObserveNodeStatus("s1", "t1", "n1", func(fog05sdk.NodeStatus) {})
+			return err
+		}},
+		{"LAD.AddNodeStatus", func() error { return lad.AddNodeStatus("n1", fog05sdk.NodeStatus{}) }},
+		{"LAD.AddOSEval", func() error {
+			return lad.AddOSEval("n1", "dir_exists", func(fog05sdk.Properties) interface{} { return true })
+		}},
+	}
+	for _, tt := range tests {
+		if err := tt.op(); !errors.Is(err, context.Canceled) {
+			t.Errorf("%s with a done context = %v, want context.Canceled", tt.name, err)
+		}
+	}
+	if len(st.Paths()) != 0 || len(st.EvalPaths()) != 0 || st.Subscriptions() != 0 {
+		t.Errorf("done context changed the store: %v %v %d", st.Paths(), st.EvalPaths(), st.Subscriptions())
+	}
+}
+
+func TestAgentEvalResults(t *testing.T) {
+	yc, st := fostest.NewConnector()
+	gad := yc.Global.Actual
+	register := func(fname string, r fog05sdk.RawEvalResult) {
+		p, err := gad.GetAgentExecPath("s1", "t1", "n1", fname)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = st.RegisterEval(context.Background(), p, func(*fog05sdk.Path, fog05sdk.Properties) string {
+			v, _ := json.Marshal(r)
+			return string(v)
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	register("define_fdu", fog05sdk.NewEvalResult(fog05sdk.FDURecord{UUID: "i1", FDUID: "f1", Status: fog05sdk.DEFINE}))
+	register("create_floating_ip", fog05sdk.NewEvalResult("10.0.0.1"))
+	register("remove_node_network", fog05sdk.NewEvalErrorResult(&fog05sdk.EvalError{Code: 2, Message: "no network"}))
+
+	tests := []struct {
+		name   string
+		eval   func() (*fog05sdk.EvalResult, error)
+		result string
+		code   int
+	}{
+		{"object result", func() (*fog05sdk.EvalResult, error) { return gad.DefineFDUInNode("s1", "t1", "n1", "f1") }, `{"uuid":"i1"`, 0},
+		{"string result", func() (*fog05sdk.EvalResult, error) { return gad.CrateFloatingIPInNode("s1", "t1", "n1") }, "10.0.0.1", 0},
+		{"error", func() (*fog05sdk.EvalResult, error) { return gad.RemoveNetworkFromNode("s1", "t1", "n1", "net1") }, "", 2},
+	}
+	for _, tt := range tests {
+		res, err := tt.eval()
+		if tt.code != 0 {
+			var eerr *fog05sdk.EvalError
+			if !errors.As(err, &eerr) || eerr.Code != tt.code || res == nil || res.Error == nil || *res.Error != tt.code {
+				t.Errorf("%s: = %+v, %v, want error code %d", tt.name, res, err, tt.code)
+			}
+			continue
+		}
+		if err != nil || res.Result == nil || !strings.HasPrefix(*res.Result, tt.result) {
+			t.Errorf("%s: = %+v, %v, want result %q", tt.name, res, err, tt.result)
+		}
+	}
+
+	record := fog05sdk.FDURecord{}
+	if _, err := gad.EvalAgent("s1", "t1", "n1", "define_fdu", map[string]interface{}{"fdu_id": "f1"}, &record); err != nil || record.UUID != "i1" || record.Status != fog05sdk.DEFINE {
+		t.Errorf("EvalAgent(define_fdu) = %+v, %v", record, err)
+	}
+	ip := ""
+	if _, err := gad.EvalAgent("s1", "t1", "n1", "create_floating_ip", nil, &ip); err != nil || ip != "10.0.0.1" {
+		t.Errorf("EvalAgent(create_floating_ip) = %q, %v", ip, err)
+	}
+	if _, err := gad.EvalAgent("s1", "t1", "n1", "create_floating_ip", nil, &record); !errors.As(err, new(*fog05sdk.DecodeError)) {
+		t.Errorf("EvalAgent(create_floating_ip) in a record = %v, want a DecodeError", err)
+	}
+}
+
+func TestExtractIDsFromPath(t *testing.T) {
+	yc, _ := fostest.NewConnector()
+	gad := yc.Global.Actual
+	lad := yc.Local.Actual
+	path := func(s string) *fog05sdk.Path {
+		p, err := fog05sdk.NewPath(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+	tests := []struct {
+		name    string
+		extract func(*fog05sdk.Path) (string, error)
+		path    string
+		want    string
+	}{
+		{"GAD node", gad.ExtractNodeIDFromPath, "/agfos/s1/tenants/t1/nodes/n1/info", "n1"},
+		{"GAD node instance", gad.ExtractNodeInstanceIDFromPath, "/agfos/s1/tenants/t1/nodes/n1/fdu/f1/instances/i1/info", "i1"},
+		{"LAD plugin", lad.ExtractPluginIDFromPath, "/alfos/n1/plugins/p1/info", "p1"},
+		{"GAD short path", gad.ExtractNodeIDFromPath, "/agfos/s1/tenants", ""},
+		{"LAD short path", lad.ExtractNodeInstanceIDFromPath, "/alfos/n1/runtimes/p1", ""},
+	}
+	for _, tt := range tests {
+		got, err := tt.extract(path(tt.path))
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("%s: extracting from %s = %q, want an error", tt.name, tt.path, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("%s: extracting from %s = %q, %v, want %q", tt.name, tt.path, got, err, tt.want)
+		}
+	}
+}
+
+func TestObserveSkipsRemovals(t *testing.T) {
+	yc, _ := fostest.NewConnector()
+	var mu sync.Mutex
+	var statuses []fog05sdk.NodeStatus
+	var errs []error
+	lad := yc.Local.Actual.WithErrorHandler(func(err error) {
+		mu.Lock()
+		errs = append(errs, err)
+		mu.Unlock()
+	})
+	_, err := lad.ObserveNodeStatus("n1", func(s fog05sdk.NodeStatus) {
+		mu.Lock()
+		statuses = append(statuses, s)
+		mu.Unlock()
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var changes []string
+	_, err = lad.ObserveNodePluginsChanges("n1", func(pid string, pl *fog05sdk.Plugin) {
+		mu.Lock()
+		changes = append(changes, fmt.Sprintf("%s %v", pid, pl != nil))
+		mu.Unlock()
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := lad.AddNodeStatus("n1", fog05sdk.NodeStatus{UUID: "n1"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := lad.RemoveNodeStatus("n1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := lad.AddNodePlugin("n1", "p1", fog05sdk.Plugin{UUID: "p1"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := lad.RemoveNodePlugin("n1", "p1"); err != nil {
+		t.Fatal(err)
+	}
+	eventually(t, "the plugin removal", func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(changes) == 2
+	})
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(statuses) != 1 || statuses[0].UUID != "n1" {
+		t.Errorf("ObserveNodeStatus listener got %+v, want only the added status", statuses)
+	}
+	if changes[0] != "p1 true" || changes[1] != "p1 false" {
+		t.Errorf("ObserveNodePluginsChanges listener got %v, want the addition then the removal of p1", changes)
+	}
+	if len(errs) != 0 {
+		t.Errorf("removals reported errors: %v", errs)
+	}
+}
+
+func TestClose(t *testing.T) {
+	yc, st := fostest.NewConnector()
+	lad := yc.Local.Actual
+	cp := lad.WithContext(context.Background())
+	noStatus := func(fog05sdk.NodeStatus) {}
+	if _, err := lad.ObserveNodeStatus("n1", noStatus); err != nil {
+		t.Fatal(err)
+	}
+	if err := cp.AddOSEval("n1", "dir_exists", func(fog05sdk.Properties) interface{} { return true }); err != nil {
+		t.Fatal(err)
+	}
+	err := lad.AddPluginFDUStartEval("n1", "p1", "f1", "i1", func(*string) fog05sdk.EvalResult { return fog05sdk.EvalResult{} })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := yc.Local.Desired.ObserveNodeStatus("n1", noStatus); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := yc.Global.Actual.ObserveNodeStatus("s1", "t1", "n1", noStatus); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := lad.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if st.Subscriptions() != 2 || len(st.EvalPaths()) != 0 {
+		t.Errorf("LAD.Close left %d subscriptions and evals %v, want the 2 of the other LAD and GAD", st.Subscriptions(), st.EvalPaths())
+	}
+	if err := lad.Close(); err != nil {
+		t.Errorf("second LAD.Close = %v", err)
+	}
+
+	if err := yc.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if st.Subscriptions() != 0 {
+		t.Errorf("YaksConnector.Close left %d subscriptions", st.Subscriptions())
+	}
+	if err := yc.Local.Actual.AddNodeStatus("n1", fog05sdk.NodeStatus{}); !errors.Is(err, fog05sdk.ErrNotConnected) {
+		t.Errorf("AddNodeStatus after YaksConnector.Close = %v, want ErrNotConnected", err)
+	}
+}
+
+// failingStore fails all the unsubscriptions from the fostest Store it wraps
+type failingStore struct {
+	*fostest.Store
+}
+
+func (fs *failingStore) Unsubscribe(ctx context.Context, sid *fog05sdk.SubscriptionID) error {
+	return &fog05sdk.FError{Msg: "Unsubscribe failed", Cause: fog05sdk.ErrTimeout}
+}
+
+func TestCloseCollectsErrors(t *testing.T) {
+	fs := &failingStore{fostest.NewStore()}
+	yc := fog05sdk.NewConnector(fs)
+	noStatus := func(fog05sdk.NodeStatus) {}
+	for _, n := range []string{"n1", "n2"} {
+		if _, err := yc.Local.Actual.ObserveNodeStatus(n, noStatus); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := yc.Global.Desired.ObserveNodeStatus("s1", "t1", "n1", noStatus); err != nil {
+		t.Fatal(err)
+	}
+
+	err := yc.Close()
+	var me *fog05sdk.MultiError
+	if !errors.As(err, &me) || len(me.Errors) != 3 || !errors.Is(err, fog05sdk.ErrTimeout) {
+		t.Errorf("YaksConnector.Close = %v, want the 3 unsubscription errors", err)
+	}
+	p, _ := yc.Local.Actual.GetNodeStatusPath("n1")
+	if err := fs.Put(context.Background(), p, "{}"); !errors.Is(err, fog05sdk.ErrNotConnected) {
+		t.Errorf("Put after a failed YaksConnector.Close = %v, want the Store closed", err)
+	}
+}
@@ -0,0 +1,238 @@
+//go:build cgo
+// +build cgo
+
+/*
+* Copyright (c) 2014,2019 Contributors to the Eclipse Foundation
+* See the NOTICE file(s) distributed with this work for additional
+* information regarding copyright ownership.
+* This program and the accompanying materials are made available under the
+* terms of the Eclipse Public License 2.0 which is available at
+* http://www.eclipse.org/legal/epl-2.0, or the Apache License, Version 2.0
+* which is available at https://www.apache.org/licenses/LICENSE-2.0.
+* SPDX-License-Identifier: EPL-2.0 OR Apache-2.0
+* Contributors: Gabriele Baldoni, ADLINK Technology Inc.
+* golang APIs
+ */
+
+package fog05sdk
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/atolab/yaks-go"
+	log "github.com/sirupsen/logrus"
+)
+
+// YaksStore is the Store backed by a YAKS workspace
+type YaksStore struct {
+	yclient *yaks.Yaks
+	yadmin  *yaks.Admin
+	ws      *yaks.Workspace
+	root    string
+	timeout time.Duration
+	logger  log.FieldLogger
+}
+
+// NewYaksStore logs in the YAKS instance reachable at the given locator and returns a Store backed by a workspace,
+// the credentials, workspace root, default timeout, logger and executor mode are taken from the options
+func NewYaksStore(locator string, opts ...ConnectorOption) (*YaksStore, error) {
+	o := NewConnectorOptions(opts...)
+	var props yaks.Properties
+	if o.User != "" {
+		props = yaks.Properties{yaks.PropUser: o.User, yaks.PropPassword: o.Password}
+	}
+	y, err := yaks.Login(&locator, props)
+	if err != nil {
+		return nil, &FError{"Unable to connect to YAKS at " + locator + ": " + err.Error(), ErrNotConnected}
+	}
+
+	root := strings.TrimSuffix(slashesRegexp.ReplaceAllString("/"+o.WorkspaceRoot, "/"), "/")
+	wpath, err := yaks.NewPath(root + "/")
+	if err != nil {
+		y.Logout()
+		return nil, err
+	}
+
+	var ws *yaks.Workspace
+	if o.Executor {
+		ws = y.WorkspaceWithExecutor(wpath)
+	} else {
+		ws = y.Workspace(wpath)
+	}
+
+	return &YaksStore{yclient: y, yadmin: y.Admin(), ws: ws, root: root, timeout: o.Timeout, logger: o.Logger}, nil
+}
+
+// NewYaksConnector returns a YaksConnector connected to the YAKS instance reachable at the given locator, configured by the options.
+// The connector reconnects to YAKS when the connection is lost
+func NewYaksConnector(locator string, opts ...ConnectorOption) (*YaksConnector, error) {
+	st, err := NewReconnectingStore(func() (Store, error) { return NewYaksStore(locator, opts...) }, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return NewConnector(st, opts...), nil
+}
+
+// context returns a context with the default timeout of the YaksStore, if ctx has no deadline
+func (ys *YaksStore) context(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok || ys.timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, ys.timeout)
+}
+
+func (ys *YaksStore) path(path *Path) (*yaks.Path, error) {
+	return yaks.NewPath(ys.root + path.ToString())
+}
+
+func (ys *YaksStore) localPath(path *yaks.Path) (*Path, error) {
+	return NewPath(strings.TrimPrefix(path.ToString(), ys.root))
+}
+
+// Get ...
+func (ys *YaksStore) Get(ctx context.Context, selector *Selector) ([]Entry, error) {
+	ctx, cancel := ys.context(ctx)
+	defer cancel()
+	s, err := yaks.NewSelector(ys.root + selector.ToString())
+	if err != nil {
+		return nil, err
+	}
+	var kvs []yaks.Entry
+	err = callWithContext(ctx, func() error {
+		kvs = ys.ws.Get(s)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	entries := []Entry{}
+	for _, kv := range kvs {
+		p, err := ys.localPath(kv.Path())
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, Entry{Path: p, Value: kv.Value().ToString()})
+	}
+	return entries, nil
+}
+
+// Put ...
+func (ys *YaksStore) Put(ctx context.Context, path *Path, value string) error {
+	ctx, cancel := ys.context(ctx)
+	defer cancel()
+	p, err := ys.path(path)
+	if err != nil {
+		return err
+	}
+	return callWithContext(ctx, func() error {
+		return ys.ws.Put(p, yaks.NewStringValue(value))
+	})
+}
+
+// Remove ...
+func (ys *YaksStore) Remove(ctx context.Context, path *Path) error {
+	ctx, cancel := ys.context(ctx)
+	defer cancel()
+	p, err := ys.path(path)
+	if err != nil {
+		return err
+	}
+	return callWithContext(ctx, func() error {
+		return ys.ws.Remove(p)
+	})
+}
+
+// Subscribe ...
+func (ys *YaksStore) Subscribe(ctx context.Context, selector *Selector, listener func([]Change)) (*SubscriptionID, error) {
+	ctx, cancel := ys.context(ctx)
+	defer cancel()
+	s, err := yaks.NewSelector(ys.root + selector.ToString())
+	if err != nil {
+		return nil, err
+	}
+
+	cb := func(ychanges []yaks.Change) {
+		changes := []Change{}
+		for _, c := range ychanges {
+			p, err := ys.localPath(c.Path())
+			if err != nil {
+				ys.logger.WithField("path", c.Path().ToString()).Warn("Ignoring change on invalid path")
+				continue
+			}
+			v := ""
+			if c.Value() != nil {
+				v = c.Value().ToString()
+			}
+			changes = append(changes, Change{Path: p, Kind: c.Kind(), Time: c.Time(), Value: v})
+		}
+		listener(changes)
+	}
+
+	var sid *yaks.SubscriptionID
+	err = callWithUndo(ctx, func() error {
+		id, err := ys.ws.Subscribe(s, cb)
+		sid = id
+		return err
+	}, func() {
+		// the caller gave up, it will never unsubscribe
+		ys.ws.Unsubscribe(sid)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &SubscriptionID{Handle: sid}, nil
+}
+
+// Unsubscribe ...
+func (ys *YaksStore) Unsubscribe(ctx context.Context, sid *SubscriptionID) error {
+	ctx, cancel := ys.context(ctx)
+	defer cancel()
+	ysid, ok := sid.Handle.(*yaks.SubscriptionID)
+	if !ok {
+		return &FError{"Not a YAKS subscription", nil}
+	}
+	return callWithContext(ctx, func() error {
+		return ys.ws.Unsubscribe(ysid)
+	})
+}
+
+// RegisterEval ...
+func (ys *YaksStore) RegisterEval(ctx context.Context, path *Path, eval EvalHandler) error {
+	ctx, cancel := ys.context(ctx)
+	defer cancel()
+	p, err := ys.path(path)
+	if err != nil {
+		return err
+	}
+
+	cb := func(ypath *yaks.Path, yprops yaks.Properties) yaks.Value {
+		return yaks.NewStringValue(eval(path, Properties(yprops)))
+	}
+
+	return callWithUndo(ctx, func() error {
+		return ys.ws.RegisterEval(p, cb)
+	}, func() {
+		// the caller gave up, it will never unregister
+		ys.ws.UnregisterEval(p)
+	})
+}
+
+// UnregisterEval ...
+func (ys *YaksStore) UnregisterEval(ctx context.Context, path *Path) error {
+	ctx, cancel := ys.context(ctx)
+	defer cancel()
+	p, err := ys.path(path)
+	if err != nil {
+		return err
+	}
+	return callWithContext(ctx, func() error {
+		return ys.ws.UnregisterEval(p)
+	})
+}
+
+// Close logs out from YAKS
+func (ys *YaksStore) Close() error {
+	return ys.yclient.Logout()
+}
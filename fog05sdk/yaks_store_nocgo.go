@@ -0,0 +1,22 @@
+//go:build !cgo
+// +build !cgo
+
+/*
+* Copyright (c) 2014,2019 Contributors to the Eclipse Foundation
+* See the NOTICE file(s) distributed with this work for additional
+* information regarding copyright ownership.
+* This program and the accompanying materials are made available under the
+* terms of the Eclipse Public License 2.0 which is available at
+* http://www.eclipse.org/legal/epl-2.0, or the Apache License, Version 2.0
+* which is available at https://www.apache.org/licenses/LICENSE-2.0.
+* SPDX-License-Identifier: EPL-2.0 OR Apache-2.0
+* Contributors: Gabriele Baldoni, ADLINK Technology Inc.
+* golang APIs
+ */
+
+package fog05sdk
+
+// NewYaksConnector is not available without cgo, as the YAKS client is built on zenoh-c, use NewConnector with another Store
+func NewYaksConnector(locator string, opts ...ConnectorOption) (*YaksConnector, error) {
+	return nil, &FError{"YAKS connector not available, built without cgo", ErrNotConnected}
+}